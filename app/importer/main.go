@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/winartodev/go-pokedex/config"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/pokeapi"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemontypserepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	typserepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/transaction"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+// importer fills the pokedex from a local copy of the PokeAPI data set
+func main() {
+	dir := flag.String("dir", "", "directory of the data set, either data/v2/csv or data/api/v2 of api-data")
+	generation := flag.String("generation", "", "generations to import like 1 or 1,3 or 1-3, every generation is imported when empty")
+	imageURL := flag.String("image-url", pokeapi.DefaultImageURL, "image url of the pokemons, %d is replaced with the pokedex number")
+	batchSize := flag.Int("batch", pokeapi.DefaultBatchSize, "number of pokemons imported between two progress reports")
+	flag.Parse()
+
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	generations, err := pokeapi.ParseGenerations(*generation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pokemons, err := pokeapi.Load(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pokemons = pokeapi.Filter(pokemons, generations)
+	log.Printf("%d pokemons found in %s", len(pokemons), *dir)
+
+	// initialize config
+	cfg := config.NewConfig()
+
+	// make connection to database
+	db, err := config.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer db.Close()
+
+	// initialize repository
+	pokemonRepository := pokemonrepository.NewPokemonRepository(db)
+	pokemonTypeRepository := pokemontypserepository.NewPokemonTypeRepository(db)
+	typeRepository := typserepository.NewTypeRepository(db)
+	auditRepository := auditrepository.NewAuditRepository(db)
+	pokemonRevisionRepository := pokemonrevisionrepository.NewPokemonRevisionRepository(db)
+	transactor := transaction.NewTransactor(db)
+
+	// initialize usecase
+	pokemonUsecase := usecase.NewPokemonUsecase(usecase.PokemonUsecase{PokemonRepository: pokemonRepository, PokemonTypeRepository: pokemonTypeRepository, TypesRepository: typeRepository, AuditRepository: auditRepository, PokemonRevisionRepository: pokemonRevisionRepository, Transactor: transactor})
+	typeUsecase := usecase.NewTypeUsecase(usecase.TypeUsecase{TypesRepository: typeRepository, PokemonTypeRepository: pokemonTypeRepository, AuditRepository: auditRepository, Transactor: transactor})
+
+	importer := pokeapi.Importer{
+		PokemonUsecase: pokemonUsecase,
+		TypeUsecase:    typeUsecase,
+		ImageURL:       *imageURL,
+		BatchSize:      *batchSize,
+		Progress:       os.Stdout,
+	}
+
+	// changes are recorded in the audit log and revisions as done by the importer
+	ctx := auth.NewContext(context.Background(), &auth.JWTClaim{Username: "pokeapi-importer"})
+	report, err := importer.Import(ctx, pokemons)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
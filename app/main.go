@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/winartodev/go-pokedex/config"
+	"github.com/winartodev/go-pokedex/enum"
 	"github.com/winartodev/go-pokedex/middleware"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	apikeyrepository "github.com/winartodev/go-pokedex/repository/apikey"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
 	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
 	pokemontypserepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	recoverycoderepository "github.com/winartodev/go-pokedex/repository/recoverycode"
+	sessionrepository "github.com/winartodev/go-pokedex/repository/session"
 	typserepository "github.com/winartodev/go-pokedex/repository/types"
 	userrepository "github.com/winartodev/go-pokedex/repository/user"
+	usertokenrepository "github.com/winartodev/go-pokedex/repository/usertoken"
+	"github.com/winartodev/go-pokedex/search"
 	"github.com/winartodev/go-pokedex/server"
+	"github.com/winartodev/go-pokedex/transaction"
 	"github.com/winartodev/go-pokedex/usecase"
 )
 
@@ -33,45 +45,191 @@ func main() {
 	pokemonTypeRepository := pokemontypserepository.NewPokemonTypeRepository(db)
 	typeRepository := typserepository.NewTypeRepository(db)
 	userrepository := userrepository.NewUserRepository(db)
+	userTokenRepository := usertokenrepository.NewUserTokenRepository(db)
+	recoveryCodeRepository := recoverycoderepository.NewRecoveryCodeRepository(db)
+	apiKeyRepository := apikeyrepository.NewAPIKeyRepository(db)
+	sessionRepository := sessionrepository.NewSessionRepository(db)
+	auditRepository := auditrepository.NewAuditRepository(db)
+	pokemonRevisionRepository := pokemonrevisionrepository.NewPokemonRevisionRepository(db)
+	transactor := transaction.NewTransactor(db)
+	searchIndex := search.NewMemoryIndex()
+	suggester := search.NewTrie()
+
+	// initialize mailer
+	mailer, err := config.NewMailer(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	// initialize password policy & login throttles
+	passwordPolicy, err := config.NewPasswordPolicy(cfg)
+	if err != nil {
+		panic(err)
+	}
+	usernameThrottle, ipThrottle := config.NewLoginThrottles(cfg)
+
+	// initialize oidc provider
+	oidcProvider, oidcGroupRoles, oidcDefaultRole, err := config.NewOIDCProvider(cfg)
+	if err != nil {
+		panic(err)
+	}
 
 	// initialize usecase
-	pokemonUsecase := usecase.NewPokemonUsecase(usecase.PokemonUsecase{PokemonRepository: pokemonRepository, PokemonTypeRepository: pokemonTypeRepository})
-	typeUsecase := usecase.NewTypeUsecase(usecase.TypeUsecase{TypesRepository: typeRepository})
-	userUsecsae := usecase.NewUserUsecase(usecase.UserUsecase{UserRepository: userrepository})
+	pokemonUsecase := usecase.NewPokemonUsecase(usecase.PokemonUsecase{PokemonRepository: pokemonRepository, PokemonTypeRepository: pokemonTypeRepository, TypesRepository: typeRepository, AuditRepository: auditRepository, PokemonRevisionRepository: pokemonRevisionRepository, Transactor: transactor, SearchIndex: searchIndex, Suggester: suggester})
+	typeUsecase := usecase.NewTypeUsecase(usecase.TypeUsecase{TypesRepository: typeRepository, PokemonTypeRepository: pokemonTypeRepository, AuditRepository: auditRepository, Transactor: transactor, PokemonUsecase: pokemonUsecase})
+	trashUsecase := usecase.NewTrashUsecase(usecase.TrashUsecase{PokemonRepository: pokemonRepository, TypesRepository: typeRepository, AuditRepository: auditRepository, Transactor: transactor, Retention: cfg.Trash.Retention})
+	auditUsecase := usecase.NewAuditUsecase(usecase.AuditUsecase{AuditRepository: auditRepository})
+	userUsecsae := usecase.NewUserUsecase(usecase.UserUsecase{
+		UserRepository:         userrepository,
+		UserTokenRepository:    userTokenRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+		Mailer:                 mailer,
+		TokenSecret:            cfg.Authorization.TokenSecret,
+		PublicURL:              cfg.Application.PublicURL,
+		PasswordPolicy:         passwordPolicy,
+		UsernameThrottle:       usernameThrottle,
+		IPThrottle:             ipThrottle,
+		TOTPIssuer:             cfg.Authorization.TOTPIssuer,
+		RequireAdminTwoFactor:  cfg.Authorization.RequireAdminTwoFactor,
+		OIDCProvider:           oidcProvider,
+		OIDCGroupRoles:         oidcGroupRoles,
+		OIDCDefaultRole:        oidcDefaultRole,
+		SessionRepository:      sessionRepository,
+		AuditRepository:        auditRepository,
+		Transactor:             transactor,
+	})
+
+	// initialize authorization policy
+	policy, err := auth.ParsePolicy(cfg.Authorization.RolePermissions, cfg.Authorization.RoleInherits)
+	if err != nil {
+		panic(err)
+	}
+
+	// api keys can only be granted permissions of the policy
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(usecase.APIKeyUsecase{
+		APIKeyRepository: apiKeyRepository,
+		AuditRepository:  auditRepository,
+		Transactor:       transactor,
+		TokenSecret:      cfg.Authorization.TokenSecret,
+		Policy:           policy,
+	})
+
+	m := middleware.NewMiddleware(middleware.Middleware{
+		Policy:                policy,
+		RequireVerifiedEmail:  cfg.Authorization.RequireVerifiedEmailToCatch,
+		RequireAdminTwoFactor: cfg.Authorization.RequireAdminTwoFactor,
+		APIKeyUsecase:         apiKeyUsecase,
+		UserUsecase:           userUsecsae,
+	})
 
 	s := server.Server{
 		Router:         httprouter.New(),
 		PokemonUsecase: pokemonUsecase,
 		TypeUsecase:    typeUsecase,
 		UserUsecase:    userUsecsae,
+		APIKeyUsecase:  apiKeyUsecase,
+		TrashUsecase:   trashUsecase,
+		AuditUsecase:   auditUsecase,
 	}
 
 	// internal
-	s.Router.GET("/internal/pokedex/pokemons", middleware.Auth(s.GetAllPokemon))
-	s.Router.POST("/internal/pokedex/pokemons", middleware.Auth(s.CreatePokemon))
-	s.Router.GET("/internal/pokedex/pokemons/:id", middleware.Auth(s.GetPokemonByID))
-	s.Router.PUT("/internal/pokedex/pokemons/:id", middleware.Auth(s.UpdatePokemon))
-	s.Router.DELETE("/internal/pokedex/pokemons/:id", middleware.Auth(s.DeletePokemon))
+	s.Router.GET("/internal/pokedex/pokemons", m.Require(enum.PokemonRead)(s.GetAllPokemon))
+	s.Router.POST("/internal/pokedex/pokemons", m.Require(enum.PokemonWrite)(s.CreatePokemon))
+	s.Router.GET("/internal/pokedex/pokemons/:id", m.Require(enum.PokemonRead)(s.GetPokemonByID))
+	s.Router.PUT("/internal/pokedex/pokemons/:id", m.Require(enum.PokemonWrite)(s.UpdatePokemon))
+	s.Router.PATCH("/internal/pokedex/pokemons/:id", m.Require(enum.PokemonWrite)(s.PatchPokemon))
+	s.Router.DELETE("/internal/pokedex/pokemons/:id", m.Require(enum.PokemonWrite)(s.DeletePokemon))
+	s.Router.POST("/internal/pokedex/pokemons/:id/restore", m.Require(enum.PokemonWrite)(s.RestorePokemon))
+	s.Router.GET("/internal/pokedex/pokemons/:id/revisions", m.Require(enum.PokemonRead)(s.GetPokemonRevisions))
+	s.Router.POST("/internal/pokedex/pokemons/:id/revisions/:rev/restore", m.Require(enum.PokemonWrite)(s.RestorePokemonRevision))
+
+	s.Router.GET("/internal/pokedex/export", m.Require(enum.PokemonRead)(s.ExportPokemon))
+	s.Router.POST("/internal/pokedex/import", m.Require(enum.PokemonWrite)(s.ImportPokemon))
+
+	s.Router.POST("/internal/pokedex/bulk/pokemons", m.Require(enum.PokemonWrite)(s.BulkCreatePokemon))
+	s.Router.PUT("/internal/pokedex/bulk/pokemons", m.Require(enum.PokemonWrite)(s.BulkUpdatePokemon))
+	s.Router.DELETE("/internal/pokedex/bulk/pokemons", m.Require(enum.PokemonWrite)(s.BulkDeletePokemon))
+
+	s.Router.GET("/internal/pokedex/types", m.Require(enum.TypeRead)(s.GetAllType))
+	s.Router.POST("/internal/pokedex/types", m.Require(enum.TypeWrite)(s.CreateType))
+	s.Router.GET("/internal/pokedex/types/:id", m.Require(enum.TypeRead)(s.GetTypeByID))
+	s.Router.PUT("/internal/pokedex/types/:id", m.Require(enum.TypeWrite)(s.UpdateType))
+	s.Router.PATCH("/internal/pokedex/types/:id", m.Require(enum.TypeWrite)(s.PatchType))
+	s.Router.DELETE("/internal/pokedex/types/:id", m.Require(enum.TypeWrite)(s.DeleteType))
+	s.Router.POST("/internal/pokedex/types/:id/restore", m.Require(enum.TypeWrite)(s.RestoreType))
+
+	s.Router.GET("/internal/pokedex/trash", m.Require(enum.TrashRead)(s.GetTrash))
 
-	s.Router.GET("/internal/pokedex/types", middleware.Auth(s.GetAllType))
-	s.Router.POST("/internal/pokedex/types", middleware.Auth(s.CreateType))
-	s.Router.GET("/internal/pokedex/types/:id", middleware.Auth(s.GetTypeByID))
-	s.Router.PUT("/internal/pokedex/types/:id", middleware.Auth(s.UpdateType))
+	s.Router.GET("/internal/audit", m.Require(enum.AuditRead)(s.GetAuditLogs))
+
+	s.Router.POST("/internal/users", m.Require(enum.UserManage)(s.CreateUser))
+	s.Router.GET("/internal/users", m.Require(enum.UserManage)(s.GetAllUsers))
+	s.Router.PUT("/internal/users/:id/role", m.Require(enum.UserManage)(s.UpdateUserRole))
+	s.Router.PUT("/internal/users/:id/status", m.Require(enum.UserManage)(s.SetUserDisabled))
+	s.Router.DELETE("/internal/users/:id", m.Require(enum.UserManage)(s.DeleteUser))
+
+	s.Router.POST("/internal/api-keys", m.Require(enum.APIKeyManage)(s.CreateAPIKey))
+	s.Router.GET("/internal/api-keys", m.Require(enum.APIKeyManage)(s.GetAllAPIKeys))
+	s.Router.DELETE("/internal/api-keys/:id", m.Require(enum.APIKeyManage)(s.RevokeAPIKey))
 
 	// user
-	s.Router.POST("/user/pokedex/pokemons/:id/catch", middleware.Auth(s.CatchPokemon))
+	s.Router.GET("/user/me", m.Auth(s.GetProfile))
+	s.Router.PATCH("/user/me", m.Auth(s.UpdateProfile))
+	s.Router.DELETE("/user/me", m.Auth(s.DeleteAccount))
+	s.Router.PUT("/user/me/password", m.Auth(s.ChangePassword))
+	s.Router.POST("/user/me/verify-email", m.Auth(s.SendVerificationEmail))
+	s.Router.POST("/user/me/2fa", m.Auth(s.EnrollTOTP))
+	s.Router.POST("/user/me/2fa/confirm", m.Auth(s.ConfirmTOTP))
+	s.Router.DELETE("/user/me/2fa", m.Auth(s.DisableTOTP))
+	s.Router.POST("/user/me/2fa/recovery-codes", m.Auth(s.RegenerateRecoveryCodes))
+	s.Router.GET("/user/sessions", m.Auth(s.GetSessions))
+	s.Router.DELETE("/user/sessions/:id", m.Auth(s.RevokeSession))
+	s.Router.POST("/user/pokedex/pokemons/:id/catch", m.Require(enum.CollectionCatch)(m.VerifiedEmail(s.CatchPokemon)))
 
 	// public
 	s.Router.GET("/pokedex/pokemons", s.GetAllPokemon)
 	s.Router.GET("/pokedex/pokemons/:id", s.GetPokemonByID)
+	s.Router.GET("/pokedex/search", s.SearchPokemon)
+	s.Router.GET("/pokedex/suggest", s.SuggestPokemon)
 	s.Router.GET("/pokedex/types", s.GetAllType)
 
 	s.Router.POST("/login", s.Login)
+	s.Router.POST("/login/2fa", s.LoginTwoFactor)
+	if oidcProvider != nil {
+		s.Router.GET("/login/oidc", s.OIDCLogin)
+		s.Router.GET("/login/oidc/callback", s.OIDCCallback)
+	}
 	s.Router.POST("/register", s.Register)
 	s.Router.POST("/logout", s.Logout)
+	s.Router.POST("/password/forgot", s.ForgotPassword)
+	s.Router.POST("/password/reset", s.ResetPassword)
+	s.Router.GET("/verify-email", s.VerifyEmail)
 
 	s.Router.GET("/healthz", s.Healthz)
 
+	// build the search index and suggestions, then rebuild them in the background so changes of other processes are found too
+	if err := pokemonUsecase.ReindexPokemon(context.Background()); err != nil {
+		panic(err)
+	}
+	go func() {
+		for range time.Tick(cfg.Search.ReindexInterval) {
+			if err := pokemonUsecase.ReindexPokemon(context.Background()); err != nil {
+				log.Printf("reindex search: %v", err)
+			}
+		}
+	}()
+
+	// purge the trash in the background once deleted items are past the retention,
+	// the purges are audited under the name of the background job
+	go func() {
+		ctx := auth.NewContext(context.Background(), &auth.JWTClaim{Username: "trash-purge"})
+		for range time.Tick(cfg.Trash.PurgeInterval) {
+			if err := trashUsecase.Purge(ctx, time.Now()); err != nil {
+				log.Printf("purge trash: %v", err)
+			}
+		}
+	}()
+
 	fmt.Printf("http listen and serve at :%d\n", cfg.Application.Port)
 	if err := http.ListenAndServe(":8080", s.Router); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/middleware"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+)
+
+// apiBackend runs the commands through the internal api authenticated with an api key,
+// updates and deletes are sent with If-Match of the ETag the resource has right before the change,
+// Force sends If-Match: * instead so the change is made whatever version the resource has
+type apiBackend struct {
+	URL        string
+	APIKey     string
+	Force      bool
+	HTTPClient *http.Client
+}
+
+// apiError is the problem+json body of failed api response
+type apiError struct {
+	Status int                   `json:"status"`
+	Detail string                `json:"detail"`
+	Code   string                `json:"code"`
+	Errors []apperror.FieldError `json:"errors"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+func (b *apiBackend) ListPokemons(ctx context.Context) (results []entity.PokemonList, err error) {
+	err = b.call(ctx, http.MethodGet, "/internal/pokedex/pokemons", nil, &results)
+	return results, err
+}
+
+func (b *apiBackend) GetPokemon(ctx context.Context, id int64) (result *entity.PokemonDetail, err error) {
+	err = b.call(ctx, http.MethodGet, fmt.Sprintf("/internal/pokedex/pokemons/%d", id), nil, &result)
+	return result, err
+}
+
+func (b *apiBackend) CreatePokemon(ctx context.Context, data entity.Pokemon) (id int64, err error) {
+	err = b.call(ctx, http.MethodPost, "/internal/pokedex/pokemons", data, &id)
+	return id, err
+}
+
+func (b *apiBackend) UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (result *entity.PokemonDetail, err error) {
+	err = b.change(ctx, http.MethodPut, fmt.Sprintf("/internal/pokedex/pokemons/%d", id), data, &result)
+	return result, err
+}
+
+func (b *apiBackend) DeletePokemon(ctx context.Context, id int64) (err error) {
+	return b.change(ctx, http.MethodDelete, fmt.Sprintf("/internal/pokedex/pokemons/%d", id), nil, nil)
+}
+
+func (b *apiBackend) ListTypes(ctx context.Context) (results []entity.Type, err error) {
+	err = b.call(ctx, http.MethodGet, "/internal/pokedex/types", nil, &results)
+	return results, err
+}
+
+func (b *apiBackend) CreateType(ctx context.Context, name string) (id int64, err error) {
+	err = b.call(ctx, http.MethodPost, "/internal/pokedex/types", entity.Type{Name: name}, &id)
+	return id, err
+}
+
+func (b *apiBackend) UpdateType(ctx context.Context, id int64, name string) (err error) {
+	return b.change(ctx, http.MethodPut, fmt.Sprintf("/internal/pokedex/types/%d", id), entity.Type{Name: name}, nil)
+}
+
+func (b *apiBackend) DeleteType(ctx context.Context, id int64, cascade bool) (err error) {
+	return b.change(ctx, http.MethodDelete, fmt.Sprintf("/internal/pokedex/types/%d?cascade=%t", id, cascade), nil, nil)
+}
+
+func (b *apiBackend) ListUsers(ctx context.Context, search string) (results []entity.UserProfile, err error) {
+	err = b.call(ctx, http.MethodGet, "/internal/users?q="+url.QueryEscape(search), nil, &results)
+	return results, err
+}
+
+func (b *apiBackend) CreateUser(ctx context.Context, username string, email string, password string, role enum.Role) (id int64, err error) {
+	err = b.call(ctx, http.MethodPost, "/internal/users", entity.User{Username: username, Email: email, Password: password, Role: int64(role)}, &id)
+	return id, err
+}
+
+func (b *apiBackend) UpdateUserRole(ctx context.Context, id int64, role enum.Role) (err error) {
+	return b.call(ctx, http.MethodPut, fmt.Sprintf("/internal/users/%d/role", id), entity.User{Role: int64(role)}, nil)
+}
+
+func (b *apiBackend) SetUserDisabled(ctx context.Context, id int64, disabled bool) (err error) {
+	return b.call(ctx, http.MethodPut, fmt.Sprintf("/internal/users/%d/status", id), entity.User{Disabled: disabled}, nil)
+}
+
+func (b *apiBackend) DeleteUser(ctx context.Context, id int64) (err error) {
+	return b.call(ctx, http.MethodDelete, fmt.Sprintf("/internal/users/%d", id), nil, nil)
+}
+
+func (b *apiBackend) Export(ctx context.Context, format string, w io.Writer) (err error) {
+	res, err := b.do(ctx, http.MethodGet, "/internal/pokedex/export?format="+format, "", "", nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	_, err = io.Copy(w, res.Body)
+	return err
+}
+
+func (b *apiBackend) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (report entity.ImportReport, err error) {
+	path := fmt.Sprintf("/internal/pokedex/import?format=%s&dry_run=%s", format, strconv.FormatBool(dryRun))
+	res, err := b.do(ctx, http.MethodPost, path, pokedexfile.ContentType(format), "", r)
+	if err != nil {
+		return report, err
+	}
+	defer res.Body.Close()
+
+	err = decodeData(res.Body, &report)
+	return report, err
+}
+
+// call sends the body as json and decodes data of the success response into result, nil result ignores the data
+func (b *apiBackend) call(ctx context.Context, method string, path string, body interface{}, result interface{}) (err error) {
+	return b.send(ctx, method, path, "", body, result)
+}
+
+// change sends the update or delete of the resource at path with If-Match of its current ETag,
+// the change fails with 412 when the resource is changed by someone else in between
+func (b *apiBackend) change(ctx context.Context, method string, path string, body interface{}, result interface{}) (err error) {
+	tag := "*"
+	if !b.Force {
+		tag, err = b.etag(ctx, strings.SplitN(path, "?", 2)[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.send(ctx, method, path, tag, body, result)
+}
+
+// etag returns the ETag of the resource at path
+func (b *apiBackend) etag(ctx context.Context, path string) (tag string, err error) {
+	res, err := b.do(ctx, http.MethodGet, path, "", "", nil)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+
+	tag = res.Header.Get("ETag")
+	if tag == "" {
+		return "", fmt.Errorf("%s has no ETag, use -force to change it anyway", path)
+	}
+
+	return tag, nil
+}
+
+// send sends the body as json with the If-Match header unless it is empty
+func (b *apiBackend) send(ctx context.Context, method string, path string, ifMatch string, body interface{}, result interface{}) (err error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	res, err := b.do(ctx, method, path, "application/json", ifMatch, reader)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+
+	return decodeData(res.Body, result)
+}
+
+// do sends the request and returns the response when it is successful, otherwise the problem of the response is returned as error
+func (b *apiBackend) do(ctx context.Context, method string, path string, contentType string, ifMatch string, body io.Reader) (res *http.Response, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(b.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(middleware.APIKeyHeader, b.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	client := b.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err = client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+
+		problem := &apiError{Status: res.StatusCode}
+		if err := json.NewDecoder(res.Body).Decode(problem); err != nil {
+			problem.Detail = http.StatusText(res.StatusCode)
+		}
+		return nil, problem
+	}
+
+	return res, nil
+}
+
+// decodeData decodes data of the success response body
+func decodeData(body io.Reader, result interface{}) (err error) {
+	success := struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: result,
+	}
+
+	return json.NewDecoder(body).Decode(&success)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/middleware"
+)
+
+func TestAPIBackend(t *testing.T) {
+	var gotMethod, gotPath, gotKey, gotIfMatch, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotKey, gotIfMatch, gotBody = r.Method, r.URL.RequestURI(), r.Header.Get(middleware.APIKeyHeader), r.Header.Get("If-Match"), string(body)
+
+		switch r.URL.Path {
+		case "/internal/pokedex/types":
+			io.WriteString(w, `{"status":"OK","message":"","data":[{"id":1,"name":"GRASS"}]}`)
+		case "/internal/pokedex/types/2":
+			if r.Method == http.MethodGet {
+				w.Header().Set("ETag", `"4"`)
+				io.WriteString(w, `{"status":"OK","message":"","data":{"id":2,"name":"FIRE"}}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			io.WriteString(w, `{"status":422,"detail":"invalid","code":"validation_failed","errors":[{"field":"name","code":"required","message":"name can't be empty"}]}`)
+		case "/internal/pokedex/export":
+			io.WriteString(w, "id,name\n")
+		default:
+			io.WriteString(w, `{"status":"OK","message":"","data":null}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	b := &apiBackend{URL: server.URL + "/", APIKey: "key"}
+
+	types, err := b.ListTypes(ctx)
+	if err != nil {
+		t.Fatalf("apiBackend.ListTypes() error = %v", err)
+	}
+	if want := []entity.Type{{ID: 1, Name: "GRASS"}}; !reflect.DeepEqual(types, want) {
+		t.Errorf("apiBackend.ListTypes() = %v, want %v", types, want)
+	}
+	if gotKey != "key" {
+		t.Errorf("apiBackend.ListTypes() api key = %q, want %q", gotKey, "key")
+	}
+
+	err = b.UpdateType(ctx, 2, "")
+	want := &apiError{Status: 422, Detail: "invalid", Code: "validation_failed", Errors: []apperror.FieldError{{Field: "name", Code: "required", Message: "name can't be empty"}}}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("apiBackend.UpdateType() error = %v, want %v", err, want)
+	}
+	if gotMethod != http.MethodPut || gotIfMatch != `"4"` || gotBody != `{"id":0,"name":""}` {
+		t.Errorf("apiBackend.UpdateType() request = %s %q %q", gotMethod, gotIfMatch, gotBody)
+	}
+
+	err = b.DeleteType(ctx, 3, true)
+	if err == nil || err.Error() != "/internal/pokedex/types/3 has no ETag, use -force to change it anyway" {
+		t.Errorf("apiBackend.DeleteType() error = %v", err)
+	}
+
+	b.Force = true
+	err = b.DeleteType(ctx, 3, true)
+	if err != nil {
+		t.Fatalf("apiBackend.DeleteType() error = %v", err)
+	}
+	if gotMethod != http.MethodDelete || gotPath != "/internal/pokedex/types/3?cascade=true" || gotIfMatch != "*" {
+		t.Errorf("apiBackend.DeleteType() request = %s %s %q", gotMethod, gotPath, gotIfMatch)
+	}
+
+	var export bytes.Buffer
+	err = b.Export(ctx, "csv", &export)
+	if err != nil {
+		t.Fatalf("apiBackend.Export() error = %v", err)
+	}
+	if export.String() != "id,name\n" || gotPath != "/internal/pokedex/export?format=csv" {
+		t.Errorf("apiBackend.Export() = %q from %s", export.String(), gotPath)
+	}
+
+	_, err = b.Import(ctx, "csv", strings.NewReader("name\nBulbasaur\n"), true)
+	if err != nil {
+		t.Fatalf("apiBackend.Import() error = %v", err)
+	}
+	if gotPath != "/internal/pokedex/import?format=csv&dry_run=true" || gotBody != "name\nBulbasaur\n" {
+		t.Errorf("apiBackend.Import() request = %s %q", gotPath, gotBody)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+// backend runs the admin commands either on the database or through the api
+type backend interface {
+	ListPokemons(ctx context.Context) (results []entity.PokemonList, err error)
+	GetPokemon(ctx context.Context, id int64) (result *entity.PokemonDetail, err error)
+	CreatePokemon(ctx context.Context, data entity.Pokemon) (id int64, err error)
+	UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (result *entity.PokemonDetail, err error)
+	DeletePokemon(ctx context.Context, id int64) (err error)
+	ListTypes(ctx context.Context) (results []entity.Type, err error)
+	CreateType(ctx context.Context, name string) (id int64, err error)
+	UpdateType(ctx context.Context, id int64, name string) (err error)
+	DeleteType(ctx context.Context, id int64, cascade bool) (err error)
+	ListUsers(ctx context.Context, search string) (results []entity.UserProfile, err error)
+	CreateUser(ctx context.Context, username string, email string, password string, role enum.Role) (id int64, err error)
+	UpdateUserRole(ctx context.Context, id int64, role enum.Role) (err error)
+	SetUserDisabled(ctx context.Context, id int64, disabled bool) (err error)
+	DeleteUser(ctx context.Context, id int64) (err error)
+	Export(ctx context.Context, format string, w io.Writer) (err error)
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (report entity.ImportReport, err error)
+}
+
+// dbBackend runs the commands with the usecases on the database, versions are never checked
+type dbBackend struct {
+	PokemonUsecase usecase.PokemonUsecaseItf
+	TypeUsecase    usecase.TypeUsecaseItf
+	UserUsecase    usecase.UserUsecaseItf
+}
+
+func (b *dbBackend) ListPokemons(ctx context.Context) (results []entity.PokemonList, err error) {
+	return b.PokemonUsecase.GetAllPokemon(ctx)
+}
+
+func (b *dbBackend) GetPokemon(ctx context.Context, id int64) (result *entity.PokemonDetail, err error) {
+	return b.PokemonUsecase.GetPokemonByID(ctx, id)
+}
+
+func (b *dbBackend) CreatePokemon(ctx context.Context, data entity.Pokemon) (id int64, err error) {
+	return b.PokemonUsecase.CreatePokemon(ctx, data)
+}
+
+func (b *dbBackend) UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (result *entity.PokemonDetail, err error) {
+	return b.PokemonUsecase.UpdatePokemon(ctx, id, 0, data)
+}
+
+func (b *dbBackend) DeletePokemon(ctx context.Context, id int64) (err error) {
+	return b.PokemonUsecase.DeletePokemon(ctx, id, 0)
+}
+
+func (b *dbBackend) ListTypes(ctx context.Context) (results []entity.Type, err error) {
+	return b.TypeUsecase.GetAllType(ctx)
+}
+
+func (b *dbBackend) CreateType(ctx context.Context, name string) (id int64, err error) {
+	return b.TypeUsecase.CreateType(ctx, entity.Type{Name: name})
+}
+
+func (b *dbBackend) UpdateType(ctx context.Context, id int64, name string) (err error) {
+	_, err = b.TypeUsecase.UpdateType(ctx, id, 0, entity.Type{Name: name})
+	return err
+}
+
+func (b *dbBackend) DeleteType(ctx context.Context, id int64, cascade bool) (err error) {
+	return b.TypeUsecase.DeleteType(ctx, id, 0, cascade)
+}
+
+func (b *dbBackend) ListUsers(ctx context.Context, search string) (results []entity.UserProfile, err error) {
+	return b.UserUsecase.GetAllUsers(ctx, entity.UserFilter{Search: search})
+}
+
+func (b *dbBackend) CreateUser(ctx context.Context, username string, email string, password string, role enum.Role) (id int64, err error) {
+	return b.UserUsecase.CreateUser(ctx, username, email, password, int64(role))
+}
+
+func (b *dbBackend) UpdateUserRole(ctx context.Context, id int64, role enum.Role) (err error) {
+	return b.UserUsecase.UpdateUserRole(ctx, id, int64(role))
+}
+
+func (b *dbBackend) SetUserDisabled(ctx context.Context, id int64, disabled bool) (err error) {
+	return b.UserUsecase.SetUserDisabled(ctx, id, disabled)
+}
+
+func (b *dbBackend) DeleteUser(ctx context.Context, id int64) (err error) {
+	return b.UserUsecase.DeleteUser(ctx, id)
+}
+
+func (b *dbBackend) Export(ctx context.Context, format string, w io.Writer) (err error) {
+	encoder := pokedexfile.NewEncoder(w, format)
+	err = b.PokemonUsecase.ExportPokemon(ctx, encoder.Encode)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func (b *dbBackend) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (report entity.ImportReport, err error) {
+	pokemons, err := pokedexfile.Decode(r, format)
+	if err != nil {
+		return report, err
+	}
+
+	return b.PokemonUsecase.ImportPokemon(ctx, pokemons, dryRun)
+}
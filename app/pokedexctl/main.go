@@ -0,0 +1,515 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/config"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemontypserepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	recoverycoderepository "github.com/winartodev/go-pokedex/repository/recoverycode"
+	sessionrepository "github.com/winartodev/go-pokedex/repository/session"
+	typserepository "github.com/winartodev/go-pokedex/repository/types"
+	userrepository "github.com/winartodev/go-pokedex/repository/user"
+	usertokenrepository "github.com/winartodev/go-pokedex/repository/usertoken"
+	"github.com/winartodev/go-pokedex/transaction"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+// modes of pokedexctl
+const (
+	modeDB  = "db"
+	modeAPI = "api"
+)
+
+const usage = `usage: pokedexctl [-mode db|api] [-url url] [-api-key key] [-force] [-output table|json] <command>
+
+commands:
+  pokemon list
+  pokemon get <id>
+  pokemon create -file <file>
+  pokemon update -file <file> <id>
+  pokemon delete <id>
+  type list
+  type create <name>
+  type update <id> <name>
+  type delete [-cascade] <id>
+  user list [-q search]
+  user create -username <username> -email <email> [-role user|admin]
+  user role <id> <user|admin>
+  user disable <id>
+  user enable <id>
+  user delete <id>
+  migrate
+  seed [-schema pokedex.sql]
+  seed -count <n> [-seed 1] [-image-url url]
+  seed -fixture <basic|catalog|edge-cases>
+  export [-format json|csv|ndjson] [-o file]
+  import [-format json|csv|ndjson] [-dry-run] <file>
+
+pokemon files are the json body of the pokemon api, "-" reads the file from stdin.
+migrate applies the migrations the database doesn't have yet in the order of their version.
+user create reads the password from POKEDEX_USER_PASSWORD or else from the first line of stdin.
+in api mode updates and deletes send the ETag the pokemon or type has right before the change,
+-force overwrites the pokemon or type whatever its version is.
+seed without -count or -fixture inserts the rows of the schema file, generated and fixture pokemons
+are imported with their missing types so seeding twice leaves the pokedex unchanged
+`
+
+// passwordEnv is the environment variable of the password of user create
+const passwordEnv = "POKEDEX_USER_PASSWORD"
+
+var (
+	errUsage  = errors.New("invalid command")
+	errDBMode = errors.New("command is only available in db mode")
+)
+
+// pokedexctl manages the pokedex either on the database directly or through the internal api
+func main() {
+	flags := flag.NewFlagSet("pokedexctl", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	mode := flags.String("mode", modeDB, "db runs the commands on the database of the config, api runs them through the api")
+	url := flags.String("url", envOr("POKEDEX_URL", "http://localhost:8080"), "url of the api in api mode")
+	apiKey := flags.String("api-key", os.Getenv("POKEDEX_API_KEY"), "api key of the api in api mode")
+	force := flags.Bool("force", false, "api mode changes pokemons and types without checking their version")
+	output := flags.String("output", outputTable, "output of the results, table or json")
+	flags.Parse(os.Args[1:])
+
+	c := &cli{
+		out:   &printer{w: os.Stdout, format: *output},
+		stdin: os.Stdin,
+	}
+
+	switch *mode {
+	case modeDB:
+		cfg := config.NewConfig()
+
+		db, err := config.NewDatabase(cfg)
+		if err != nil {
+			fail(err)
+		}
+		defer db.Close()
+
+		c.db = db
+		c.backend, err = newDBBackend(cfg, db)
+		if err != nil {
+			fail(err)
+		}
+	case modeAPI:
+		c.backend = &apiBackend{URL: *url, APIKey: *apiKey, Force: *force}
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// changes are recorded in the audit log and revisions as done by pokedexctl
+	ctx := auth.NewContext(context.Background(), &auth.JWTClaim{Username: "pokedexctl"})
+	err := c.run(ctx, flags.Args())
+	if errors.Is(err, errUsage) {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func newDBBackend(cfg config.Config, db *sql.DB) (b *dbBackend, err error) {
+	// initialize repository
+	pokemonRepository := pokemonrepository.NewPokemonRepository(db)
+	pokemonTypeRepository := pokemontypserepository.NewPokemonTypeRepository(db)
+	typeRepository := typserepository.NewTypeRepository(db)
+	auditRepository := auditrepository.NewAuditRepository(db)
+	pokemonRevisionRepository := pokemonrevisionrepository.NewPokemonRevisionRepository(db)
+	transactor := transaction.NewTransactor(db)
+
+	// initialize mailer
+	mailer, err := config.NewMailer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// initialize password policy & login throttles
+	passwordPolicy, err := config.NewPasswordPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	usernameThrottle, ipThrottle := config.NewLoginThrottles(cfg)
+
+	// initialize usecase
+	pokemonUsecase := usecase.NewPokemonUsecase(usecase.PokemonUsecase{PokemonRepository: pokemonRepository, PokemonTypeRepository: pokemonTypeRepository, TypesRepository: typeRepository, AuditRepository: auditRepository, PokemonRevisionRepository: pokemonRevisionRepository, Transactor: transactor})
+	return &dbBackend{
+		PokemonUsecase: pokemonUsecase,
+		TypeUsecase:    usecase.NewTypeUsecase(usecase.TypeUsecase{TypesRepository: typeRepository, PokemonTypeRepository: pokemonTypeRepository, AuditRepository: auditRepository, Transactor: transactor, PokemonUsecase: pokemonUsecase}),
+		UserUsecase: usecase.NewUserUsecase(usecase.UserUsecase{
+			UserRepository:         userrepository.NewUserRepository(db),
+			UserTokenRepository:    usertokenrepository.NewUserTokenRepository(db),
+			RecoveryCodeRepository: recoverycoderepository.NewRecoveryCodeRepository(db),
+			SessionRepository:      sessionrepository.NewSessionRepository(db),
+			AuditRepository:        auditRepository,
+			Transactor:             transactor,
+			Mailer:                 mailer,
+			TokenSecret:            cfg.Authorization.TokenSecret,
+			PublicURL:              cfg.Application.PublicURL,
+			PasswordPolicy:         passwordPolicy,
+			UsernameThrottle:       usernameThrottle,
+			IPThrottle:             ipThrottle,
+			TOTPIssuer:             cfg.Authorization.TOTPIssuer,
+			RequireAdminTwoFactor:  cfg.Authorization.RequireAdminTwoFactor,
+		}),
+	}, nil
+}
+
+// cli runs a command of pokedexctl on the backend and prints the result
+type cli struct {
+	backend backend
+	db      *sql.DB // db is nil in api mode
+	out     *printer
+	stdin   io.Reader
+}
+
+func (c *cli) run(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "pokemon":
+		return c.pokemon(ctx, args[1:])
+	case "type":
+		return c.types(ctx, args[1:])
+	case "user":
+		return c.user(ctx, args[1:])
+	case "migrate":
+		return c.migrate(ctx, args[1:])
+	case "seed":
+		return c.seed(ctx, args[1:])
+	case "export":
+		return c.export(ctx, args[1:])
+	case "import":
+		return c.importFile(ctx, args[1:])
+	}
+
+	return errUsage
+}
+
+func (c *cli) pokemon(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	flags := newFlagSet(args[0])
+	file := flags.String("file", "", "json file of the pokemon, - reads stdin")
+	if err = flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		pokemons, err := c.backend.ListPokemons(ctx)
+		if err != nil {
+			return err
+		}
+		return c.out.pokemons(pokemons)
+	case "get":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		pokemon, err := c.backend.GetPokemon(ctx, id)
+		if err != nil {
+			return err
+		}
+		return c.out.pokemon(pokemon)
+	case "create":
+		data, err := c.readPokemon(*file)
+		if err != nil {
+			return err
+		}
+		id, err := c.backend.CreatePokemon(ctx, data)
+		if err != nil {
+			return err
+		}
+		return c.out.id(id)
+	case "update":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		data, err := c.readPokemon(*file)
+		if err != nil {
+			return err
+		}
+		pokemon, err := c.backend.UpdatePokemon(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		return c.out.pokemon(pokemon)
+	case "delete":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		return c.backend.DeletePokemon(ctx, id)
+	}
+
+	return errUsage
+}
+
+func (c *cli) types(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	flags := newFlagSet(args[0])
+	cascade := flags.Bool("cascade", false, "delete the type even when pokemons have it")
+	if err = flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		types, err := c.backend.ListTypes(ctx)
+		if err != nil {
+			return err
+		}
+		return c.out.types(types)
+	case "create":
+		if flags.NArg() != 1 {
+			return errUsage
+		}
+		id, err := c.backend.CreateType(ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return c.out.id(id)
+	case "update":
+		if flags.NArg() != 2 {
+			return errUsage
+		}
+		id, err := idArg(flags.Args()[:1])
+		if err != nil {
+			return err
+		}
+		return c.backend.UpdateType(ctx, id, flags.Arg(1))
+	case "delete":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		return c.backend.DeleteType(ctx, id, *cascade)
+	}
+
+	return errUsage
+}
+
+func (c *cli) user(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	flags := newFlagSet(args[0])
+	search := flags.String("q", "", "search of username or email")
+	username := flags.String("username", "", "username of the new user")
+	email := flags.String("email", "", "email of the new user")
+	roleName := flags.String("role", enum.User.String(), "role of the new user")
+	if err = flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		users, err := c.backend.ListUsers(ctx, *search)
+		if err != nil {
+			return err
+		}
+		return c.out.users(users)
+	case "create":
+		role, err := enum.ParseRole(*roleName)
+		if err != nil {
+			return err
+		}
+		password, err := c.readPassword()
+		if err != nil {
+			return err
+		}
+		id, err := c.backend.CreateUser(ctx, *username, *email, password, role)
+		if err != nil {
+			return err
+		}
+		return c.out.id(id)
+	case "role":
+		if flags.NArg() != 2 {
+			return errUsage
+		}
+		id, err := idArg(flags.Args()[:1])
+		if err != nil {
+			return err
+		}
+		role, err := enum.ParseRole(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		return c.backend.UpdateUserRole(ctx, id, role)
+	case "disable", "enable":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		return c.backend.SetUserDisabled(ctx, id, args[0] == "disable")
+	case "delete":
+		id, err := idArg(flags.Args())
+		if err != nil {
+			return err
+		}
+		return c.backend.DeleteUser(ctx, id)
+	}
+
+	return errUsage
+}
+
+func (c *cli) export(ctx context.Context, args []string) (err error) {
+	flags := newFlagSet("export")
+	formatName := flags.String("format", pokedexfile.JSON, "format of the export, json, csv or ndjson")
+	output := flags.String("o", "", "file of the export, stdout when empty")
+	if err = flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	format, err := pokedexfile.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return c.backend.Export(ctx, format, c.out.w)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	err = c.backend.Export(ctx, format, file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (c *cli) importFile(ctx context.Context, args []string) (err error) {
+	flags := newFlagSet("import")
+	formatName := flags.String("format", pokedexfile.JSON, "format of the file, json, csv or ndjson")
+	dryRun := flags.Bool("dry-run", false, "validate the file and report the changes without saving them")
+	if err = flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	format, err := pokedexfile.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	r, closeFile, err := c.open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+
+	report, err := c.backend.Import(ctx, format, r, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	return c.out.report(report)
+}
+
+// readPokemon decodes the pokemon of the json file
+func (c *cli) readPokemon(name string) (data entity.Pokemon, err error) {
+	if name == "" {
+		return data, errUsage
+	}
+
+	r, closeFile, err := c.open(name)
+	if err != nil {
+		return data, err
+	}
+	defer closeFile()
+
+	err = json.NewDecoder(r).Decode(&data)
+	return data, err
+}
+
+// readPassword returns the password of POKEDEX_USER_PASSWORD or else the first line of stdin,
+// so the password never shows up in the process list or the shell history
+func (c *cli) readPassword() (password string, err error) {
+	if password, ok := os.LookupEnv(passwordEnv); ok {
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// open opens the file, - is stdin
+func (c *cli) open(name string) (r io.Reader, closeFile func() error, err error) {
+	if name == "-" {
+		return c.stdin, func() error { return nil }, nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, file.Close, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// idArg parses the only argument as id
+func idArg(args []string) (id int64, err error) {
+	if len(args) != 1 {
+		return 0, errUsage
+	}
+
+	return strconv.ParseInt(args[0], 10, 64)
+}
+
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func fail(err error) {
+	printError(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadPassword(t *testing.T) {
+	c := &cli{stdin: strings.NewReader("s3cret pass\r\nnext line\n")}
+
+	got, err := c.readPassword()
+	if err != nil || got != "s3cret pass" {
+		t.Errorf("cli.readPassword() = %q, %v, want %q", got, err, "s3cret pass")
+	}
+
+	t.Setenv(passwordEnv, "from env")
+	got, err = c.readPassword()
+	if err != nil || got != "from env" {
+		t.Errorf("cli.readPassword() = %q, %v, want %q", got, err, "from env")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/migration"
+)
+
+func (c *cli) migrate(ctx context.Context, args []string) (err error) {
+	if len(args) != 0 {
+		return errUsage
+	}
+	if c.db == nil {
+		return errDBMode
+	}
+
+	applied, err := migration.Up(ctx, c.db)
+	for _, m := range applied {
+		fmt.Fprintf(c.out.w, "migrate: applied %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(c.out.w, "migrate: schema is up to date")
+	}
+	return nil
+}
+
+// seedSchema inserts the rows of the schema file
+func (c *cli) seedSchema(ctx context.Context, path string) (err error) {
+	if c.db == nil {
+		return errDBMode
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	all, err := migration.Statements(file)
+	if err != nil {
+		return err
+	}
+
+	selected := seeds(all)
+	err = execute(ctx, c.db, selected)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out.w, "seed: %d statements executed\n", len(selected))
+	return nil
+}
+
+// seeds returns the insert statements of the schema as INSERT IGNORE so rows that already exist are kept
+func seeds(statements []string) (results []string) {
+	for _, statement := range statements {
+		if hasKeyword(statement, "INSERT INTO") {
+			results = append(results, "INSERT IGNORE INTO"+statement[len("INSERT INTO"):])
+		}
+	}
+
+	return results
+}
+
+func hasKeyword(statement string, keyword string) bool {
+	return len(statement) >= len(keyword) && strings.EqualFold(statement[:len(keyword)], keyword)
+}
+
+// execute runs the statements in order and stops at the first failure
+func execute(ctx context.Context, db *sql.DB, statements []string) (err error) {
+	for _, statement := range statements {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeeds(t *testing.T) {
+	statements := []string{
+		"CREATE DATABASE IF NOT EXISTS pokedex",
+		"CREATE TABLE IF NOT EXISTS types (\nid int NOT NULL\n)",
+		"LOCK TABLES types WRITE",
+		"INSERT INTO pokedex.types (id,name) VALUES\n(1,'NORMAL;'),\n(2,'GRASS')",
+		"UNLOCK TABLES",
+	}
+
+	want := []string{"INSERT IGNORE INTO pokedex.types (id,name) VALUES\n(1,'NORMAL;'),\n(2,'GRASS')"}
+	if got := seeds(statements); !reflect.DeepEqual(got, want) {
+		t.Errorf("seeds() = %q, want %q", got, want)
+	}
+}
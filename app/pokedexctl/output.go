@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+// output formats of the command results
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer prints the command results either as aligned table or as indented json
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// table prints the rows under the header, json prints the value instead
+func (p *printer) table(value interface{}, header []string, rows [][]string) (err error) {
+	if p.format == outputJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func (p *printer) pokemons(pokemons []entity.PokemonList) error {
+	rows := make([][]string, 0, len(pokemons))
+	for _, pokemon := range pokemons {
+		rows = append(rows, []string{
+			strconv.FormatInt(pokemon.ID, 10),
+			pokemon.Name,
+			strings.Join(pokemon.Types, ", "),
+			strconv.FormatInt(pokemon.Catched, 10),
+		})
+	}
+
+	return p.table(pokemons, []string{"ID", "NAME", "TYPES", "CATCHED"}, rows)
+}
+
+func (p *printer) pokemon(pokemon *entity.PokemonDetail) error {
+	rows := [][]string{
+		{"ID", strconv.FormatInt(pokemon.ID, 10)},
+		{"NAME", pokemon.Name},
+		{"SPECIES", pokemon.Species},
+		{"TYPES", strings.Join(pokemon.Types, ", ")},
+		{"CATCHED", strconv.FormatInt(pokemon.Catched, 10)},
+		{"IMAGE URL", pokemon.ImageURL},
+		{"DESCRIPTION", pokemon.Description},
+		{"WEIGHT", strconv.FormatFloat(pokemon.Weight, 'f', -1, 64)},
+		{"HEIGHT", strconv.FormatFloat(pokemon.Height, 'f', -1, 64)},
+		{"STATS", fmt.Sprintf("hp %d, attack %d, def %d, speed %d", pokemon.Stats.HP, pokemon.Stats.Attack, pokemon.Stats.Def, pokemon.Stats.Speed)},
+		{"VERSION", strconv.FormatInt(pokemon.Version, 10)},
+	}
+
+	return p.table(pokemon, []string{"FIELD", "VALUE"}, rows)
+}
+
+func (p *printer) types(types []entity.Type) error {
+	rows := make([][]string, 0, len(types))
+	for _, t := range types {
+		rows = append(rows, []string{strconv.FormatInt(t.ID, 10), t.Name})
+	}
+
+	return p.table(types, []string{"ID", "NAME"}, rows)
+}
+
+func (p *printer) users(users []entity.UserProfile) error {
+	rows := make([][]string, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, []string{
+			strconv.FormatInt(user.ID, 10),
+			user.Username,
+			user.Email,
+			enum.Role(user.Role).String(),
+			strconv.FormatBool(user.Disabled),
+		})
+	}
+
+	return p.table(users, []string{"ID", "USERNAME", "EMAIL", "ROLE", "DISABLED"}, rows)
+}
+
+// id prints the id of the created item
+func (p *printer) id(id int64) error {
+	return p.table(map[string]int64{"id": id}, []string{"ID"}, [][]string{{strconv.FormatInt(id, 10)}})
+}
+
+func (p *printer) report(report entity.ImportReport) error {
+	rows := make([][]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		result := row.Action
+		if row.Error != nil {
+			result = "failed: " + row.Error.Message
+		}
+		rows = append(rows, []string{strconv.Itoa(row.Row), row.Name, strconv.FormatInt(row.ID, 10), result})
+	}
+
+	err := p.table(report, []string{"ROW", "NAME", "ID", "RESULT"}, rows)
+	if err != nil || p.format == outputJSON {
+		return err
+	}
+
+	_, err = fmt.Fprintf(p.w, "%d created, %d updated, %d unchanged, %d failed, dry run %t\n", report.Created, report.Updated, report.Unchanged, report.Failed, report.DryRun)
+	return err
+}
+
+// printError prints the error followed by the field errors of validation failure
+func printError(w io.Writer, err error) {
+	fmt.Fprintf(w, "error: %v\n", err)
+
+	var fields []apperror.FieldError
+	var appErr *apperror.Error
+	var apiErr *apiError
+	if errors.As(err, &appErr) {
+		fields = appErr.Fields
+	} else if errors.As(err, &apiErr) {
+		fields = apiErr.Errors
+	}
+
+	for _, field := range fields {
+		fmt.Fprintf(w, "  %s: %s\n", field.Field, field.Message)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+	"github.com/winartodev/go-pokedex/seeding"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+func (c *cli) seed(ctx context.Context, args []string) (err error) {
+	flags := newFlagSet("seed")
+	schema := flags.String("schema", "pokedex.sql", "schema file of the pokedex")
+	count := flags.Int("count", 0, "number of pokemons to generate")
+	seed := flags.Int64("seed", seeding.DefaultSeed, "seed of the generator, the same seed generates the same pokemons")
+	imageURL := flags.String("image-url", seeding.DefaultImageURL, "image url of generated pokemons, %d is replaced with the number of the pokemon")
+	fixture := flags.String("fixture", "", "name of the fixture set to seed")
+	if err = flags.Parse(args); err != nil || (*count > 0 && *fixture != "") {
+		return errUsage
+	}
+
+	var pokemons []entity.PokemonDetail
+	switch {
+	case *fixture != "":
+		pokemons, err = seeding.Fixture(*fixture)
+		if err != nil {
+			return err
+		}
+	case *count > 0:
+		pokemons = seeding.Generator{Seed: *seed, ImageURL: *imageURL}.Generate(*count)
+	default:
+		return c.seedSchema(ctx, *schema)
+	}
+
+	err = c.createTypes(ctx, pokemons)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.importPokemons(ctx, pokemons)
+	if err != nil {
+		return err
+	}
+
+	return c.out.report(report)
+}
+
+// createTypes creates the types of the pokemons the pokedex doesn't have yet
+func (c *cli) createTypes(ctx context.Context, pokemons []entity.PokemonDetail) (err error) {
+	types, err := c.backend.ListTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(types))
+	for _, t := range types {
+		existing[strings.ToUpper(t.Name)] = true
+	}
+
+	for _, pokemon := range pokemons {
+		for _, name := range pokemon.Types {
+			if existing[strings.ToUpper(name)] {
+				continue
+			}
+
+			_, err = c.backend.CreateType(ctx, name)
+			if err != nil {
+				return fmt.Errorf("create type %s: %w", name, err)
+			}
+			existing[strings.ToUpper(name)] = true
+		}
+	}
+
+	return nil
+}
+
+// importPokemons imports the pokemons in batches of the largest import,
+// the report only keeps the failed rows so seeding thousands of pokemons prints a short report
+func (c *cli) importPokemons(ctx context.Context, pokemons []entity.PokemonDetail) (report entity.ImportReport, err error) {
+	for start := 0; start < len(pokemons); start += usecase.MaxImportRows {
+		end := start + usecase.MaxImportRows
+		if end > len(pokemons) {
+			end = len(pokemons)
+		}
+
+		var file bytes.Buffer
+		encoder := pokedexfile.NewEncoder(&file, pokedexfile.NDJSON)
+		for _, pokemon := range pokemons[start:end] {
+			if err = encoder.Encode(pokemon); err != nil {
+				return report, err
+			}
+		}
+		if err = encoder.Close(); err != nil {
+			return report, err
+		}
+
+		res, err := c.backend.Import(ctx, pokedexfile.NDJSON, &file, false)
+		if err != nil {
+			return report, err
+		}
+
+		for _, row := range res.Rows {
+			if row.Error != nil {
+				row.Row += start
+				report.Rows = append(report.Rows, row)
+			}
+		}
+		report.Created += res.Created
+		report.Updated += res.Updated
+		report.Unchanged += res.Unchanged
+		report.Failed += res.Failed
+	}
+
+	return report, nil
+}
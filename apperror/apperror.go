@@ -0,0 +1,79 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind is the category of an error, it decides the status code of the response
+type Kind int
+
+const (
+	Internal Kind = iota
+	Validation
+	Unauthorized
+	Forbidden
+	NotFound
+	Conflict
+	PreconditionFailed
+	PreconditionRequired
+)
+
+// Error is an error of the domain that can be shown to the client,
+// Code is a stable identifier clients can rely on while Message is for humans
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError is the reason a single field of the request payload is not valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates error of the kind
+func New(kind Kind, code string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Newf creates error of the kind with formatted message
+func Newf(kind Kind, code string, format string, args ...interface{}) *Error {
+	return New(kind, code, fmt.Sprintf(format, args...))
+}
+
+// Wrap gives err a kind and code, the message of err is shown to the client and err can still be matched with errors.Is
+func Wrap(kind Kind, code string, err error) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+// KindOf returns the kind of err, errors that are not an Error are internal
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+
+	return Internal
+}
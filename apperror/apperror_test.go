@@ -0,0 +1,54 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	notFound := New(NotFound, "pokemon_not_found", "pokemon not found")
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{
+			name: "app error",
+			err:  notFound,
+			want: NotFound,
+		},
+		{
+			name: "wrapped app error",
+			err:  fmt.Errorf("get pokemon: %w", notFound),
+			want: NotFound,
+		},
+		{
+			name: "other error is internal",
+			err:  sql.ErrConnDone,
+			want: Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("password is too common")
+	err := Wrap(Validation, "weak_password", cause)
+
+	if !errors.Is(err, cause) {
+		t.Errorf("Wrap() does not unwrap to the cause")
+	}
+
+	if err.Error() != cause.Error() {
+		t.Errorf("Wrap() message = %v, want %v", err.Error(), cause.Error())
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/joeshaw/envdecode"
 	"github.com/subosito/gotenv"
 )
@@ -10,6 +12,7 @@ type Config struct {
 		Environment string `env:"APP_ENV,required"`
 		Host        string `env:"APP_URL,required"`
 		Port        int64  `env:"APP_PORT,required"`
+		PublicURL   string `env:"APP_PUBLIC_URL,default=http://localhost:8080"`
 	}
 
 	Database struct {
@@ -20,6 +23,62 @@ type Config struct {
 		Port       string `env:"DB_PORT,required"`
 		Database   string `env:"DB_DATABASE,required"`
 	}
+
+	Authorization struct {
+		RolePermissions string `env:"AUTH_ROLE_PERMISSIONS"`
+		RoleInherits    string `env:"AUTH_ROLE_INHERITS"`
+
+		TokenSecret                 string `env:"AUTH_TOKEN_SECRET,required"`
+		RequireVerifiedEmailToCatch bool   `env:"AUTH_REQUIRE_VERIFIED_EMAIL_TO_CATCH,default=false"`
+
+		TOTPIssuer            string `env:"AUTH_TOTP_ISSUER,default=Pokedex"`
+		RequireAdminTwoFactor bool   `env:"AUTH_REQUIRE_ADMIN_2FA,default=false"`
+	}
+
+	Password struct {
+		MinLength        int64  `env:"PASSWORD_MIN_LENGTH,default=8"`
+		MaxLength        int64  `env:"PASSWORD_MAX_LENGTH,default=72"`
+		BreachedListPath string `env:"PASSWORD_BREACHED_LIST_PATH"`
+	}
+
+	Login struct {
+		FreeAttempts            int64         `env:"LOGIN_FREE_ATTEMPTS,default=3"`
+		BaseDelay               time.Duration `env:"LOGIN_BASE_DELAY,default=1s"`
+		MaxDelay                time.Duration `env:"LOGIN_MAX_DELAY,default=1m"`
+		UsernameLockoutAttempts int64         `env:"LOGIN_USERNAME_LOCKOUT_ATTEMPTS,default=10"`
+		IPLockoutAttempts       int64         `env:"LOGIN_IP_LOCKOUT_ATTEMPTS,default=50"`
+		LockoutDuration         time.Duration `env:"LOGIN_LOCKOUT_DURATION,default=15m"`
+	}
+
+	OIDC struct {
+		Issuer       string `env:"OIDC_ISSUER"`
+		ClientID     string `env:"OIDC_CLIENT_ID"`
+		ClientSecret string `env:"OIDC_CLIENT_SECRET"`
+		RedirectURL  string `env:"OIDC_REDIRECT_URL"`
+		Scopes       string `env:"OIDC_SCOPES,default=openid email profile"`
+		GroupsClaim  string `env:"OIDC_GROUPS_CLAIM,default=groups"`
+		GroupRoles   string `env:"OIDC_GROUP_ROLES"`
+		DefaultRole  string `env:"OIDC_DEFAULT_ROLE,default=user"`
+	}
+
+	Mail struct {
+		Driver   string `env:"MAIL_DRIVER,default=log"`
+		Host     string `env:"MAIL_HOST"`
+		Port     int64  `env:"MAIL_PORT,default=587"`
+		Username string `env:"MAIL_USERNAME"`
+		Password string `env:"MAIL_PASSWORD"`
+		From     string `env:"MAIL_FROM,default=no-reply@pokedex.local"`
+		LogPath  string `env:"MAIL_LOG_PATH"`
+	}
+
+	Trash struct {
+		Retention     time.Duration `env:"TRASH_RETENTION,default=720h"`
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL,default=1h"`
+	}
+
+	Search struct {
+		ReindexInterval time.Duration `env:"SEARCH_REINDEX_INTERVAL,default=5m"`
+	}
 }
 
 // NewConfig will return the Config read from the .env file
@@ -11,7 +11,8 @@ import (
 func NewDatabase(cfg Config) (db *sql.DB, err error) {
 	dbConfig := fmt.Sprintf("%s:%s@tcp(%s:%s)/", cfg.Database.Username, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port)
 
-	db, err = sql.Open(cfg.Database.Connection, fmt.Sprint(dbConfig, cfg.Database.Database))
+	// parseTime scans timestamp columns into time.Time
+	db, err = sql.Open(cfg.Database.Connection, fmt.Sprint(dbConfig, cfg.Database.Database, "?parseTime=true"))
 	if err != nil {
 		return db, err
 	}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/winartodev/go-pokedex/mailer"
+)
+
+// NewMailer is function to create the mailer selected by MAIL_DRIVER
+func NewMailer(cfg Config) (m mailer.Mailer, err error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPMailer{
+			Host:     cfg.Mail.Host,
+			Port:     cfg.Mail.Port,
+			Username: cfg.Mail.Username,
+			Password: cfg.Mail.Password,
+			From:     cfg.Mail.From,
+		}), nil
+	case "log":
+		return mailer.NewLogMailer(cfg.Mail.LogPath, cfg.Mail.From), nil
+	}
+
+	return m, fmt.Errorf("mail driver %s is not supported", cfg.Mail.Driver)
+}
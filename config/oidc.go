@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/oidc"
+)
+
+// NewOIDCProvider is function to create the oidc provider with its group roles, the provider is nil when no issuer is set.
+// Users without a mapped group get the default role, setting it to none only allows users of mapped groups to login
+func NewOIDCProvider(cfg Config) (provider oidc.ProviderItf, groupRoles map[string]enum.Role, defaultRole enum.Role, err error) {
+	if cfg.OIDC.Issuer == "" {
+		return nil, nil, enum.Public, nil
+	}
+
+	groupRoles, err = oidc.ParseGroupRoles(cfg.OIDC.GroupRoles)
+	if err != nil {
+		return nil, nil, enum.Public, err
+	}
+
+	if cfg.OIDC.DefaultRole != "" && cfg.OIDC.DefaultRole != "none" {
+		defaultRole, err = enum.ParseRole(cfg.OIDC.DefaultRole)
+		if err != nil {
+			return nil, nil, enum.Public, err
+		}
+	}
+
+	redirectURL := cfg.OIDC.RedirectURL
+	if redirectURL == "" {
+		redirectURL = strings.TrimSuffix(cfg.Application.PublicURL, "/") + "/login/oidc/callback"
+	}
+
+	provider = oidc.NewProvider(oidc.Provider{
+		Issuer:       cfg.OIDC.Issuer,
+		ClientID:     cfg.OIDC.ClientID,
+		ClientSecret: cfg.OIDC.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       strings.Fields(cfg.OIDC.Scopes),
+		GroupsClaim:  cfg.OIDC.GroupsClaim,
+	})
+
+	return provider, groupRoles, defaultRole, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/winartodev/go-pokedex/throttle"
+	"github.com/winartodev/go-pokedex/util"
+)
+
+// NewPasswordPolicy is function to create the password policy, the breached password list is loaded when its path is set
+func NewPasswordPolicy(cfg Config) (policy util.PasswordPolicy, err error) {
+	policy = util.PasswordPolicy{
+		MinLength: cfg.Password.MinLength,
+		MaxLength: cfg.Password.MaxLength,
+	}
+
+	if cfg.Password.BreachedListPath != "" {
+		policy.Breached, err = util.LoadBreachedPasswords(cfg.Password.BreachedListPath)
+		if err != nil {
+			return policy, err
+		}
+	}
+
+	return policy, nil
+}
+
+// NewLoginThrottles is function to create the throttles of failed logins per username and per ip
+func NewLoginThrottles(cfg Config) (usernameThrottle *throttle.Throttle, ipThrottle *throttle.Throttle) {
+	usernameThrottle = throttle.NewThrottle(throttle.Throttle{
+		FreeAttempts:    cfg.Login.FreeAttempts,
+		BaseDelay:       cfg.Login.BaseDelay,
+		MaxDelay:        cfg.Login.MaxDelay,
+		LockoutAttempts: cfg.Login.UsernameLockoutAttempts,
+		LockoutDuration: cfg.Login.LockoutDuration,
+	})
+
+	// many users can share an ip, so it only gets locked instead of slowing down every failed attempt
+	ipThrottle = throttle.NewThrottle(throttle.Throttle{
+		FreeAttempts:    cfg.Login.IPLockoutAttempts,
+		LockoutAttempts: cfg.Login.IPLockoutAttempts,
+		LockoutDuration: cfg.Login.LockoutDuration,
+	})
+
+	return usernameThrottle, ipThrottle
+}
@@ -0,0 +1,180 @@
+// Package dbtest runs integration tests on a real mysql server. Tests are skipped unless POKEDEX_TEST_DSN is the
+// dsn of the server like `root:secret@tcp(localhost:3306)/`.
+//
+// Open DROPS the pokedex database of that server and migrates a new one for every test,
+// never point POKEDEX_TEST_DSN at a server with data worth keeping
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/winartodev/go-pokedex/config"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/migration"
+	"github.com/winartodev/go-pokedex/seeding"
+)
+
+// DSNEnv is the environment variable of the dsn of the test server
+const DSNEnv = "POKEDEX_TEST_DSN"
+
+// database is the name the queries of the repositories expect
+const database = "pokedex"
+
+// Seeded is the ids of the seeded pokemons and types by name, type names are upper case
+type Seeded struct {
+	Pokemons map[string]int64
+	Types    map[string]int64
+}
+
+// Open returns a connection to a new pokedex database with every migration applied, the connection is opened like
+// the application opens it so timestamps are scanned the same way
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNEnv)
+	}
+
+	dsnConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("parse %s: %v", DSNEnv, err)
+	}
+	dsnConfig.DBName = ""
+
+	server, err := sql.Open("mysql", dsnConfig.FormatDSN())
+	if err != nil {
+		t.Fatalf("open test server: %v", err)
+	}
+	defer server.Close()
+
+	for _, statement := range []string{"DROP DATABASE IF EXISTS " + database, "CREATE DATABASE " + database} {
+		if _, err = server.Exec(statement); err != nil {
+			t.Fatalf("recreate %s database: %v", database, err)
+		}
+	}
+
+	var cfg config.Config
+	cfg.Database.Connection = "mysql"
+	cfg.Database.Username = dsnConfig.User
+	cfg.Database.Password = dsnConfig.Passwd
+	cfg.Database.Host, cfg.Database.Port, err = net.SplitHostPort(dsnConfig.Addr)
+	if err != nil {
+		t.Fatalf("address of %s: %v", DSNEnv, err)
+	}
+	cfg.Database.Database = database
+
+	db, err := config.NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("open %s database: %v", database, err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err = migration.Up(context.Background(), db); err != nil {
+		t.Fatalf("migrate %s database: %v", database, err)
+	}
+
+	return db
+}
+
+// SameSecond reports whether the times are the same to the second, timestamp columns are stored in seconds
+func SameSecond(a time.Time, b time.Time) bool {
+	diff := a.Sub(b)
+	return diff > -time.Second && diff < time.Second
+}
+
+// SeedFixture seeds the pokemons of the fixture set of the seeding package
+func SeedFixture(t testing.TB, db *sql.DB, name string) Seeded {
+	t.Helper()
+
+	pokemons, err := seeding.Fixture(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Seed(t, db, pokemons)
+}
+
+// Seed inserts the pokemons with their types, types the database doesn't have yet are created
+func Seed(t testing.TB, db *sql.DB, pokemons []entity.PokemonDetail) Seeded {
+	t.Helper()
+
+	seeded := Seeded{Pokemons: map[string]int64{}, Types: map[string]int64{}}
+	for _, pokemon := range pokemons {
+		for _, name := range pokemon.Types {
+			name = strings.ToUpper(name)
+			if _, ok := seeded.Types[name]; !ok {
+				seeded.Types[name] = typeID(t, db, name)
+			}
+		}
+
+		metadata, err := json.Marshal(map[string]interface{}{
+			"image_url":   pokemon.ImageURL,
+			"description": pokemon.Description,
+			"weight":      pokemon.Weight,
+			"height":      pokemon.Height,
+			"stats":       pokemon.Stats,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id := insert(t, db, "INSERT INTO pokemons (name, species, catched, metadata) VALUES (?, ?, ?, ?)",
+			pokemon.Name, pokemon.Species, pokemon.Catched, string(metadata))
+		seeded.Pokemons[pokemon.Name] = id
+
+		for _, name := range pokemon.Types {
+			insert(t, db, "INSERT INTO pokemon_types (pokemon_id, types_id) VALUES (?, ?)", id, seeded.Types[strings.ToUpper(name)])
+		}
+	}
+
+	return seeded
+}
+
+// SeedUser inserts a user with the user role and without password
+func SeedUser(t testing.TB, db *sql.DB, username string) (id int64) {
+	t.Helper()
+
+	return insert(t, db, "INSERT INTO users (username, email, password, role) VALUES (?, ?, '', ?)", username, username+"@mail.com", int64(enum.User))
+}
+
+// typeID returns the id of the type, the type is created when the database doesn't have it yet
+func typeID(t testing.TB, db *sql.DB, name string) (id int64) {
+	t.Helper()
+
+	err := db.QueryRow("SELECT id FROM types WHERE name = ?", name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return insert(t, db, "INSERT INTO types (name) VALUES (?)", name)
+	}
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	return id
+}
+
+func insert(t testing.TB, db *sql.DB, query string, args ...interface{}) (id int64) {
+	t.Helper()
+
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	id, err = res.LastInsertId()
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	return id
+}
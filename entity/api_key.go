@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+// Attributes APIKey, only the hash of the key is stored and Prefix identifies the key in listings
+type APIKey struct {
+	ID         int64             `json:"id" db:"id"`
+	Name       string            `json:"name" db:"name"`
+	Prefix     string            `json:"prefix" db:"prefix"`
+	KeyHash    string            `json:"-" db:"key_hash"`
+	Scopes     []enum.Permission `json:"scopes" db:"scopes"`
+	CreatedBy  int64             `json:"created_by" db:"created_by"`
+	ExpiresAt  *time.Time        `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time        `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time        `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
+
+// Attributes CreateAPIKey, the key never expires when ExpiresAt is nil
+type CreateAPIKey struct {
+	Name      string            `json:"name"`
+	Scopes    []enum.Permission `json:"scopes"`
+	ExpiresAt *time.Time        `json:"expires_at"`
+}
+
+// Attributes CreatedAPIKey, Key is only returned once when the key is created
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
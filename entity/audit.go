@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Attributes AuditLog, a mutation of a pokemon, type, user or api key made by an admin, an api key or a background job.
+// Before and After hold the json snapshot of the resource, null when the resource doesn't exist on that side
+type AuditLog struct {
+	ID         int64           `json:"id" db:"id"`
+	ActorID    int64           `json:"actor_id" db:"actor_id"`
+	APIKeyID   int64           `json:"api_key_id,omitempty" db:"api_key_id"`
+	Actor      string          `json:"actor" db:"actor"`
+	Action     string          `json:"action" db:"action"`
+	Resource   string          `json:"resource" db:"resource"`
+	ResourceID int64           `json:"resource_id" db:"resource_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Attributes AuditFilter
+type AuditFilter struct {
+	Resource string
+	Actor    string
+	Since    *time.Time
+	Limit    int64
+	Offset   int64
+}
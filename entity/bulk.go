@@ -0,0 +1,23 @@
+package entity
+
+import "github.com/winartodev/go-pokedex/apperror"
+
+// Attributes BulkPokemon, a pokemon of bulk request. Version is required to update and delete, it is ignored on create
+type BulkPokemon struct {
+	Pokemon
+	Version int64 `json:"version"`
+}
+
+// Attributes BulkResult, the result of a single item of bulk request, Index is the position of the item in the request
+type BulkResult struct {
+	Index int        `json:"index"`
+	ID    int64      `json:"id,omitempty"`
+	Error *BulkError `json:"error,omitempty"`
+}
+
+// Attributes BulkError
+type BulkError struct {
+	Code    string                `json:"code"`
+	Message string                `json:"message"`
+	Errors  []apperror.FieldError `json:"errors,omitempty"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+// Attributes ImportRow, a pokemon of import. Err is set when a cell of the row can't be decoded, the row is then reported as failed
+type ImportRow struct {
+	PokemonDetail
+	Err error `json:"-"`
+}
+
+// Attributes ImportResult, the result of a single row of import. Row is the 1-based position of the row without the csv header
+type ImportResult struct {
+	Row    int        `json:"row"`
+	Name   string     `json:"name"`
+	Action string     `json:"action,omitempty"`
+	ID     int64      `json:"id,omitempty"`
+	Error  *BulkError `json:"error,omitempty"`
+}
+
+// Attributes ImportReport, nothing is saved on dry run but the rows report what would happen
+type ImportReport struct {
+	DryRun    bool           `json:"dry_run"`
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Failed    int            `json:"failed"`
+	Rows      []ImportResult `json:"rows"`
+}
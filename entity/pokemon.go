@@ -1,5 +1,11 @@
 package entity
 
+import (
+	"time"
+
+	"github.com/winartodev/go-pokedex/filter"
+)
+
 // Attributes PokemonDB
 type PokemonDB struct {
 	ID       int64  `db:"id"`
@@ -7,6 +13,9 @@ type PokemonDB struct {
 	Species  string `db:"species"`
 	Catched  int64  `db:"catched"`
 	Metadata string `db:"metadata"`
+	Version  int64  `db:"version"`
+	// DeletedAt is set while the pokemon is in the trash
+	DeletedAt *time.Time `db:"deleted_at"`
 }
 
 // Attributes Pokemon
@@ -34,6 +43,9 @@ type PokemonDetail struct {
 	Weight      float64  `json:"weight,omitempty"`
 	Height      float64  `json:"height,omitempty"`
 	Stats       Stats    `json:"stats,omitempty"`
+	Version     int64    `json:"-"`
+	// TypeVersions are the versions of Types in the same order, renamed types change the detail but not Version
+	TypeVersions []int64 `json:"-"`
 }
 
 // Attributes PokemonList
@@ -46,6 +58,29 @@ type PokemonList struct {
 	ImageURL string   `json:"image_url"`
 }
 
+// Attributes PokemonFilter
+type PokemonFilter struct {
+	// Expr is the parsed filter of the listing, nil lists every pokemon
+	Expr filter.Expr
+	// TypeAll, TypeAny and TypeNot are type names or ids, listed pokemons have every type of TypeAll,
+	// at least one type of TypeAny and none of TypeNot
+	TypeAll []string
+	TypeAny []string
+	TypeNot []string
+	SortBy  string
+	OrderBy string
+}
+
+// Attributes PokemonFilterDB
+type PokemonFilterDB struct {
+	Expr    filter.Expr
+	TypeAll []int64
+	TypeAny []int64
+	TypeNot []int64
+	SortBy  string
+	OrderBy string
+}
+
 // Attributes Stats
 type Stats struct {
 	HP     int64 `json:"hp"`
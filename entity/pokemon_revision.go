@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Attributes PokemonRevision, the pokemon as it was saved by a create or an update. Revision is the version the pokemon got by that save
+type PokemonRevision struct {
+	ID        int64     `json:"-" db:"id"`
+	PokemonID int64     `json:"pokemon_id" db:"pokemon_id"`
+	Revision  int64     `json:"revision" db:"revision"`
+	Pokemon   Pokemon   `json:"pokemon" db:"snapshot"`
+	ActorID   int64     `json:"actor_id" db:"actor_id"`
+	Actor     string    `json:"actor" db:"actor"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// Changes are the fields that differ from the previous revision
+	Changes []FieldChange `json:"changes"`
+}
+
+// Attributes FieldChange, nested fields are joined with a dot
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
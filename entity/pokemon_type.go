@@ -6,4 +6,6 @@ type PokemonType struct {
 	PokemonID int64 `db:"pokemon_id"`
 	TypeID    int64 `db:"types_id"`
 	Name      string
+	// TypeVersion is the version of the type, it changes when the type is renamed
+	TypeVersion int64
 }
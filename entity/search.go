@@ -0,0 +1,15 @@
+package entity
+
+// Attributes PokemonSearchResult, highlights has the matching fields with the matching words wrapped in <em>
+type PokemonSearchResult struct {
+	PokemonList
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Attributes PokemonSuggestion, text is a name or species starting with the prefix and ids are the pokemons having it
+type PokemonSuggestion struct {
+	Text  string  `json:"text"`
+	Field string  `json:"field"`
+	IDs   []int64 `json:"ids"`
+}
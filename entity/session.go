@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Attributes Client, the device a request is sent from
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// Attributes Session, every login creates a session that is referenced by the jwt token
+type Session struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"-" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IP         string     `json:"ip" db:"ip"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"-" db:"revoked_at"`
+	Current    bool       `json:"current"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Attributes TrashItem, a pokemon or type that was deleted and can still be restored until PurgeAt
+type TrashItem struct {
+	Kind      string    `json:"kind"`
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -1,7 +1,13 @@
 package entity
 
+import "time"
+
 // Attributes Type
 type Type struct {
 	ID   int64  `json:"id" db:"id"`
 	Name string `json:"name" db:"name"`
+	// Version is sent as ETag header instead of in the body
+	Version int64 `json:"-" db:"version"`
+	// DeletedAt is set while the type is in the trash
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
 }
@@ -1,10 +1,93 @@
 package entity
 
+import "time"
+
 // Attributes User
 type User struct {
-	ID       int64  `json:"id" db:"id"`
-	Username string `json:"username" db:"username"`
-	Email    string `json:"email" db:"email"`
-	Password string `json:"password" db:"password"`
-	Role     int64  `json:"role" db:"role"`
+	ID            int64  `json:"id" db:"id"`
+	Username      string `json:"username" db:"username"`
+	Email         string `json:"email" db:"email"`
+	Password      string `json:"password" db:"password"`
+	Role          int64  `json:"role" db:"role"`
+	DisplayName   string `json:"display_name" db:"display_name"`
+	Disabled      bool   `json:"disabled" db:"disabled"`
+	EmailVerified bool   `json:"email_verified" db:"email_verified"`
+	TOTPSecret    string `json:"-" db:"totp_secret"`
+	TOTPEnabled   bool   `json:"-" db:"totp_enabled"`
+}
+
+// Attributes UserProfile
+type UserProfile struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	DisplayName   string `json:"display_name"`
+	Role          int64  `json:"role"`
+	Disabled      bool   `json:"disabled"`
+	EmailVerified bool   `json:"email_verified"`
+	TOTPEnabled   bool   `json:"totp_enabled"`
+}
+
+// Attributes UpdateProfile, nil fields are left unchanged
+type UpdateProfile struct {
+	Email       *string `json:"email"`
+	DisplayName *string `json:"display_name"`
+}
+
+// Attributes ChangePassword
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// Attributes ResetPassword
+type ResetPassword struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Attributes LoginResult, Challenge is set instead of Token when the second factor is still required
+type LoginResult struct {
+	Token     string `json:"-"`
+	Challenge string `json:"challenge,omitempty"`
+}
+
+// Attributes OIDCLogin, Flow must be returned to FinishOIDCLogin by the browser that is redirected to AuthURL
+type OIDCLogin struct {
+	AuthURL string
+	Flow    string
+}
+
+// Attributes TwoFactorLogin, Code is either a totp code or a recovery code
+type TwoFactorLogin struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// Attributes TOTPEnrollment
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Attributes TOTPConfirmation
+type TOTPConfirmation struct {
+	Code string `json:"code"`
+}
+
+// Attributes UserFilter
+type UserFilter struct {
+	Search string
+	Limit  int64
+	Offset int64
+}
+
+// Attributes UserRoleAudit
+type UserRoleAudit struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	ActorID   int64     `json:"actor_id" db:"actor_id"`
+	OldRole   int64     `json:"old_role" db:"old_role"`
+	NewRole   int64     `json:"new_role" db:"new_role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
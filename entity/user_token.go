@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Attributes UserToken, Email is the address an email verification token was sent to
+type UserToken struct {
+	ID        int64        `db:"id"`
+	UserID    int64        `db:"user_id"`
+	Purpose   string       `db:"purpose"`
+	TokenHash string       `db:"token_hash"`
+	Email     string       `db:"email"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+}
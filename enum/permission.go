@@ -0,0 +1,34 @@
+package enum
+
+// Permission is an action a role is allowed to perform
+type Permission string
+
+const (
+	PokemonRead     Permission = "pokemon:read"
+	PokemonWrite    Permission = "pokemon:write"
+	TypeRead        Permission = "type:read"
+	TypeWrite       Permission = "type:write"
+	CollectionCatch Permission = "collection:catch"
+	UserManage      Permission = "user:manage"
+	APIKeyManage    Permission = "apikey:manage"
+	TrashRead       Permission = "trash:read"
+	AuditRead       Permission = "audit:read"
+)
+
+// Permissions lists every permission that can be granted
+var Permissions = []Permission{PokemonRead, PokemonWrite, TypeRead, TypeWrite, CollectionCatch, UserManage, APIKeyManage, TrashRead, AuditRead}
+
+// String() method returns permission as a string
+func (p Permission) String() string {
+	return string(p)
+}
+
+// IsValid reports whether the permission is one of Permissions
+func (p Permission) IsValid() bool {
+	for _, permission := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package enum
+
+import "testing"
+
+func TestPermission_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Permission
+		want bool
+	}{
+		{
+			name: "valid permission",
+			p:    PokemonWrite,
+			want: true,
+		},
+		{
+			name: "invalid permission",
+			p:    Permission("pokemon:delete"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.IsValid(); got != tt.want {
+				t.Errorf("Permission.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package enum
+
+// TokenPurpose is the action a single use user token can be exchanged for
+type TokenPurpose string
+
+const (
+	PasswordReset     TokenPurpose = "password_reset"
+	EmailVerification TokenPurpose = "email_verification"
+	LoginChallenge    TokenPurpose = "login_challenge"
+)
+
+// String() method returns token purpose as a string
+func (tp TokenPurpose) String() string {
+	return string(tp)
+}
@@ -1,5 +1,7 @@
 package enum
 
+import "fmt"
+
 type Role int64
 
 const (
@@ -19,3 +21,19 @@ func (r Role) String() string {
 	}
 	return ""
 }
+
+// IsValid reports whether the role can be assigned to a user account
+func (r Role) IsValid() bool {
+	return r == User || r == Admin
+}
+
+// ParseRole returns the role for the given name, it is the inverse of String()
+func ParseRole(name string) (Role, error) {
+	switch name {
+	case User.String():
+		return User, nil
+	case Admin.String():
+		return Admin, nil
+	}
+	return Public, fmt.Errorf("role %s is not valid", name)
+}
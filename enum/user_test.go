@@ -31,3 +31,79 @@ func TestRole_String(t *testing.T) {
 		})
 	}
 }
+
+func TestRole_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Role
+		want bool
+	}{
+		{
+			name: "success admin role",
+			r:    Admin,
+			want: true,
+		},
+		{
+			name: "success user role",
+			r:    User,
+			want: true,
+		},
+		{
+			name: "failed public role",
+			r:    Public,
+			want: false,
+		},
+		{
+			name: "failed unknown role",
+			r:    Role(99),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.IsValid(); got != tt.want {
+				t.Errorf("Role.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		want    Role
+		wantErr bool
+	}{
+		{
+			name:    "success admin role",
+			role:    "admin",
+			want:    Admin,
+			wantErr: false,
+		},
+		{
+			name:    "success user role",
+			role:    "user",
+			want:    User,
+			wantErr: false,
+		},
+		{
+			name:    "failed unknown role",
+			role:    "root",
+			want:    Public,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRole(tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
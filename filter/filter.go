@@ -0,0 +1,109 @@
+// Package filter parses filter expressions like `type:fire AND (stats.speed>60 OR name~char)` into a tree,
+// checks the tree against the fields a listing allows and compiles it into parameterized sql
+package filter
+
+import "fmt"
+
+// Op is the operator of a comparison
+type Op string
+
+const (
+	OpEqual        Op = "="
+	OpNotEqual     Op = "!="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	// OpContains matches text containing the value, ignoring case
+	OpContains Op = "~"
+)
+
+// Expr is a node of the parsed filter, one of And, Or, Not and Comparison
+type Expr interface {
+	expr()
+}
+
+// And matches when both Left and Right match
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+// Or matches when Left or Right matches
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+// Not matches when Expr doesn't match
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares a field with a value, Position is where the field starts in the filter counted from 1
+// and 0 for comparisons that are not parsed
+type Comparison struct {
+	Field    string
+	Op       Op
+	Value    string
+	Position int
+}
+
+// AllOf returns an expression matching when every expression matches, nil expressions are skipped
+// and nil is returned when none is left
+func AllOf(exprs ...Expr) (result Expr) {
+	for _, expr := range exprs {
+		switch {
+		case expr == nil:
+		case result == nil:
+			result = expr
+		default:
+			result = And{Left: result, Right: expr}
+		}
+	}
+
+	return result
+}
+
+// AnyOf returns an expression matching when any expression matches, nil expressions are skipped
+// and nil is returned when none is left
+func AnyOf(exprs ...Expr) (result Expr) {
+	for _, expr := range exprs {
+		switch {
+		case expr == nil:
+		case result == nil:
+			result = expr
+		default:
+			result = Or{Left: result, Right: expr}
+		}
+	}
+
+	return result
+}
+
+func (And) expr()        {}
+func (Or) expr()         {}
+func (Not) expr()        {}
+func (Comparison) expr() {}
+
+// Error is a filter that can't be parsed or doesn't fit the fields, Position is where the problem is counted from 1
+// and 0 when the problem is in a comparison that is not parsed
+type Error struct {
+	Position int
+	Message  string
+}
+
+func (e *Error) Error() string {
+	if e.Position == 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+func errorf(position int, format string, args ...interface{}) *Error {
+	return &Error{
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
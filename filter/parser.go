@@ -0,0 +1,263 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// limits of a filter, they keep parsing and the compiled query small
+const (
+	MaxLength = 1024
+	MaxDepth  = 16
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenOpen
+	tokenClose
+)
+
+// token is a part of the filter, position is where it starts counted from 1
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// describe returns how the token is named in error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "the end of the filter"
+	case tokenString:
+		return "string " + strconv.Quote(t.text)
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// Parse parses the filter. Comparisons are a field, an operator and a value like `name~char` or `stats.speed>=60`,
+// values with spaces or operators are quoted with "". Comparisons are combined with AND, OR, NOT and parentheses,
+// AND binds tighter than OR and the keywords are case insensitive
+func Parse(input string) (result Expr, err error) {
+	if utf8.RuneCountInString(input) > MaxLength {
+		return nil, errorf(MaxLength+1, "filter is longer than %d characters", MaxLength)
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens[0].kind == tokenEOF {
+		return nil, errorf(1, "filter is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	result, err = p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); t.kind {
+	case tokenEOF:
+		return result, nil
+	case tokenClose:
+		return nil, errorf(t.position, `unexpected ")" without opening parenthesis`)
+	default:
+		return nil, errorf(t.position, "expected AND or OR but found %s", t.describe())
+	}
+}
+
+func lex(input string) (tokens []token, err error) {
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", position: position})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", position: position})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, errorf(position, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), position: position})
+			i++
+		case r == ':' || r == '=' || r == '~':
+			tokens = append(tokens, token{kind: tokenOp, text: string(r), position: position})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenOp, text: string(runes[i : i+2]), position: position})
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, errorf(position, `unexpected "!", did you mean "!="`)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: string(r), position: position})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()":=~!<>`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), position: position})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: len(runes) + 1}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+
+	return t
+}
+
+// keyword reports whether the next token is the keyword, the token is taken when it is
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind != tokenWord || !strings.EqualFold(t.text, word) {
+		return false
+	}
+
+	p.take()
+	return true
+}
+
+func (p *parser) or() (result Expr, err error) {
+	result, err = p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		result = Or{Left: result, Right: right}
+	}
+
+	return result, nil
+}
+
+func (p *parser) and() (result Expr, err error) {
+	result, err = p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("AND") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		result = And{Left: result, Right: right}
+	}
+
+	return result, nil
+}
+
+func (p *parser) unary() (result Expr, err error) {
+	t := p.peek()
+	if p.depth >= MaxDepth {
+		return nil, errorf(t.position, "filter is nested deeper than %d levels", MaxDepth)
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	// NOT followed by an operator is a field named not
+	if t.kind == tokenWord && strings.EqualFold(t.text, "NOT") && p.tokens[p.next+1].kind != tokenOp {
+		p.take()
+		result, err = p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: result}, nil
+	}
+
+	if t.kind == tokenOpen {
+		p.take()
+		result, err = p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.take(); closing.kind != tokenClose {
+			return nil, errorf(closing.position, `expected ")" to close the "(" at position %d but found %s`, t.position, closing.describe())
+		}
+		return result, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (result Expr, err error) {
+	field := p.take()
+	if field.kind != tokenWord {
+		return nil, errorf(field.position, "expected a field name but found %s", field.describe())
+	}
+
+	op := p.take()
+	if op.kind != tokenOp {
+		return nil, errorf(op.position, "expected an operator after %s but found %s", field.describe(), op.describe())
+	}
+
+	value := p.take()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, errorf(value.position, "expected a value after %s but found %s", op.describe(), value.describe())
+	}
+
+	return Comparison{
+		Field:    field.text,
+		Op:       parseOp(op.text),
+		Value:    value.text,
+		Position: field.position,
+	}, nil
+}
+
+func parseOp(text string) Op {
+	if text == ":" {
+		return OpEqual
+	}
+
+	return Op(text)
+}
@@ -0,0 +1,151 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		input string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult Expr
+		wantErr    string
+	}{
+		{
+			name: "success comparison",
+			args: args{
+				input: "type:fire",
+			},
+			wantResult: Comparison{Field: "type", Op: OpEqual, Value: "fire", Position: 1},
+		},
+		{
+			name: "success and binds tighter than or",
+			args: args{
+				input: "name~char or type:fire AND stats.speed>60",
+			},
+			wantResult: Or{
+				Left: Comparison{Field: "name", Op: OpContains, Value: "char", Position: 1},
+				Right: And{
+					Left:  Comparison{Field: "type", Op: OpEqual, Value: "fire", Position: 14},
+					Right: Comparison{Field: "stats.speed", Op: OpGreater, Value: "60", Position: 28},
+				},
+			},
+		},
+		{
+			name: "success parentheses and not",
+			args: args{
+				input: `type:fire AND NOT (stats.speed>=60 OR name!="Mr. Mime")`,
+			},
+			wantResult: And{
+				Left: Comparison{Field: "type", Op: OpEqual, Value: "fire", Position: 1},
+				Right: Not{Expr: Or{
+					Left:  Comparison{Field: "stats.speed", Op: OpGreaterEqual, Value: "60", Position: 20},
+					Right: Comparison{Field: "name", Op: OpNotEqual, Value: "Mr. Mime", Position: 39},
+				}},
+			},
+		},
+		{
+			name: "success escaped quote",
+			args: args{
+				input: `name="say \"hi\""`,
+			},
+			wantResult: Comparison{Field: "name", Op: OpEqual, Value: `say "hi"`, Position: 1},
+		},
+		{
+			name: "success field named like a keyword",
+			args: args{
+				input: "not:1",
+			},
+			wantResult: Comparison{Field: "not", Op: OpEqual, Value: "1", Position: 1},
+		},
+		{
+			name: "failed empty",
+			args: args{
+				input: "  ",
+			},
+			wantErr: "filter is empty at position 1",
+		},
+		{
+			name: "failed missing operator",
+			args: args{
+				input: "type fire",
+			},
+			wantErr: `expected an operator after "type" but found "fire" at position 6`,
+		},
+		{
+			name: "failed missing value",
+			args: args{
+				input: "stats.speed>",
+			},
+			wantErr: `expected a value after ">" but found the end of the filter at position 13`,
+		},
+		{
+			name: "failed missing connective",
+			args: args{
+				input: "type:fire name~char",
+			},
+			wantErr: `expected AND or OR but found "name" at position 11`,
+		},
+		{
+			name: "failed unclosed parenthesis",
+			args: args{
+				input: "(type:fire OR type:water",
+			},
+			wantErr: `expected ")" to close the "(" at position 1 but found the end of the filter at position 25`,
+		},
+		{
+			name: "failed unopened parenthesis",
+			args: args{
+				input: "type:fire)",
+			},
+			wantErr: `unexpected ")" without opening parenthesis at position 10`,
+		},
+		{
+			name: "failed unterminated string",
+			args: args{
+				input: `name:"mime`,
+			},
+			wantErr: "unterminated string at position 6",
+		},
+		{
+			name: "failed bang",
+			args: args{
+				input: "type!fire",
+			},
+			wantErr: `unexpected "!", did you mean "!=" at position 5`,
+		},
+		{
+			name: "failed too deep",
+			args: args{
+				input: strings.Repeat("(", MaxDepth) + "type:fire" + strings.Repeat(")", MaxDepth),
+			},
+			wantErr: "filter is nested deeper than 16 levels at position 17",
+		},
+		{
+			name: "failed too long",
+			args: args{
+				input: "name~" + strings.Repeat("a", MaxLength),
+			},
+			wantErr: "filter is longer than 1024 characters at position 1025",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResult, err := Parse(tt.args.input)
+			if err != nil || tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("Parse() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of the values of a field, it decides which operators the field allows
+type Kind int
+
+const (
+	Text Kind = iota
+	Number
+	Bool
+)
+
+// operators are the operators every kind allows
+var operators = map[Kind][]Op{
+	Text:   {OpEqual, OpNotEqual, OpContains},
+	Number: {OpEqual, OpNotEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual},
+	Bool:   {OpEqual, OpNotEqual},
+}
+
+var kindNames = map[Kind]string{
+	Text:   "text",
+	Number: "number",
+	Bool:   "boolean",
+}
+
+// Field is a field filters can compare and how it is found in sql
+type Field struct {
+	Kind Kind
+	// Column is the column or sql expression compared with the value, text is compared ignoring case
+	Column string
+	// Path makes the field the number at the path of the json stored in Column
+	Path []string
+	// Exists is a subquery with %s where the comparison goes, the field matches when any row of the subquery matches.
+	// It is used for fields with many values like the types of a pokemon, != matches when no row is equal
+	Exists string
+}
+
+// Fields are the fields a listing can be filtered by, by the name used in filters
+type Fields map[string]Field
+
+// names returns the field names sorted for error messages
+func (f Fields) names() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// Dialect writes the parts of the sql that differ between databases
+type Dialect interface {
+	// Placeholder returns the placeholder of the nth argument of the query, n counts from 1
+	Placeholder(n int) string
+	// JSONNumber returns the expression of the number at the path of the json stored in column
+	JSONNumber(column string, path []string) string
+}
+
+type mysql struct{}
+
+func (mysql) Placeholder(n int) string {
+	return "?"
+}
+
+func (mysql) JSONNumber(column string, path []string) string {
+	return fmt.Sprintf("CAST(JSON_EXTRACT(%s, '$.%s') AS DECIMAL(20,6))", column, strings.Join(path, "."))
+}
+
+type postgres struct{}
+
+func (postgres) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (postgres) JSONNumber(column string, path []string) string {
+	return fmt.Sprintf("CAST(CAST(%s AS jsonb) #>> '{%s}' AS NUMERIC)", column, strings.Join(path, ","))
+}
+
+var (
+	MySQL      Dialect = mysql{}
+	PostgreSQL Dialect = postgres{}
+)
+
+// Validate checks every comparison of the filter uses a field of fields with an operator and a value the field allows
+func Validate(expr Expr, fields Fields) (err error) {
+	switch e := expr.(type) {
+	case And:
+		err = Validate(e.Left, fields)
+		if err != nil {
+			return err
+		}
+		return Validate(e.Right, fields)
+	case Or:
+		err = Validate(e.Left, fields)
+		if err != nil {
+			return err
+		}
+		return Validate(e.Right, fields)
+	case Not:
+		return Validate(e.Expr, fields)
+	case Comparison:
+		_, _, err = comparison(e, fields)
+		return err
+	}
+
+	return fmt.Errorf("unknown filter expression %T", expr)
+}
+
+// comparison returns the field of the comparison and its value converted to the kind of the field
+func comparison(c Comparison, fields Fields) (field Field, value interface{}, err error) {
+	field, ok := fields[c.Field]
+	if !ok {
+		return field, nil, errorf(c.Position, "unknown field %q, the fields are %s", c.Field, fields.names())
+	}
+
+	allowed := false
+	for _, op := range operators[field.Kind] {
+		allowed = allowed || op == c.Op
+	}
+	if !allowed {
+		return field, nil, errorf(c.Position, "operator %q can't be used with %s field %q", c.Op, kindNames[field.Kind], c.Field)
+	}
+
+	switch field.Kind {
+	case Number:
+		number, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return field, nil, errorf(c.Position, "value %q of field %q is not a number", c.Value, c.Field)
+		}
+		return field, number, nil
+	case Bool:
+		switch strings.ToLower(c.Value) {
+		case "true", "1":
+			return field, 1, nil
+		case "false", "0":
+			return field, 0, nil
+		}
+		return field, nil, errorf(c.Position, "value %q of field %q is not a boolean", c.Value, c.Field)
+	}
+
+	if c.Op == OpContains {
+		return field, "%" + escapeLike(c.Value) + "%", nil
+	}
+
+	return field, c.Value, nil
+}
+
+// Compile returns the sql condition of the filter and its arguments, placeholders are numbered from 1
+// so the condition must come before any other argument of the query
+func Compile(expr Expr, fields Fields, dialect Dialect) (condition string, args []interface{}, err error) {
+	err = Validate(expr, fields)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &compiler{fields: fields, dialect: dialect}
+	condition = c.compile(expr)
+
+	return condition, c.args, nil
+}
+
+type compiler struct {
+	fields  Fields
+	dialect Dialect
+	args    []interface{}
+}
+
+func (c *compiler) compile(expr Expr) string {
+	switch e := expr.(type) {
+	case And:
+		return fmt.Sprintf("(%s AND %s)", c.compile(e.Left), c.compile(e.Right))
+	case Or:
+		return fmt.Sprintf("(%s OR %s)", c.compile(e.Left), c.compile(e.Right))
+	case Not:
+		return fmt.Sprintf("NOT %s", c.compile(e.Expr))
+	}
+
+	return c.comparison(expr.(Comparison))
+}
+
+func (c *compiler) comparison(e Comparison) string {
+	// the filter is validated already
+	field, value, _ := comparison(e, c.fields)
+
+	op := e.Op
+	if field.Exists != "" && op == OpNotEqual {
+		op = OpEqual
+	}
+
+	c.args = append(c.args, value)
+	placeholder := c.dialect.Placeholder(len(c.args))
+
+	column := field.Column
+	if field.Path != nil {
+		column = c.dialect.JSONNumber(column, field.Path)
+	}
+
+	var condition string
+	switch {
+	case op == OpContains:
+		condition = fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, placeholder)
+	case field.Kind == Text:
+		condition = fmt.Sprintf("LOWER(%s) %s LOWER(%s)", column, sqlOp(op), placeholder)
+	default:
+		condition = fmt.Sprintf("%s %s %s", column, sqlOp(op), placeholder)
+	}
+
+	if field.Exists == "" {
+		return condition
+	}
+
+	condition = fmt.Sprintf("EXISTS ("+field.Exists+")", condition)
+	if e.Op == OpNotEqual {
+		return "NOT " + condition
+	}
+
+	return condition
+}
+
+func sqlOp(op Op) string {
+	if op == OpNotEqual {
+		return "<>"
+	}
+
+	return string(op)
+}
+
+// escapeLike escapes the wildcards of LIKE so they match themselves
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
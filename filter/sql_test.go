@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testFields = Fields{
+	"name":        {Kind: Text, Column: "pokemons.name"},
+	"catched":     {Kind: Bool, Column: "pokemons.catched"},
+	"stats.speed": {Kind: Number, Column: "pokemons.metadata", Path: []string{"stats", "speed"}},
+	"type":        {Kind: Text, Column: "t.name", Exists: "SELECT 1 FROM types t WHERE t.pokemon_id = pokemons.id AND %s"},
+}
+
+func TestCompile(t *testing.T) {
+	type args struct {
+		input   string
+		dialect Dialect
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantCondition string
+		wantArgs      []interface{}
+		wantErr       string
+	}{
+		{
+			name: "success mysql",
+			args: args{
+				input:   "type:fire AND (stats.speed>60 OR name~char)",
+				dialect: MySQL,
+			},
+			wantCondition: "(EXISTS (SELECT 1 FROM types t WHERE t.pokemon_id = pokemons.id AND LOWER(t.name) = LOWER(?)) AND " +
+				"(CAST(JSON_EXTRACT(pokemons.metadata, '$.stats.speed') AS DECIMAL(20,6)) > ? OR LOWER(pokemons.name) LIKE LOWER(?)))",
+			wantArgs: []interface{}{"fire", float64(60), "%char%"},
+		},
+		{
+			name: "success postgresql",
+			args: args{
+				input:   "type:fire AND (stats.speed>60 OR name~char)",
+				dialect: PostgreSQL,
+			},
+			wantCondition: "(EXISTS (SELECT 1 FROM types t WHERE t.pokemon_id = pokemons.id AND LOWER(t.name) = LOWER($1)) AND " +
+				"(CAST(CAST(pokemons.metadata AS jsonb) #>> '{stats,speed}' AS NUMERIC) > $2 OR LOWER(pokemons.name) LIKE LOWER($3)))",
+			wantArgs: []interface{}{"fire", float64(60), "%char%"},
+		},
+		{
+			name: "success not equal of many values field",
+			args: args{
+				input:   "type!=fire",
+				dialect: MySQL,
+			},
+			wantCondition: "NOT EXISTS (SELECT 1 FROM types t WHERE t.pokemon_id = pokemons.id AND LOWER(t.name) = LOWER(?))",
+			wantArgs:      []interface{}{"fire"},
+		},
+		{
+			name: "success not, boolean and escaped wildcards",
+			args: args{
+				input:   `NOT catched:true AND name~"100%_"`,
+				dialect: MySQL,
+			},
+			wantCondition: `(NOT pokemons.catched = ? AND LOWER(pokemons.name) LIKE LOWER(?))`,
+			wantArgs:      []interface{}{1, `%100\%\_%`},
+		},
+		{
+			name: "failed unknown field",
+			args: args{
+				input:   "type:fire OR color:red",
+				dialect: MySQL,
+			},
+			wantErr: `unknown field "color", the fields are catched, name, stats.speed, type at position 14`,
+		},
+		{
+			name: "failed operator not allowed",
+			args: args{
+				input:   "name>char",
+				dialect: MySQL,
+			},
+			wantErr: `operator ">" can't be used with text field "name" at position 1`,
+		},
+		{
+			name: "failed not a number",
+			args: args{
+				input:   "stats.speed>fast",
+				dialect: MySQL,
+			},
+			wantErr: `value "fast" of field "stats.speed" is not a number at position 1`,
+		},
+		{
+			name: "failed not a boolean",
+			args: args{
+				input:   "catched:maybe",
+				dialect: MySQL,
+			},
+			wantErr: `value "maybe" of field "catched" is not a boolean at position 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.args.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			gotCondition, gotArgs, err := Compile(expr, testFields, tt.args.dialect)
+			if err != nil || tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if gotCondition != tt.wantCondition {
+				t.Errorf("Compile() condition = %v, want %v", gotCondition, tt.wantCondition)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -2,9 +2,48 @@ package helper
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/winartodev/go-pokedex/apperror"
 )
 
+// problem is the body of failed response described by RFC 7807
+type problem struct {
+	Type   string                `json:"type"`
+	Title  string                `json:"title"`
+	Status int                   `json:"status"`
+	Detail string                `json:"detail"`
+	Code   string                `json:"code"`
+	Errors []apperror.FieldError `json:"errors,omitempty"`
+}
+
+var kindStatus = map[apperror.Kind]int{
+	apperror.Internal:             http.StatusInternalServerError,
+	apperror.Validation:           http.StatusUnprocessableEntity,
+	apperror.Unauthorized:         http.StatusUnauthorized,
+	apperror.Forbidden:            http.StatusForbidden,
+	apperror.NotFound:             http.StatusNotFound,
+	apperror.Conflict:             http.StatusConflict,
+	apperror.PreconditionFailed:   http.StatusPreconditionFailed,
+	apperror.PreconditionRequired: http.StatusPreconditionRequired,
+}
+
+var statusCode = map[int]string{
+	http.StatusBadRequest:           "invalid_request",
+	http.StatusUnauthorized:         "unauthorized",
+	http.StatusForbidden:            "forbidden",
+	http.StatusNotFound:             "not_found",
+	http.StatusConflict:             "conflict",
+	http.StatusPreconditionFailed:   "precondition_failed",
+	http.StatusUnsupportedMediaType: "unsupported_media_type",
+	http.StatusUnprocessableEntity:  "validation_failed",
+	http.StatusPreconditionRequired: "precondition_required",
+	http.StatusTooManyRequests:      "too_many_requests",
+	http.StatusInternalServerError:  "internal_error",
+}
+
 // SuccessResponse creates success response for the http handler
 func SuccessResponse(w http.ResponseWriter, message string, data interface{}) {
 	success := struct {
@@ -23,18 +62,45 @@ func SuccessResponse(w http.ResponseWriter, message string, data interface{}) {
 	w.Write(jsonData)
 }
 
-// FailedResponse creates error response for the http handler
+// FailedResponse creates error response with the given status for errors found by the http handler itself
 func FailedResponse(w http.ResponseWriter, status int, err error) {
-	failed := struct {
-		Status int    `json:"status"`
-		Error  string `json:"error"`
-	}{
+	p := problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
 		Status: status,
-		Error:  err.Error(),
+		Detail: err.Error(),
+		Code:   statusCode[status],
 	}
 
-	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(status)
-	jsonData, _ := json.Marshal(failed)
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		p.Detail, p.Code, p.Errors = appErr.Message, appErr.Code, appErr.Fields
+	}
+
+	writeProblem(w, p)
+}
+
+// ErrorResponse creates error response with the status of the error kind,
+// internal errors are logged and only a generic message is sent so their details do not leak
+func ErrorResponse(w http.ResponseWriter, err error) {
+	status := StatusCode(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("internal error: %v", err)
+		FailedResponse(w, status, errors.New("internal server error"))
+		return
+	}
+
+	FailedResponse(w, status, err)
+}
+
+// StatusCode returns the http status of the error, errors without kind are internal
+func StatusCode(err error) int {
+	return kindStatus[apperror.KindOf(err)]
+}
+
+func writeProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	jsonData, _ := json.Marshal(p)
 	w.Write(jsonData)
 }
@@ -1,10 +1,14 @@
 package helper
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/winartodev/go-pokedex/apperror"
 )
 
 func TestSuccessResponse(t *testing.T) {
@@ -58,3 +62,62 @@ func TestFailedResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "not found",
+			err:        apperror.New(apperror.NotFound, "pokemon_not_found", "pokemon not found"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "pokemon_not_found",
+			wantDetail: "pokemon not found",
+		},
+		{
+			name:       "validation",
+			err:        apperror.New(apperror.Validation, "name_required", "name can't be empty"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "name_required",
+			wantDetail: "name can't be empty",
+		},
+		{
+			name:       "wrapped conflict",
+			err:        fmt.Errorf("create user: %w", apperror.New(apperror.Conflict, "username_taken", "username admin already taken")),
+			wantStatus: http.StatusConflict,
+			wantCode:   "username_taken",
+			wantDetail: "username admin already taken",
+		},
+		{
+			name:       "internal error is not leaked",
+			err:        errors.New("dial tcp 127.0.0.1:3306: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+			wantDetail: "internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ErrorResponse(w, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ErrorResponse() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("ErrorResponse() content type = %v, want application/problem+json", contentType)
+			}
+
+			var body problem
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body.Status != tt.wantStatus || body.Code != tt.wantCode || body.Detail != tt.wantDetail {
+				t.Errorf("ErrorResponse() body = %+v, want status %v code %v detail %v", body, tt.wantStatus, tt.wantCode, tt.wantDetail)
+			}
+		})
+	}
+}
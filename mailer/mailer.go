@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is an email that will be sent to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(ctx context.Context, message Message) (err error)
+}
+
+// SMTPMailer sends messages through an SMTP server
+type SMTPMailer struct {
+	Host     string
+	Port     int64
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPMailer(mailer SMTPMailer) Mailer {
+	return &SMTPMailer{
+		Host:     mailer.Host,
+		Port:     mailer.Port,
+		Username: mailer.Username,
+		Password: mailer.Password,
+		From:     mailer.From,
+	}
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, message Message) (err error) {
+	var auth smtp.Auth
+	if sm.Username != "" {
+		auth = smtp.PlainAuth("", sm.Username, sm.Password, sm.Host)
+	}
+
+	return smtp.SendMail(fmt.Sprintf("%s:%d", sm.Host, sm.Port), auth, sm.From, []string{message.To}, buildMessage(sm.From, message))
+}
+
+// LogMailer writes messages to a file, or to the standard logger when Path is empty, it is meant for local runs
+type LogMailer struct {
+	Path string
+	From string
+
+	mu sync.Mutex
+}
+
+func NewLogMailer(path string, from string) Mailer {
+	return &LogMailer{
+		Path: path,
+		From: from,
+	}
+}
+
+func (lm *LogMailer) Send(ctx context.Context, message Message) (err error) {
+	data := buildMessage(lm.From, message)
+
+	if lm.Path == "" {
+		log.Printf("mail:\n%s", data)
+		return nil
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	file, err := os.OpenFile(lm.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\n\n", data)
+	return err
+}
+
+func buildMessage(from string, message Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(message.Body)
+	return []byte(b.String())
+}
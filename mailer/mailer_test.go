@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "mail.log")
+
+	type args struct {
+		ctx     context.Context
+		message Message
+	}
+	tests := []struct {
+		name    string
+		mailer  Mailer
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "success write to file",
+			mailer: NewLogMailer(path, "no-reply@pokedex.local"),
+			args: args{
+				ctx: ctx,
+				message: Message{
+					To:      "ash@mail.com",
+					Subject: "Reset password",
+					Body:    "token: abc",
+				},
+			},
+			want:    []string{"From: no-reply@pokedex.local", "To: ash@mail.com", "Subject: Reset password", "token: abc"},
+			wantErr: false,
+		},
+		{
+			name:   "success write to log",
+			mailer: NewLogMailer("", "no-reply@pokedex.local"),
+			args: args{
+				ctx:     ctx,
+				message: Message{To: "ash@mail.com"},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "failed open file",
+			mailer: NewLogMailer(filepath.Join(t.TempDir(), "missing", "mail.log"), "no-reply@pokedex.local"),
+			args: args{
+				ctx:     ctx,
+				message: Message{To: "ash@mail.com"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mailer.Send(tt.args.ctx, tt.args.message); (err != nil) != tt.wantErr {
+				t.Errorf("LogMailer.Send() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(tt.want) == 0 {
+				return
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("LogMailer.Send() wrote %q, want it to contain %q", data, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the claims of the logged in user
+func NewContext(ctx context.Context, claims *JWTClaim) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext returns the claims stored in ctx by NewContext, if any
+func FromContext(ctx context.Context) (claims *JWTClaim, ok bool) {
+	claims, ok = ctx.Value(contextKey{}).(*JWTClaim)
+	return claims, ok
+}
@@ -5,27 +5,43 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/winartodev/go-pokedex/entity"
 	"github.com/winartodev/go-pokedex/enum"
 )
 
+// TokenTTL is how long jwt token and the session it belongs to are valid
+const TokenTTL = time.Hour
+
 var jwtKey = []byte("supersecretkey")
 
 // JWTClaim is struct represent of jwt.Claims
 type JWTClaim struct {
-	Username string    `json:"username"`
-	Email    string    `json:"email"`
-	Role     enum.Role `json:"role"`
+	ID            int64     `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	Role          enum.Role `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	TwoFactor     bool      `json:"two_factor"`
+	SessionID     int64     `json:"sid"`
+	// APIKeyID and Scopes are only set for requests authenticated with an api key, they are never part of a jwt token
+	APIKeyID int64             `json:"-"`
+	Scopes   []enum.Permission `json:"-"`
 	jwt.StandardClaims
 }
 
-// GenerateJWT will generate token
-func GenerateJWT(username string, email string, role enum.Role) (tokenString string, err error) {
-	expirationTime := time.Now().Add(1 * time.Hour)
+// GenerateJWT will generate token of the session
+func GenerateJWT(user entity.User, sessionID int64) (tokenString string, err error) {
+	expirationTime := time.Now().Add(TokenTTL)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{
-		Username: username,
-		Email:    email,
-		Role:     role,
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		Role:          enum.Role(user.Role),
+		EmailVerified: user.EmailVerified,
+		// users with totp enabled only get a token after the second factor is verified
+		TwoFactor: user.TOTPEnabled,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
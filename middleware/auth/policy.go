@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+const (
+	// DefaultRolePermissions is used when AUTH_ROLE_PERMISSIONS is not configured
+	DefaultRolePermissions = "user=collection:catch;admin=pokemon:read,pokemon:write,type:read,type:write,user:manage,apikey:manage,trash:read,audit:read"
+
+	// DefaultRoleInherits is used when AUTH_ROLE_INHERITS is not configured
+	DefaultRoleInherits = "admin=user"
+)
+
+// Policy maps every role to the permissions it is granted, a role also owns the permissions of the roles it inherits
+type Policy struct {
+	permissions map[enum.Role]map[enum.Permission]bool
+	inherits    map[enum.Role][]enum.Role
+}
+
+// NewPolicy creates policy from the given role permissions and role hierarchy
+func NewPolicy(permissions map[enum.Role][]enum.Permission, inherits map[enum.Role][]enum.Role) *Policy {
+	policy := &Policy{
+		permissions: make(map[enum.Role]map[enum.Permission]bool),
+		inherits:    inherits,
+	}
+
+	for role, perms := range permissions {
+		policy.permissions[role] = make(map[enum.Permission]bool)
+		for _, perm := range perms {
+			policy.permissions[role][perm] = true
+		}
+	}
+
+	return policy
+}
+
+// ParsePolicy creates policy from configuration strings.
+// permissions use format "role=perm,perm;role=perm" and inherits use format "role=parent,parent;role=parent"
+func ParsePolicy(permissions string, inherits string) (policy *Policy, err error) {
+	if permissions == "" {
+		permissions = DefaultRolePermissions
+	}
+	if inherits == "" {
+		inherits = DefaultRoleInherits
+	}
+
+	rolePermissions := make(map[enum.Role][]enum.Permission)
+	err = parseRoleList(permissions, func(role enum.Role, value string) error {
+		permission := enum.Permission(value)
+		if !permission.IsValid() {
+			return fmt.Errorf("permission %s is not valid", value)
+		}
+		rolePermissions[role] = append(rolePermissions[role], permission)
+		return nil
+	})
+	if err != nil {
+		return policy, err
+	}
+
+	roleInherits := make(map[enum.Role][]enum.Role)
+	err = parseRoleList(inherits, func(role enum.Role, value string) error {
+		parent, err := enum.ParseRole(value)
+		if err != nil {
+			return err
+		}
+		roleInherits[role] = append(roleInherits[role], parent)
+		return nil
+	})
+	if err != nil {
+		return policy, err
+	}
+
+	return NewPolicy(rolePermissions, roleInherits), nil
+}
+
+// Can reports whether role is granted permission directly or through the roles it inherits
+func (p *Policy) Can(role enum.Role, permission enum.Permission) bool {
+	return p.can(role, permission, make(map[enum.Role]bool))
+}
+
+func (p *Policy) can(role enum.Role, permission enum.Permission, visited map[enum.Role]bool) bool {
+	if visited[role] {
+		return false
+	}
+	visited[role] = true
+
+	if p.permissions[role][permission] {
+		return true
+	}
+
+	for _, parent := range p.inherits[role] {
+		if p.can(parent, permission, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseRoleList(value string, fn func(role enum.Role, value string) error) (err error) {
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid policy entry %q", entry)
+		}
+
+		role, err := enum.ParseRole(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return err
+		}
+
+		for _, item := range strings.Split(parts[1], ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+
+			err = fn(role, item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
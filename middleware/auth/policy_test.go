@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+func TestPolicy_Can(t *testing.T) {
+	policy, err := ParsePolicy("", "")
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+
+	type args struct {
+		role       enum.Role
+		permission enum.Permission
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "success user can catch",
+			args: args{
+				role:       enum.User,
+				permission: enum.CollectionCatch,
+			},
+			want: true,
+		},
+		{
+			name: "success admin inherits user permission",
+			args: args{
+				role:       enum.Admin,
+				permission: enum.CollectionCatch,
+			},
+			want: true,
+		},
+		{
+			name: "success admin can write pokemon",
+			args: args{
+				role:       enum.Admin,
+				permission: enum.PokemonWrite,
+			},
+			want: true,
+		},
+		{
+			name: "failed user can not write pokemon",
+			args: args{
+				role:       enum.User,
+				permission: enum.PokemonWrite,
+			},
+			want: false,
+		},
+		{
+			name: "failed public has no permission",
+			args: args{
+				role:       enum.Public,
+				permission: enum.CollectionCatch,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Can(tt.args.role, tt.args.permission); got != tt.want {
+				t.Errorf("Policy.Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_CanWithCycle(t *testing.T) {
+	policy := NewPolicy(
+		map[enum.Role][]enum.Permission{enum.User: {enum.CollectionCatch}},
+		map[enum.Role][]enum.Role{enum.Admin: {enum.User}, enum.User: {enum.Admin}},
+	)
+
+	if !policy.Can(enum.Admin, enum.CollectionCatch) {
+		t.Errorf("Policy.Can() = false, want true")
+	}
+	if policy.Can(enum.Admin, enum.PokemonWrite) {
+		t.Errorf("Policy.Can() = true, want false")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	type args struct {
+		permissions string
+		inherits    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				permissions: "user=collection:catch; admin=pokemon:write",
+				inherits:    "admin=user",
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed invalid entry",
+			args: args{
+				permissions: "user",
+				inherits:    "admin=user",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed unknown role",
+			args: args{
+				permissions: "root=pokemon:write",
+				inherits:    "admin=user",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed unknown permission",
+			args: args{
+				permissions: "admin=pokemon:wirte",
+				inherits:    "admin=user",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed unknown parent role",
+			args: args{
+				permissions: "user=collection:catch",
+				inherits:    "admin=root",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy(tt.args.permissions, tt.args.inherits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
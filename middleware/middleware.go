@@ -3,17 +3,49 @@ package middleware
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/winartodev/go-pokedex/enum"
 	"github.com/winartodev/go-pokedex/helper"
 	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/usecase"
 )
 
-// Auth will validate url path, and role of user obtained from jwt token
-func Auth(handle httprouter.Handle) httprouter.Handle {
+// APIKeyHeader is the header service accounts send their api key in instead of logging in
+const APIKeyHeader = "X-API-Key"
+
+type Middleware struct {
+	Policy                *auth.Policy
+	RequireVerifiedEmail  bool
+	RequireAdminTwoFactor bool
+	APIKeyUsecase         usecase.APIKeyUsecaseItf
+	UserUsecase           usecase.UserUsecaseItf
+}
+
+func NewMiddleware(middleware Middleware) *Middleware {
+	return &Middleware{
+		Policy:                middleware.Policy,
+		RequireVerifiedEmail:  middleware.RequireVerifiedEmail,
+		RequireAdminTwoFactor: middleware.RequireAdminTwoFactor,
+		APIKeyUsecase:         middleware.APIKeyUsecase,
+		UserUsecase:           middleware.UserUsecase,
+	}
+}
+
+// Auth will validate jwt token and its session, or the api key of X-API-Key header, and store the claims of the user in request context
+func (m *Middleware) Auth(handle httprouter.Handle) httprouter.Handle {
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		if key := r.Header.Get(APIKeyHeader); key != "" && m.APIKeyUsecase != nil {
+			claims, err := m.APIKeyUsecase.Authenticate(r.Context(), key)
+			if err != nil {
+				helper.ErrorResponse(w, err)
+				return
+			}
+
+			handle(w, r.WithContext(auth.NewContext(r.Context(), claims)), p)
+			return
+		}
+
 		c, err := r.Cookie("token")
 		if err != nil {
 			if err == http.ErrNoCookie {
@@ -31,16 +63,61 @@ func Auth(handle httprouter.Handle) httprouter.Handle {
 			return
 		}
 
-		urlPath := strings.Split(r.URL.Path, "/")[1]
-		switch urlPath {
-		case "internal":
-			if claims.Role != enum.Admin {
-				helper.FailedResponse(w, http.StatusUnauthorized, fmt.Errorf("user not %s", enum.Admin.String()))
+		if m.UserUsecase != nil {
+			err = m.UserUsecase.ValidateSession(r.Context(), claims)
+			if err != nil {
+				helper.ErrorResponse(w, err)
 				return
 			}
-		case "user":
-			if claims.Role != enum.User {
-				helper.FailedResponse(w, http.StatusUnauthorized, fmt.Errorf("user is not logged in"))
+		}
+
+		handle(w, r.WithContext(auth.NewContext(r.Context(), claims)), p)
+	})
+}
+
+// Require will authenticate the user and check the role of the user is granted all of the permissions,
+// admins must have logged in with two factor authentication when RequireAdminTwoFactor is enabled.
+// Api keys are checked against their scopes instead of a role
+func (m *Middleware) Require(permissions ...enum.Permission) func(httprouter.Handle) httprouter.Handle {
+	return func(handle httprouter.Handle) httprouter.Handle {
+		return m.Auth(func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+			claims, _ := auth.FromContext(r.Context())
+			if claims.APIKeyID != 0 {
+				for _, permission := range permissions {
+					if !hasScope(claims.Scopes, permission) {
+						helper.FailedResponse(w, http.StatusForbidden, fmt.Errorf("api key scope %s is required", permission))
+						return
+					}
+				}
+
+				handle(w, r, p)
+				return
+			}
+
+			if m.RequireAdminTwoFactor && claims.Role == enum.Admin && !claims.TwoFactor {
+				helper.FailedResponse(w, http.StatusForbidden, fmt.Errorf("two factor authentication is required for admin accounts"))
+				return
+			}
+
+			for _, permission := range permissions {
+				if !m.Policy.Can(claims.Role, permission) {
+					helper.FailedResponse(w, http.StatusForbidden, fmt.Errorf("permission %s is required", permission))
+					return
+				}
+			}
+
+			handle(w, r, p)
+		})
+	}
+}
+
+// VerifiedEmail will reject users whose email is not verified when RequireVerifiedEmail is enabled, it must be wrapped by Auth
+func (m *Middleware) VerifiedEmail(handle httprouter.Handle) httprouter.Handle {
+	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		if m.RequireVerifiedEmail {
+			claims, ok := auth.FromContext(r.Context())
+			if !ok || !claims.EmailVerified {
+				helper.FailedResponse(w, http.StatusForbidden, fmt.Errorf("email is not verified"))
 				return
 			}
 		}
@@ -48,3 +125,12 @@ func Auth(handle httprouter.Handle) httprouter.Handle {
 		handle(w, r, p)
 	})
 }
+
+func hasScope(scopes []enum.Permission, permission enum.Permission) bool {
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/usecase"
+	usecasemock "github.com/winartodev/go-pokedex/usecase/mocks"
+)
+
+func TestMiddleware_Require(t *testing.T) {
+	policy, _ := auth.ParsePolicy("", "")
+	m := NewMiddleware(Middleware{Policy: policy})
+
+	userToken, _ := auth.GenerateJWT(entity.User{ID: 2, Username: "user", Email: "user@mail", Role: int64(enum.User)}, 1)
+	adminToken, _ := auth.GenerateJWT(entity.User{ID: 1, Username: "admin", Email: "admin@mail", Role: int64(enum.Admin)}, 1)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	type args struct {
+		token       string
+		permissions []enum.Permission
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+	}{
+		{
+			name: "success admin",
+			args: args{
+				token:       adminToken,
+				permissions: []enum.Permission{enum.PokemonWrite},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "success admin inherits user permission",
+			args: args{
+				token:       adminToken,
+				permissions: []enum.Permission{enum.CollectionCatch},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "success authenticated without permission",
+			args: args{
+				token: userToken,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "failed user lacks permission",
+			args: args{
+				token:       userToken,
+				permissions: []enum.Permission{enum.PokemonWrite},
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "failed not logged in",
+			args: args{
+				permissions: []enum.Permission{enum.CollectionCatch},
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "failed invalid token",
+			args: args{
+				token:       "invalid",
+				permissions: []enum.Permission{enum.CollectionCatch},
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.args.token != "" {
+				r.AddCookie(&http.Cookie{Name: "token", Value: tt.args.token})
+			}
+
+			m.Require(tt.args.permissions...)(handle)(w, r, httprouter.Params{})
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware.Require() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMiddleware_VerifiedEmail(t *testing.T) {
+	policy, _ := auth.ParsePolicy("", "")
+
+	verifiedToken, _ := auth.GenerateJWT(entity.User{ID: 2, Username: "user", Role: int64(enum.User), EmailVerified: true}, 1)
+	unverifiedToken, _ := auth.GenerateJWT(entity.User{ID: 3, Username: "other", Role: int64(enum.User)}, 1)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	type args struct {
+		requireVerifiedEmail bool
+		token                string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+	}{
+		{
+			name: "success verified email",
+			args: args{
+				requireVerifiedEmail: true,
+				token:                verifiedToken,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "success verification not required",
+			args: args{
+				requireVerifiedEmail: false,
+				token:                unverifiedToken,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "failed email not verified",
+			args: args{
+				requireVerifiedEmail: true,
+				token:                unverifiedToken,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(Middleware{Policy: policy, RequireVerifiedEmail: tt.args.requireVerifiedEmail})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/", nil)
+			r.AddCookie(&http.Cookie{Name: "token", Value: tt.args.token})
+
+			m.Require(enum.CollectionCatch)(m.VerifiedEmail(handle))(w, r, httprouter.Params{})
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware.VerifiedEmail() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMiddleware_RequireAdminTwoFactor(t *testing.T) {
+	policy, _ := auth.ParsePolicy("", "")
+	m := NewMiddleware(Middleware{Policy: policy, RequireAdminTwoFactor: true})
+
+	adminToken, _ := auth.GenerateJWT(entity.User{ID: 1, Username: "admin", Role: int64(enum.Admin)}, 1)
+	adminTwoFactorToken, _ := auth.GenerateJWT(entity.User{ID: 1, Username: "admin", Role: int64(enum.Admin), TOTPEnabled: true}, 1)
+	userToken, _ := auth.GenerateJWT(entity.User{ID: 2, Username: "user", Role: int64(enum.User)}, 1)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	type args struct {
+		token      string
+		permission enum.Permission
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+	}{
+		{
+			name: "success admin with two factor",
+			args: args{
+				token:      adminTwoFactorToken,
+				permission: enum.PokemonWrite,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "success user without two factor",
+			args: args{
+				token:      userToken,
+				permission: enum.CollectionCatch,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "failed admin without two factor",
+			args: args{
+				token:      adminToken,
+				permission: enum.PokemonWrite,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/", nil)
+			r.AddCookie(&http.Cookie{Name: "token", Value: tt.args.token})
+
+			m.Require(tt.args.permission)(handle)(w, r, httprouter.Params{})
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware.Require() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMiddleware_RequireAPIKey(t *testing.T) {
+	policy, _ := auth.ParsePolicy("", "")
+	apiKeyUsecase := new(usecasemock.APIKeyUsecaseItf)
+	m := NewMiddleware(Middleware{Policy: policy, APIKeyUsecase: apiKeyUsecase, RequireAdminTwoFactor: true})
+
+	apiKeyUsecase.On("Authenticate", mock.Anything, "pk_read").
+		Return(&auth.JWTClaim{Username: "pokemon sync", APIKeyID: 1, Scopes: []enum.Permission{enum.PokemonRead}}, nil)
+	apiKeyUsecase.On("Authenticate", mock.Anything, "pk_revoked").
+		Return(nil, usecase.ErrInvalidAPIKey)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	type args struct {
+		key        string
+		permission enum.Permission
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+	}{
+		{
+			name: "success key has scope",
+			args: args{
+				key:        "pk_read",
+				permission: enum.PokemonRead,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "failed key lacks scope",
+			args: args{
+				key:        "pk_read",
+				permission: enum.PokemonWrite,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "failed invalid key",
+			args: args{
+				key:        "pk_revoked",
+				permission: enum.PokemonRead,
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(APIKeyHeader, tt.args.key)
+
+			m.Require(tt.args.permission)(handle)(w, r, httprouter.Params{})
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware.Require() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMiddleware_AuthSession(t *testing.T) {
+	userUsecase := new(usecasemock.UserUsecaseItf)
+	m := NewMiddleware(Middleware{UserUsecase: userUsecase})
+
+	activeToken, _ := auth.GenerateJWT(entity.User{ID: 2, Username: "user", Email: "user@mail", Role: int64(enum.User)}, 1)
+	revokedToken, _ := auth.GenerateJWT(entity.User{ID: 2, Username: "user", Email: "user@mail", Role: int64(enum.User)}, 2)
+
+	userUsecase.On("ValidateSession", mock.Anything, mock.MatchedBy(func(claims *auth.JWTClaim) bool { return claims.SessionID == 1 })).
+		Return(nil)
+	userUsecase.On("ValidateSession", mock.Anything, mock.MatchedBy(func(claims *auth.JWTClaim) bool { return claims.SessionID == 2 })).
+		Return(usecase.ErrSessionRevoked)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "success active session",
+			token:      activeToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "failed revoked session",
+			token:      revokedToken,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.AddCookie(&http.Cookie{Name: "token", Value: tt.token})
+
+			m.Auth(handle)(w, r, httprouter.Params{})
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware.Auth() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
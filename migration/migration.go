@@ -0,0 +1,207 @@
+// Package migration keeps the schema of the pokedex database up to date. Migrations are the numbered sql files
+// of the sql directory, every migration runs once in the order of its version and the applied versions are kept
+// in the schema_migrations table
+package migration
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// fileName is the name of a migration file, the version and a name like 0003_user_profile.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// errors of mysql for schema changes that are already done, databases created from an older pokedex.sql
+// have some of the columns and indexes of the migrations before the migrations are recorded
+const (
+	errDuplicateColumn = 1060
+	errDuplicateKey    = 1061
+)
+
+const (
+	createTableQuery = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version int NOT NULL,
+			name varchar(255) NOT NULL,
+			applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (version)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
+	`
+
+	getAppliedQuery = `SELECT version FROM schema_migrations`
+
+	insertAppliedQuery = `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`
+)
+
+// Migration is a change of the schema
+type Migration struct {
+	Version    int64
+	Name       string
+	Statements []string
+}
+
+// All returns every migration sorted by version
+func All() (results []Migration, err error) {
+	entries, err := files.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]string, len(entries))
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s is not named like 0001_name.sql", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		file, err := files.Open(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		statements, err := Statements(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, Migration{Version: version, Name: match[2], Statements: statements})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Version < results[j].Version })
+	return results, nil
+}
+
+// Pending returns the migrations that are not applied to the database yet sorted by version
+func Pending(ctx context.Context, db *sql.DB) (results []Migration, err error) {
+	_, err = db.ExecContext(ctx, createTableQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, getAppliedQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range all {
+		if !applied[migration.Version] {
+			results = append(results, migration)
+		}
+	}
+
+	return results, nil
+}
+
+// Up applies the pending migrations in order and stops at the first failure. Schema changes of mysql commit
+// on their own, so a migration is recorded once all of its statements ran and a failed migration is retried
+// from its first statement, columns and indexes it already added are skipped then
+func Up(ctx context.Context, db *sql.DB) (applied []Migration, err error) {
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range pending {
+		for _, statement := range migration.Statements {
+			_, err = db.ExecContext(ctx, statement)
+			if err != nil && !alreadyDone(err) {
+				return applied, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		_, err = db.ExecContext(ctx, insertAppliedQuery, migration.Version, migration.Name)
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// alreadyDone reports whether the statement failed because the column or index it adds exists already
+func alreadyDone(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+
+	return mysqlErr.Number == errDuplicateColumn || mysqlErr.Number == errDuplicateKey
+}
+
+// Statements returns the sql statements of the file, a statement ends with the line ending in ;
+// and comment lines are left out
+func Statements(r io.Reader) (results []string, err error) {
+	var statement strings.Builder
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+
+		if statement.Len() > 0 {
+			statement.WriteString("\n")
+		}
+		statement.WriteString(line)
+
+		if strings.HasSuffix(line, ";") {
+			results = append(results, strings.TrimSuffix(statement.String(), ";"))
+			statement.Reset()
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if statement.Len() > 0 {
+		results = append(results, statement.String())
+	}
+
+	return results, nil
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("All() returned no migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("All()[%d] version = %d, want %d", i, migration.Version, i+1)
+		}
+		if len(migration.Statements) == 0 {
+			t.Errorf("All()[%d] %s has no statements", i, migration.Name)
+		}
+	}
+}
+
+func TestStatements(t *testing.T) {
+	schema := `CREATE DATABASE IF NOT EXISTS pokedex;
+
+-- pokedex.types definition
+
+CREATE TABLE IF NOT EXISTS types (
+  id int NOT NULL
+);
+
+LOCK TABLES types WRITE;
+INSERT INTO pokedex.types (id,name) VALUES
+	 (1,'NORMAL;'),
+	 (2,'GRASS');
+UNLOCK TABLES;
+`
+	got, err := Statements(strings.NewReader(schema))
+	if err != nil {
+		t.Fatalf("Statements() error = %v", err)
+	}
+
+	want := []string{
+		"CREATE DATABASE IF NOT EXISTS pokedex",
+		"CREATE TABLE IF NOT EXISTS types (\nid int NOT NULL\n)",
+		"LOCK TABLES types WRITE",
+		"INSERT INTO pokedex.types (id,name) VALUES\n(1,'NORMAL;'),\n(2,'GRASS')",
+		"UNLOCK TABLES",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE `users` ADD COLUMN `display_name` varchar(255) NOT NULL DEFAULT '';
+
+ALTER TABLE `users` ADD COLUMN `disabled` tinyint(1) NOT NULL DEFAULT '0';
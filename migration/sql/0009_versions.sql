@@ -0,0 +1,3 @@
+ALTER TABLE `pokemons` ADD COLUMN `version` int NOT NULL DEFAULT 1;
+
+ALTER TABLE `types` ADD COLUMN `version` int NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE `pokemons` ADD COLUMN `deleted_at` timestamp NULL DEFAULT NULL;
+
+ALTER TABLE `types` ADD COLUMN `deleted_at` timestamp NULL DEFAULT NULL;
@@ -0,0 +1,18 @@
+-- rows are only ever inserted
+
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `actor_id` int NOT NULL,
+  `api_key_id` int NOT NULL DEFAULT '0',
+  `actor` varchar(255) NOT NULL,
+  `action` varchar(32) NOT NULL,
+  `resource` varchar(32) NOT NULL,
+  `resource_id` int NOT NULL,
+  `before_snapshot` json NOT NULL,
+  `after_snapshot` json NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_audit_logs_resource` (`resource`, `resource_id`),
+  KEY `idx_audit_logs_actor` (`actor`),
+  KEY `idx_audit_logs_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,13 @@
+-- revision is the version of the pokemon the snapshot was saved as
+
+CREATE TABLE IF NOT EXISTS `pokemon_revisions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `pokemon_id` int NOT NULL,
+  `revision` int NOT NULL,
+  `snapshot` json NOT NULL,
+  `actor_id` int NOT NULL,
+  `actor` varchar(255) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_pokemon_revisions_pokemon_id_revision` (`pokemon_id`, `revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
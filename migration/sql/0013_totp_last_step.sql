@@ -0,0 +1,2 @@
+-- the time step of the last accepted totp code, codes of that step and earlier ones are rejected so they can't be replayed
+ALTER TABLE `users` ADD COLUMN `totp_last_step` bigint NOT NULL DEFAULT '0';
@@ -0,0 +1,7 @@
+-- names were only checked before they were written, two requests could still create the same name at once.
+-- Pokemons and types in the trash keep their name so it is still unique when they are restored
+ALTER TABLE `pokemons` ADD UNIQUE KEY `uq_pokemons_name` (`name`);
+
+ALTER TABLE `types` ADD UNIQUE KEY `uq_types_name` (`name`);
+
+ALTER TABLE `users` ADD UNIQUE KEY `uq_users_username` (`username`);
@@ -0,0 +1,4 @@
+-- the email address an email verification token was sent to, the token only verifies that address
+-- so an earlier token can't verify an email the user changed to afterwards. Tokens sent before have
+-- no email and are rejected, a new verification email can be requested
+ALTER TABLE `user_tokens` ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '';
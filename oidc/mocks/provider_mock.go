@@ -0,0 +1,72 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package oidcmock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	oidc "github.com/winartodev/go-pokedex/oidc"
+)
+
+// ProviderItf is an autogenerated mock type for the ProviderItf type
+type ProviderItf struct {
+	mock.Mock
+}
+
+// AuthCodeURL provides a mock function with given fields: ctx, state, nonce, codeChallenge
+func (_m *ProviderItf) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	ret := _m.Called(ctx, state, nonce, codeChallenge)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, state, nonce, codeChallenge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, state, nonce, codeChallenge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exchange provides a mock function with given fields: ctx, code, codeVerifier
+func (_m *ProviderItf) Exchange(ctx context.Context, code string, codeVerifier string) (oidc.Claims, error) {
+	ret := _m.Called(ctx, code, codeVerifier)
+
+	var r0 oidc.Claims
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) oidc.Claims); ok {
+		r0 = rf(ctx, code, codeVerifier)
+	} else {
+		r0 = ret.Get(0).(oidc.Claims)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, code, codeVerifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewProviderItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewProviderItf creates a new instance of ProviderItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewProviderItf(t mockConstructorTestingTNewProviderItf) *ProviderItf {
+	mock := &ProviderItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,384 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+// Claims is the identity of the user returned by the provider in the id token
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string
+	Nonce             string
+}
+
+// Provider is OpenID Connect relying party for the authorization code flow with PKCE,
+// the endpoints and signing keys of the issuer are discovered on first use
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	HTTPClient   *http.Client
+
+	mu        *sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type ProviderItf interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (authURL string, err error)
+	Exchange(ctx context.Context, code string, codeVerifier string) (claims Claims, err error)
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewProvider(provider Provider) ProviderItf {
+	httpClient := provider.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	scopes := provider.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	groupsClaim := provider.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return &Provider{
+		Issuer:       strings.TrimSuffix(provider.Issuer, "/"),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  provider.RedirectURL,
+		Scopes:       scopes,
+		GroupsClaim:  groupsClaim,
+		HTTPClient:   httpClient,
+		mu:           &sync.Mutex{},
+	}
+}
+
+// CodeChallengeS256 returns the PKCE code challenge of the code verifier
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the url of the provider the user has to be redirected to
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (authURL string, err error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return authURL, err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURL)
+	query.Set("scope", strings.Join(p.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the claims of the verified id token
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (claims Claims, err error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return claims, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return claims, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	response, err := p.HTTPClient.Do(request)
+	if err != nil {
+		return claims, err
+	}
+	defer response.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&token)
+	if err != nil {
+		return claims, fmt.Errorf("failed to decode token response: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return claims, fmt.Errorf("token request failed: %s %s", token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return claims, errors.New("token response does not contain id_token")
+	}
+
+	return p.verify(ctx, token.IDToken)
+}
+
+// verify checks the signature, issuer, audience and expiry of the id token
+func (p *Provider) verify(ctx context.Context, idToken string) (claims Claims, err error) {
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return claims, err
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return claims, errors.New("couldn't parse claims")
+	}
+
+	claims = Claims{
+		Issuer:            stringClaim(mapClaims, "iss"),
+		Subject:           stringClaim(mapClaims, "sub"),
+		Email:             stringClaim(mapClaims, "email"),
+		Name:              stringClaim(mapClaims, "name"),
+		PreferredUsername: stringClaim(mapClaims, "preferred_username"),
+		Nonce:             stringClaim(mapClaims, "nonce"),
+		Groups:            stringsClaim(mapClaims, p.GroupsClaim),
+	}
+	claims.EmailVerified, _ = mapClaims["email_verified"].(bool)
+
+	if claims.Issuer != p.Issuer {
+		return claims, fmt.Errorf("id token issuer %s is not %s", claims.Issuer, p.Issuer)
+	}
+
+	audience := stringsClaim(mapClaims, "aud")
+	if !contains(audience, p.ClientID) {
+		return claims, errors.New("id token is not issued for this client")
+	}
+
+	if len(audience) > 1 && stringClaim(mapClaims, "azp") != p.ClientID {
+		return claims, errors.New("id token is not authorized for this client")
+	}
+
+	if claims.Subject == "" {
+		return claims, errors.New("id token does not contain subject")
+	}
+
+	return claims, nil
+}
+
+// discover fetches the provider metadata once and caches it
+func (p *Provider) discover(ctx context.Context) (d *discovery, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	d = &discovery{}
+	err = p.getJSON(ctx, p.Issuer+"/.well-known/openid-configuration", d)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(d.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("discovered issuer %s is not %s", d.Issuer, p.Issuer)
+	}
+
+	p.discovery = d
+	return d, nil
+}
+
+// key returns the signing key with the given id, the key set is fetched again when the id is unknown so rotated keys are found
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.findKey(kid); ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	err = p.getJSON(ctx, d.JWKSURI, &jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		p.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if key, ok := p.findKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("signing key %s is not found", kid)
+}
+
+// findKey returns the key with the id, a token without id can use the only key of the set
+func (p *Provider) findKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) getJSON(ctx context.Context, target string, result interface{}) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := p.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("request %s failed with status %d", target, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim reads claim that can be a single string or a list of strings like aud and groups
+func stringsClaim(claims jwt.MapClaims, name string) (results []string) {
+	switch value := claims[name].(type) {
+	case string:
+		results = append(results, value)
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				results = append(results, s)
+			}
+		}
+	}
+
+	return results
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseGroupRoles creates the mapping of provider groups to roles from format "group=role;group=role"
+func ParseGroupRoles(value string) (groupRoles map[string]enum.Role, err error) {
+	groupRoles = make(map[string]enum.Role)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return groupRoles, fmt.Errorf("group role %s is not valid", entry)
+		}
+
+		role, err := enum.ParseRole(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return groupRoles, err
+		}
+
+		groupRoles[strings.TrimSpace(parts[0])] = role
+	}
+
+	return groupRoles, nil
+}
@@ -0,0 +1,233 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/oidc/oidctest"
+)
+
+func TestCodeChallengeS256(t *testing.T) {
+	// example of RFC 7636 appendix B
+	got := CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("CodeChallengeS256() = %v, want %v", got, want)
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	idp := oidctest.NewServer("pokedex", "secret")
+	defer idp.Close()
+
+	idp.SetIdentity(oidctest.Identity{
+		Subject:           "ash-1",
+		Email:             "ash@mail",
+		EmailVerified:     true,
+		Name:              "Ash Ketchum",
+		PreferredUsername: "ash",
+		Groups:            []string{"trainers", "pokedex-admins"},
+	})
+
+	ctx := context.Background()
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	type fields struct {
+		clientSecret string
+	}
+	type args struct {
+		codeVerifier string
+		reuseCode    bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    Claims
+		wantErr bool
+	}{
+		{
+			name: "success",
+			fields: fields{
+				clientSecret: "secret",
+			},
+			args: args{
+				codeVerifier: verifier,
+			},
+			want: Claims{
+				Issuer:            idp.Issuer(),
+				Subject:           "ash-1",
+				Email:             "ash@mail",
+				EmailVerified:     true,
+				Name:              "Ash Ketchum",
+				PreferredUsername: "ash",
+				Groups:            []string{"trainers", "pokedex-admins"},
+				Nonce:             "nonce",
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed code verifier does not match challenge",
+			fields: fields{
+				clientSecret: "secret",
+			},
+			args: args{
+				codeVerifier: "another-verifier",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed client secret not valid",
+			fields: fields{
+				clientSecret: "wrong",
+			},
+			args: args{
+				codeVerifier: verifier,
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed code used twice",
+			fields: fields{
+				clientSecret: "secret",
+			},
+			args: args{
+				codeVerifier: verifier,
+				reuseCode:    true,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(Provider{
+				Issuer:       idp.Issuer(),
+				ClientID:     "pokedex",
+				ClientSecret: tt.fields.clientSecret,
+				RedirectURL:  "http://localhost:8080/login/oidc/callback",
+			})
+
+			authURL, err := p.AuthCodeURL(ctx, "state", "nonce", CodeChallengeS256(verifier))
+			if err != nil {
+				t.Fatalf("Provider.AuthCodeURL() error = %v", err)
+			}
+
+			code, state, err := idp.Authorize(authURL)
+			if err != nil || state != "state" {
+				t.Fatalf("Authorize() state = %v, error = %v", state, err)
+			}
+
+			if tt.args.reuseCode {
+				p.Exchange(ctx, code, tt.args.codeVerifier)
+			}
+
+			got, err := p.Exchange(ctx, code, tt.args.codeVerifier)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Provider.Exchange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Provider.Exchange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	idp := oidctest.NewServer("pokedex", "")
+	defer idp.Close()
+
+	p := NewProvider(Provider{
+		Issuer:      idp.Issuer() + "/",
+		ClientID:    "pokedex",
+		RedirectURL: "http://localhost:8080/login/oidc/callback",
+		Scopes:      []string{"openid", "groups"},
+	})
+
+	got, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	if err != nil {
+		t.Fatalf("Provider.AuthCodeURL() error = %v", err)
+	}
+
+	authURL, _ := url.Parse(got)
+	want := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"pokedex"},
+		"redirect_uri":          {"http://localhost:8080/login/oidc/callback"},
+		"scope":                 {"openid groups"},
+		"state":                 {"state"},
+		"nonce":                 {"nonce"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+	}
+	if authURL.Path != "/authorize" || !reflect.DeepEqual(authURL.Query(), want) {
+		t.Errorf("Provider.AuthCodeURL() = %v", got)
+	}
+}
+
+func TestProvider_AuthCodeURLDiscoveryFailed(t *testing.T) {
+	idp := oidctest.NewServer("pokedex", "")
+	idp.Close()
+
+	p := NewProvider(Provider{Issuer: idp.Issuer(), ClientID: "pokedex"})
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil {
+		t.Errorf("Provider.AuthCodeURL() error = nil, want error when issuer is not reachable")
+	}
+}
+
+func TestParseGroupRoles(t *testing.T) {
+	type args struct {
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]enum.Role
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				value: "pokedex-admins=admin; pokedex-users=user;",
+			},
+			want:    map[string]enum.Role{"pokedex-admins": enum.Admin, "pokedex-users": enum.User},
+			wantErr: false,
+		},
+		{
+			name: "success empty",
+			args: args{
+				value: "",
+			},
+			want:    map[string]enum.Role{},
+			wantErr: false,
+		},
+		{
+			name: "failed invalid entry",
+			args: args{
+				value: "pokedex-admins",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed unknown role",
+			args: args{
+				value: "pokedex-admins=root",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGroupRoles(tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGroupRoles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGroupRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
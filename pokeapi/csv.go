@@ -0,0 +1,193 @@
+package pokeapi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+// englishLanguageID is the id of english in languages.csv
+const englishLanguageID = "9"
+
+type csvType struct {
+	slot int64
+	name string
+}
+
+// LoadCSV reads the default form of every pokemon species from the csv files of the data set in dir
+func LoadCSV(dir string) (pokemons []Pokemon, err error) {
+	generations := make(map[string]int64)
+	err = readCSV(dir, "pokemon_species.csv", func(row map[string]string) (err error) {
+		generations[row["id"]], err = strconv.ParseInt(row["generation_id"], 10, 64)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	genera := make(map[string]string)
+	err = readCSV(dir, "pokemon_species_names.csv", func(row map[string]string) error {
+		if row["local_language_id"] == englishLanguageID {
+			names[row["pokemon_species_id"]] = row["name"]
+			genera[row["pokemon_species_id"]] = row["genus"]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the first english text is kept so the description doesn't change when newer games are added to the data set
+	descriptions := make(map[string]string)
+	err = readCSV(dir, "pokemon_species_flavor_text.csv", func(row map[string]string) error {
+		if _, ok := descriptions[row["species_id"]]; !ok && row["language_id"] == englishLanguageID {
+			descriptions[row["species_id"]] = cleanText(row["flavor_text"])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	typeNames := make(map[string]string)
+	err = readCSV(dir, "types.csv", func(row map[string]string) error {
+		typeNames[row["id"]] = typeName(row["identifier"])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string][]csvType)
+	err = readCSV(dir, "pokemon_types.csv", func(row map[string]string) (err error) {
+		slot, err := strconv.ParseInt(row["slot"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		types[row["pokemon_id"]] = append(types[row["pokemon_id"]], csvType{slot: slot, name: typeNames[row["type_id"]]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statNames := make(map[string]string)
+	err = readCSV(dir, "stats.csv", func(row map[string]string) error {
+		statNames[row["id"]] = row["identifier"]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]entity.Stats)
+	err = readCSV(dir, "pokemon_stats.csv", func(row map[string]string) (err error) {
+		value, err := strconv.ParseInt(row["base_stat"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		stat := stats[row["pokemon_id"]]
+		setStat(&stat, statNames[row["stat_id"]], value)
+		stats[row["pokemon_id"]] = stat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV(dir, "pokemon.csv", func(row map[string]string) (err error) {
+		if row["is_default"] != "1" {
+			return nil
+		}
+
+		pokemon := Pokemon{
+			Name:        names[row["species_id"]],
+			Genus:       genera[row["species_id"]],
+			Generation:  generations[row["species_id"]],
+			Description: descriptions[row["species_id"]],
+		}
+
+		pokemon.ID, err = strconv.ParseInt(row["species_id"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if pokemon.Name == "" {
+			pokemon.Name = row["identifier"]
+		}
+
+		height, err := strconv.ParseFloat(row["height"], 64)
+		if err != nil {
+			return err
+		}
+
+		weight, err := strconv.ParseFloat(row["weight"], 64)
+		if err != nil {
+			return err
+		}
+
+		// height is stored in decimetres and weight in hectograms
+		pokemon.Height, pokemon.Weight = height/10, weight/10
+
+		pokemonTypes := types[row["id"]]
+		sort.Slice(pokemonTypes, func(i, j int) bool {
+			return pokemonTypes[i].slot < pokemonTypes[j].slot
+		})
+		for _, t := range pokemonTypes {
+			pokemon.Types = append(pokemon.Types, t.name)
+		}
+
+		pokemon.Stats = stats[row["id"]]
+		pokemons = append(pokemons, pokemon)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pokemons, nil
+}
+
+// readCSV calls fn with every row of the csv file in dir, the row is keyed by the columns of the header
+func readCSV(dir string, name string, fn func(row map[string]string) error) (err error) {
+	file, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+
+		err = fn(row)
+		if err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+}
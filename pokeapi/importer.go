@@ -0,0 +1,138 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+// DefaultBatchSize is how many pokemons are imported between two progress reports
+const DefaultBatchSize = 100
+
+// Importer saves the pokemons of the data set through the usecases. It can be run again and again,
+// missing types are created, pokemons are upserted by name and pokemons without change are left as they are
+type Importer struct {
+	PokemonUsecase usecase.PokemonUsecaseItf
+	TypeUsecase    usecase.TypeUsecaseItf
+	// ImageURL is formatted with the pokedex number of the pokemon
+	ImageURL  string
+	BatchSize int
+	// Progress receives a line after every batch and every failed pokemon
+	Progress io.Writer
+}
+
+// Import saves the types and pokemons, the report has every pokemon even when the import is done in batches
+func (i *Importer) Import(ctx context.Context, pokemons []Pokemon) (report entity.ImportReport, err error) {
+	err = i.importTypes(ctx, pokemons)
+	if err != nil {
+		return report, err
+	}
+
+	// the data set doesn't know which pokemons were caught, so the catch of existing pokemons is kept.
+	// names are compared case insensitive like the pokedex does
+	catched := make(map[string]int64)
+	err = i.PokemonUsecase.ExportPokemon(ctx, func(data entity.PokemonDetail) error {
+		catched[catchedKey(data.Name)] = data.Catched
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	batchSize := i.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	for start := 0; start < len(pokemons); start += batchSize {
+		end := start + batchSize
+		if end > len(pokemons) {
+			end = len(pokemons)
+		}
+
+		batch := make([]entity.ImportRow, 0, end-start)
+		for _, pokemon := range pokemons[start:end] {
+			batch = append(batch, entity.ImportRow{PokemonDetail: i.buildPokemon(pokemon, catched[catchedKey(pokemon.Name)])})
+		}
+
+		res, err := i.PokemonUsecase.ImportPokemon(ctx, batch, false)
+		if err != nil {
+			return report, err
+		}
+
+		for _, row := range res.Rows {
+			row.Row += start
+			if row.Error != nil {
+				fmt.Fprintf(i.Progress, "failed %s: %s\n", row.Name, row.Error.Message)
+			}
+			report.Rows = append(report.Rows, row)
+		}
+		report.Created += res.Created
+		report.Updated += res.Updated
+		report.Unchanged += res.Unchanged
+		report.Failed += res.Failed
+
+		fmt.Fprintf(i.Progress, "%d/%d pokemons imported: %d created, %d updated, %d unchanged, %d failed\n",
+			end, len(pokemons), report.Created, report.Updated, report.Unchanged, report.Failed)
+	}
+
+	return report, nil
+}
+
+// importTypes creates the types of the pokemons that the pokedex doesn't have yet
+func (i *Importer) importTypes(ctx context.Context, pokemons []Pokemon) (err error) {
+	types, err := i.TypeUsecase.GetAllType(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(types))
+	for _, t := range types {
+		known[strings.ToUpper(t.Name)] = true
+	}
+
+	for _, pokemon := range pokemons {
+		for _, name := range pokemon.Types {
+			if known[name] {
+				continue
+			}
+
+			_, err = i.TypeUsecase.CreateType(ctx, entity.Type{Name: name})
+			if err != nil {
+				return fmt.Errorf("create type %s: %w", name, err)
+			}
+
+			known[name] = true
+			fmt.Fprintf(i.Progress, "type %s created\n", name)
+		}
+	}
+
+	return nil
+}
+
+func catchedKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+func (i *Importer) buildPokemon(pokemon Pokemon, catched int64) entity.PokemonDetail {
+	var imageURL string
+	if i.ImageURL != "" {
+		imageURL = fmt.Sprintf(i.ImageURL, pokemon.ID)
+	}
+
+	return entity.PokemonDetail{
+		Name:        pokemon.Name,
+		Species:     pokemon.Genus,
+		Types:       pokemon.Types,
+		Catched:     catched,
+		ImageURL:    imageURL,
+		Description: pokemon.Description,
+		Weight:      pokemon.Weight,
+		Height:      pokemon.Height,
+		Stats:       pokemon.Stats,
+	}
+}
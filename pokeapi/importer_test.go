@@ -0,0 +1,142 @@
+package pokeapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/usecase"
+	usecasemock "github.com/winartodev/go-pokedex/usecase/mocks"
+)
+
+func TestImporter_Import(t *testing.T) {
+	ctx := context.Background()
+	pokemonUsecase := new(usecasemock.PokemonUsecaseItf)
+	typeUsecase := new(usecasemock.TypeUsecaseItf)
+
+	type fields struct {
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		ImageURL       string
+		BatchSize      int
+	}
+	type args struct {
+		ctx      context.Context
+		pokemons []Pokemon
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		wantReport   entity.ImportReport
+		wantProgress string
+		wantErr      bool
+		mock         func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonUsecase: pokemonUsecase,
+				TypeUsecase:    typeUsecase,
+				ImageURL:       "https://img.local/%d.png",
+				BatchSize:      1,
+			},
+			args: args{
+				ctx:      ctx,
+				pokemons: []Pokemon{bulbasaur, chikorita},
+			},
+			wantReport: entity.ImportReport{
+				Updated: 1,
+				Failed:  1,
+				Rows: []entity.ImportResult{
+					{Row: 1, Name: "Bulbasaur", Action: usecase.ImportUpdate, ID: 1},
+					{Row: 2, Name: "Chikorita", Error: &entity.BulkError{Code: "validation_failed", Message: "species can't be empty"}},
+				},
+			},
+			wantProgress: "type POISON created\n" +
+				"1/2 pokemons imported: 0 created, 1 updated, 0 unchanged, 0 failed\n" +
+				"failed Chikorita: species can't be empty\n" +
+				"2/2 pokemons imported: 0 created, 1 updated, 0 unchanged, 1 failed\n",
+			wantErr: false,
+			mock: func() {
+				typeUsecase.On("GetAllType", mock.Anything).
+					Return([]entity.Type{{ID: 1, Name: "grass"}}, nil).Times(1)
+
+				typeUsecase.On("CreateType", mock.Anything, entity.Type{Name: "POISON"}).
+					Return(int64(2), nil).Times(1)
+
+				pokemonUsecase.On("ExportPokemon", mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, fn func(data entity.PokemonDetail) error) error {
+						return fn(entity.PokemonDetail{ID: 1, Name: "bulbasaur ", Catched: 1})
+					}).Times(1)
+
+				pokemonUsecase.On("ImportPokemon", mock.Anything, []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{
+					Name:        "Bulbasaur",
+					Species:     "Seed Pokémon",
+					Types:       []string{"GRASS", "POISON"},
+					Catched:     1,
+					ImageURL:    "https://img.local/1.png",
+					Description: "A strange seed was planted on its back at birth.",
+					Weight:      6.9,
+					Height:      0.7,
+					Stats:       entity.Stats{HP: 45, Attack: 49, Def: 49, Speed: 45},
+				}}}, false).Return(entity.ImportReport{
+					Updated: 1,
+					Rows:    []entity.ImportResult{{Row: 1, Name: "Bulbasaur", Action: usecase.ImportUpdate, ID: 1}},
+				}, nil).Times(1)
+
+				pokemonUsecase.On("ImportPokemon", mock.Anything, mock.Anything, false).Return(entity.ImportReport{
+					Failed: 1,
+					Rows:   []entity.ImportResult{{Row: 1, Name: "Chikorita", Error: &entity.BulkError{Code: "validation_failed", Message: "species can't be empty"}}},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get types",
+			fields: fields{
+				PokemonUsecase: pokemonUsecase,
+				TypeUsecase:    typeUsecase,
+			},
+			args: args{
+				ctx:      ctx,
+				pokemons: []Pokemon{bulbasaur},
+			},
+			wantReport:   entity.ImportReport{},
+			wantProgress: "",
+			wantErr:      true,
+			mock: func() {
+				typeUsecase.On("GetAllType", mock.Anything).
+					Return(nil, errors.New("errors")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			var progress bytes.Buffer
+			i := &Importer{
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				ImageURL:       tt.fields.ImageURL,
+				BatchSize:      tt.fields.BatchSize,
+				Progress:       &progress,
+			}
+			gotReport, err := i.Import(tt.args.ctx, tt.args.pokemons)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Importer.Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotReport, tt.wantReport) {
+				t.Errorf("Importer.Import() = %v, want %v", gotReport, tt.wantReport)
+			}
+			if progress.String() != tt.wantProgress {
+				t.Errorf("Importer.Import() progress = %q, want %q", progress.String(), tt.wantProgress)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// englishLanguage is the name of english in the json resources
+const englishLanguage = "en"
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonPokemon struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Height    float64       `json:"height"`
+	Weight    float64       `json:"weight"`
+	IsDefault bool          `json:"is_default"`
+	Species   namedResource `json:"species"`
+	Stats     []struct {
+		BaseStat int64         `json:"base_stat"`
+		Stat     namedResource `json:"stat"`
+	} `json:"stats"`
+	Types []struct {
+		Slot int64         `json:"slot"`
+		Type namedResource `json:"type"`
+	} `json:"types"`
+}
+
+type jsonSpecies struct {
+	Generation namedResource `json:"generation"`
+	Names      []struct {
+		Name     string        `json:"name"`
+		Language namedResource `json:"language"`
+	} `json:"names"`
+	Genera []struct {
+		Genus    string        `json:"genus"`
+		Language namedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+	} `json:"flavor_text_entries"`
+}
+
+// LoadJSON reads the default form of every pokemon species from pokemon/<id>/index.json and
+// pokemon-species/<id>/index.json of the data set in dir
+func LoadJSON(dir string) (pokemons []Pokemon, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "pokemon", "*", "index.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no pokemon.csv or pokemon/*/index.json found in %s", dir)
+	}
+
+	for _, file := range files {
+		var data jsonPokemon
+		err = readJSON(file, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		if !data.IsDefault {
+			continue
+		}
+
+		speciesID, err := idFromURL(data.Species.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		var species jsonSpecies
+		err = readJSON(filepath.Join(dir, "pokemon-species", fmt.Sprint(speciesID), "index.json"), &species)
+		if err != nil {
+			return nil, err
+		}
+
+		pokemon := Pokemon{
+			ID:     speciesID,
+			Name:   data.Species.Name,
+			Height: data.Height / 10,
+			Weight: data.Weight / 10,
+		}
+
+		pokemon.Generation, err = idFromURL(species.Generation.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		for _, name := range species.Names {
+			if name.Language.Name == englishLanguage {
+				pokemon.Name = name.Name
+			}
+		}
+
+		for _, genus := range species.Genera {
+			if genus.Language.Name == englishLanguage {
+				pokemon.Genus = genus.Genus
+			}
+		}
+
+		for _, entry := range species.FlavorTextEntries {
+			if entry.Language.Name == englishLanguage {
+				pokemon.Description = cleanText(entry.FlavorText)
+				break
+			}
+		}
+
+		sort.Slice(data.Types, func(i, j int) bool {
+			return data.Types[i].Slot < data.Types[j].Slot
+		})
+		for _, t := range data.Types {
+			pokemon.Types = append(pokemon.Types, typeName(t.Type.Name))
+		}
+
+		for _, stat := range data.Stats {
+			setStat(&pokemon.Stats, stat.Stat.Name, stat.BaseStat)
+		}
+
+		pokemons = append(pokemons, pokemon)
+	}
+
+	return pokemons, nil
+}
+
+func readJSON(path string, v interface{}) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package pokeapi reads a local copy of the PokeAPI data set, either the csv files of data/v2/csv
+// or the json files of data/api/v2 in the api-data repository. Nothing is fetched from the network
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+// DefaultImageURL is the official artwork of the PokeAPI sprites repository, %d is the pokedex number
+const DefaultImageURL = "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/official-artwork/%d.png"
+
+var errInvalidGeneration = errors.New("generation must be a number or a range like 1-3")
+
+// Pokemon is the default form of a pokemon species in the data set
+type Pokemon struct {
+	ID          int64
+	Name        string
+	Genus       string
+	Generation  int64
+	Types       []string
+	Height      float64
+	Weight      float64
+	Stats       entity.Stats
+	Description string
+}
+
+// Load reads the pokemons of the data set in dir sorted by pokedex number,
+// dir is the csv directory when it has pokemon.csv, otherwise data/api/v2 of api-data
+func Load(dir string) (pokemons []Pokemon, err error) {
+	if _, statErr := os.Stat(filepath.Join(dir, "pokemon.csv")); statErr == nil {
+		pokemons, err = LoadCSV(dir)
+	} else {
+		pokemons, err = LoadJSON(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pokemons, func(i, j int) bool {
+		return pokemons[i].ID < pokemons[j].ID
+	})
+
+	return pokemons, nil
+}
+
+// ParseGenerations parses comma separated generations, every item is either a number or a range like 1-3.
+// Empty value returns nil which selects every generation
+func ParseGenerations(value string) (generations map[int64]bool, err error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	generations = make(map[int64]bool)
+	for _, item := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(item), "-", 2)
+
+		from, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 64)
+		if err != nil || from <= 0 {
+			return nil, errInvalidGeneration
+		}
+
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 64)
+			if err != nil || to < from {
+				return nil, errInvalidGeneration
+			}
+		}
+
+		for generation := from; generation <= to; generation++ {
+			generations[generation] = true
+		}
+	}
+
+	return generations, nil
+}
+
+// Filter returns the pokemons of the generations, nil generations keeps every pokemon
+func Filter(pokemons []Pokemon, generations map[int64]bool) (results []Pokemon) {
+	if generations == nil {
+		return pokemons
+	}
+
+	for _, pokemon := range pokemons {
+		if generations[pokemon.Generation] {
+			results = append(results, pokemon)
+		}
+	}
+
+	return results
+}
+
+// typeName returns the name of type identifier the way types are named in the pokedex
+func typeName(identifier string) string {
+	return strings.ToUpper(identifier)
+}
+
+// cleanText joins the lines of flavor text, the data set keeps the line and page breaks of the games
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// setStat stores the base stat by its identifier, stats the pokedex has no field for are ignored
+func setStat(stats *entity.Stats, identifier string, value int64) {
+	switch identifier {
+	case "hp":
+		stats.HP = value
+	case "attack":
+		stats.Attack = value
+	case "defense":
+		stats.Def = value
+	case "speed":
+		stats.Speed = value
+	}
+}
+
+// idFromURL returns the id at the end of resource url like /api/v2/generation/1/
+func idFromURL(url string) (id int64, err error) {
+	segments := strings.Split(strings.Trim(url, "/"), "/")
+	id, err = strconv.ParseInt(segments[len(segments)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("no id at the end of %s", url)
+	}
+
+	return id, nil
+}
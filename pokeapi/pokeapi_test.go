@@ -0,0 +1,175 @@
+package pokeapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+var (
+	bulbasaur = Pokemon{
+		ID:          1,
+		Name:        "Bulbasaur",
+		Genus:       "Seed Pokémon",
+		Generation:  1,
+		Types:       []string{"GRASS", "POISON"},
+		Height:      0.7,
+		Weight:      6.9,
+		Stats:       entity.Stats{HP: 45, Attack: 49, Def: 49, Speed: 45},
+		Description: "A strange seed was planted on its back at birth.",
+	}
+	chikorita = Pokemon{
+		ID:          152,
+		Name:        "Chikorita",
+		Genus:       "Leaf Pokémon",
+		Generation:  2,
+		Types:       []string{"GRASS"},
+		Height:      0.9,
+		Weight:      6.4,
+		Stats:       entity.Stats{HP: 45, Attack: 49, Def: 65, Speed: 45},
+		Description: "A sweet aroma gently wafts from the leaf on its head.",
+	}
+)
+
+func TestLoad(t *testing.T) {
+	type args struct {
+		dir string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantPokemons []Pokemon
+		wantErr      bool
+	}{
+		{
+			name: "success csv",
+			args: args{
+				dir: "testdata/csv",
+			},
+			wantPokemons: []Pokemon{bulbasaur, chikorita},
+			wantErr:      false,
+		},
+		{
+			name: "success json",
+			args: args{
+				dir: "testdata/json",
+			},
+			wantPokemons: []Pokemon{bulbasaur, chikorita},
+			wantErr:      false,
+		},
+		{
+			name: "failed no data set",
+			args: args{
+				dir: "testdata",
+			},
+			wantPokemons: nil,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPokemons, err := Load(tt.args.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotPokemons, tt.wantPokemons) {
+				t.Errorf("Load() = %v, want %v", gotPokemons, tt.wantPokemons)
+			}
+		})
+	}
+}
+
+func TestParseGenerations(t *testing.T) {
+	type args struct {
+		value string
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantGenerations map[int64]bool
+		wantErr         bool
+	}{
+		{
+			name: "success every generation",
+			args: args{
+				value: "",
+			},
+			wantGenerations: nil,
+			wantErr:         false,
+		},
+		{
+			name: "success list and range",
+			args: args{
+				value: "1, 3-5",
+			},
+			wantGenerations: map[int64]bool{1: true, 3: true, 4: true, 5: true},
+			wantErr:         false,
+		},
+		{
+			name: "failed not a number",
+			args: args{
+				value: "first",
+			},
+			wantGenerations: nil,
+			wantErr:         true,
+		},
+		{
+			name: "failed reversed range",
+			args: args{
+				value: "3-1",
+			},
+			wantGenerations: nil,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGenerations, err := ParseGenerations(tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGenerations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotGenerations, tt.wantGenerations) {
+				t.Errorf("ParseGenerations() = %v, want %v", gotGenerations, tt.wantGenerations)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	type args struct {
+		pokemons    []Pokemon
+		generations map[int64]bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantResults []Pokemon
+	}{
+		{
+			name: "success every generation",
+			args: args{
+				pokemons:    []Pokemon{bulbasaur, chikorita},
+				generations: nil,
+			},
+			wantResults: []Pokemon{bulbasaur, chikorita},
+		},
+		{
+			name: "success selected generation",
+			args: args{
+				pokemons:    []Pokemon{bulbasaur, chikorita},
+				generations: map[int64]bool{2: true},
+			},
+			wantResults: []Pokemon{chikorita},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotResults := Filter(tt.args.pokemons, tt.args.generations); !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("Filter() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Package pokedexfile encodes and decodes the pokemons of pokedex export and import as csv, json or ndjson
+package pokedexfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/validation"
+)
+
+// formats of pokedex export and import
+const (
+	JSON   = "json"
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+// typeSeparator joins the type names of a pokemon in a single csv cell
+const typeSeparator = "|"
+
+// Header is the header of csv export, import accepts the same columns in any order
+var Header = []string{"id", "name", "species", "types", "catched", "image_url", "description", "weight", "height", "hp", "attack", "def", "speed"}
+
+var contentTypes = map[string]string{
+	JSON:   "application/json",
+	CSV:    "text/csv",
+	NDJSON: "application/x-ndjson",
+}
+
+var (
+	ErrInvalidFormat   = errors.New("format must be csv, json or ndjson")
+	ErrCSVNameRequired = apperror.New(apperror.Validation, "name_column_required", "csv header must have the name column")
+)
+
+// ParseFormat returns the format of the name, json is the default format
+func ParseFormat(name string) (format string, err error) {
+	switch name {
+	case "":
+		return JSON, nil
+	case JSON, CSV, NDJSON:
+		return name, nil
+	}
+
+	return "", ErrInvalidFormat
+}
+
+// ContentType returns the media type of the format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Record returns the csv row of the pokemon in the order of Header
+func Record(data entity.PokemonDetail) []string {
+	return []string{
+		strconv.FormatInt(data.ID, 10),
+		data.Name,
+		data.Species,
+		strings.Join(data.Types, typeSeparator),
+		strconv.FormatInt(data.Catched, 10),
+		data.ImageURL,
+		data.Description,
+		strconv.FormatFloat(data.Weight, 'f', -1, 64),
+		strconv.FormatFloat(data.Height, 'f', -1, 64),
+		strconv.FormatInt(data.Stats.HP, 10),
+		strconv.FormatInt(data.Stats.Attack, 10),
+		strconv.FormatInt(data.Stats.Def, 10),
+		strconv.FormatInt(data.Stats.Speed, 10),
+	}
+}
+
+// Decode decodes the pokemons of r in the format
+func Decode(r io.Reader, format string) (results []entity.ImportRow, err error) {
+	switch format {
+	case CSV:
+		return decodeCSV(r)
+	case NDJSON:
+		decoder := json.NewDecoder(r)
+		for {
+			var pokemon entity.ImportRow
+			err = decoder.Decode(&pokemon)
+			if err == io.EOF {
+				return results, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, pokemon)
+		}
+	}
+
+	err = json.NewDecoder(r).Decode(&results)
+	return results, err
+}
+
+// decodeCSV decodes the csv rows by the columns of the header so columns can be in any order and be left out,
+// a cell that is not a valid number fails only its row so the other rows can still be imported
+func decodeCSV(r io.Reader) (results []entity.ImportRow, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, ErrCSVNameRequired
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		var v validation.Validator
+		integer := func(column string) int64 {
+			value, err := strconv.ParseInt(cell(column), 10, 64)
+			v.Check(err == nil || cell(column) == "", column, "invalid", fmt.Sprintf("%s must be an integer", column))
+			return value
+		}
+		number := func(column string) float64 {
+			value, err := strconv.ParseFloat(cell(column), 64)
+			v.Check(err == nil || cell(column) == "", column, "invalid", fmt.Sprintf("%s must be a number", column))
+			return value
+		}
+
+		var types []string
+		for _, name := range strings.Split(cell("types"), typeSeparator) {
+			if name = strings.TrimSpace(name); name != "" {
+				types = append(types, name)
+			}
+		}
+
+		pokemon := entity.PokemonDetail{
+			ID:          integer("id"),
+			Name:        cell("name"),
+			Species:     cell("species"),
+			Types:       types,
+			Catched:     integer("catched"),
+			ImageURL:    cell("image_url"),
+			Description: cell("description"),
+			Weight:      number("weight"),
+			Height:      number("height"),
+			Stats: entity.Stats{
+				HP:     integer("hp"),
+				Attack: integer("attack"),
+				Def:    integer("def"),
+				Speed:  integer("speed"),
+			},
+		}
+		results = append(results, entity.ImportRow{PokemonDetail: pokemon, Err: v.Err()})
+	}
+
+	return results, nil
+}
+
+// Encoder writes every pokemon in the format as soon as it is encoded so exports can be streamed
+type Encoder struct {
+	w       io.Writer
+	format  string
+	csv     *csv.Writer
+	started bool
+	count   int
+}
+
+func NewEncoder(w io.Writer, format string) *Encoder {
+	return &Encoder{
+		w:      w,
+		format: format,
+		csv:    csv.NewWriter(w),
+	}
+}
+
+// start writes what comes before the first pokemon
+func (e *Encoder) start() (err error) {
+	e.started = true
+
+	switch e.format {
+	case CSV:
+		return e.csv.Write(Header)
+	case JSON:
+		_, err = io.WriteString(e.w, "[")
+	}
+
+	return err
+}
+
+// Encode writes the pokemon
+func (e *Encoder) Encode(data entity.PokemonDetail) (err error) {
+	if !e.started {
+		err = e.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	switch e.format {
+	case CSV:
+		err = e.csv.Write(Record(data))
+		if err == nil {
+			e.csv.Flush()
+			err = e.csv.Error()
+		}
+	case NDJSON:
+		err = json.NewEncoder(e.w).Encode(data)
+	default:
+		if e.count > 0 {
+			_, err = io.WriteString(e.w, ",")
+		}
+		if err == nil {
+			err = json.NewEncoder(e.w).Encode(data)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	e.count++
+	return nil
+}
+
+// Close ends the pokemons, a file without pokemon still has the header of the format
+func (e *Encoder) Close() (err error) {
+	if !e.started {
+		err = e.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	switch e.format {
+	case CSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case JSON:
+		_, err = io.WriteString(e.w, "]")
+	}
+
+	return err
+}
@@ -0,0 +1,139 @@
+package pokedexfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func TestRecord(t *testing.T) {
+	type args struct {
+		data entity.PokemonDetail
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "success",
+			args: args{
+				data: entity.PokemonDetail{
+					ID:          1,
+					Name:        "Bulbasour",
+					Species:     "pokemon",
+					Types:       []string{"GRASS", "POISON"},
+					Description: "seed, pokemon",
+					Weight:      6.9,
+					Height:      0.7,
+					Stats:       entity.Stats{HP: 45, Attack: 49, Def: 49, Speed: 45},
+				},
+			},
+			want: []string{"1", "Bulbasour", "pokemon", "GRASS|POISON", "0", "", "seed, pokemon", "6.9", "0.7", "45", "49", "49", "45"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Record(tt.args.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Record() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type args struct {
+		body   string
+		format string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantResults []entity.ImportRow
+		wantErr     bool
+	}{
+		{
+			name: "success csv",
+			args: args{
+				body:   "name,types,weight,id\nBulbasour,GRASS | POISON,6.9,1\nIvysaur,,,\n",
+				format: CSV,
+			},
+			wantResults: []entity.ImportRow{
+				{PokemonDetail: entity.PokemonDetail{ID: 1, Name: "Bulbasour", Types: []string{"GRASS", "POISON"}, Weight: 6.9}},
+				{PokemonDetail: entity.PokemonDetail{Name: "Ivysaur"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success json",
+			args: args{
+				body:   `[{"name":"Bulbasour","types":["GRASS"]}]`,
+				format: JSON,
+			},
+			wantResults: []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Bulbasour", Types: []string{"GRASS"}}}},
+			wantErr:     false,
+		},
+		{
+			name: "success ndjson",
+			args: args{
+				body:   "{\"name\":\"Bulbasour\"}\n{\"name\":\"Ivysaur\"}\n",
+				format: NDJSON,
+			},
+			wantResults: []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Bulbasour"}}, {PokemonDetail: entity.PokemonDetail{Name: "Ivysaur"}}},
+			wantErr:     false,
+		},
+		{
+			name: "success csv with invalid number",
+			args: args{
+				body:   "name,weight,hp\nBulbasour,heavy,many\nIvysaur,13,60\n",
+				format: CSV,
+			},
+			wantResults: []entity.ImportRow{
+				{PokemonDetail: entity.PokemonDetail{Name: "Bulbasour"}, Err: &apperror.Error{
+					Kind:    apperror.Validation,
+					Code:    "validation_failed",
+					Message: "weight must be a number, hp must be an integer",
+					Fields: []apperror.FieldError{
+						{Field: "weight", Code: "invalid", Message: "weight must be a number"},
+						{Field: "hp", Code: "invalid", Message: "hp must be an integer"},
+					},
+				}},
+				{PokemonDetail: entity.PokemonDetail{Name: "Ivysaur", Weight: 13, Stats: entity.Stats{HP: 60}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed csv without name column",
+			args: args{
+				body:   "species\npokemon\n",
+				format: CSV,
+			},
+			wantResults: nil,
+			wantErr:     true,
+		},
+		{
+			name: "failed ndjson",
+			args: args{
+				body:   "{\"name\":\"Bulbasour\"}\n{",
+				format: NDJSON,
+			},
+			wantResults: nil,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResults, err := Decode(strings.NewReader(tt.args.body), tt.args.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("Decode() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package apikeyrepository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+type APIKeyRepository struct {
+	APIKeyDB *sql.DB
+}
+
+type APIKeyRepositoryItf interface {
+	CreateAPIKeyDB(ctx context.Context, data entity.APIKey) (id int64, err error)
+	GetAllAPIKeysDB(ctx context.Context) (results []entity.APIKey, err error)
+	GetAPIKeyByHashDB(ctx context.Context, keyHash string) (result entity.APIKey, err error)
+	GetAPIKeyByIDDB(ctx context.Context, id int64) (result entity.APIKey, err error)
+	UpdateAPIKeyLastUsedDB(ctx context.Context, id int64) (err error)
+	RevokeAPIKeyDB(ctx context.Context, id int64) (err error)
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepositoryItf {
+	return &APIKeyRepository{
+		APIKeyDB: db,
+	}
+}
+
+func (ak *APIKeyRepository) CreateAPIKeyDB(ctx context.Context, data entity.APIKey) (id int64, err error) {
+	row, err := transaction.Conn(ctx, ak.APIKeyDB).ExecContext(ctx, InsertAPIKeyQuery, data.Name, data.Prefix, data.KeyHash, joinScopes(data.Scopes), data.CreatedBy, data.ExpiresAt)
+	if err != nil {
+		return id, err
+	}
+
+	id, err = row.LastInsertId()
+	if err != nil {
+		return id, err
+	}
+
+	return id, err
+}
+
+func (ak *APIKeyRepository) GetAllAPIKeysDB(ctx context.Context) (results []entity.APIKey, err error) {
+	rows, err := transaction.Conn(ctx, ak.APIKeyDB).QueryContext(ctx, GetAllAPIKeysQuery)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result, err := scanAPIKey(rows)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+func (ak *APIKeyRepository) GetAPIKeyByHashDB(ctx context.Context, keyHash string) (result entity.APIKey, err error) {
+	return scanAPIKey(transaction.Conn(ctx, ak.APIKeyDB).QueryRowContext(ctx, GetAPIKeyByHashQuery, keyHash))
+}
+
+func (ak *APIKeyRepository) GetAPIKeyByIDDB(ctx context.Context, id int64) (result entity.APIKey, err error) {
+	return scanAPIKey(transaction.Conn(ctx, ak.APIKeyDB).QueryRowContext(ctx, GetAPIKeyByIDQuery, id))
+}
+
+func (ak *APIKeyRepository) UpdateAPIKeyLastUsedDB(ctx context.Context, id int64) (err error) {
+	_, err = transaction.Conn(ctx, ak.APIKeyDB).ExecContext(ctx, UpdateAPIKeyLastUsedQuery, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// RevokeAPIKeyDB revokes the key, sql.ErrNoRows is returned when the key does not exist or is already revoked
+func (ak *APIKeyRepository) RevokeAPIKeyDB(ctx context.Context, id int64) (err error) {
+	row, err := transaction.Conn(ctx, ak.APIKeyDB).ExecContext(ctx, RevokeAPIKeyQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (result entity.APIKey, err error) {
+	var scopes string
+	err = row.Scan(&result.ID, &result.Name, &result.Prefix, &result.KeyHash, &scopes, &result.CreatedBy, &result.ExpiresAt, &result.LastUsedAt, &result.RevokedAt, &result.CreatedAt)
+	if err != nil {
+		return result, err
+	}
+
+	result.Scopes = splitScopes(scopes)
+	return result, err
+}
+
+// scopes are stored as comma separated permissions
+func joinScopes(scopes []enum.Permission) string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, scope.String())
+	}
+
+	return strings.Join(values, ",")
+}
+
+func splitScopes(value string) (scopes []enum.Permission) {
+	for _, scope := range strings.Split(value, ",") {
+		if scope != "" {
+			scopes = append(scopes, enum.Permission(scope))
+		}
+	}
+
+	return scopes
+}
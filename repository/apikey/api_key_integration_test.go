@@ -0,0 +1,59 @@
+package apikeyrepository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/winartodev/go-pokedex/dbtest"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+func TestAPIKeyRepository_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	ak := NewAPIKeyRepository(db)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	scopes := []enum.Permission{enum.PokemonRead, enum.TypeRead}
+
+	id, err := ak.CreateAPIKeyDB(ctx, entity.APIKey{
+		Name:      "ci",
+		Prefix:    "pk_ci",
+		KeyHash:   "hash",
+		Scopes:    scopes,
+		CreatedBy: dbtest.SeedUser(t, db, "budi"),
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("APIKeyRepository.CreateAPIKeyDB() error = %v", err)
+	}
+
+	key, err := ak.GetAPIKeyByHashDB(ctx, "hash")
+	if err != nil {
+		t.Fatalf("APIKeyRepository.GetAPIKeyByHashDB() error = %v", err)
+	}
+	if key.ID != id || !reflect.DeepEqual(key.Scopes, scopes) || key.ExpiresAt == nil || !dbtest.SameSecond(*key.ExpiresAt, expiresAt) ||
+		key.LastUsedAt != nil || key.RevokedAt != nil || time.Since(key.CreatedAt) > time.Minute {
+		t.Errorf("APIKeyRepository.GetAPIKeyByHashDB() = %+v, want expires at %v", key, expiresAt)
+	}
+
+	err = ak.UpdateAPIKeyLastUsedDB(ctx, id)
+	if err != nil {
+		t.Fatalf("APIKeyRepository.UpdateAPIKeyLastUsedDB() error = %v", err)
+	}
+
+	err = ak.RevokeAPIKeyDB(ctx, id)
+	if err != nil {
+		t.Fatalf("APIKeyRepository.RevokeAPIKeyDB() error = %v", err)
+	}
+
+	keys, err := ak.GetAllAPIKeysDB(ctx)
+	if err != nil {
+		t.Fatalf("APIKeyRepository.GetAllAPIKeysDB() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].LastUsedAt == nil || keys[0].RevokedAt == nil {
+		t.Errorf("APIKeyRepository.GetAllAPIKeysDB() = %+v, want used and revoked key", keys)
+	}
+}
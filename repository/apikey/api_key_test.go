@@ -0,0 +1,503 @@
+package apikeyrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+var (
+	apiKeyColumns = []string{"id", "name", "prefix", "key_hash", "scopes", "created_by", "expires_at", "last_used_at", "revoked_at", "created_at"}
+	apiKeyExpires = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	apiKeyCreated = time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	apiKey        = entity.APIKey{
+		ID:        1,
+		Name:      "pokemon sync",
+		Prefix:    "pk_abcd1234",
+		KeyHash:   "hash",
+		Scopes:    []enum.Permission{enum.PokemonRead, enum.PokemonWrite},
+		CreatedBy: 1,
+		ExpiresAt: &apiKeyExpires,
+		CreatedAt: apiKeyCreated,
+	}
+)
+
+func TestNewAPIKeyRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want APIKeyRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &APIKeyRepository{
+				APIKeyDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAPIKeyRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAPIKeyRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_CreateAPIKeyDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertAPIKeyQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.APIKey
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantId  int64
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: apiKey,
+			},
+			wantId:  1,
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "pokemon:read,pokemon:write", apiKey.CreatedBy, apiKey.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: apiKey,
+			},
+			wantId:  0,
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "pokemon:read,pokemon:write", apiKey.CreatedBy, apiKey.ExpiresAt).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			gotId, err := ak.CreateAPIKeyDB(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.CreateAPIKeyDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("APIKeyRepository.CreateAPIKeyDB() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetAllAPIKeysDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetAllAPIKeysQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.APIKey
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: []entity.APIKey{apiKey},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(
+					sqlmock.NewRows(apiKeyColumns).
+						AddRow(apiKey.ID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "pokemon:read,pokemon:write", apiKey.CreatedBy, apiKeyExpires, nil, nil, apiKeyCreated),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			gotResults, err := ak.GetAllAPIKeysDB(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.GetAllAPIKeysDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("APIKeyRepository.GetAllAPIKeysDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetAPIKeyByHashDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetAPIKeyByHashQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		keyHash string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.APIKey
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				keyHash: "hash",
+			},
+			wantResult: apiKey,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash").WillReturnRows(
+					sqlmock.NewRows(apiKeyColumns).
+						AddRow(apiKey.ID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "pokemon:read,pokemon:write", apiKey.CreatedBy, apiKeyExpires, nil, nil, apiKeyCreated),
+				)
+			},
+		},
+		{
+			name: "failed not found",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				keyHash: "hash",
+			},
+			wantResult: entity.APIKey{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			gotResult, err := ak.GetAPIKeyByHashDB(tt.args.ctx, tt.args.keyHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.GetAPIKeyByHashDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("APIKeyRepository.GetAPIKeyByHashDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetAPIKeyByIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetAPIKeyByIDQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.APIKey
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: apiKey,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows(apiKeyColumns).
+						AddRow(apiKey.ID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "pokemon:read,pokemon:write", apiKey.CreatedBy, apiKeyExpires, nil, nil, apiKeyCreated),
+				)
+			},
+		},
+		{
+			name: "failed not found",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: entity.APIKey{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			gotResult, err := ak.GetAPIKeyByIDDB(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.GetAPIKeyByIDDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("APIKeyRepository.GetAPIKeyByIDDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_UpdateAPIKeyLastUsedDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateAPIKeyLastUsedQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			if err := ak.UpdateAPIKeyLastUsedDB(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.UpdateAPIKeyLastUsedDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_RevokeAPIKeyDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := RevokeAPIKeyQuery
+
+	type fields struct {
+		APIKeyDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		wantErrIs error
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed already revoked",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr:   true,
+			wantErrIs: sql.ErrNoRows,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				APIKeyDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyRepository{
+				APIKeyDB: tt.fields.APIKeyDB,
+			}
+			err := ak.RevokeAPIKeyDB(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyRepository.RevokeAPIKeyDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("APIKeyRepository.RevokeAPIKeyDB() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
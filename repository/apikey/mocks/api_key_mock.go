@@ -0,0 +1,144 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package apikeyrepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// APIKeyRepositoryItf is an autogenerated mock type for the APIKeyRepositoryItf type
+type APIKeyRepositoryItf struct {
+	mock.Mock
+}
+
+// CreateAPIKeyDB provides a mock function with given fields: ctx, data
+func (_m *APIKeyRepositoryItf) CreateAPIKeyDB(ctx context.Context, data entity.APIKey) (int64, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, entity.APIKey) int64); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.APIKey) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeyByHashDB provides a mock function with given fields: ctx, keyHash
+func (_m *APIKeyRepositoryItf) GetAPIKeyByHashDB(ctx context.Context, keyHash string) (entity.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	var r0 entity.APIKey
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		r0 = ret.Get(0).(entity.APIKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeyByIDDB provides a mock function with given fields: ctx, id
+func (_m *APIKeyRepositoryItf) GetAPIKeyByIDDB(ctx context.Context, id int64) (entity.APIKey, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.APIKey
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.APIKey); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.APIKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllAPIKeysDB provides a mock function with given fields: ctx
+func (_m *APIKeyRepositoryItf) GetAllAPIKeysDB(ctx context.Context) ([]entity.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.APIKey
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKeyDB provides a mock function with given fields: ctx, id
+func (_m *APIKeyRepositoryItf) RevokeAPIKeyDB(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAPIKeyLastUsedDB provides a mock function with given fields: ctx, id
+func (_m *APIKeyRepositoryItf) UpdateAPIKeyLastUsedDB(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyRepositoryItf creates a new instance of APIKeyRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyRepositoryItf(t mockConstructorTestingTNewAPIKeyRepositoryItf) *APIKeyRepositoryItf {
+	mock := &APIKeyRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+package apikeyrepository
+
+const (
+	InsertAPIKeyQuery = `
+		INSERT INTO pokedex.api_keys
+		(
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			created_by,
+			expires_at
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	GetAllAPIKeysQuery = `
+		SELECT
+			id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			created_by,
+			expires_at,
+			last_used_at,
+			revoked_at,
+			created_at
+		FROM pokedex.api_keys
+		ORDER BY id
+	`
+
+	GetAPIKeyByHashQuery = `
+		SELECT
+			id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			created_by,
+			expires_at,
+			last_used_at,
+			revoked_at,
+			created_at
+		FROM pokedex.api_keys
+		WHERE key_hash = ?
+	`
+
+	GetAPIKeyByIDQuery = `
+		SELECT
+			id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			created_by,
+			expires_at,
+			last_used_at,
+			revoked_at,
+			created_at
+		FROM pokedex.api_keys
+		WHERE id = ?
+	`
+
+	UpdateAPIKeyLastUsedQuery = `
+		UPDATE pokedex.api_keys
+		SET
+			last_used_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	RevokeAPIKeyQuery = `
+		UPDATE pokedex.api_keys
+		SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND revoked_at IS NULL
+	`
+)
@@ -0,0 +1,90 @@
+package auditrepository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+type AuditRepository struct {
+	AuditDB *sql.DB
+}
+
+// AuditRepositoryItf is append only, audit logs are never updated nor deleted
+type AuditRepositoryItf interface {
+	CreateAuditLogDB(ctx context.Context, data entity.AuditLog) (err error)
+	GetAuditLogsDB(ctx context.Context, filter entity.AuditFilter) (results []entity.AuditLog, err error)
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepositoryItf {
+	return &AuditRepository{
+		AuditDB: db,
+	}
+}
+
+func (ar *AuditRepository) CreateAuditLogDB(ctx context.Context, data entity.AuditLog) (err error) {
+	_, err = transaction.Conn(ctx, ar.AuditDB).ExecContext(ctx, InsertAuditLogQuery, data.ActorID, data.APIKeyID, data.Actor, data.Action, data.Resource, data.ResourceID, string(data.Before), string(data.After))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// GetAuditLogsDB returns the audit logs matching the filter, the newest first
+func (ar *AuditRepository) GetAuditLogsDB(ctx context.Context, filter entity.AuditFilter) (results []entity.AuditLog, err error) {
+	query := GetAuditLogQuery
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Resource != "" {
+		conditions = append(conditions, `resource = ?`)
+		args = append(args, filter.Resource)
+	}
+
+	if filter.Actor != "" {
+		conditions = append(conditions, `actor = ?`)
+		args = append(args, filter.Actor)
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, *filter.Since)
+	}
+
+	if len(conditions) > 0 {
+		query += `WHERE ` + strings.Join(conditions, ` AND `) + ` `
+	}
+
+	query += `ORDER BY id DESC `
+
+	if filter.Limit > 0 {
+		query += `LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := transaction.Conn(ctx, ar.AuditDB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row entity.AuditLog
+		var before, after string
+
+		err := rows.Scan(&row.ID, &row.ActorID, &row.APIKeyID, &row.Actor, &row.Action, &row.Resource, &row.ResourceID, &before, &after, &row.CreatedAt)
+		if err != nil {
+			return results, err
+		}
+
+		row.Before = []byte(before)
+		row.After = []byte(after)
+		results = append(results, row)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,229 @@
+package auditrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+var (
+	auditColumns = []string{"id", "actor_id", "api_key_id", "actor", "action", "resource", "resource_id", "before_snapshot", "after_snapshot", "created_at"}
+	auditTime    = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	auditLog     = entity.AuditLog{
+		ID:         1,
+		ActorID:    1,
+		Actor:      "admin",
+		Action:     "update",
+		Resource:   "type",
+		ResourceID: 1,
+		Before:     json.RawMessage(`{"id":1,"name":"FIRE"}`),
+		After:      json.RawMessage(`{"id":1,"name":"ICE"}`),
+		CreatedAt:  auditTime,
+	}
+)
+
+func TestNewAuditRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want AuditRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &AuditRepository{
+				AuditDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAuditRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAuditRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditRepository_CreateAuditLogDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertAuditLogQuery
+
+	type fields struct {
+		AuditDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.AuditLog
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				AuditDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: auditLog,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(auditLog.ActorID, auditLog.APIKeyID, auditLog.Actor, auditLog.Action, auditLog.Resource, auditLog.ResourceID, string(auditLog.Before), string(auditLog.After)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				AuditDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: auditLog,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(auditLog.ActorID, auditLog.APIKeyID, auditLog.Actor, auditLog.Action, auditLog.Resource, auditLog.ResourceID, string(auditLog.Before), string(auditLog.After)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ar := &AuditRepository{
+				AuditDB: tt.fields.AuditDB,
+			}
+			if err := ar.CreateAuditLogDB(tt.args.ctx, tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("AuditRepository.CreateAuditLogDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuditRepository_GetAuditLogsDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	since := auditTime.Add(-time.Hour)
+
+	type fields struct {
+		AuditDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		filter entity.AuditFilter
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.AuditLog
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success without filter",
+			fields: fields{
+				AuditDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.AuditFilter{},
+			},
+			wantResults: []entity.AuditLog{auditLog},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetAuditLogQuery + `ORDER BY id DESC `)).WillReturnRows(
+					sqlmock.NewRows(auditColumns).
+						AddRow(auditLog.ID, auditLog.ActorID, auditLog.APIKeyID, auditLog.Actor, auditLog.Action, auditLog.Resource, auditLog.ResourceID, string(auditLog.Before), string(auditLog.After), auditLog.CreatedAt),
+				)
+			},
+		},
+		{
+			name: "success with filter",
+			fields: fields{
+				AuditDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.AuditFilter{Resource: "type", Actor: "admin", Since: &since, Limit: 10, Offset: 20},
+			},
+			wantResults: []entity.AuditLog{auditLog},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetAuditLogQuery+`WHERE resource = ? AND actor = ? AND created_at >= ? ORDER BY id DESC LIMIT ? OFFSET ?`)).
+					WithArgs("type", "admin", since, int64(10), int64(20)).
+					WillReturnRows(
+						sqlmock.NewRows(auditColumns).
+							AddRow(auditLog.ID, auditLog.ActorID, auditLog.APIKeyID, auditLog.Actor, auditLog.Action, auditLog.Resource, auditLog.ResourceID, string(auditLog.Before), string(auditLog.After), auditLog.CreatedAt),
+					)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				AuditDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.AuditFilter{},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetAuditLogQuery + `ORDER BY id DESC `)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ar := &AuditRepository{
+				AuditDB: tt.fields.AuditDB,
+			}
+			gotResults, err := ar.GetAuditLogsDB(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AuditRepository.GetAuditLogsDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("AuditRepository.GetAuditLogsDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
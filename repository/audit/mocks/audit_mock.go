@@ -0,0 +1,67 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package auditrepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// AuditRepositoryItf is an autogenerated mock type for the AuditRepositoryItf type
+type AuditRepositoryItf struct {
+	mock.Mock
+}
+
+// CreateAuditLogDB provides a mock function with given fields: ctx, data
+func (_m *AuditRepositoryItf) CreateAuditLogDB(ctx context.Context, data entity.AuditLog) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AuditLog) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditLogsDB provides a mock function with given fields: ctx, filter
+func (_m *AuditRepositoryItf) GetAuditLogsDB(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditLog, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.AuditLog
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AuditFilter) []entity.AuditLog); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AuditLog)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditRepositoryItf creates a new instance of AuditRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditRepositoryItf(t mockConstructorTestingTNewAuditRepositoryItf) *AuditRepositoryItf {
+	mock := &AuditRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,41 @@
+package auditrepository
+
+const (
+	InsertAuditLogQuery = `
+		INSERT INTO pokedex.audit_logs
+		(
+			actor_id,
+			api_key_id,
+			actor,
+			action,
+			resource,
+			resource_id,
+			before_snapshot,
+			after_snapshot
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	GetAuditLogQuery = `
+		SELECT
+			id,
+			actor_id,
+			api_key_id,
+			actor,
+			action,
+			resource,
+			resource_id,
+			before_snapshot,
+			after_snapshot,
+			created_at
+		FROM pokedex.audit_logs
+	`
+)
@@ -0,0 +1,79 @@
+package pokemonrepository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+)
+
+// typesExists finds the types of the listed pokemon, types in the trash don't count
+const typesExists = `
+	SELECT 1 FROM pokedex.pokemon_types AS pt
+	JOIN pokedex.types AS t ON t.id = pt.types_id AND t.deleted_at IS NULL
+	WHERE pt.pokemon_id = pokemons.id AND %s`
+
+// FilterFields are the fields pokemons can be filtered by
+var FilterFields = filter.Fields{
+	"name":         {Kind: filter.Text, Column: "pokemons.name"},
+	"species":      {Kind: filter.Text, Column: "pokemons.species"},
+	"catched":      {Kind: filter.Bool, Column: "pokemons.catched"},
+	"type":         {Kind: filter.Text, Column: "t.name", Exists: typesExists},
+	"type_id":      {Kind: filter.Number, Column: "t.id", Exists: typesExists},
+	"weight":       {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"weight"}},
+	"height":       {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"height"}},
+	"stats.hp":     {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"stats", "hp"}},
+	"stats.attack": {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"stats", "attack"}},
+	"stats.def":    {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"stats", "def"}},
+	"stats.speed":  {Kind: filter.Number, Column: "pokemons.metadata", Path: []string{"stats", "speed"}},
+}
+
+// SortColumns are the columns pokemons can be sorted by
+var SortColumns = map[string]string{
+	"id":      "pokemons.id",
+	"name":    "pokemons.name",
+	"species": "pokemons.species",
+	"catched": "pokemons.catched",
+}
+
+// typesJoin joins the types of the rows of GetPokemonQuery, types in the trash are joined as NULL so they don't count
+const typesJoin = `LEFT JOIN pokedex.types ON types.id = pokemon_types.types_id AND types.deleted_at IS NULL `
+
+// typeHaving returns the HAVING conditions of the types of a pokemon, GetPokemonQuery with typesJoin has a row for
+// every type of the pokemon and the rows are grouped by pokemon. TypeAll counts the distinct types found so a type
+// linked twice is not counted twice
+func typeHaving(data entity.PokemonFilterDB) (conditions []string, args []interface{}) {
+	if ids := distinct(data.TypeAll); len(ids) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`COUNT(DISTINCT CASE WHEN types.id IN (%s) THEN types.id END) = ?`, placeholders(len(ids))))
+		args = append(append(args, ids...), len(ids))
+	}
+
+	if ids := distinct(data.TypeAny); len(ids) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`COUNT(CASE WHEN types.id IN (%s) THEN 1 END) > 0`, placeholders(len(ids))))
+		args = append(args, ids...)
+	}
+
+	if ids := distinct(data.TypeNot); len(ids) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`COUNT(CASE WHEN types.id IN (%s) THEN 1 END) = 0`, placeholders(len(ids))))
+		args = append(args, ids...)
+	}
+
+	return conditions, args
+}
+
+func distinct(ids []int64) (results []interface{}) {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			results = append(results, id)
+		}
+	}
+
+	return results
+}
+
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
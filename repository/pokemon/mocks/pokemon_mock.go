@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	entity "github.com/winartodev/go-pokedex/entity"
+
+	time "time"
 )
 
 // PokemonRepositoryItf is an autogenerated mock type for the PokemonRepositoryItf type
@@ -35,13 +37,13 @@ func (_m *PokemonRepositoryItf) CreatePokemonDB(ctx context.Context, data entity
 	return r0, r1
 }
 
-// DeletePokemonByIDDB provides a mock function with given fields: ctx, id
-func (_m *PokemonRepositoryItf) DeletePokemonByIDDB(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// DeletePokemonByIDDB provides a mock function with given fields: ctx, id, version
+func (_m *PokemonRepositoryItf) DeletePokemonByIDDB(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,13 +51,13 @@ func (_m *PokemonRepositoryItf) DeletePokemonByIDDB(ctx context.Context, id int6
 	return r0
 }
 
-// GetAllPokemonByFilterDB provides a mock function with given fields: ctx, filter
-func (_m *PokemonRepositoryItf) GetAllPokemonByFilterDB(ctx context.Context, filter map[string]string) ([]entity.PokemonDB, error) {
-	ret := _m.Called(ctx, filter)
+// GetAllPokemonByFilterDB provides a mock function with given fields: ctx, data
+func (_m *PokemonRepositoryItf) GetAllPokemonByFilterDB(ctx context.Context, data entity.PokemonFilterDB) ([]entity.PokemonDB, error) {
+	ret := _m.Called(ctx, data)
 
 	var r0 []entity.PokemonDB
-	if rf, ok := ret.Get(0).(func(context.Context, map[string]string) []entity.PokemonDB); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PokemonFilterDB) []entity.PokemonDB); ok {
+		r0 = rf(ctx, data)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.PokemonDB)
@@ -63,8 +65,8 @@ func (_m *PokemonRepositoryItf) GetAllPokemonByFilterDB(ctx context.Context, fil
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, map[string]string) error); ok {
-		r1 = rf(ctx, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PokemonFilterDB) error); ok {
+		r1 = rf(ctx, data)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,6 +97,29 @@ func (_m *PokemonRepositoryItf) GetAllPokemonDB(ctx context.Context) ([]entity.P
 	return r0, r1
 }
 
+// GetDeletedPokemonDB provides a mock function with given fields: ctx
+func (_m *PokemonRepositoryItf) GetDeletedPokemonDB(ctx context.Context) ([]entity.PokemonDB, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.PokemonDB
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.PokemonDB); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonDB)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPokemonByIDDB provides a mock function with given fields: ctx, id
 func (_m *PokemonRepositoryItf) GetPokemonByIDDB(ctx context.Context, id int64) (entity.PokemonDB, error) {
 	ret := _m.Called(ctx, id)
@@ -116,6 +141,78 @@ func (_m *PokemonRepositoryItf) GetPokemonByIDDB(ctx context.Context, id int64)
 	return r0, r1
 }
 
+// GetPokemonByNameDB provides a mock function with given fields: ctx, name
+func (_m *PokemonRepositoryItf) GetPokemonByNameDB(ctx context.Context, name string) (entity.PokemonDB, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 entity.PokemonDB
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.PokemonDB); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(entity.PokemonDB)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPokemonByTypeIDDB provides a mock function with given fields: ctx, typeID
+func (_m *PokemonRepositoryItf) GetPokemonByTypeIDDB(ctx context.Context, typeID int64) ([]entity.PokemonDB, error) {
+	ret := _m.Called(ctx, typeID)
+
+	var r0 []entity.PokemonDB
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.PokemonDB); ok {
+		r0 = rf(ctx, typeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonDB)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, typeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgePokemonDB provides a mock function with given fields: ctx, before
+func (_m *PokemonRepositoryItf) PurgePokemonDB(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestorePokemonDB provides a mock function with given fields: ctx, id
+func (_m *PokemonRepositoryItf) RestorePokemonDB(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePokemonDB provides a mock function with given fields: ctx, id, data
 func (_m *PokemonRepositoryItf) UpdatePokemonDB(ctx context.Context, id int64, data entity.PokemonDB) error {
 	ret := _m.Called(ctx, id, data)
@@ -130,6 +227,20 @@ func (_m *PokemonRepositoryItf) UpdatePokemonDB(ctx context.Context, id int64, d
 	return r0
 }
 
+// UpdatePokemonVersionDB provides a mock function with given fields: ctx, id, version
+func (_m *PokemonRepositoryItf) UpdatePokemonVersionDB(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPokemonRepositoryItf interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,8 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	"github.com/winartodev/go-pokedex/transaction"
 )
 
 type PokemonRepository struct {
@@ -14,11 +18,17 @@ type PokemonRepository struct {
 
 type PokemonRepositoryItf interface {
 	GetAllPokemonDB(ctx context.Context) (results []entity.PokemonDB, err error)
-	GetAllPokemonByFilterDB(ctx context.Context, filter map[string]string) (results []entity.PokemonDB, err error)
+	GetAllPokemonByFilterDB(ctx context.Context, data entity.PokemonFilterDB) (results []entity.PokemonDB, err error)
 	CreatePokemonDB(ctx context.Context, data entity.PokemonDB) (id int64, err error)
 	GetPokemonByIDDB(ctx context.Context, id int64) (result entity.PokemonDB, err error)
+	GetPokemonByNameDB(ctx context.Context, name string) (result entity.PokemonDB, err error)
 	UpdatePokemonDB(ctx context.Context, id int64, data entity.PokemonDB) (err error)
-	DeletePokemonByIDDB(ctx context.Context, id int64) (err error)
+	DeletePokemonByIDDB(ctx context.Context, id int64, version int64) (err error)
+	GetDeletedPokemonDB(ctx context.Context) (results []entity.PokemonDB, err error)
+	GetPokemonByTypeIDDB(ctx context.Context, typeID int64) (results []entity.PokemonDB, err error)
+	UpdatePokemonVersionDB(ctx context.Context, id int64, version int64) (err error)
+	RestorePokemonDB(ctx context.Context, id int64) (err error)
+	PurgePokemonDB(ctx context.Context, before time.Time) (err error)
 }
 
 func NewPokemonRepository(db *sql.DB) PokemonRepositoryItf {
@@ -28,7 +38,7 @@ func NewPokemonRepository(db *sql.DB) PokemonRepositoryItf {
 }
 
 func (pr *PokemonRepository) GetAllPokemonDB(ctx context.Context) (results []entity.PokemonDB, err error) {
-	rows, err := pr.PokemonDB.QueryContext(ctx, fmt.Sprintf("%s %s", GetPokemonQuery, `GROUP BY pokemons.id`))
+	rows, err := transaction.Conn(ctx, pr.PokemonDB).QueryContext(ctx, fmt.Sprintf("%s %s", GetPokemonQuery, `WHERE pokemons.deleted_at IS NULL GROUP BY pokemons.id`))
 	if err != nil {
 		return results, err
 	}
@@ -36,7 +46,7 @@ func (pr *PokemonRepository) GetAllPokemonDB(ctx context.Context) (results []ent
 	for rows.Next() {
 		var row entity.PokemonDB
 
-		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata)
+		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata, &row.Version)
 		if err != nil {
 			return results, err
 		}
@@ -48,7 +58,7 @@ func (pr *PokemonRepository) GetAllPokemonDB(ctx context.Context) (results []ent
 }
 
 func (pr *PokemonRepository) CreatePokemonDB(ctx context.Context, data entity.PokemonDB) (id int64, err error) {
-	row, err := pr.PokemonDB.ExecContext(ctx, InsertPokemonQuery, &data.Name, &data.Species, &data.Catched, &data.Metadata)
+	row, err := transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, InsertPokemonQuery, &data.Name, &data.Species, &data.Catched, &data.Metadata)
 	if err != nil {
 		return id, err
 	}
@@ -62,7 +72,7 @@ func (pr *PokemonRepository) CreatePokemonDB(ctx context.Context, data entity.Po
 }
 
 func (pr *PokemonRepository) GetPokemonByIDDB(ctx context.Context, id int64) (result entity.PokemonDB, err error) {
-	err = pr.PokemonDB.QueryRowContext(ctx, fmt.Sprintf(`%s %s`, GetPokemonQuery, `WHERE pokemons.id = ?`), id).Scan(&result.ID, &result.Name, &result.Species, &result.Catched, &result.Metadata)
+	err = transaction.Conn(ctx, pr.PokemonDB).QueryRowContext(ctx, fmt.Sprintf(`%s %s`, GetPokemonQuery, `WHERE pokemons.id = ? AND pokemons.deleted_at IS NULL`), id).Scan(&result.ID, &result.Name, &result.Species, &result.Catched, &result.Metadata, &result.Version)
 	if err != nil {
 		return result, err
 	}
@@ -70,44 +80,186 @@ func (pr *PokemonRepository) GetPokemonByIDDB(ctx context.Context, id int64) (re
 	return result, err
 }
 
+// GetPokemonByNameDB returns the pokemon with the name, names are compared by the case insensitive collation of the table.
+// Pokemons in the trash are also returned, DeletedAt is set for them
+func (pr *PokemonRepository) GetPokemonByNameDB(ctx context.Context, name string) (result entity.PokemonDB, err error) {
+	err = transaction.Conn(ctx, pr.PokemonDB).QueryRowContext(ctx, GetPokemonByNameQuery, name).Scan(&result.ID, &result.Name, &result.Species, &result.Catched, &result.Metadata, &result.Version, &result.DeletedAt)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// UpdatePokemonDB updates the pokemon when its version is still data.Version and increments the version,
+// sql.ErrNoRows is returned when the pokemon was changed in the meantime
 func (pr *PokemonRepository) UpdatePokemonDB(ctx context.Context, id int64, data entity.PokemonDB) (err error) {
-	_, err = pr.PokemonDB.ExecContext(ctx, UpdatePokemonQuery, &data.Name, &data.Species, &data.Catched, &data.Metadata, id)
+	row, err := transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, UpdatePokemonQuery, &data.Name, &data.Species, &data.Catched, &data.Metadata, id, &data.Version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return err
 }
 
-func (pr *PokemonRepository) DeletePokemonByIDDB(ctx context.Context, id int64) (err error) {
-	_, err = pr.PokemonDB.ExecContext(ctx, DeletePokemonQuery, id)
+// DeletePokemonByIDDB moves the pokemon to the trash when its version is still version,
+// sql.ErrNoRows is returned when the pokemon was changed in the meantime
+func (pr *PokemonRepository) DeletePokemonByIDDB(ctx context.Context, id int64, version int64) (err error) {
+	row, err := transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, DeletePokemonQuery, id, version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return err
 }
 
-func (pr *PokemonRepository) GetAllPokemonByFilterDB(ctx context.Context, filter map[string]string) (pokemons []entity.PokemonDB, err error) {
-	query := GetPokemonQuery
+// GetDeletedPokemonDB returns pokemons in the trash, the most recently deleted first
+func (pr *PokemonRepository) GetDeletedPokemonDB(ctx context.Context) (results []entity.PokemonDB, err error) {
+	rows, err := transaction.Conn(ctx, pr.PokemonDB).QueryContext(ctx, GetDeletedPokemonQuery)
+	if err != nil {
+		return results, err
+	}
 
-	query += fmt.Sprint(`WHERE pokemons.name LIKE '%`, filter["name"], `%'`)
+	for rows.Next() {
+		var row entity.PokemonDB
+
+		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata, &row.Version, &row.DeletedAt)
+		if err != nil {
+			return results, err
+		}
 
-	if filter["options"] != "" {
-		query += fmt.Sprintf(`AND pokemons.catched = %v `, filter["options"])
+		results = append(results, row)
 	}
 
-	if filter["type"] != "" {
-		query += fmt.Sprintf(`AND pokemon_types.types_id IN (%v) `, filter["type"])
+	return results, err
+}
+
+// GetPokemonByTypeIDDB returns the pokemons that have the type, in the trash or not
+func (pr *PokemonRepository) GetPokemonByTypeIDDB(ctx context.Context, typeID int64) (results []entity.PokemonDB, err error) {
+	rows, err := transaction.Conn(ctx, pr.PokemonDB).QueryContext(ctx, GetPokemonByTypeIDQuery, typeID)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row entity.PokemonDB
+
+		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata, &row.Version, &row.DeletedAt)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, row)
+	}
+
+	return results, rows.Err()
+}
+
+// UpdatePokemonVersionDB bumps the version of the pokemon when only its links changed,
+// sql.ErrNoRows is returned when the pokemon was changed in the meantime
+func (pr *PokemonRepository) UpdatePokemonVersionDB(ctx context.Context, id int64, version int64) (err error) {
+	row, err := transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, UpdatePokemonVersionQuery, id, version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// RestorePokemonDB takes the pokemon out of the trash, sql.ErrNoRows is returned when the pokemon is not in the trash
+func (pr *PokemonRepository) RestorePokemonDB(ctx context.Context, id int64) (err error) {
+	row, err := transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, RestorePokemonQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// PurgePokemonDB permanently deletes pokemons that were moved to the trash before the time
+func (pr *PokemonRepository) PurgePokemonDB(ctx context.Context, before time.Time) (err error) {
+	_, err = transaction.Conn(ctx, pr.PokemonDB).ExecContext(ctx, PurgePokemonQuery, before)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (pr *PokemonRepository) GetAllPokemonByFilterDB(ctx context.Context, data entity.PokemonFilterDB) (pokemons []entity.PokemonDB, err error) {
+	having, havingArgs := typeHaving(data)
+
+	query := GetPokemonQuery
+	if len(having) > 0 {
+		query += typesJoin
+	}
+	query += `WHERE pokemons.deleted_at IS NULL `
+
+	var args []interface{}
+	if data.Expr != nil {
+		var condition string
+		condition, args, err = filter.Compile(data.Expr, FilterFields, filter.MySQL)
+		if err != nil {
+			return pokemons, err
+		}
+
+		query += fmt.Sprintf(`AND %s `, condition)
 	}
 
 	query += `GROUP BY pokemons.id `
 
-	if filter["sort_by"] != "" && filter["order_by"] != "" {
-		query += fmt.Sprintf("ORDER BY %v %v", filter["sort_by"], filter["order_by"])
+	if len(having) > 0 {
+		query += fmt.Sprintf(`HAVING %s `, strings.Join(having, ` AND `))
+		args = append(args, havingArgs...)
+	}
+
+	// the sort column and order are checked by the usecase, unknown ones are ignored here so they never reach the query
+	if column, ok := SortColumns[data.SortBy]; ok {
+		order := `ASC`
+		if strings.EqualFold(data.OrderBy, `desc`) {
+			order = `DESC`
+		}
+		query += fmt.Sprintf(`ORDER BY %s %s`, column, order)
 	}
 
-	rows, err := pr.PokemonDB.QueryContext(ctx, query)
+	rows, err := transaction.Conn(ctx, pr.PokemonDB).QueryContext(ctx, query, args...)
 	if err != nil {
 		return pokemons, err
 	}
@@ -115,7 +267,7 @@ func (pr *PokemonRepository) GetAllPokemonByFilterDB(ctx context.Context, filter
 	for rows.Next() {
 		var row entity.PokemonDB
 
-		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata)
+		err := rows.Scan(&row.ID, &row.Name, &row.Species, &row.Catched, &row.Metadata, &row.Version)
 		if err != nil {
 			return pokemons, err
 		}
@@ -0,0 +1,185 @@
+package pokemonrepository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/dbtest"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	"github.com/winartodev/go-pokedex/seeding"
+)
+
+func TestPokemonRepository_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	seeded := dbtest.SeedFixture(t, db, seeding.FixtureBasic)
+	pr := NewPokemonRepository(db)
+	ctx := context.Background()
+
+	pokemons, err := pr.GetAllPokemonDB(ctx)
+	if err != nil {
+		t.Fatalf("PokemonRepository.GetAllPokemonDB() error = %v", err)
+	}
+	if len(pokemons) != len(seeded.Pokemons) {
+		t.Errorf("PokemonRepository.GetAllPokemonDB() = %d pokemons, want %d", len(pokemons), len(seeded.Pokemons))
+	}
+
+	bulbasaur, err := pr.GetPokemonByNameDB(ctx, "Bulbasaur")
+	if err != nil || bulbasaur.ID != seeded.Pokemons["Bulbasaur"] || bulbasaur.Version != 1 {
+		t.Fatalf("PokemonRepository.GetPokemonByNameDB() = %+v, %v", bulbasaur, err)
+	}
+
+	bulbasaur.Catched = 0
+	err = pr.UpdatePokemonDB(ctx, bulbasaur.ID, bulbasaur)
+	if err != nil {
+		t.Fatalf("PokemonRepository.UpdatePokemonDB() error = %v", err)
+	}
+
+	err = pr.DeletePokemonByIDDB(ctx, bulbasaur.ID, bulbasaur.Version)
+	if err == nil {
+		t.Errorf("PokemonRepository.DeletePokemonByIDDB() of a changed version error = nil")
+	}
+
+	err = pr.DeletePokemonByIDDB(ctx, bulbasaur.ID, bulbasaur.Version+1)
+	if err != nil {
+		t.Fatalf("PokemonRepository.DeletePokemonByIDDB() error = %v", err)
+	}
+
+	deleted, err := pr.GetDeletedPokemonDB(ctx)
+	if err != nil {
+		t.Fatalf("PokemonRepository.GetDeletedPokemonDB() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != bulbasaur.ID || deleted[0].DeletedAt == nil || deleted[0].Catched != 0 {
+		t.Errorf("PokemonRepository.GetDeletedPokemonDB() = %+v", deleted)
+	}
+}
+
+func TestPokemonRepository_GetAllPokemonByFilterDB_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	seeded := dbtest.SeedFixture(t, db, seeding.FixtureBasic)
+	pr := NewPokemonRepository(db)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "type",
+			input: "type:poison",
+			want:  []string{"Bulbasaur"},
+		},
+		{
+			name:  "metadata number",
+			input: "stats.hp>100",
+			want:  []string{"Wigglytuff"},
+		},
+		{
+			name:  "not type and name",
+			input: "NOT type:fire AND name~a",
+			want:  []string{"Bulbasaur"},
+		},
+		{
+			name:  "or of booleans and species",
+			input: "catched:true OR species:\"lizard pokemon\"",
+			want:  []string{"Bulbasaur", "Charmander"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := filter.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			pokemons, err := pr.GetAllPokemonByFilterDB(context.Background(), entity.PokemonFilterDB{Expr: expr, SortBy: "name"})
+			if err != nil {
+				t.Fatalf("PokemonRepository.GetAllPokemonByFilterDB() error = %v", err)
+			}
+
+			if got := names(pokemons); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PokemonRepository.GetAllPokemonByFilterDB() = %v, want %v", got, tt.want)
+			}
+			for _, pokemon := range pokemons {
+				if pokemon.ID != seeded.Pokemons[pokemon.Name] {
+					t.Errorf("PokemonRepository.GetAllPokemonByFilterDB() %s id = %d, want %d", pokemon.Name, pokemon.ID, seeded.Pokemons[pokemon.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestPokemonRepository_GetAllPokemonByFilterDB_Types_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	seeded := dbtest.SeedFixture(t, db, seeding.FixtureBasic)
+	pr := NewPokemonRepository(db)
+	normal, fire, poison := seeded.Types["NORMAL"], seeded.Types["FIRE"], seeded.Types["POISON"]
+
+	tests := []struct {
+		name    string
+		filter  entity.PokemonFilterDB
+		trashed []int64
+		want    []string
+	}{
+		{
+			name:   "having all types of dual type pokemon",
+			filter: entity.PokemonFilterDB{TypeAll: []int64{normal, poison}},
+			want:   []string{"Bulbasaur"},
+		},
+		{
+			name:   "having any type",
+			filter: entity.PokemonFilterDB{TypeAny: []int64{fire, poison}},
+			want:   []string{"Bulbasaur", "Charmander"},
+		},
+		{
+			name:   "not having type keeps its other types out of the count",
+			filter: entity.PokemonFilterDB{TypeAll: []int64{normal}, TypeNot: []int64{poison}},
+			want:   []string{"Charmander", "Wigglytuff"},
+		},
+		{
+			name:   "types with filter",
+			filter: entity.PokemonFilterDB{Expr: filter.Comparison{Field: "type", Op: filter.OpEqual, Value: "normal"}, TypeNot: []int64{fire}},
+			want:   []string{"Bulbasaur", "Wigglytuff"},
+		},
+		{
+			name:    "types in the trash don't count",
+			filter:  entity.PokemonFilterDB{TypeAny: []int64{poison}},
+			trashed: []int64{poison},
+		},
+		{
+			name:    "types in the trash don't exclude pokemons",
+			filter:  entity.PokemonFilterDB{TypeAll: []int64{normal}, TypeNot: []int64{poison}},
+			trashed: []int64{poison},
+			want:    []string{"Bulbasaur", "Charmander", "Wigglytuff"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, id := range tt.trashed {
+				if _, err := db.Exec("UPDATE types SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?", id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			defer db.Exec("UPDATE types SET deleted_at = NULL")
+
+			tt.filter.SortBy = "name"
+			pokemons, err := pr.GetAllPokemonByFilterDB(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("PokemonRepository.GetAllPokemonByFilterDB() error = %v", err)
+			}
+
+			if got := names(pokemons); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PokemonRepository.GetAllPokemonByFilterDB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func names(pokemons []entity.PokemonDB) (results []string) {
+	for _, pokemon := range pokemons {
+		results = append(results, pokemon.Name)
+	}
+
+	return results
+}
@@ -9,9 +9,11 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
 )
 
 func NewMock() (*sql.DB, sqlmock.Sqlmock) {
@@ -55,7 +57,7 @@ func TestNewPokemonRepository(t *testing.T) {
 func TestPokemonRepository_GetAllPokemonDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
-	query := fmt.Sprintf("%s %s", GetPokemonQuery, `GROUP BY pokemons.id`)
+	query := regexp.QuoteMeta(fmt.Sprintf("%s %s", GetPokemonQuery, `WHERE pokemons.deleted_at IS NULL GROUP BY pokemons.id`))
 	pokemon := []entity.PokemonDB{
 		{
 			ID:       1,
@@ -92,8 +94,8 @@ func TestPokemonRepository_GetAllPokemonDB(t *testing.T) {
 			wantErr:     false,
 			mock: func() {
 				dbmock.ExpectQuery(query).WillReturnRows(
-					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata"}).
-						AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata))
+					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version"}).
+						AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata, pokemon[0].Version))
 			},
 		},
 		{
@@ -209,7 +211,7 @@ func TestPokemonRepository_CreatePokemonDB(t *testing.T) {
 func TestPokemonRepository_GetPokemonByIDDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
-	query := fmt.Sprintf(`%s %s`, GetPokemonQuery, `WHERE pokemons.id = \\?`)
+	query := regexp.QuoteMeta(fmt.Sprintf(`%s %s`, GetPokemonQuery, `WHERE pokemons.id = ? AND pokemons.deleted_at IS NULL`))
 	pokemon := entity.PokemonDB{
 		ID:       1,
 		Name:     "Bulbasour",
@@ -246,8 +248,8 @@ func TestPokemonRepository_GetPokemonByIDDB(t *testing.T) {
 			wantErr:    false,
 			mock: func() {
 				dbmock.ExpectQuery(query).WithArgs(pokemon.ID).WillReturnRows(
-					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata"}).
-						AddRow(pokemon.ID, pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata))
+					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version"}).
+						AddRow(pokemon.ID, pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, pokemon.Version))
 			},
 		},
 		{
@@ -285,6 +287,85 @@ func TestPokemonRepository_GetPokemonByIDDB(t *testing.T) {
 	}
 }
 
+func TestPokemonRepository_GetPokemonByNameDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(GetPokemonByNameQuery)
+	pokemon := entity.PokemonDB{
+		ID:       1,
+		Name:     "Bulbasour",
+		Species:  "ganteng",
+		Catched:  0,
+		Metadata: "",
+	}
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		name string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.PokemonDB
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				name: "Bulbasour",
+			},
+			wantResult: pokemon,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(pokemon.Name).WillReturnRows(
+					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version", "deleted_at"}).
+						AddRow(pokemon.ID, pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, pokemon.Version, nil))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				name: "Bulbasour",
+			},
+			wantResult: entity.PokemonDB{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(pokemon.Name).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			gotResult, err := pr.GetPokemonByNameDB(tt.args.ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.GetPokemonByNameDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("PokemonRepository.GetPokemonByNameDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
 func TestPokemonRepository_UpdatePokemonDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
@@ -297,6 +378,7 @@ func TestPokemonRepository_UpdatePokemonDB(t *testing.T) {
 		Species:  "ganteng",
 		Catched:  0,
 		Metadata: "",
+		Version:  2,
 	}
 
 	type fields struct {
@@ -326,7 +408,7 @@ func TestPokemonRepository_UpdatePokemonDB(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, id).WillReturnResult(sqlmock.NewResult(1, 0))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, id, pokemon.Version).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
@@ -341,7 +423,22 @@ func TestPokemonRepository_UpdatePokemonDB(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, id).WillReturnError(errors.New("error"))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, id, pokemon.Version).WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "failed version changed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: pokemon,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(pokemon.Name, pokemon.Species, pokemon.Catched, pokemon.Metadata, id, pokemon.Version).WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
 	}
@@ -364,13 +461,15 @@ func TestPokemonRepository_DeletePokemonByIDDB(t *testing.T) {
 	ctx := context.Background()
 	query := DeletePokemonQuery
 	id := 1
+	version := 2
 
 	type fields struct {
 		PokemonDB *sql.DB
 	}
 	type args struct {
-		ctx context.Context
-		id  int64
+		ctx     context.Context
+		id      int64
+		version int64
 	}
 	tests := []struct {
 		name    string
@@ -385,12 +484,13 @@ func TestPokemonRepository_DeletePokemonByIDDB(t *testing.T) {
 				PokemonDB: db,
 			},
 			args: args{
-				ctx: ctx,
-				id:  1,
+				ctx:     ctx,
+				id:      1,
+				version: 2,
 			},
 			wantErr: false,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
@@ -399,12 +499,28 @@ func TestPokemonRepository_DeletePokemonByIDDB(t *testing.T) {
 				PokemonDB: db,
 			},
 			args: args{
-				ctx: ctx,
-				id:  1,
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "failed version changed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
 			},
 			wantErr: true,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id).WillReturnError(errors.New("error"))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
 	}
@@ -415,7 +531,7 @@ func TestPokemonRepository_DeletePokemonByIDDB(t *testing.T) {
 			pr := &PokemonRepository{
 				PokemonDB: tt.fields.PokemonDB,
 			}
-			if err := pr.DeletePokemonByIDDB(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			if err := pr.DeletePokemonByIDDB(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
 				t.Errorf("PokemonRepository.DeletePokemonByIDDB() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -435,13 +551,27 @@ func TestPokemonRepository_GetAllPokemonByFilterDB(t *testing.T) {
 			Metadata: "",
 		},
 	}
+	// charmander is NORMAL (1) and FIRE (5) like in pokedex.sql
+	charmander := []entity.PokemonDB{
+		{
+			ID:       3,
+			Name:     "Charmander",
+			Species:  "Lizard Pokemon",
+			Catched:  0,
+			Metadata: "{}",
+		},
+	}
+	charmanderRows := func() *sqlmock.Rows {
+		return dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version"}).
+			AddRow(charmander[0].ID, charmander[0].Name, charmander[0].Species, charmander[0].Catched, charmander[0].Metadata, charmander[0].Version)
+	}
 
 	type fields struct {
 		PokemonDB *sql.DB
 	}
 	type args struct {
 		ctx    context.Context
-		filter map[string]string
+		filter entity.PokemonFilterDB
 	}
 	tests := []struct {
 		name         string
@@ -458,20 +588,99 @@ func TestPokemonRepository_GetAllPokemonByFilterDB(t *testing.T) {
 			},
 			args: args{
 				ctx: ctx,
-				filter: map[string]string{
-					"name":     "Bulbasour",
-					"options":  "1",
-					"type":     "1,2,3",
-					"sort_by":  "id",
-					"order_by": "desc",
+				filter: entity.PokemonFilterDB{
+					Expr: filter.And{
+						Left:  filter.Comparison{Field: "name", Op: filter.OpContains, Value: "Bulbasour"},
+						Right: filter.Comparison{Field: "catched", Op: filter.OpEqual, Value: "1"},
+					},
+					SortBy:  "id",
+					OrderBy: "desc",
 				},
 			},
 			wantPokemons: pokemon,
 			wantErr:      false,
 			mock: func() {
-				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(
-					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata"}).
-						AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata))
+				dbmock.ExpectQuery(regexp.QuoteMeta(query+`WHERE pokemons.deleted_at IS NULL AND (LOWER(pokemons.name) LIKE LOWER(?) AND pokemons.catched = ?) GROUP BY pokemons.id ORDER BY pokemons.id DESC`)).
+					WithArgs("%Bulbasour%", 1).
+					WillReturnRows(
+						dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version"}).
+							AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata, pokemon[0].Version))
+			},
+		},
+		{
+			name: "success without filter",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.PokemonFilterDB{SortBy: "name"},
+			},
+			wantPokemons: pokemon,
+			wantErr:      false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query + `WHERE pokemons.deleted_at IS NULL GROUP BY pokemons.id ORDER BY pokemons.name ASC`)).
+					WillReturnRows(
+						dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version"}).
+							AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata, pokemon[0].Version))
+			},
+		},
+		{
+			name: "success pokemons having all types",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.PokemonFilterDB{TypeAll: []int64{1, 5}},
+			},
+			wantPokemons: charmander,
+			wantErr:      false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query+typesJoin+`WHERE pokemons.deleted_at IS NULL GROUP BY pokemons.id `+
+					`HAVING COUNT(DISTINCT CASE WHEN types.id IN (?, ?) THEN types.id END) = ?`)).
+					WithArgs(int64(1), int64(5), 2).
+					WillReturnRows(charmanderRows())
+			},
+		},
+		{
+			name: "success type listed twice in all types is counted once",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.PokemonFilterDB{TypeAll: []int64{5, 5}},
+			},
+			wantPokemons: charmander,
+			wantErr:      false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query+typesJoin+`WHERE pokemons.deleted_at IS NULL GROUP BY pokemons.id `+
+					`HAVING COUNT(DISTINCT CASE WHEN types.id IN (?) THEN types.id END) = ?`)).
+					WithArgs(int64(5), 1).
+					WillReturnRows(charmanderRows())
+			},
+		},
+		{
+			name: "success pokemons having any type but excluded types",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilterDB{
+					Expr:    filter.Comparison{Field: "catched", Op: filter.OpEqual, Value: "0"},
+					TypeAny: []int64{5, 9},
+					TypeNot: []int64{9},
+				},
+			},
+			wantPokemons: charmander,
+			wantErr:      false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query+typesJoin+`WHERE pokemons.deleted_at IS NULL AND pokemons.catched = ? GROUP BY pokemons.id `+
+					`HAVING COUNT(CASE WHEN types.id IN (?, ?) THEN 1 END) > 0 AND COUNT(CASE WHEN types.id IN (?) THEN 1 END) = 0`)).
+					WithArgs(0, int64(5), int64(9), int64(9)).
+					WillReturnRows(charmanderRows())
 			},
 		},
 		{
@@ -481,12 +690,13 @@ func TestPokemonRepository_GetAllPokemonByFilterDB(t *testing.T) {
 			},
 			args: args{
 				ctx: ctx,
-				filter: map[string]string{
-					"name":     "Bulbasour",
-					"options":  "1",
-					"type":     "1,2,3",
-					"sort_by":  "id",
-					"order_by": "desc",
+				filter: entity.PokemonFilterDB{
+					Expr: filter.And{
+						Left:  filter.Comparison{Field: "name", Op: filter.OpContains, Value: "Bulbasour"},
+						Right: filter.Comparison{Field: "catched", Op: filter.OpEqual, Value: "1"},
+					},
+					SortBy:  "id",
+					OrderBy: "desc",
 				},
 			},
 			wantPokemons: nil,
@@ -514,3 +724,373 @@ func TestPokemonRepository_GetAllPokemonByFilterDB(t *testing.T) {
 		})
 	}
 }
+
+func TestPokemonRepository_RestorePokemonDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(RestorePokemonQuery)
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed not in trash",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			if err := pr.RestorePokemonDB(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.RestorePokemonDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonRepository_PurgePokemonDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	before := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(PurgePokemonQuery)
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		before time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				before: before,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				before: before,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(before).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			if err := pr.PurgePokemonDB(tt.args.ctx, tt.args.before); (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.PurgePokemonDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonRepository_GetDeletedPokemonDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(GetDeletedPokemonQuery)
+	deletedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	pokemon := []entity.PokemonDB{
+		{ID: 1, Name: "Bulbasour", Species: "Seed Pokemon", Metadata: "{}", Version: 2, DeletedAt: &deletedAt},
+	}
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonDB
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: pokemon,
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WillReturnRows(
+					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version", "deleted_at"}).
+						AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata, pokemon[0].Version, deletedAt))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			gotResults, err := pr.GetDeletedPokemonDB(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.GetDeletedPokemonDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonRepository.GetDeletedPokemonDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonRepository_GetPokemonByTypeIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(GetPokemonByTypeIDQuery)
+	deletedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	pokemon := []entity.PokemonDB{
+		{ID: 1, Name: "Bulbasour", Species: "Seed Pokemon", Metadata: "{}", Version: 2, DeletedAt: &deletedAt},
+	}
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		typeID int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonDB
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantResults: pokemon,
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(1).WillReturnRows(
+					dbmock.NewRows([]string{"id", "name", "species", "catched", "metadata", "version", "deleted_at"}).
+						AddRow(pokemon[0].ID, pokemon[0].Name, pokemon[0].Species, pokemon[0].Catched, pokemon[0].Metadata, pokemon[0].Version, deletedAt))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(1).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			gotResults, err := pr.GetPokemonByTypeIDDB(tt.args.ctx, tt.args.typeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.GetPokemonByTypeIDDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonRepository.GetPokemonByTypeIDDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonRepository_UpdatePokemonVersionDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdatePokemonVersionQuery
+	id := 1
+	version := 2
+
+	type fields struct {
+		PokemonDB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "failed version changed",
+			fields: fields{
+				PokemonDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(id, version).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRepository{
+				PokemonDB: tt.fields.PokemonDB,
+			}
+			if err := pr.UpdatePokemonVersionDB(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRepository.UpdatePokemonVersionDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,12 +7,28 @@ const (
 			pokemons.name, 
 			pokemons.species, 
 			pokemons.catched,
-			pokemons.metadata
+			pokemons.metadata,
+			pokemons.version
 		FROM pokedex.pokemons
 		JOIN pokedex.pokemon_types 
 			ON pokemons.id = pokemon_types.pokemon_id
 	`
 
+	// pokemons without type and pokemons in the trash are also found so names stay unique
+	GetPokemonByNameQuery = `
+		SELECT
+			id,
+			name,
+			species,
+			catched,
+			metadata,
+			version,
+			deleted_at
+		FROM pokedex.pokemons
+		WHERE name = ?
+		LIMIT 1
+	`
+
 	InsertPokemonQuery = `
 		INSERT INTO pokedex.pokemons
 		(
@@ -34,12 +50,73 @@ const (
 			name = ?,
 			species = ?,
 			catched = ?,
-			metadata = ?
-		WHERE id = ?
+			metadata = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
 	`
 
 	DeletePokemonQuery = `
-		DELETE FROM pokedex.pokemons 
-		WHERE id = ?
+		UPDATE pokedex.pokemons
+		SET
+			deleted_at = CURRENT_TIMESTAMP,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	GetDeletedPokemonQuery = `
+		SELECT
+			id,
+			name,
+			species,
+			catched,
+			metadata,
+			version,
+			deleted_at
+		FROM pokedex.pokemons
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	// pokemons in the trash are also returned, their types are removed together with the other pokemons
+	GetPokemonByTypeIDQuery = `
+		SELECT DISTINCT
+			pokemons.id,
+			pokemons.name,
+			pokemons.species,
+			pokemons.catched,
+			pokemons.metadata,
+			pokemons.version,
+			pokemons.deleted_at
+		FROM pokedex.pokemons
+		JOIN pokedex.pokemon_types
+			ON pokemons.id = pokemon_types.pokemon_id
+		WHERE pokemon_types.types_id = ?
+		ORDER BY pokemons.id
+	`
+
+	UpdatePokemonVersionQuery = `
+		UPDATE pokedex.pokemons
+		SET
+			version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	RestorePokemonQuery = `
+		UPDATE pokedex.pokemons
+		SET
+			deleted_at = NULL,
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+
+	// type links and revisions of the pokemon are kept in the trash so they are purged together with the pokemon
+	PurgePokemonQuery = `
+		DELETE pokemons, pokemon_types, pokemon_revisions
+		FROM pokedex.pokemons
+		LEFT JOIN pokedex.pokemon_types
+			ON pokemon_types.pokemon_id = pokemons.id
+		LEFT JOIN pokedex.pokemon_revisions
+			ON pokemon_revisions.pokemon_id = pokemons.id
+		WHERE pokemons.deleted_at < ?
 	`
 )
@@ -0,0 +1,88 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package pokemonrevisionrepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// PokemonRevisionRepositoryItf is an autogenerated mock type for the PokemonRevisionRepositoryItf type
+type PokemonRevisionRepositoryItf struct {
+	mock.Mock
+}
+
+// CreatePokemonRevisionDB provides a mock function with given fields: ctx, data
+func (_m *PokemonRevisionRepositoryItf) CreatePokemonRevisionDB(ctx context.Context, data entity.PokemonRevision) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PokemonRevision) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPokemonRevisionDB provides a mock function with given fields: ctx, pokemonID, revision
+func (_m *PokemonRevisionRepositoryItf) GetPokemonRevisionDB(ctx context.Context, pokemonID int64, revision int64) (entity.PokemonRevision, error) {
+	ret := _m.Called(ctx, pokemonID, revision)
+
+	var r0 entity.PokemonRevision
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) entity.PokemonRevision); ok {
+		r0 = rf(ctx, pokemonID, revision)
+	} else {
+		r0 = ret.Get(0).(entity.PokemonRevision)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, pokemonID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPokemonRevisionsDB provides a mock function with given fields: ctx, pokemonID
+func (_m *PokemonRevisionRepositoryItf) GetPokemonRevisionsDB(ctx context.Context, pokemonID int64) ([]entity.PokemonRevision, error) {
+	ret := _m.Called(ctx, pokemonID)
+
+	var r0 []entity.PokemonRevision
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.PokemonRevision); ok {
+		r0 = rf(ctx, pokemonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, pokemonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPokemonRevisionRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPokemonRevisionRepositoryItf creates a new instance of PokemonRevisionRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPokemonRevisionRepositoryItf(t mockConstructorTestingTNewPokemonRevisionRepositoryItf) *PokemonRevisionRepositoryItf {
+	mock := &PokemonRevisionRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
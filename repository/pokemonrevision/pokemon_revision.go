@@ -0,0 +1,84 @@
+package pokemonrevisionrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+type PokemonRevisionRepository struct {
+	PokemonRevisionDB *sql.DB
+}
+
+type PokemonRevisionRepositoryItf interface {
+	CreatePokemonRevisionDB(ctx context.Context, data entity.PokemonRevision) (err error)
+	GetPokemonRevisionsDB(ctx context.Context, pokemonID int64) (results []entity.PokemonRevision, err error)
+	GetPokemonRevisionDB(ctx context.Context, pokemonID int64, revision int64) (result entity.PokemonRevision, err error)
+}
+
+func NewPokemonRevisionRepository(db *sql.DB) PokemonRevisionRepositoryItf {
+	return &PokemonRevisionRepository{
+		PokemonRevisionDB: db,
+	}
+}
+
+// CreatePokemonRevisionDB stores the pokemon of the revision as json snapshot
+func (pr *PokemonRevisionRepository) CreatePokemonRevisionDB(ctx context.Context, data entity.PokemonRevision) (err error) {
+	snapshot, err := json.Marshal(data.Pokemon)
+	if err != nil {
+		return err
+	}
+
+	_, err = transaction.Conn(ctx, pr.PokemonRevisionDB).ExecContext(ctx, InsertPokemonRevisionQuery, data.PokemonID, data.Revision, string(snapshot), data.ActorID, data.Actor)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// GetPokemonRevisionsDB returns the revisions of the pokemon, the oldest first
+func (pr *PokemonRevisionRepository) GetPokemonRevisionsDB(ctx context.Context, pokemonID int64) (results []entity.PokemonRevision, err error) {
+	rows, err := transaction.Conn(ctx, pr.PokemonRevisionDB).QueryContext(ctx, GetPokemonRevisionQuery+`WHERE pokemon_id = ? ORDER BY revision`, pokemonID)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result, err := scanPokemonRevision(rows)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+func (pr *PokemonRevisionRepository) GetPokemonRevisionDB(ctx context.Context, pokemonID int64, revision int64) (result entity.PokemonRevision, err error) {
+	return scanPokemonRevision(transaction.Conn(ctx, pr.PokemonRevisionDB).QueryRowContext(ctx, GetPokemonRevisionQuery+`WHERE pokemon_id = ? AND revision = ?`, pokemonID, revision))
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPokemonRevision(row scanner) (result entity.PokemonRevision, err error) {
+	var snapshot string
+	err = row.Scan(&result.ID, &result.PokemonID, &result.Revision, &snapshot, &result.ActorID, &result.Actor, &result.CreatedAt)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal([]byte(snapshot), &result.Pokemon)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
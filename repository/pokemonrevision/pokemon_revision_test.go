@@ -0,0 +1,304 @@
+package pokemonrevisionrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+var (
+	revisionColumns = []string{"id", "pokemon_id", "revision", "snapshot", "actor_id", "actor", "created_at"}
+	revisionTime    = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	revisionJSON    = `{"id":1,"name":"Bulbasour","species":"pokemon","types":[1],"catched":0,"stats":{"hp":0,"attack":0,"def":0,"speed":0}}`
+	revision        = entity.PokemonRevision{
+		ID:        1,
+		PokemonID: 1,
+		Revision:  2,
+		Pokemon: entity.Pokemon{
+			ID:      1,
+			Name:    "Bulbasour",
+			Species: "pokemon",
+			Types:   []int64{1},
+		},
+		ActorID:   1,
+		Actor:     "admin",
+		CreatedAt: revisionTime,
+	}
+)
+
+func TestNewPokemonRevisionRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want PokemonRevisionRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &PokemonRevisionRepository{
+				PokemonRevisionDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPokemonRevisionRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewPokemonRevisionRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPokemonRevisionRepository_CreatePokemonRevisionDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertPokemonRevisionQuery
+
+	type fields struct {
+		PokemonRevisionDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.PokemonRevision
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: revision,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(revision.PokemonID, revision.Revision, revisionJSON, revision.ActorID, revision.Actor).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: revision,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(revision.PokemonID, revision.Revision, revisionJSON, revision.ActorID, revision.Actor).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRevisionRepository{
+				PokemonRevisionDB: tt.fields.PokemonRevisionDB,
+			}
+			if err := pr.CreatePokemonRevisionDB(tt.args.ctx, tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRevisionRepository.CreatePokemonRevisionDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonRevisionRepository_GetPokemonRevisionsDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetPokemonRevisionQuery + `WHERE pokemon_id = ? ORDER BY revision`
+
+	type fields struct {
+		PokemonRevisionDB *sql.DB
+	}
+	type args struct {
+		ctx       context.Context
+		pokemonID int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonRevision
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				pokemonID: 1,
+			},
+			wantResults: []entity.PokemonRevision{revision},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows(revisionColumns).
+						AddRow(revision.ID, revision.PokemonID, revision.Revision, revisionJSON, revision.ActorID, revision.Actor, revision.CreatedAt),
+				)
+			},
+		},
+		{
+			name: "failed invalid snapshot",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				pokemonID: 1,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows(revisionColumns).
+						AddRow(revision.ID, revision.PokemonID, revision.Revision, `{`, revision.ActorID, revision.Actor, revision.CreatedAt),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				pokemonID: 1,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRevisionRepository{
+				PokemonRevisionDB: tt.fields.PokemonRevisionDB,
+			}
+			gotResults, err := pr.GetPokemonRevisionsDB(tt.args.ctx, tt.args.pokemonID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRevisionRepository.GetPokemonRevisionsDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonRevisionRepository.GetPokemonRevisionsDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonRevisionRepository_GetPokemonRevisionDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetPokemonRevisionQuery + `WHERE pokemon_id = ? AND revision = ?`
+
+	type fields struct {
+		PokemonRevisionDB *sql.DB
+	}
+	type args struct {
+		ctx       context.Context
+		pokemonID int64
+		revision  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.PokemonRevision
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				pokemonID: 1,
+				revision:  2,
+			},
+			wantResult: revision,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(2)).WillReturnRows(
+					sqlmock.NewRows(revisionColumns).
+						AddRow(revision.ID, revision.PokemonID, revision.Revision, revisionJSON, revision.ActorID, revision.Actor, revision.CreatedAt),
+				)
+			},
+		},
+		{
+			name: "failed not found",
+			fields: fields{
+				PokemonRevisionDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				pokemonID: 1,
+				revision:  9,
+			},
+			wantResult: entity.PokemonRevision{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(9)).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PokemonRevisionRepository{
+				PokemonRevisionDB: tt.fields.PokemonRevisionDB,
+			}
+			gotResult, err := pr.GetPokemonRevisionDB(tt.args.ctx, tt.args.pokemonID, tt.args.revision)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonRevisionRepository.GetPokemonRevisionDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("PokemonRevisionRepository.GetPokemonRevisionDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
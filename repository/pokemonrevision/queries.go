@@ -0,0 +1,32 @@
+package pokemonrevisionrepository
+
+const (
+	InsertPokemonRevisionQuery = `
+		INSERT INTO pokedex.pokemon_revisions
+		(
+			pokemon_id,
+			revision,
+			snapshot,
+			actor_id,
+			actor
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	GetPokemonRevisionQuery = `
+		SELECT
+			id,
+			pokemon_id,
+			revision,
+			snapshot,
+			actor_id,
+			actor,
+			created_at
+		FROM pokedex.pokemon_revisions
+	`
+)
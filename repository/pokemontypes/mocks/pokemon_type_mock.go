@@ -14,6 +14,27 @@ type PokemonTypeRepositoryItf struct {
 	mock.Mock
 }
 
+// CountPokemonTypeByTypeIDDB provides a mock function with given fields: ctx, typeID
+func (_m *PokemonTypeRepositoryItf) CountPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) (int64, error) {
+	ret := _m.Called(ctx, typeID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, typeID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, typeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreatePokemonTypeDB provides a mock function with given fields: ctx, data
 func (_m *PokemonTypeRepositoryItf) CreatePokemonTypeDB(ctx context.Context, data entity.PokemonType) error {
 	ret := _m.Called(ctx, data)
@@ -56,6 +77,20 @@ func (_m *PokemonTypeRepositoryItf) DeletePokemonTypeByPokemonIDDB(ctx context.C
 	return r0
 }
 
+// DetachPokemonTypeByTypeIDDB provides a mock function with given fields: ctx, typeID
+func (_m *PokemonTypeRepositoryItf) DetachPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) error {
+	ret := _m.Called(ctx, typeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, typeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetPokemonTypeByPokemonIDDB provides a mock function with given fields: ctx, pokemonID
 func (_m *PokemonTypeRepositoryItf) GetPokemonTypeByPokemonIDDB(ctx context.Context, pokemonID int64) ([]entity.PokemonType, error) {
 	ret := _m.Called(ctx, pokemonID)
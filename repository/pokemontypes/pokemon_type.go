@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
 )
 
 type PokemonTypeRepository struct {
@@ -17,6 +18,8 @@ type PokemonTypeRepositoryItf interface {
 	UpdatePokemonTypeDB(ctx context.Context, id int64, data entity.PokemonType) (err error)
 	DeletePokemonTypeByPokemonIDDB(ctx context.Context, pokemonID int64) (err error)
 	DeletePokemonTypeByIDDB(ctx context.Context, id int64) (err error)
+	CountPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) (count int64, err error)
+	DetachPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) (err error)
 }
 
 func NewPokemonTypeRepository(db *sql.DB) PokemonTypeRepositoryItf {
@@ -26,7 +29,7 @@ func NewPokemonTypeRepository(db *sql.DB) PokemonTypeRepositoryItf {
 }
 
 func (pt *PokemonTypeRepository) CreatePokemonTypeDB(ctx context.Context, data entity.PokemonType) (err error) {
-	_, err = pt.PokemonTypeDB.ExecContext(ctx, InsertPokemonTypeQuery, &data.PokemonID, &data.TypeID)
+	_, err = transaction.Conn(ctx, pt.PokemonTypeDB).ExecContext(ctx, InsertPokemonTypeQuery, &data.PokemonID, &data.TypeID)
 	if err != nil {
 		return err
 	}
@@ -35,7 +38,7 @@ func (pt *PokemonTypeRepository) CreatePokemonTypeDB(ctx context.Context, data e
 }
 
 func (pt *PokemonTypeRepository) GetPokemonTypeByPokemonIDDB(ctx context.Context, pokemonID int64) (result []entity.PokemonType, err error) {
-	rows, err := pt.PokemonTypeDB.QueryContext(ctx, GetPokemonTypesByPokemonIDQuery, pokemonID)
+	rows, err := transaction.Conn(ctx, pt.PokemonTypeDB).QueryContext(ctx, GetPokemonTypesByPokemonIDQuery, pokemonID)
 	if err != nil {
 		return result, err
 	}
@@ -43,7 +46,7 @@ func (pt *PokemonTypeRepository) GetPokemonTypeByPokemonIDDB(ctx context.Context
 	for rows.Next() {
 		var row entity.PokemonType
 
-		err = rows.Scan(&row.ID, &row.PokemonID, &row.TypeID, &row.Name)
+		err = rows.Scan(&row.ID, &row.PokemonID, &row.TypeID, &row.Name, &row.TypeVersion)
 		if err != nil {
 			return result, err
 		}
@@ -55,7 +58,7 @@ func (pt *PokemonTypeRepository) GetPokemonTypeByPokemonIDDB(ctx context.Context
 }
 
 func (pt *PokemonTypeRepository) UpdatePokemonTypeDB(ctx context.Context, id int64, data entity.PokemonType) (err error) {
-	_, err = pt.PokemonTypeDB.ExecContext(ctx, UpdatePokemonTokenQuery, &data.PokemonID, &data.TypeID, id)
+	_, err = transaction.Conn(ctx, pt.PokemonTypeDB).ExecContext(ctx, UpdatePokemonTokenQuery, &data.PokemonID, &data.TypeID, id)
 	if err != nil {
 		return err
 	}
@@ -64,7 +67,7 @@ func (pt *PokemonTypeRepository) UpdatePokemonTypeDB(ctx context.Context, id int
 }
 
 func (pt *PokemonTypeRepository) DeletePokemonTypeByPokemonIDDB(ctx context.Context, pokemonID int64) (err error) {
-	_, err = pt.PokemonTypeDB.ExecContext(ctx, DeletePokemonTypeByPokemonIDQuery, pokemonID)
+	_, err = transaction.Conn(ctx, pt.PokemonTypeDB).ExecContext(ctx, DeletePokemonTypeByPokemonIDQuery, pokemonID)
 	if err != nil {
 		return err
 	}
@@ -73,7 +76,27 @@ func (pt *PokemonTypeRepository) DeletePokemonTypeByPokemonIDDB(ctx context.Cont
 }
 
 func (pt *PokemonTypeRepository) DeletePokemonTypeByIDDB(ctx context.Context, id int64) (err error) {
-	_, err = pt.PokemonTypeDB.ExecContext(ctx, DeletePokemonTypeByIDQuery, id)
+	_, err = transaction.Conn(ctx, pt.PokemonTypeDB).ExecContext(ctx, DeletePokemonTypeByIDQuery, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// CountPokemonTypeByTypeIDDB returns how many pokemons have the type, pokemons in the trash included
+func (pt *PokemonTypeRepository) CountPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) (count int64, err error) {
+	err = transaction.Conn(ctx, pt.PokemonTypeDB).QueryRowContext(ctx, CountPokemonTypeByTypeIDQuery, typeID).Scan(&count)
+	if err != nil {
+		return count, err
+	}
+
+	return count, err
+}
+
+// DetachPokemonTypeByTypeIDDB removes the type from every pokemon that has it
+func (pt *PokemonTypeRepository) DetachPokemonTypeByTypeIDDB(ctx context.Context, typeID int64) (err error) {
+	_, err = transaction.Conn(ctx, pt.PokemonTypeDB).ExecContext(ctx, DetachPokemonTypeByTypeIDQuery, typeID)
 	if err != nil {
 		return err
 	}
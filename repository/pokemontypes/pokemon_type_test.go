@@ -124,10 +124,11 @@ func TestPokemonTypeRepository_GetPokemonTypeByPokemonIDDB(t *testing.T) {
 	id := 1
 	pokemonType := []entity.PokemonType{
 		{
-			ID:        1,
-			PokemonID: 1,
-			TypeID:    2,
-			Name:      "FIRE",
+			ID:          1,
+			PokemonID:   1,
+			TypeID:      2,
+			Name:        "FIRE",
+			TypeVersion: 3,
 		},
 	}
 
@@ -159,8 +160,8 @@ func TestPokemonTypeRepository_GetPokemonTypeByPokemonIDDB(t *testing.T) {
 			wantErr:    false,
 			mock: func() {
 				dbmock.ExpectQuery(query).WithArgs(id).WillReturnRows(
-					sqlmock.NewRows([]string{"id", "pokemon_id", "types_id", "types.name"}).
-						AddRow(pokemonType[0].ID, pokemonType[0].PokemonID, pokemonType[0].TypeID, pokemonType[0].Name))
+					sqlmock.NewRows([]string{"id", "pokemon_id", "types_id", "types.name", "types.version"}).
+						AddRow(pokemonType[0].ID, pokemonType[0].PokemonID, pokemonType[0].TypeID, pokemonType[0].Name, pokemonType[0].TypeVersion))
 			},
 		},
 		{
@@ -395,3 +396,135 @@ func TestPokemonTypeRepository_DeletePokemonTypeByIDDB(t *testing.T) {
 		})
 	}
 }
+
+func TestPokemonTypeRepository_DetachPokemonTypeByTypeIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(DetachPokemonTypeByTypeIDQuery)
+
+	type fields struct {
+		PokemonTypeDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		typeID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonTypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonTypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pt := &PokemonTypeRepository{
+				PokemonTypeDB: tt.fields.PokemonTypeDB,
+			}
+			if err := pt.DetachPokemonTypeByTypeIDDB(tt.args.ctx, tt.args.typeID); (err != nil) != tt.wantErr {
+				t.Errorf("PokemonTypeRepository.DetachPokemonTypeByTypeIDDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonTypeRepository_CountPokemonTypeByTypeIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(CountPokemonTypeByTypeIDQuery)
+
+	type fields struct {
+		PokemonTypeDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		typeID int64
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantCount int64
+		wantErr   bool
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonTypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantCount: 2,
+			wantErr:   false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				PokemonTypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 1,
+			},
+			wantCount: 0,
+			wantErr:   true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(1).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pt := &PokemonTypeRepository{
+				PokemonTypeDB: tt.fields.PokemonTypeDB,
+			}
+			gotCount, err := pt.CountPokemonTypeByTypeIDDB(tt.args.ctx, tt.args.typeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonTypeRepository.CountPokemonTypeByTypeIDDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotCount != tt.wantCount {
+				t.Errorf("PokemonTypeRepository.CountPokemonTypeByTypeIDDB() = %v, want %v", gotCount, tt.wantCount)
+			}
+		})
+	}
+}
@@ -19,7 +19,8 @@ const (
 		pokemon_types.id,
 		pokemon_types.pokemon_id,
 		pokemon_types.types_id,
-		types.name
+		types.name,
+		types.version
 	FROM pokedex.pokemon_types
 	JOIN types ON types.id = pokemon_types.types_id
 	WHERE pokemon_id = ?
@@ -38,6 +39,20 @@ const (
 		WHERE pokemon_id = ?
 	`
 
+	CountPokemonTypeByTypeIDQuery = `
+		SELECT COUNT(*)
+		FROM pokedex.pokemon_types
+		WHERE types_id = ?
+	`
+
+	// links are kept with types_id 0, the same way pokemon types are removed on update
+	DetachPokemonTypeByTypeIDQuery = `
+		UPDATE pokedex.pokemon_types
+		SET
+			types_id = 0
+		WHERE types_id = ?
+	`
+
 	DeletePokemonTypeByIDQuery = `
 		DELETE FROM pokedex.pokemon_types 
 		WHERE id = ?
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package recoverycoderepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecoveryCodeRepositoryItf is an autogenerated mock type for the RecoveryCodeRepositoryItf type
+type RecoveryCodeRepositoryItf struct {
+	mock.Mock
+}
+
+// DeleteRecoveryCodesDB provides a mock function with given fields: ctx, userID
+func (_m *RecoveryCodeRepositoryItf) DeleteRecoveryCodesDB(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceRecoveryCodesDB provides a mock function with given fields: ctx, userID, codeHashes
+func (_m *RecoveryCodeRepositoryItf) ReplaceRecoveryCodesDB(ctx context.Context, userID int64, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, codeHashes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) error); ok {
+		r0 = rf(ctx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCodeDB provides a mock function with given fields: ctx, userID, codeHash
+func (_m *RecoveryCodeRepositoryItf) UseRecoveryCodeDB(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) bool); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRecoveryCodeRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRecoveryCodeRepositoryItf creates a new instance of RecoveryCodeRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRecoveryCodeRepositoryItf(t mockConstructorTestingTNewRecoveryCodeRepositoryItf) *RecoveryCodeRepositoryItf {
+	mock := &RecoveryCodeRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,26 @@
+package recoverycoderepository
+
+const (
+	InsertRecoveryCodeQuery = `
+		INSERT INTO pokedex.user_recovery_codes
+		(
+			user_id,
+			code_hash
+		) VALUES (
+			?,
+			?
+		)
+	`
+
+	UseRecoveryCodeQuery = `
+		UPDATE pokedex.user_recovery_codes
+		SET
+			used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`
+
+	DeleteRecoveryCodesQuery = `
+		DELETE FROM pokedex.user_recovery_codes
+		WHERE user_id = ?
+	`
+)
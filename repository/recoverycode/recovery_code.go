@@ -0,0 +1,70 @@
+package recoverycoderepository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type RecoveryCodeRepository struct {
+	RecoveryCodeDB *sql.DB
+}
+
+type RecoveryCodeRepositoryItf interface {
+	ReplaceRecoveryCodesDB(ctx context.Context, userID int64, codeHashes []string) (err error)
+	UseRecoveryCodeDB(ctx context.Context, userID int64, codeHash string) (used bool, err error)
+	DeleteRecoveryCodesDB(ctx context.Context, userID int64) (err error)
+}
+
+func NewRecoveryCodeRepository(db *sql.DB) RecoveryCodeRepositoryItf {
+	return &RecoveryCodeRepository{
+		RecoveryCodeDB: db,
+	}
+}
+
+// ReplaceRecoveryCodesDB removes the previous recovery codes of the user and stores the new ones in a single transaction
+func (rc *RecoveryCodeRepository) ReplaceRecoveryCodesDB(ctx context.Context, userID int64, codeHashes []string) (err error) {
+	tx, err := rc.RecoveryCodeDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, DeleteRecoveryCodesQuery, userID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, InsertRecoveryCodeQuery, userID, codeHash)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCodeDB marks recovery code as used, used is false when the code does not exist or was already used
+func (rc *RecoveryCodeRepository) UseRecoveryCodeDB(ctx context.Context, userID int64, codeHash string) (used bool, err error) {
+	row, err := rc.RecoveryCodeDB.ExecContext(ctx, UseRecoveryCodeQuery, userID, codeHash)
+	if err != nil {
+		return used, err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return used, err
+	}
+
+	return affected == 1, err
+}
+
+func (rc *RecoveryCodeRepository) DeleteRecoveryCodesDB(ctx context.Context, userID int64) (err error) {
+	_, err = rc.RecoveryCodeDB.ExecContext(ctx, DeleteRecoveryCodesQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
@@ -0,0 +1,289 @@
+package recoverycoderepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+func TestNewRecoveryCodeRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want RecoveryCodeRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &RecoveryCodeRepository{
+				RecoveryCodeDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRecoveryCodeRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewRecoveryCodeRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodeRepository_ReplaceRecoveryCodesDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+
+	type fields struct {
+		RecoveryCodeDB *sql.DB
+	}
+	type args struct {
+		ctx        context.Context
+		userID     int64
+		codeHashes []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:        ctx,
+				userID:     1,
+				codeHashes: []string{"hash1", "hash2"},
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectExec(regexp.QuoteMeta(DeleteRecoveryCodesQuery)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 2))
+				dbmock.ExpectExec(regexp.QuoteMeta(InsertRecoveryCodeQuery)).WithArgs(int64(1), "hash1").WillReturnResult(sqlmock.NewResult(1, 1))
+				dbmock.ExpectExec(regexp.QuoteMeta(InsertRecoveryCodeQuery)).WithArgs(int64(1), "hash2").WillReturnResult(sqlmock.NewResult(2, 1))
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name: "failed delete previous codes",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:        ctx,
+				userID:     1,
+				codeHashes: []string{"hash1"},
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectExec(regexp.QuoteMeta(DeleteRecoveryCodesQuery)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+				dbmock.ExpectRollback()
+			},
+		},
+		{
+			name: "failed insert code",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:        ctx,
+				userID:     1,
+				codeHashes: []string{"hash1"},
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectExec(regexp.QuoteMeta(DeleteRecoveryCodesQuery)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+				dbmock.ExpectExec(regexp.QuoteMeta(InsertRecoveryCodeQuery)).WithArgs(int64(1), "hash1").WillReturnError(errors.New("error"))
+				dbmock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &RecoveryCodeRepository{
+				RecoveryCodeDB: tt.fields.RecoveryCodeDB,
+			}
+			if err := rc.ReplaceRecoveryCodesDB(tt.args.ctx, tt.args.userID, tt.args.codeHashes); (err != nil) != tt.wantErr {
+				t.Errorf("RecoveryCodeRepository.ReplaceRecoveryCodesDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodeRepository_UseRecoveryCodeDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UseRecoveryCodeQuery
+
+	type fields struct {
+		RecoveryCodeDB *sql.DB
+	}
+	type args struct {
+		ctx      context.Context
+		userID   int64
+		codeHash string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantUsed bool
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				userID:   1,
+				codeHash: "hash",
+			},
+			wantUsed: true,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "success code already used",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				userID:   1,
+				codeHash: "hash",
+			},
+			wantUsed: false,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				userID:   1,
+				codeHash: "hash",
+			},
+			wantUsed: false,
+			wantErr:  true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "hash").WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &RecoveryCodeRepository{
+				RecoveryCodeDB: tt.fields.RecoveryCodeDB,
+			}
+			gotUsed, err := rc.UseRecoveryCodeDB(tt.args.ctx, tt.args.userID, tt.args.codeHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecoveryCodeRepository.UseRecoveryCodeDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotUsed != tt.wantUsed {
+				t.Errorf("RecoveryCodeRepository.UseRecoveryCodeDB() = %v, want %v", gotUsed, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodeRepository_DeleteRecoveryCodesDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := DeleteRecoveryCodesQuery
+
+	type fields struct {
+		RecoveryCodeDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 10))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				RecoveryCodeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &RecoveryCodeRepository{
+				RecoveryCodeDB: tt.fields.RecoveryCodeDB,
+			}
+			if err := rc.DeleteRecoveryCodesDB(tt.args.ctx, tt.args.userID); (err != nil) != tt.wantErr {
+				t.Errorf("RecoveryCodeRepository.DeleteRecoveryCodesDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
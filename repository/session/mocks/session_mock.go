@@ -0,0 +1,151 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package sessionrepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// SessionRepositoryItf is an autogenerated mock type for the SessionRepositoryItf type
+type SessionRepositoryItf struct {
+	mock.Mock
+}
+
+// CreateSessionDB provides a mock function with given fields: ctx, data
+func (_m *SessionRepositoryItf) CreateSessionDB(ctx context.Context, data entity.Session) (int64, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Session) int64); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Session) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetActiveSessionsByUserIDDB provides a mock function with given fields: ctx, userID
+func (_m *SessionRepositoryItf) GetActiveSessionsByUserIDDB(ctx context.Context, userID int64) ([]entity.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []entity.Session
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionByIDDB provides a mock function with given fields: ctx, id
+func (_m *SessionRepositoryItf) GetSessionByIDDB(ctx context.Context, id int64) (entity.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.Session
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.Session)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeOtherUserSessionsDB provides a mock function with given fields: ctx, userID, keepID
+func (_m *SessionRepositoryItf) RevokeOtherUserSessionsDB(ctx context.Context, userID int64, keepID int64) error {
+	ret := _m.Called(ctx, userID, keepID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, keepID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSessionDB provides a mock function with given fields: ctx, id, userID
+func (_m *SessionRepositoryItf) RevokeSessionDB(ctx context.Context, id int64, userID int64) error {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeUserSessionsDB provides a mock function with given fields: ctx, userID
+func (_m *SessionRepositoryItf) RevokeUserSessionsDB(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSessionLastSeenDB provides a mock function with given fields: ctx, id
+func (_m *SessionRepositoryItf) UpdateSessionLastSeenDB(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionRepositoryItf creates a new instance of SessionRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionRepositoryItf(t mockConstructorTestingTNewSessionRepositoryItf) *SessionRepositoryItf {
+	mock := &SessionRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+package sessionrepository
+
+const (
+	InsertSessionQuery = `
+		INSERT INTO pokedex.user_sessions
+		(
+			user_id,
+			user_agent,
+			ip,
+			expires_at
+		) VALUES (
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	GetSessionQuery = `
+		SELECT
+			id,
+			user_id,
+			user_agent,
+			ip,
+			created_at,
+			last_seen_at,
+			expires_at,
+			revoked_at
+		FROM pokedex.user_sessions
+	`
+
+	UpdateSessionLastSeenQuery = `
+		UPDATE pokedex.user_sessions
+		SET
+			last_seen_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	RevokeSessionQuery = `
+		UPDATE pokedex.user_sessions
+		SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`
+
+	RevokeUserSessionsQuery = `
+		UPDATE pokedex.user_sessions
+		SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND revoked_at IS NULL
+	`
+
+	RevokeOtherUserSessionsQuery = `
+		UPDATE pokedex.user_sessions
+		SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND id <> ? AND revoked_at IS NULL
+	`
+)
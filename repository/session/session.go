@@ -0,0 +1,129 @@
+package sessionrepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+type SessionRepository struct {
+	SessionDB *sql.DB
+}
+
+type SessionRepositoryItf interface {
+	CreateSessionDB(ctx context.Context, data entity.Session) (id int64, err error)
+	GetSessionByIDDB(ctx context.Context, id int64) (result entity.Session, err error)
+	GetActiveSessionsByUserIDDB(ctx context.Context, userID int64) (results []entity.Session, err error)
+	UpdateSessionLastSeenDB(ctx context.Context, id int64) (err error)
+	RevokeSessionDB(ctx context.Context, id int64, userID int64) (err error)
+	RevokeUserSessionsDB(ctx context.Context, userID int64) (err error)
+	RevokeOtherUserSessionsDB(ctx context.Context, userID int64, keepID int64) (err error)
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepositoryItf {
+	return &SessionRepository{
+		SessionDB: db,
+	}
+}
+
+func (sr *SessionRepository) CreateSessionDB(ctx context.Context, data entity.Session) (id int64, err error) {
+	row, err := transaction.Conn(ctx, sr.SessionDB).ExecContext(ctx, InsertSessionQuery, data.UserID, data.UserAgent, data.IP, data.ExpiresAt)
+	if err != nil {
+		return id, err
+	}
+
+	id, err = row.LastInsertId()
+	if err != nil {
+		return id, err
+	}
+
+	return id, err
+}
+
+func (sr *SessionRepository) GetSessionByIDDB(ctx context.Context, id int64) (result entity.Session, err error) {
+	return scanSession(transaction.Conn(ctx, sr.SessionDB).QueryRowContext(ctx, GetSessionQuery+`WHERE id = ?`, id))
+}
+
+// GetActiveSessionsByUserIDDB returns the sessions of the user that are neither revoked nor expired, the last seen first
+func (sr *SessionRepository) GetActiveSessionsByUserIDDB(ctx context.Context, userID int64) (results []entity.Session, err error) {
+	rows, err := transaction.Conn(ctx, sr.SessionDB).QueryContext(ctx, GetSessionQuery+`WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY last_seen_at DESC`, userID, time.Now())
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result, err := scanSession(rows)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+func (sr *SessionRepository) UpdateSessionLastSeenDB(ctx context.Context, id int64) (err error) {
+	_, err = transaction.Conn(ctx, sr.SessionDB).ExecContext(ctx, UpdateSessionLastSeenQuery, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// RevokeSessionDB revokes session of the user, sql.ErrNoRows is returned when the user has no such active session
+func (sr *SessionRepository) RevokeSessionDB(ctx context.Context, id int64, userID int64) (err error) {
+	row, err := transaction.Conn(ctx, sr.SessionDB).ExecContext(ctx, RevokeSessionQuery, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// RevokeUserSessionsDB revokes every active session of the user
+func (sr *SessionRepository) RevokeUserSessionsDB(ctx context.Context, userID int64) (err error) {
+	_, err = transaction.Conn(ctx, sr.SessionDB).ExecContext(ctx, RevokeUserSessionsQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// RevokeOtherUserSessionsDB revokes every active session of the user except the session keepID
+func (sr *SessionRepository) RevokeOtherUserSessionsDB(ctx context.Context, userID int64, keepID int64) (err error) {
+	_, err = transaction.Conn(ctx, sr.SessionDB).ExecContext(ctx, RevokeOtherUserSessionsQuery, userID, keepID)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row scanner) (result entity.Session, err error) {
+	err = row.Scan(&result.ID, &result.UserID, &result.UserAgent, &result.IP, &result.CreatedAt, &result.LastSeenAt, &result.ExpiresAt, &result.RevokedAt)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
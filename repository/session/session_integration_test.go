@@ -0,0 +1,47 @@
+package sessionrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/winartodev/go-pokedex/dbtest"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func TestSessionRepository_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	sr := NewSessionRepository(db)
+	ctx := context.Background()
+	userID := dbtest.SeedUser(t, db, "budi")
+	expiresAt := time.Now().Add(time.Hour)
+
+	id, err := sr.CreateSessionDB(ctx, entity.Session{UserID: userID, UserAgent: "curl", IP: "127.0.0.1", ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatalf("SessionRepository.CreateSessionDB() error = %v", err)
+	}
+
+	_, err = sr.CreateSessionDB(ctx, entity.Session{UserID: userID, UserAgent: "curl", IP: "127.0.0.1", ExpiresAt: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("SessionRepository.CreateSessionDB() error = %v", err)
+	}
+
+	sessions, err := sr.GetActiveSessionsByUserIDDB(ctx, userID)
+	if err != nil {
+		t.Fatalf("SessionRepository.GetActiveSessionsByUserIDDB() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != id || !dbtest.SameSecond(sessions[0].ExpiresAt, expiresAt) ||
+		time.Since(sessions[0].CreatedAt) > time.Minute || time.Since(sessions[0].LastSeenAt) > time.Minute {
+		t.Errorf("SessionRepository.GetActiveSessionsByUserIDDB() = %+v, want session %d expiring at %v", sessions, id, expiresAt)
+	}
+
+	err = sr.RevokeSessionDB(ctx, id, userID)
+	if err != nil {
+		t.Fatalf("SessionRepository.RevokeSessionDB() error = %v", err)
+	}
+
+	session, err := sr.GetSessionByIDDB(ctx, id)
+	if err != nil || session.RevokedAt == nil {
+		t.Errorf("SessionRepository.GetSessionByIDDB() = %+v, %v, want revoked", session, err)
+	}
+}
@@ -0,0 +1,561 @@
+package sessionrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+var (
+	sessionColumns = []string{"id", "user_id", "user_agent", "ip", "created_at", "last_seen_at", "expires_at", "revoked_at"}
+	sessionTime    = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	session        = entity.Session{
+		ID:         1,
+		UserID:     1,
+		UserAgent:  "curl/7.79.1",
+		IP:         "127.0.0.1",
+		CreatedAt:  sessionTime,
+		LastSeenAt: sessionTime,
+		ExpiresAt:  sessionTime.Add(time.Hour),
+	}
+)
+
+func TestNewSessionRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want SessionRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &SessionRepository{
+				SessionDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewSessionRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSessionRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_CreateSessionDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertSessionQuery
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.Session
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantId  int64
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: session,
+			},
+			wantId:  1,
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(session.UserID, session.UserAgent, session.IP, session.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: session,
+			},
+			wantId:  0,
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(session.UserID, session.UserAgent, session.IP, session.ExpiresAt).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			gotId, err := sr.CreateSessionDB(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.CreateSessionDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("SessionRepository.CreateSessionDB() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_GetSessionByIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetSessionQuery + `WHERE id = ?`
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.Session
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: session,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows(sessionColumns).
+						AddRow(session.ID, session.UserID, session.UserAgent, session.IP, session.CreatedAt, session.LastSeenAt, session.ExpiresAt, nil),
+				)
+			},
+		},
+		{
+			name: "failed not found",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: entity.Session{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			gotResult, err := sr.GetSessionByIDDB(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.GetSessionByIDDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("SessionRepository.GetSessionByIDDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_GetActiveSessionsByUserIDDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetSessionQuery + `WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY last_seen_at DESC`
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.Session
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantResults: []entity.Session{session},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1), sqlmock.AnyArg()).WillReturnRows(
+					sqlmock.NewRows(sessionColumns).
+						AddRow(session.ID, session.UserID, session.UserAgent, session.IP, session.CreatedAt, session.LastSeenAt, session.ExpiresAt, nil),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(1), sqlmock.AnyArg()).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			gotResults, err := sr.GetActiveSessionsByUserIDDB(tt.args.ctx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.GetActiveSessionsByUserIDDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("SessionRepository.GetActiveSessionsByUserIDDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_UpdateSessionLastSeenDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateSessionLastSeenQuery
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			if err := sr.UpdateSessionLastSeenDB(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.UpdateSessionLastSeenDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_RevokeSessionDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := RevokeSessionQuery
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		id     int64
+		userID int64
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		wantErrIs error
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				id:     1,
+				userID: 1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed session of other user",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				id:     1,
+				userID: 2,
+			},
+			wantErr:   true,
+			wantErrIs: sql.ErrNoRows,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				id:     1,
+				userID: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			err := sr.RevokeSessionDB(tt.args.ctx, tt.args.id, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.RevokeSessionDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("SessionRepository.RevokeSessionDB() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_RevokeUserSessionsDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := RevokeUserSessionsQuery
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			if err := sr.RevokeUserSessionsDB(tt.args.ctx, tt.args.userID); (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.RevokeUserSessionsDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_RevokeOtherUserSessionsDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := RevokeOtherUserSessionsQuery
+
+	type fields struct {
+		SessionDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+		keepID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+				keepID: 3,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(3)).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+				keepID: 3,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), int64(3)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SessionRepository{
+				SessionDB: tt.fields.SessionDB,
+			}
+			if err := sr.RevokeOtherUserSessionsDB(tt.args.ctx, tt.args.userID, tt.args.keepID); (err != nil) != tt.wantErr {
+				t.Errorf("SessionRepository.RevokeOtherUserSessionsDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	entity "github.com/winartodev/go-pokedex/entity"
+
+	time "time"
 )
 
 // TypeRepositoryItf is an autogenerated mock type for the TypeRepositoryItf type
@@ -35,6 +37,20 @@ func (_m *TypeRepositoryItf) CreateTypeDB(ctx context.Context, data entity.Type)
 	return r0, r1
 }
 
+// DeleteTypeDB provides a mock function with given fields: ctx, id, version
+func (_m *TypeRepositoryItf) DeleteTypeDB(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GeTypeByIDDB provides a mock function with given fields: ctx, id
 func (_m *TypeRepositoryItf) GeTypeByIDDB(ctx context.Context, id int64) (entity.Type, error) {
 	ret := _m.Called(ctx, id)
@@ -79,6 +95,78 @@ func (_m *TypeRepositoryItf) GetAllTypeDB(ctx context.Context) ([]entity.Type, e
 	return r0, r1
 }
 
+// GetDeletedTypeDB provides a mock function with given fields: ctx
+func (_m *TypeRepositoryItf) GetDeletedTypeDB(ctx context.Context) ([]entity.Type, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Type
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Type); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Type)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTypeByNameDB provides a mock function with given fields: ctx, name
+func (_m *TypeRepositoryItf) GetTypeByNameDB(ctx context.Context, name string) (entity.Type, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 entity.Type
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.Type); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(entity.Type)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeTypeDB provides a mock function with given fields: ctx, before
+func (_m *TypeRepositoryItf) PurgeTypeDB(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreTypeDB provides a mock function with given fields: ctx, id
+func (_m *TypeRepositoryItf) RestoreTypeDB(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTypeDB provides a mock function with given fields: ctx, id, data
 func (_m *TypeRepositoryItf) UpdateTypeDB(ctx context.Context, id int64, data entity.Type) error {
 	ret := _m.Called(ctx, id, data)
@@ -4,10 +4,23 @@ const (
 	GetTypesQuery = `
 		SELECT
 			id,
-			name
+			name,
+			version
 		FROM pokedex.types
 	`
 
+	// types in the trash are also found so names stay unique when they are restored
+	GetTypeByNameQuery = `
+		SELECT
+			id,
+			name,
+			version,
+			deleted_at
+		FROM pokedex.types
+		WHERE name = ?
+		LIMIT 1
+	`
+
 	InsertTypeQuery = `
 		INSERT INTO pokedex.types 
 		(
@@ -22,7 +35,40 @@ const (
 	UpdateTypeQuery = `
 		UPDATE pokedex.types  
 		SET 
-			name = ?
-		WHERE id = ? 
+			name = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	DeleteTypeQuery = `
+		UPDATE pokedex.types
+		SET
+			deleted_at = CURRENT_TIMESTAMP,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	GetDeletedTypesQuery = `
+		SELECT
+			id,
+			name,
+			version,
+			deleted_at
+		FROM pokedex.types
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	RestoreTypeQuery = `
+		UPDATE pokedex.types
+		SET
+			deleted_at = NULL,
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+
+	PurgeTypeQuery = `
+		DELETE FROM pokedex.types
+		WHERE deleted_at < ?
 	`
 )
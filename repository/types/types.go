@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
 )
 
 type TypeRepository struct {
@@ -16,7 +18,12 @@ type TypeRepositoryItf interface {
 	CreateTypeDB(ctx context.Context, data entity.Type) (id int64, err error)
 	GetAllTypeDB(ctx context.Context) (results []entity.Type, err error)
 	GeTypeByIDDB(ctx context.Context, id int64) (result entity.Type, err error)
+	GetTypeByNameDB(ctx context.Context, name string) (result entity.Type, err error)
 	UpdateTypeDB(ctx context.Context, id int64, data entity.Type) (err error)
+	DeleteTypeDB(ctx context.Context, id int64, version int64) (err error)
+	GetDeletedTypeDB(ctx context.Context) (results []entity.Type, err error)
+	RestoreTypeDB(ctx context.Context, id int64) (err error)
+	PurgeTypeDB(ctx context.Context, before time.Time) (err error)
 }
 
 func NewTypeRepository(db *sql.DB) TypeRepositoryItf {
@@ -26,7 +33,7 @@ func NewTypeRepository(db *sql.DB) TypeRepositoryItf {
 }
 
 func (tr *TypeRepository) CreateTypeDB(ctx context.Context, data entity.Type) (id int64, err error) {
-	row, err := tr.TypeDB.ExecContext(ctx, InsertTypeQuery, &data.Name)
+	row, err := transaction.Conn(ctx, tr.TypeDB).ExecContext(ctx, InsertTypeQuery, &data.Name)
 	if err != nil {
 		return id, err
 	}
@@ -40,7 +47,7 @@ func (tr *TypeRepository) CreateTypeDB(ctx context.Context, data entity.Type) (i
 }
 
 func (tr *TypeRepository) GetAllTypeDB(ctx context.Context) (results []entity.Type, err error) {
-	rows, err := tr.TypeDB.QueryContext(ctx, GetTypesQuery)
+	rows, err := transaction.Conn(ctx, tr.TypeDB).QueryContext(ctx, fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE deleted_at IS NULL`))
 	if err != nil {
 		return results, err
 	}
@@ -48,7 +55,7 @@ func (tr *TypeRepository) GetAllTypeDB(ctx context.Context) (results []entity.Ty
 	for rows.Next() {
 		var row entity.Type
 
-		err := rows.Scan(&row.ID, &row.Name)
+		err := rows.Scan(&row.ID, &row.Name, &row.Version)
 		if err != nil {
 			return results, err
 		}
@@ -60,7 +67,7 @@ func (tr *TypeRepository) GetAllTypeDB(ctx context.Context) (results []entity.Ty
 }
 
 func (tr *TypeRepository) GeTypeByIDDB(ctx context.Context, id int64) (result entity.Type, err error) {
-	err = tr.TypeDB.QueryRowContext(ctx, fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE id = ?`), id).Scan(&result.ID, &result.Name)
+	err = transaction.Conn(ctx, tr.TypeDB).QueryRowContext(ctx, fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE id = ? AND deleted_at IS NULL`), id).Scan(&result.ID, &result.Name, &result.Version)
 	if err != nil {
 		return result, err
 	}
@@ -68,8 +75,100 @@ func (tr *TypeRepository) GeTypeByIDDB(ctx context.Context, id int64) (result en
 	return result, err
 }
 
+// GetTypeByNameDB returns the type with the name, names are compared by the case insensitive collation of the table.
+// Types in the trash are also found so names stay unique when they are restored, DeletedAt is set for them
+func (tr *TypeRepository) GetTypeByNameDB(ctx context.Context, name string) (result entity.Type, err error) {
+	err = transaction.Conn(ctx, tr.TypeDB).QueryRowContext(ctx, GetTypeByNameQuery, name).Scan(&result.ID, &result.Name, &result.Version, &result.DeletedAt)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// UpdateTypeDB updates the type when its version is still data.Version and increments the version,
+// sql.ErrNoRows is returned when the type was changed in the meantime
 func (tr *TypeRepository) UpdateTypeDB(ctx context.Context, id int64, data entity.Type) (err error) {
-	_, err = tr.TypeDB.ExecContext(ctx, UpdateTypeQuery, data.Name, id)
+	row, err := transaction.Conn(ctx, tr.TypeDB).ExecContext(ctx, UpdateTypeQuery, data.Name, id, data.Version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// DeleteTypeDB moves the type to the trash when its version is still version,
+// sql.ErrNoRows is returned when the type was changed in the meantime
+func (tr *TypeRepository) DeleteTypeDB(ctx context.Context, id int64, version int64) (err error) {
+	row, err := transaction.Conn(ctx, tr.TypeDB).ExecContext(ctx, DeleteTypeQuery, id, version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// GetDeletedTypeDB returns types in the trash, the most recently deleted first
+func (tr *TypeRepository) GetDeletedTypeDB(ctx context.Context) (results []entity.Type, err error) {
+	rows, err := transaction.Conn(ctx, tr.TypeDB).QueryContext(ctx, GetDeletedTypesQuery)
+	if err != nil {
+		return results, err
+	}
+
+	for rows.Next() {
+		var row entity.Type
+
+		err := rows.Scan(&row.ID, &row.Name, &row.Version, &row.DeletedAt)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, row)
+	}
+
+	return results, err
+}
+
+// RestoreTypeDB takes the type out of the trash, sql.ErrNoRows is returned when the type is not in the trash
+func (tr *TypeRepository) RestoreTypeDB(ctx context.Context, id int64) (err error) {
+	row, err := transaction.Conn(ctx, tr.TypeDB).ExecContext(ctx, RestoreTypeQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+// PurgeTypeDB permanently deletes types that were moved to the trash before the time
+func (tr *TypeRepository) PurgeTypeDB(ctx context.Context, before time.Time) (err error) {
+	_, err = transaction.Conn(ctx, tr.TypeDB).ExecContext(ctx, PurgeTypeQuery, before)
 	if err != nil {
 		return err
 	}
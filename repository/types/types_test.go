@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/winartodev/go-pokedex/entity"
@@ -130,7 +131,7 @@ func TestTypeRepository_CreateTypeDB(t *testing.T) {
 func TestTypeRepository_GetAllTypeDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
-	query := GetTypesQuery
+	query := fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE deleted_at IS NULL`)
 	typeData := []entity.Type{
 		{Name: "FIRE"},
 	}
@@ -160,8 +161,8 @@ func TestTypeRepository_GetAllTypeDB(t *testing.T) {
 			wantResults: typeData,
 			wantErr:     false,
 			mock: func() {
-				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-					AddRow(typeData[0].ID, typeData[0].Name))
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).
+					AddRow(typeData[0].ID, typeData[0].Name, typeData[0].Version))
 			},
 		},
 		{
@@ -202,7 +203,7 @@ func TestTypeRepository_GeTypeByIDDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
 	id := 1
-	query := fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE id = ?`)
+	query := regexp.QuoteMeta(fmt.Sprintf(`%s %s`, GetTypesQuery, `WHERE id = ? AND deleted_at IS NULL`))
 	typeData := entity.Type{
 		Name: "FIRE",
 	}
@@ -235,7 +236,7 @@ func TestTypeRepository_GeTypeByIDDB(t *testing.T) {
 			wantErr:    false,
 			mock: func() {
 				dbmock.ExpectQuery(query).WithArgs(id).WillReturnRows(
-					sqlmock.NewRows([]string{"id", "name"}).AddRow(typeData.ID, typeData.Name),
+					sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(typeData.ID, typeData.Name, typeData.Version),
 				)
 			},
 		},
@@ -274,6 +275,82 @@ func TestTypeRepository_GeTypeByIDDB(t *testing.T) {
 	}
 }
 
+func TestTypeRepository_GetTypeByNameDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	name := "FIRE"
+	query := regexp.QuoteMeta(GetTypeByNameQuery)
+	typeData := entity.Type{
+		Name: "FIRE",
+	}
+
+	type fields struct {
+		TypeDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		name string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.Type
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				name: name,
+			},
+			wantResult: typeData,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(name).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "name", "version", "deleted_at"}).AddRow(typeData.ID, typeData.Name, typeData.Version, nil),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				name: name,
+			},
+			wantResult: entity.Type{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WithArgs(name).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeRepository{
+				TypeDB: tt.fields.TypeDB,
+			}
+			gotResult, err := tr.GetTypeByNameDB(tt.args.ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TypeRepository.GetTypeByNameDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("TypeRepository.GetTypeByNameDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
 func TestTypeRepository_UpdateTypeDB(t *testing.T) {
 	db, dbmock := NewMock()
 	ctx := context.Background()
@@ -312,7 +389,7 @@ func TestTypeRepository_UpdateTypeDB(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(typeData.Name, id).WillReturnResult(sqlmock.NewResult(0, 0))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(typeData.Name, id, typeData.Version).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
@@ -329,7 +406,24 @@ func TestTypeRepository_UpdateTypeDB(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(typeData.Name, id).WillReturnError(errors.New("error"))
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(typeData.Name, id, typeData.Version).WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "failed version changed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+				data: entity.Type{
+					Name: "FIRE",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(typeData.Name, id, typeData.Version).WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
 	}
@@ -346,3 +440,294 @@ func TestTypeRepository_UpdateTypeDB(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeRepository_DeleteTypeDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(DeleteTypeQuery)
+
+	type fields struct {
+		TypeDB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed version changed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1, 2).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeRepository{
+				TypeDB: tt.fields.TypeDB,
+			}
+			if err := tr.DeleteTypeDB(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
+				t.Errorf("TypeRepository.DeleteTypeDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeRepository_RestoreTypeDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(RestoreTypeQuery)
+
+	type fields struct {
+		TypeDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed not in trash",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(1).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeRepository{
+				TypeDB: tt.fields.TypeDB,
+			}
+			if err := tr.RestoreTypeDB(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("TypeRepository.RestoreTypeDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeRepository_PurgeTypeDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	before := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(PurgeTypeQuery)
+
+	type fields struct {
+		TypeDB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		before time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				before: before,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				before: before,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(query).WithArgs(before).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeRepository{
+				TypeDB: tt.fields.TypeDB,
+			}
+			if err := tr.PurgeTypeDB(tt.args.ctx, tt.args.before); (err != nil) != tt.wantErr {
+				t.Errorf("TypeRepository.PurgeTypeDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeRepository_GetDeletedTypeDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := regexp.QuoteMeta(GetDeletedTypesQuery)
+	deletedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	typeData := []entity.Type{
+		{ID: 1, Name: "FIRE", Version: 2, DeletedAt: &deletedAt},
+	}
+
+	type fields struct {
+		TypeDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.Type
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: typeData,
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version", "deleted_at"}).
+					AddRow(typeData[0].ID, typeData[0].Name, typeData[0].Version, deletedAt))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				TypeDB: db,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(query).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeRepository{
+				TypeDB: tt.fields.TypeDB,
+			}
+			gotResults, err := tr.GetDeletedTypeDB(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TypeRepository.GetDeletedTypeDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("TypeRepository.GetDeletedTypeDB() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
@@ -35,6 +35,134 @@ func (_m *UserRepositoryItf) CreateUser(ctx context.Context, username string, em
 	return r0, r1
 }
 
+// CreateUserIdentity provides a mock function with given fields: ctx, userID, issuer, subject
+func (_m *UserRepositoryItf) CreateUserIdentity(ctx context.Context, userID int64, issuer string, subject string) error {
+	ret := _m.Called(ctx, userID, issuer, subject)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, userID, issuer, subject)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserRoleAudit provides a mock function with given fields: ctx, data
+func (_m *UserRepositoryItf) CreateUserRoleAudit(ctx context.Context, data entity.UserRoleAudit) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserRoleAudit) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryItf) DeleteUserByID(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllUsers provides a mock function with given fields: ctx, filter
+func (_m *UserRepositoryItf) GetAllUsers(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.User
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserFilter) []entity.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepositoryItf) GetUserByEmail(ctx context.Context, email string) (entity.User, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 entity.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryItf) GetUserByID(ctx context.Context, id int64) (entity.User, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.User
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByIdentity provides a mock function with given fields: ctx, issuer, subject
+func (_m *UserRepositoryItf) GetUserByIdentity(ctx context.Context, issuer string, subject string) (entity.User, error) {
+	ret := _m.Called(ctx, issuer, subject)
+
+	var r0 entity.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.User); ok {
+		r0 = rf(ctx, issuer, subject)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByUsername provides a mock function with given fields: ctx, username
 func (_m *UserRepositoryItf) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
 	ret := _m.Called(ctx, username)
@@ -56,6 +184,111 @@ func (_m *UserRepositoryItf) GetUserByUsername(ctx context.Context, username str
 	return r0, r1
 }
 
+// UpdateUserDisabled provides a mock function with given fields: ctx, id, disabled
+func (_m *UserRepositoryItf) UpdateUserDisabled(ctx context.Context, id int64, disabled bool) error {
+	ret := _m.Called(ctx, id, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, id, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserPassword provides a mock function with given fields: ctx, id, password
+func (_m *UserRepositoryItf) UpdateUserPassword(ctx context.Context, id int64, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserProfile provides a mock function with given fields: ctx, id, email, displayName
+func (_m *UserRepositoryItf) UpdateUserProfile(ctx context.Context, id int64, email string, displayName string) error {
+	ret := _m.Called(ctx, id, email, displayName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, id, email, displayName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserRole provides a mock function with given fields: ctx, id, role
+func (_m *UserRepositoryItf) UpdateUserRole(ctx context.Context, id int64, role int64) error {
+	ret := _m.Called(ctx, id, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserTOTP provides a mock function with given fields: ctx, id, secret, enabled
+func (_m *UserRepositoryItf) UpdateUserTOTP(ctx context.Context, id int64, secret string, enabled bool) error {
+	ret := _m.Called(ctx, id, secret, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, bool) error); ok {
+		r0 = rf(ctx, id, secret, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, id, step
+func (_m *UserRepositoryItf) UseTOTPStep(ctx context.Context, id int64, step int64) (bool, error) {
+	ret := _m.Called(ctx, id, step)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
+		r0 = rf(ctx, id, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyUserEmail provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryItf) VerifyUserEmail(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepositoryItf interface {
 	mock.TestingT
 	Cleanup(func())
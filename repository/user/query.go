@@ -24,7 +24,105 @@ const (
 			username,
 			email,
 			password,
-			role
+			role,
+			display_name,
+			disabled,
+			email_verified_at IS NOT NULL,
+			totp_secret,
+			totp_enabled
 		FROM pokedex.users 
 	`
+
+	UpdateUserRoleQuery = `
+		UPDATE pokedex.users
+		SET
+			role = ?
+		WHERE id = ?
+	`
+
+	// a new email is not verified yet, mysql assigns from left to right so email is still the old one when
+	// email_verified_at is assigned
+	UpdateUserProfileQuery = `
+		UPDATE pokedex.users
+		SET
+			email_verified_at = IF(email = ?, email_verified_at, NULL),
+			email = ?,
+			display_name = ?
+		WHERE id = ?
+	`
+
+	UpdateUserPasswordQuery = `
+		UPDATE pokedex.users
+		SET
+			password = ?
+		WHERE id = ?
+	`
+
+	UpdateUserDisabledQuery = `
+		UPDATE pokedex.users
+		SET
+			disabled = ?
+		WHERE id = ?
+	`
+
+	VerifyUserEmailQuery = `
+		UPDATE pokedex.users
+		SET
+			email_verified_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND email_verified_at IS NULL
+	`
+
+	UpdateUserTOTPQuery = `
+		UPDATE pokedex.users
+		SET
+			totp_secret = ?,
+			totp_enabled = ?
+		WHERE id = ?
+	`
+
+	UseTOTPStepQuery = `
+		UPDATE pokedex.users
+		SET
+			totp_last_step = ?
+		WHERE id = ? AND totp_last_step < ?
+	`
+
+	DeleteUserQuery = `
+		DELETE FROM pokedex.users
+		WHERE id = ?
+	`
+
+	InsertUserRoleAuditQuery = `
+		INSERT INTO pokedex.user_role_audits
+			(
+				user_id,
+				actor_id,
+				old_role,
+				new_role
+			)
+		VALUES
+		(
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	// the identity of a deleted user is moved to the account it is linked to again
+	InsertUserIdentityQuery = `
+		INSERT INTO pokedex.user_identities
+			(
+				user_id,
+				issuer,
+				subject
+			)
+		VALUES
+		(
+			?,
+			?,
+			?
+		)
+		ON DUPLICATE KEY UPDATE user_id = VALUES(user_id)
+	`
 )
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
 )
 
 type UserRepository struct {
@@ -15,6 +16,20 @@ type UserRepository struct {
 type UserRepositoryItf interface {
 	CreateUser(ctx context.Context, username string, email string, password string, role int64) (id int64, err error)
 	GetUserByUsername(ctx context.Context, username string) (result entity.User, err error)
+	GetUserByID(ctx context.Context, id int64) (result entity.User, err error)
+	GetUserByEmail(ctx context.Context, email string) (result entity.User, err error)
+	GetUserByIdentity(ctx context.Context, issuer string, subject string) (result entity.User, err error)
+	CreateUserIdentity(ctx context.Context, userID int64, issuer string, subject string) (err error)
+	UpdateUserRole(ctx context.Context, id int64, role int64) (err error)
+	CreateUserRoleAudit(ctx context.Context, data entity.UserRoleAudit) (err error)
+	GetAllUsers(ctx context.Context, filter entity.UserFilter) (results []entity.User, err error)
+	UpdateUserProfile(ctx context.Context, id int64, email string, displayName string) (err error)
+	UpdateUserPassword(ctx context.Context, id int64, password string) (err error)
+	UpdateUserDisabled(ctx context.Context, id int64, disabled bool) (err error)
+	VerifyUserEmail(ctx context.Context, id int64) (err error)
+	UpdateUserTOTP(ctx context.Context, id int64, secret string, enabled bool) (err error)
+	UseTOTPStep(ctx context.Context, id int64, step int64) (used bool, err error)
+	DeleteUserByID(ctx context.Context, id int64) (err error)
 }
 
 func NewUserRepository(db *sql.DB) *UserRepository {
@@ -22,7 +37,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 func (ur *UserRepository) CreateUser(ctx context.Context, username string, email string, password string, role int64) (id int64, err error) {
-	row, err := ur.DB.ExecContext(ctx, InsertUserQuery, username, email, password, role)
+	row, err := transaction.Conn(ctx, ur.DB).ExecContext(ctx, InsertUserQuery, username, email, password, role)
 	if err != nil {
 		return id, err
 	}
@@ -36,10 +51,172 @@ func (ur *UserRepository) CreateUser(ctx context.Context, username string, email
 }
 
 func (ur *UserRepository) GetUserByUsername(ctx context.Context, username string) (result entity.User, err error) {
-	err = ur.DB.QueryRowContext(ctx, fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE username = ?`), username).Scan(&result.ID, &result.Username, &result.Email, &result.Password, &result.Role)
+	err = transaction.Conn(ctx, ur.DB).QueryRowContext(ctx, fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE username = ?`), username).Scan(&result.ID, &result.Username, &result.Email, &result.Password, &result.Role, &result.DisplayName, &result.Disabled, &result.EmailVerified, &result.TOTPSecret, &result.TOTPEnabled)
 	if err != nil {
 		return result, err
 	}
 
 	return result, err
 }
+
+func (ur *UserRepository) GetUserByID(ctx context.Context, id int64) (result entity.User, err error) {
+	err = transaction.Conn(ctx, ur.DB).QueryRowContext(ctx, fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE id = ?`), id).Scan(&result.ID, &result.Username, &result.Email, &result.Password, &result.Role, &result.DisplayName, &result.Disabled, &result.EmailVerified, &result.TOTPSecret, &result.TOTPEnabled)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (result entity.User, err error) {
+	err = transaction.Conn(ctx, ur.DB).QueryRowContext(ctx, fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE email = ?`), email).Scan(&result.ID, &result.Username, &result.Email, &result.Password, &result.Role, &result.DisplayName, &result.Disabled, &result.EmailVerified, &result.TOTPSecret, &result.TOTPEnabled)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// GetUserByIdentity returns the user linked to the subject of an external identity provider
+func (ur *UserRepository) GetUserByIdentity(ctx context.Context, issuer string, subject string) (result entity.User, err error) {
+	err = transaction.Conn(ctx, ur.DB).QueryRowContext(ctx, fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE id = (SELECT user_id FROM pokedex.user_identities WHERE issuer = ? AND subject = ?)`), issuer, subject).Scan(&result.ID, &result.Username, &result.Email, &result.Password, &result.Role, &result.DisplayName, &result.Disabled, &result.EmailVerified, &result.TOTPSecret, &result.TOTPEnabled)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// CreateUserIdentity links the subject of an external identity provider to the user
+func (ur *UserRepository) CreateUserIdentity(ctx context.Context, userID int64, issuer string, subject string) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, InsertUserIdentityQuery, userID, issuer, subject)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) UpdateUserRole(ctx context.Context, id int64, role int64) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, UpdateUserRoleQuery, role, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) CreateUserRoleAudit(ctx context.Context, data entity.UserRoleAudit) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, InsertUserRoleAuditQuery, data.UserID, data.ActorID, data.OldRole, data.NewRole)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) GetAllUsers(ctx context.Context, filter entity.UserFilter) (results []entity.User, err error) {
+	query := GetUserQuery
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		query += `WHERE username LIKE ? OR email LIKE ? OR display_name LIKE ? `
+		search := fmt.Sprint("%", filter.Search, "%")
+		args = append(args, search, search, search)
+	}
+
+	query += `ORDER BY id `
+
+	if filter.Limit > 0 {
+		query += `LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := transaction.Conn(ctx, ur.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return results, err
+	}
+
+	for rows.Next() {
+		var row entity.User
+
+		err := rows.Scan(&row.ID, &row.Username, &row.Email, &row.Password, &row.Role, &row.DisplayName, &row.Disabled, &row.EmailVerified, &row.TOTPSecret, &row.TOTPEnabled)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, row)
+	}
+
+	return results, err
+}
+
+func (ur *UserRepository) UpdateUserProfile(ctx context.Context, id int64, email string, displayName string) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, UpdateUserProfileQuery, email, email, displayName, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) UpdateUserPassword(ctx context.Context, id int64, password string) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, UpdateUserPasswordQuery, password, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) UpdateUserDisabled(ctx context.Context, id int64, disabled bool) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, UpdateUserDisabledQuery, disabled, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) VerifyUserEmail(ctx context.Context, id int64) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, VerifyUserEmailQuery, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (ur *UserRepository) UpdateUserTOTP(ctx context.Context, id int64, secret string, enabled bool) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, UpdateUserTOTPQuery, secret, enabled, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// UseTOTPStep records the time step of an accepted totp code, used is false when a code of that step or a later one
+// was already accepted
+func (ur *UserRepository) UseTOTPStep(ctx context.Context, id int64, step int64) (used bool, err error) {
+	row, err := transaction.Conn(ctx, ur.DB).ExecContext(ctx, UseTOTPStepQuery, step, id, step)
+	if err != nil {
+		return used, err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return used, err
+	}
+
+	return affected == 1, err
+}
+
+func (ur *UserRepository) DeleteUserByID(ctx context.Context, id int64) (err error) {
+	_, err = transaction.Conn(ctx, ur.DB).ExecContext(ctx, DeleteUserQuery, id)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
@@ -179,8 +179,8 @@ func TestUserRepository_GetUserByUsername(t *testing.T) {
 			wantErr:    false,
 			mock: func() {
 				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(username).WillReturnRows(
-					sqlmock.NewRows([]string{"id", "username", "email", "password", "role"}).
-						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role),
+					sqlmock.NewRows([]string{"id", "username", "email", "password", "role", "display_name", "disabled", "email_verified", "totp_secret", "totp_enabled"}).
+						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
 				)
 			},
 		},
@@ -218,3 +218,1050 @@ func TestUserRepository_GetUserByUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_GetUserByID(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	id := int64(1)
+	query := fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE id = ?`)
+	user := entity.User{
+		ID:       1,
+		Username: "ganteng",
+		Email:    "ganteng@mail.com",
+		Password: "ganteng banget",
+		Role:     1,
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.User
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: user,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(id).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "username", "email", "password", "role", "display_name", "disabled", "email_verified", "totp_secret", "totp_enabled"}).
+						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: entity.User{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(id).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			gotResult, err := ur.GetUserByID(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.GetUserByID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserRepository.GetUserByID() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUserRole(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateUserRoleQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		role int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				role: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(2), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				role: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(2), int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.UpdateUserRole(tt.args.ctx, tt.args.id, tt.args.role); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UpdateUserRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_CreateUserRoleAudit(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertUserRoleAuditQuery
+	audit := entity.UserRoleAudit{
+		UserID:  2,
+		ActorID: 1,
+		OldRole: 1,
+		NewRole: 2,
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.UserRoleAudit
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: audit,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(audit.UserID, audit.ActorID, audit.OldRole, audit.NewRole).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: audit,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(audit.UserID, audit.ActorID, audit.OldRole, audit.NewRole).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.CreateUserRoleAudit(tt.args.ctx, tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.CreateUserRoleAudit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetAllUsers(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	user := entity.User{
+		ID:          1,
+		Username:    "ganteng",
+		Email:       "ganteng@mail.com",
+		Password:    "ganteng banget",
+		Role:        1,
+		DisplayName: "Ganteng",
+	}
+	columns := []string{"id", "username", "email", "password", "role", "display_name", "disabled", "email_verified", "totp_secret", "totp_enabled"}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		filter entity.UserFilter
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.User
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success without filter",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.UserFilter{},
+			},
+			wantResults: []entity.User{user},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetUserQuery + `ORDER BY id `)).WillReturnRows(
+					sqlmock.NewRows(columns).
+						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
+				)
+			},
+		},
+		{
+			name: "success with search and pagination",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.UserFilter{Search: "gan", Limit: 10, Offset: 20},
+			},
+			wantResults: []entity.User{user},
+			wantErr:     false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetUserQuery+`WHERE username LIKE ? OR email LIKE ? OR display_name LIKE ? ORDER BY id LIMIT ? OFFSET ?`)).
+					WithArgs("%gan%", "%gan%", "%gan%", int64(10), int64(20)).
+					WillReturnRows(
+						sqlmock.NewRows(columns).
+							AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
+					)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.UserFilter{},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(GetUserQuery + `ORDER BY id `)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			gotResults, err := ur.GetAllUsers(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.GetAllUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("UserRepository.GetAllUsers() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUserProfile(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateUserProfileQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx         context.Context
+		id          int64
+		email       string
+		displayName string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:         ctx,
+				id:          1,
+				email:       "ganteng@mail.com",
+				displayName: "Ganteng",
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("ganteng@mail.com", "ganteng@mail.com", "Ganteng", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:         ctx,
+				id:          1,
+				email:       "ganteng@mail.com",
+				displayName: "Ganteng",
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("ganteng@mail.com", "ganteng@mail.com", "Ganteng", int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.UpdateUserProfile(tt.args.ctx, tt.args.id, tt.args.email, tt.args.displayName); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UpdateUserProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUserPassword(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateUserPasswordQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		password string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				password: "hash",
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("hash", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				password: "hash",
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("hash", int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.UpdateUserPassword(tt.args.ctx, tt.args.id, tt.args.password); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UpdateUserPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUserDisabled(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateUserDisabledQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		disabled bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				disabled: true,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(true, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				disabled: true,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(true, int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.UpdateUserDisabled(tt.args.ctx, tt.args.id, tt.args.disabled); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UpdateUserDisabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_DeleteUserByID(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := DeleteUserQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.DeleteUserByID(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.DeleteUserByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUserByEmail(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	email := "ganteng@mail.com"
+	query := fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE email = ?`)
+	user := entity.User{
+		ID:       1,
+		Username: "ganteng",
+		Email:    "ganteng@mail.com",
+		Password: "ganteng banget",
+		Role:     1,
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx   context.Context
+		email string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.User
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:   ctx,
+				email: "ganteng@mail.com",
+			},
+			wantResult: user,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(email).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "username", "email", "password", "role", "display_name", "disabled", "email_verified", "totp_secret", "totp_enabled"}).
+						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:   ctx,
+				email: "ganteng@mail.com",
+			},
+			wantResult: entity.User{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(email).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			gotResult, err := ur.GetUserByEmail(tt.args.ctx, tt.args.email)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.GetUserByEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserRepository.GetUserByEmail() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUserByIdentity(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := fmt.Sprintf(`%v %v`, GetUserQuery, `WHERE id = (SELECT user_id FROM pokedex.user_identities WHERE issuer = ? AND subject = ?)`)
+	user := entity.User{
+		ID:       1,
+		Username: "ganteng",
+		Email:    "ganteng@mail.com",
+		Password: "ganteng banget",
+		Role:     1,
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		issuer  string
+		subject string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.User
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				issuer:  "https://sso.mail.com",
+				subject: "ganteng-1",
+			},
+			wantResult: user,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("https://sso.mail.com", "ganteng-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id", "username", "email", "password", "role", "display_name", "disabled", "email_verified", "totp_secret", "totp_enabled"}).
+						AddRow(user.ID, user.Username, user.Email, user.Password, user.Role, user.DisplayName, user.Disabled, user.EmailVerified, user.TOTPSecret, user.TOTPEnabled),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				issuer:  "https://sso.mail.com",
+				subject: "ganteng-1",
+			},
+			wantResult: entity.User{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("https://sso.mail.com", "ganteng-1").WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			gotResult, err := ur.GetUserByIdentity(tt.args.ctx, tt.args.issuer, tt.args.subject)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.GetUserByIdentity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserRepository.GetUserByIdentity() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserRepository_CreateUserIdentity(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertUserIdentityQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		userID  int64
+		issuer  string
+		subject string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				userID:  1,
+				issuer:  "https://sso.mail.com",
+				subject: "ganteng-1",
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "https://sso.mail.com", "ganteng-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				userID:  1,
+				issuer:  "https://sso.mail.com",
+				subject: "ganteng-1",
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "https://sso.mail.com", "ganteng-1").
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.CreateUserIdentity(tt.args.ctx, tt.args.userID, tt.args.issuer, tt.args.subject); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.CreateUserIdentity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_VerifyUserEmail(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := VerifyUserEmailQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.VerifyUserEmail(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.VerifyUserEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUserTOTP(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UpdateUserTOTPQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		secret  string
+		enabled bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				secret:  "SECRET",
+				enabled: true,
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("SECRET", true, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				secret:  "SECRET",
+				enabled: true,
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("SECRET", true, int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			if err := ur.UpdateUserTOTP(tt.args.ctx, tt.args.id, tt.args.secret, tt.args.enabled); (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UpdateUserTOTP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UseTOTPStep(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UseTOTPStepQuery
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		step int64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantUsed bool
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				step: 37037036,
+			},
+			wantUsed: true,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(37037036), int64(1), int64(37037036)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "success step already used",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				step: 37037036,
+			},
+			wantUsed: false,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(37037036), int64(1), int64(37037036)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				step: 37037036,
+			},
+			wantUsed: false,
+			wantErr:  true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(37037036), int64(1), int64(37037036)).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &UserRepository{
+				DB: tt.fields.DB,
+			}
+			gotUsed, err := ur.UseTOTPStep(tt.args.ctx, tt.args.id, tt.args.step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.UseTOTPStep() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotUsed != tt.wantUsed {
+				t.Errorf("UserRepository.UseTOTPStep() = %v, want %v", gotUsed, tt.wantUsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package usertokenrepositorymock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// UserTokenRepositoryItf is an autogenerated mock type for the UserTokenRepositoryItf type
+type UserTokenRepositoryItf struct {
+	mock.Mock
+}
+
+// CreateUserTokenDB provides a mock function with given fields: ctx, data
+func (_m *UserTokenRepositoryItf) CreateUserTokenDB(ctx context.Context, data entity.UserToken) (int64, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserToken) int64); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserToken) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTokenByHashDB provides a mock function with given fields: ctx, tokenHash, purpose
+func (_m *UserTokenRepositoryItf) GetUserTokenByHashDB(ctx context.Context, tokenHash string, purpose string) (entity.UserToken, error) {
+	ret := _m.Called(ctx, tokenHash, purpose)
+
+	var r0 entity.UserToken
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.UserToken); ok {
+		r0 = rf(ctx, tokenHash, purpose)
+	} else {
+		r0 = ret.Get(0).(entity.UserToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, tokenHash, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseUserTokenDB provides a mock function with given fields: ctx, id
+func (_m *UserTokenRepositoryItf) UseUserTokenDB(ctx context.Context, id int64) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseUserTokensDB provides a mock function with given fields: ctx, userID, purpose
+func (_m *UserTokenRepositoryItf) UseUserTokensDB(ctx context.Context, userID int64, purpose string) error {
+	ret := _m.Called(ctx, userID, purpose)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewUserTokenRepositoryItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserTokenRepositoryItf creates a new instance of UserTokenRepositoryItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserTokenRepositoryItf(t mockConstructorTestingTNewUserTokenRepositoryItf) *UserTokenRepositoryItf {
+	mock := &UserTokenRepositoryItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
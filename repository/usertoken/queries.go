@@ -0,0 +1,47 @@
+package usertokenrepository
+
+const (
+	InsertUserTokenQuery = `
+		INSERT INTO pokedex.user_tokens
+		(
+			user_id,
+			purpose,
+			token_hash,
+			email,
+			expires_at
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`
+
+	GetUserTokenByHashQuery = `
+		SELECT
+			id,
+			user_id,
+			purpose,
+			token_hash,
+			email,
+			expires_at,
+			used_at
+		FROM pokedex.user_tokens
+		WHERE token_hash = ? AND purpose = ?
+	`
+
+	UseUserTokenQuery = `
+		UPDATE pokedex.user_tokens
+		SET
+			used_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND used_at IS NULL
+	`
+
+	UseUserTokensQuery = `
+		UPDATE pokedex.user_tokens
+		SET
+			used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND purpose = ? AND used_at IS NULL
+	`
+)
@@ -0,0 +1,74 @@
+package usertokenrepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+type UserTokenRepository struct {
+	UserTokenDB *sql.DB
+}
+
+type UserTokenRepositoryItf interface {
+	CreateUserTokenDB(ctx context.Context, data entity.UserToken) (id int64, err error)
+	GetUserTokenByHashDB(ctx context.Context, tokenHash string, purpose string) (result entity.UserToken, err error)
+	UseUserTokenDB(ctx context.Context, id int64) (used bool, err error)
+	UseUserTokensDB(ctx context.Context, userID int64, purpose string) (err error)
+}
+
+func NewUserTokenRepository(db *sql.DB) UserTokenRepositoryItf {
+	return &UserTokenRepository{
+		UserTokenDB: db,
+	}
+}
+
+func (ut *UserTokenRepository) CreateUserTokenDB(ctx context.Context, data entity.UserToken) (id int64, err error) {
+	row, err := transaction.Conn(ctx, ut.UserTokenDB).ExecContext(ctx, InsertUserTokenQuery, data.UserID, data.Purpose, data.TokenHash, data.Email, data.ExpiresAt)
+	if err != nil {
+		return id, err
+	}
+
+	id, err = row.LastInsertId()
+	if err != nil {
+		return id, err
+	}
+
+	return id, err
+}
+
+func (ut *UserTokenRepository) GetUserTokenByHashDB(ctx context.Context, tokenHash string, purpose string) (result entity.UserToken, err error) {
+	err = transaction.Conn(ctx, ut.UserTokenDB).QueryRowContext(ctx, GetUserTokenByHashQuery, tokenHash, purpose).Scan(&result.ID, &result.UserID, &result.Purpose, &result.TokenHash, &result.Email, &result.ExpiresAt, &result.UsedAt)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// UseUserTokenDB marks token as used, used is false when the token was already used by another request
+func (ut *UserTokenRepository) UseUserTokenDB(ctx context.Context, id int64) (used bool, err error) {
+	row, err := transaction.Conn(ctx, ut.UserTokenDB).ExecContext(ctx, UseUserTokenQuery, id)
+	if err != nil {
+		return used, err
+	}
+
+	affected, err := row.RowsAffected()
+	if err != nil {
+		return used, err
+	}
+
+	return affected == 1, err
+}
+
+// UseUserTokensDB marks every unused token of the user with the purpose as used so none of them can be used anymore
+func (ut *UserTokenRepository) UseUserTokensDB(ctx context.Context, userID int64, purpose string) (err error) {
+	_, err = transaction.Conn(ctx, ut.UserTokenDB).ExecContext(ctx, UseUserTokensQuery, userID, purpose)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package usertokenrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/winartodev/go-pokedex/dbtest"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+)
+
+func TestUserTokenRepository_Integration(t *testing.T) {
+	db := dbtest.Open(t)
+	ut := NewUserTokenRepository(db)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	id, err := ut.CreateUserTokenDB(ctx, entity.UserToken{
+		UserID:    dbtest.SeedUser(t, db, "budi"),
+		Purpose:   enum.EmailVerification.String(),
+		TokenHash: "hash",
+		Email:     "budi@mail.com",
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("UserTokenRepository.CreateUserTokenDB() error = %v", err)
+	}
+
+	token, err := ut.GetUserTokenByHashDB(ctx, "hash", enum.EmailVerification.String())
+	if err != nil {
+		t.Fatalf("UserTokenRepository.GetUserTokenByHashDB() error = %v", err)
+	}
+	if token.ID != id || token.Email != "budi@mail.com" || token.UsedAt.Valid || !dbtest.SameSecond(token.ExpiresAt, expiresAt) {
+		t.Errorf("UserTokenRepository.GetUserTokenByHashDB() = %+v, want email budi@mail.com and expires at %v", token, expiresAt)
+	}
+
+	for _, want := range []bool{true, false} {
+		used, err := ut.UseUserTokenDB(ctx, id)
+		if err != nil || used != want {
+			t.Errorf("UserTokenRepository.UseUserTokenDB() = %v, %v, want %v", used, err, want)
+		}
+	}
+
+	token, err = ut.GetUserTokenByHashDB(ctx, "hash", enum.EmailVerification.String())
+	if err != nil || !token.UsedAt.Valid || time.Since(token.UsedAt.Time) > time.Minute {
+		t.Errorf("UserTokenRepository.GetUserTokenByHashDB() = %+v, %v, want used now", token, err)
+	}
+}
@@ -0,0 +1,365 @@
+package usertokenrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+func TestNewUserTokenRepository(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want UserTokenRepositoryItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &UserTokenRepository{
+				UserTokenDB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewUserTokenRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewUserTokenRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserTokenRepository_CreateUserTokenDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := InsertUserTokenQuery
+	token := entity.UserToken{
+		UserID:    1,
+		Purpose:   "password_reset",
+		TokenHash: "hash",
+		ExpiresAt: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	type fields struct {
+		UserTokenDB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.UserToken
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantId  int64
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: token,
+			},
+			wantId:  1,
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(token.UserID, token.Purpose, token.TokenHash, token.Email, token.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:  ctx,
+				data: token,
+			},
+			wantId:  0,
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(token.UserID, token.Purpose, token.TokenHash, token.Email, token.ExpiresAt).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ut := &UserTokenRepository{
+				UserTokenDB: tt.fields.UserTokenDB,
+			}
+			gotId, err := ut.CreateUserTokenDB(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserTokenRepository.CreateUserTokenDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("UserTokenRepository.CreateUserTokenDB() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestUserTokenRepository_GetUserTokenByHashDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := GetUserTokenByHashQuery
+	token := entity.UserToken{
+		ID:        1,
+		UserID:    1,
+		Purpose:   "email_verification",
+		TokenHash: "hash",
+		Email:     "budi@mail.com",
+		ExpiresAt: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	type fields struct {
+		UserTokenDB *sql.DB
+	}
+	type args struct {
+		ctx       context.Context
+		tokenHash string
+		purpose   string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.UserToken
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				tokenHash: "hash",
+				purpose:   "email_verification",
+			},
+			wantResult: token,
+			wantErr:    false,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash", "email_verification").WillReturnRows(
+					sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "email", "expires_at", "used_at"}).
+						AddRow(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Email, token.ExpiresAt, nil),
+				)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:       ctx,
+				tokenHash: "hash",
+				purpose:   "password_reset",
+			},
+			wantResult: entity.UserToken{},
+			wantErr:    true,
+			mock: func() {
+				dbmock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash", "password_reset").WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ut := &UserTokenRepository{
+				UserTokenDB: tt.fields.UserTokenDB,
+			}
+			gotResult, err := ut.GetUserTokenByHashDB(tt.args.ctx, tt.args.tokenHash, tt.args.purpose)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserTokenRepository.GetUserTokenByHashDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserTokenRepository.GetUserTokenByHashDB() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserTokenRepository_UseUserTokenDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UseUserTokenQuery
+
+	type fields struct {
+		UserTokenDB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantUsed bool
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantUsed: true,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "success token already used",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantUsed: false,
+			wantErr:  false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantUsed: false,
+			wantErr:  true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ut := &UserTokenRepository{
+				UserTokenDB: tt.fields.UserTokenDB,
+			}
+			gotUsed, err := ut.UseUserTokenDB(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserTokenRepository.UseUserTokenDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotUsed != tt.wantUsed {
+				t.Errorf("UserTokenRepository.UseUserTokenDB() = %v, want %v", gotUsed, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestUserTokenRepository_UseUserTokensDB(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+	query := UseUserTokensQuery
+
+	type fields struct {
+		UserTokenDB *sql.DB
+	}
+	type args struct {
+		ctx     context.Context
+		userID  int64
+		purpose string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				userID:  1,
+				purpose: "password_reset",
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "password_reset").WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserTokenDB: db,
+			},
+			args: args{
+				ctx:     ctx,
+				userID:  1,
+				purpose: "password_reset",
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(int64(1), "password_reset").WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ut := &UserTokenRepository{
+				UserTokenDB: tt.fields.UserTokenDB,
+			}
+			if err := ut.UseUserTokensDB(tt.args.ctx, tt.args.userID, tt.args.purpose); (err != nil) != tt.wantErr {
+				t.Errorf("UserTokenRepository.UseUserTokensDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
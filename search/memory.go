@@ -0,0 +1,257 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// similarities of a query word to an indexed word, prefixes are more similar the more of the word they cover
+// and typos lower the similarity by their share of the word
+const (
+	exactSimilarity  = 1
+	prefixSimilarity = 0.7
+	typoSimilarity   = 0.8
+)
+
+// MemoryIndex is an inverted index kept in memory. Indexed words are also indexed by their trigrams,
+// so words with typos are found by the trigrams they share with the query word and checked with their levenshtein distance
+type MemoryIndex struct {
+	mu sync.RWMutex
+	// docs are the indexed documents by id
+	docs map[int64]Document
+	// postings has the weight of every word in the documents it appears in
+	postings map[string]map[int64]float64
+	// trigrams has the indexed words of every trigram
+	trigrams map[string]map[string]bool
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[int64]Document),
+		postings: make(map[string]map[int64]float64),
+		trigrams: make(map[string]map[string]bool),
+	}
+}
+
+func (m *MemoryIndex) Index(doc Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.ID)
+	m.add(doc)
+}
+
+func (m *MemoryIndex) Remove(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(id)
+}
+
+func (m *MemoryIndex) Reset(docs []Document) {
+	fresh := NewMemoryIndex()
+	for _, doc := range docs {
+		fresh.remove(doc.ID)
+		fresh.add(doc)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.docs, m.postings, m.trigrams = fresh.docs, fresh.postings, fresh.trigrams
+}
+
+// add indexes the words of every field, a word that appears n times in a field weighs boost * (1 + ln n)
+func (m *MemoryIndex) add(doc Document) {
+	m.docs[doc.ID] = doc
+
+	for term, weight := range weights(doc) {
+		if m.postings[term] == nil {
+			m.postings[term] = make(map[int64]float64)
+			for _, trigram := range trigrams(term) {
+				if m.trigrams[trigram] == nil {
+					m.trigrams[trigram] = make(map[string]bool)
+				}
+				m.trigrams[trigram][term] = true
+			}
+		}
+		m.postings[term][doc.ID] = weight
+	}
+}
+
+func (m *MemoryIndex) remove(id int64) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	delete(m.docs, id)
+
+	for term := range weights(doc) {
+		delete(m.postings[term], id)
+		if len(m.postings[term]) > 0 {
+			continue
+		}
+
+		// the word is not in any document anymore
+		delete(m.postings, term)
+		for _, trigram := range trigrams(term) {
+			delete(m.trigrams[trigram], term)
+			if len(m.trigrams[trigram]) == 0 {
+				delete(m.trigrams, trigram)
+			}
+		}
+	}
+}
+
+func weights(doc Document) map[string]float64 {
+	results := make(map[string]float64)
+	for _, field := range doc.Fields {
+		counts := make(map[string]int)
+		for _, t := range tokenize(field.Text) {
+			counts[t.term]++
+		}
+
+		for term, count := range counts {
+			results[term] += field.Boost * (1 + math.Log(float64(count)))
+		}
+	}
+
+	return results
+}
+
+// Search scores every document by the words of the query. Each query word counts with its best match in the document,
+// weighted by the similarity of the match, the weight of the word in the document and how rare the word is.
+// Documents matching more words of the query rank first, then the ones with the higher score
+func (m *MemoryIndex) Search(query string, limit int) (hits []Hit) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	type result struct {
+		hit   Hit
+		best  map[int]float64
+		terms map[string]bool
+	}
+
+	results := make(map[int64]*result)
+	seen := make(map[string]bool)
+	queryTerms := tokenize(query)
+	for i, queryTerm := range queryTerms {
+		if seen[queryTerm.term] {
+			continue
+		}
+		seen[queryTerm.term] = true
+
+		for term, similarity := range m.match(queryTerm.term) {
+			idf := math.Log(1 + float64(len(m.docs))/float64(len(m.postings[term])))
+			for id, weight := range m.postings[term] {
+				r, ok := results[id]
+				if !ok {
+					r = &result{hit: Hit{ID: id}, best: make(map[int]float64), terms: make(map[string]bool)}
+					results[id] = r
+				}
+
+				r.terms[term] = true
+				if score := similarity * weight * idf; score > r.best[i] {
+					r.best[i] = score
+				}
+			}
+		}
+	}
+
+	ranked := make([]*result, 0, len(results))
+	for _, r := range results {
+		for _, score := range r.best {
+			r.hit.Score += score
+		}
+		r.hit.Matched = len(r.best)
+		ranked = append(ranked, r)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i].hit, ranked[j].hit
+		if a.Matched != b.Matched {
+			return a.Matched > b.Matched
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	// only the returned hits are highlighted
+	hits = make([]Hit, 0, len(ranked))
+	for _, r := range ranked {
+		r.hit.Highlights = m.highlights(m.docs[r.hit.ID], r.terms)
+		hits = append(hits, r.hit)
+	}
+
+	return hits
+}
+
+// match returns the indexed words that match the query word with their similarity. Every typo removes at most
+// three trigrams of a word, so words with typos share all but 3 * distance trigrams with the query word
+func (m *MemoryIndex) match(queryTerm string) map[string]float64 {
+	results := make(map[string]float64)
+	if _, ok := m.postings[queryTerm]; ok {
+		results[queryTerm] = exactSimilarity
+	}
+
+	queryTrigrams := trigrams(queryTerm)
+	shared := make(map[string]int)
+	for _, trigram := range queryTrigrams {
+		for term := range m.trigrams[trigram] {
+			shared[term]++
+		}
+	}
+
+	distance := maxDistance(queryTerm)
+	length := utf8.RuneCountInString(queryTerm)
+	for term, count := range shared {
+		if term == queryTerm {
+			continue
+		}
+
+		// a word starting with the query word shares its leading trigram, so it is always among the candidates
+		if length >= 2 && strings.HasPrefix(term, queryTerm) {
+			results[term] = prefixSimilarity * (0.5 + 0.5*float64(length)/float64(utf8.RuneCountInString(term)))
+			continue
+		}
+
+		if distance == 0 || count < len(queryTrigrams)-3*distance || abs(utf8.RuneCountInString(term)-length) > distance {
+			continue
+		}
+
+		if d := levenshtein(queryTerm, term); d <= distance {
+			results[term] = typoSimilarity * (1 - float64(d)/float64(length+1))
+		}
+	}
+
+	return results
+}
+
+// highlights returns the highlighted text of the fields with a matching word
+func (m *MemoryIndex) highlights(doc Document, terms map[string]bool) map[string]string {
+	results := make(map[string]string)
+	for _, field := range doc.Fields {
+		if text, ok := highlight(field.Text, terms); ok {
+			results[field.Name] = text
+		}
+	}
+
+	return results
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
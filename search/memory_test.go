@@ -0,0 +1,131 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func pokemonDocument(id int64, name string, species string, description string) Document {
+	return Document{
+		ID: id,
+		Fields: []Field{
+			{Name: "name", Text: name, Boost: 3},
+			{Name: "species", Text: species, Boost: 2},
+			{Name: "description", Text: description, Boost: 1},
+		},
+	}
+}
+
+func newTestIndex() *MemoryIndex {
+	index := NewMemoryIndex()
+	index.Reset([]Document{
+		pokemonDocument(1, "Wigglytuff", "Balloon Pokemon", "It has a very fine fur."),
+		pokemonDocument(2, "Bulbasaur", "Seed Pokémon", "A strange seed was planted on its back at birth."),
+		pokemonDocument(3, "Charmander", "Lizard Pokemon", "The flame on its tail shows its life force."),
+		pokemonDocument(4, "Ivysaur", "Seed Pokemon", "When the bulb on its back grows large, it appears to lose the ability to stand."),
+	})
+
+	return index
+}
+
+func ids(hits []Hit) (results []int64) {
+	for _, hit := range hits {
+		results = append(results, hit.ID)
+	}
+
+	return results
+}
+
+func TestMemoryIndex_Search(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []int64
+	}{
+		{
+			name:  "success exact name",
+			query: "charmander",
+			want:  []int64{3},
+		},
+		{
+			name:  "success typo",
+			query: "bulbsaur",
+			want:  []int64{2},
+		},
+		{
+			name:  "success prefix",
+			query: "wiggly",
+			want:  []int64{1},
+		},
+		{
+			name:  "success accent folded and every word ranks first",
+			query: "seed pokemon",
+			want:  []int64{2, 4, 1, 3},
+		},
+		{
+			name:  "success name ranks above description",
+			query: "bulb",
+			want:  []int64{2, 4},
+		},
+		{
+			name:  "success limit",
+			query: "pokemon",
+			limit: 2,
+			want:  []int64{1, 2},
+		},
+		{
+			name:  "success no match",
+			query: "pikachu",
+			want:  nil,
+		},
+		{
+			name:  "success short words need exact match",
+			query: "fut",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := newTestIndex()
+			if got := ids(index.Search(tt.query, tt.limit)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MemoryIndex.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndex_Search_highlights(t *testing.T) {
+	index := newTestIndex()
+
+	hits := index.Search("bulbsaur seed", 1)
+	want := map[string]string{
+		"name":        "<em>Bulbasaur</em>",
+		"species":     "<em>Seed</em> Pokémon",
+		"description": "A strange <em>seed</em> was planted on its back at birth.",
+	}
+	if len(hits) != 1 || hits[0].Matched != 2 || !reflect.DeepEqual(hits[0].Highlights, want) {
+		t.Errorf("MemoryIndex.Search() = %+v, want highlights %v", hits, want)
+	}
+}
+
+func TestMemoryIndex_Index(t *testing.T) {
+	index := newTestIndex()
+
+	index.Index(pokemonDocument(3, "Charmeleon", "Flame Pokemon", ""))
+	if got := ids(index.Search("charmander", 0)); got != nil {
+		t.Errorf("MemoryIndex.Search() of replaced words = %v, want nil", got)
+	}
+	if got := ids(index.Search("charmeleon", 0)); !reflect.DeepEqual(got, []int64{3}) {
+		t.Errorf("MemoryIndex.Search() of new words = %v, want [3]", got)
+	}
+
+	index.Remove(3)
+	index.Remove(42)
+	if got := ids(index.Search("charmeleon", 0)); got != nil {
+		t.Errorf("MemoryIndex.Search() of removed document = %v, want nil", got)
+	}
+	if _, ok := index.postings["flame"]; ok {
+		t.Errorf("MemoryIndex.Remove() kept the words of removed document")
+	}
+}
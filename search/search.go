@@ -0,0 +1,38 @@
+// Package search finds documents by the words of their fields. Words are matched exactly, by prefix
+// and with typos, then documents are ranked by how many words of the query they match and how well
+package search
+
+// Field is a searchable text of a document, matches in fields with higher boost rank higher
+type Field struct {
+	Name  string
+	Text  string
+	Boost float64
+}
+
+// Document is what the index stores for an id, indexing the id again replaces it
+type Document struct {
+	ID     int64
+	Fields []Field
+}
+
+// Hit is a document that matches the query
+type Hit struct {
+	ID    int64
+	Score float64
+	// Matched is how many words of the query the document matches
+	Matched int
+	// Highlights has the matching fields by name, matching words are wrapped in <em> and the rest of the text is html escaped
+	Highlights map[string]string
+}
+
+// Index is where the documents are searched, implementations are safe for concurrent use
+type Index interface {
+	// Index adds the document or replaces the document with the same id
+	Index(doc Document)
+	// Remove removes the document with the id, unknown ids are ignored
+	Remove(id int64)
+	// Reset replaces every document of the index with docs
+	Reset(docs []Document)
+	// Search returns at most limit hits for the query, the best hit first
+	Search(query string, limit int) []Hit
+}
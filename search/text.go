@@ -0,0 +1,190 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fragmentLength is how many characters of a long field are kept around the first match of the highlight
+const fragmentLength = 160
+
+// token is a word of the text, start and end are the byte offsets of the word in the text
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// folds maps the accented letters to the letter without accent so pokémon matches pokemon
+var folds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+}
+
+// tokenize splits the text into lower case words without accents, every rune that is not a letter or digit separates words
+func tokenize(text string) (tokens []token) {
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, token{term: normalize(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, token{term: normalize(text[start:]), start: start, end: len(text)})
+	}
+
+	return tokens
+}
+
+func normalize(word string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if folded, ok := folds[r]; ok {
+			return folded
+		}
+		return r
+	}, word)
+}
+
+// trigrams returns the distinct three letter parts of the term padded with $ at both ends
+func trigrams(term string) (results []string) {
+	runes := []rune("$" + term + "$")
+	seen := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !seen[trigram] {
+			seen[trigram] = true
+			results = append(results, trigram)
+		}
+	}
+
+	return results
+}
+
+// maxDistance is how many typos a query word of the length can have, short words must match exactly
+func maxDistance(term string) int {
+	switch length := utf8.RuneCountInString(term); {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	}
+
+	return 2
+}
+
+// levenshtein returns the number of single letter insertions, deletions and substitutions between a and b
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func min(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// highlight wraps the words of the text that are one of terms in <em>, text longer than fragmentLength
+// is cut around the first match. ok is false when no word matches
+func highlight(text string, terms map[string]bool) (result string, ok bool) {
+	var matches []token
+	for _, t := range tokenize(text) {
+		if terms[t.term] {
+			matches = append(matches, t)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	from, to := fragment(text, matches[0])
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+
+	position := from
+	for _, match := range matches {
+		if match.start < position || match.end > to {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(text[position:match.start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[match.start:match.end]))
+		b.WriteString("</em>")
+		position = match.end
+	}
+	b.WriteString(html.EscapeString(text[position:to]))
+
+	if to < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
+
+// fragment returns the byte range of at most fragmentLength runes of the text that starts a little before the match
+func fragment(text string, match token) (from int, to int) {
+	if utf8.RuneCountInString(text) <= fragmentLength {
+		return 0, len(text)
+	}
+
+	// step back a quarter of the fragment so the match has some context before it
+	from = match.start
+	for i := 0; i < fragmentLength/4 && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+
+	to = from
+	for i := 0; i < fragmentLength && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	return from, to
+}
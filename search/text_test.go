@@ -0,0 +1,56 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("Mr. Mime, Pokémon #122")
+	want := []token{
+		{term: "mr", start: 0, end: 2},
+		{term: "mime", start: 4, end: 8},
+		{term: "pokemon", start: 10, end: 18},
+		{term: "122", start: 20, end: 23},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "bulbasaur", b: "bulbasaur", want: 0},
+		{a: "bulbsaur", b: "bulbasaur", want: 1},
+		{a: "charmandr", b: "charmander", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "", b: "abc", want: 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got, ok := highlight("<b>Seed</b> & seeds", map[string]bool{"seed": true})
+	if want := "&lt;b&gt;<em>Seed</em>&lt;/b&gt; &amp; seeds"; !ok || got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+
+	long := strings.Repeat("grass ", 40) + "seed " + strings.Repeat("grass ", 40)
+	got, ok = highlight(long, map[string]bool{"seed": true})
+	if !ok || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "<em>seed</em>") {
+		t.Errorf("highlight() of long text = %q", got)
+	}
+
+	if _, ok = highlight("grass", map[string]bool{"seed": true}); ok {
+		t.Errorf("highlight() without match ok = true, want false")
+	}
+}
@@ -0,0 +1,254 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Suggestion is a field text that completes the prefix, IDs are the documents having the text sorted by id
+type Suggestion struct {
+	Text  string
+	Field string
+	IDs   []int64
+}
+
+// Suggester completes prefixes to the texts of the indexed fields, implementations are safe for concurrent use
+type Suggester interface {
+	// Index adds the fields of the document or replaces the fields of the document with the same id
+	Index(doc Document)
+	// Remove removes the document with the id, unknown ids are ignored
+	Remove(id int64)
+	// Reset replaces every document of the suggester with docs
+	Reset(docs []Document)
+	// Suggest returns at most limit suggestions for the prefix. Fields listed first in their document come first,
+	// then texts starting with the prefix before texts with a later word starting with it, then shorter texts first
+	Suggest(prefix string, limit int) []Suggestion
+}
+
+// completion is the text of a field, the same text of different documents is a single completion.
+// Order is the position of the field in the document, the first fields rank first
+type completion struct {
+	field string
+	text  string
+	order int
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	// completions are the completions whose key ends at this node with the documents having them
+	completions map[completion]map[int64]bool
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{
+		children:    make(map[rune]*trieNode),
+		completions: make(map[completion]map[int64]bool),
+	}
+}
+
+// Trie is a prefix tree of the field texts. A text is added from the start of each of its words,
+// so "Mr. Mime" completes both "mr" and "mime". Suggest ranks every completion under the prefix so
+// the many texts sharing a later word, like "... Pokemon" species, can't crowd out better completions
+type Trie struct {
+	mu   sync.RWMutex
+	root *trieNode
+	// docs are the indexed documents by id
+	docs map[int64]Document
+}
+
+func NewTrie() *Trie {
+	return &Trie{
+		root: newTrieNode(),
+		docs: make(map[int64]Document),
+	}
+}
+
+func (t *Trie) Index(doc Document) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remove(doc.ID)
+	t.add(doc)
+}
+
+func (t *Trie) Remove(id int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remove(id)
+}
+
+func (t *Trie) Reset(docs []Document) {
+	fresh := NewTrie()
+	for _, doc := range docs {
+		fresh.remove(doc.ID)
+		fresh.add(doc)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.root, t.docs = fresh.root, fresh.docs
+}
+
+func (t *Trie) add(doc Document) {
+	t.docs[doc.ID] = doc
+
+	for i, field := range doc.Fields {
+		c := completion{field: field.Name, text: field.Text, order: i}
+		for _, key := range suffixKeys(field.Text) {
+			node := t.root
+			for _, r := range key {
+				child, ok := node.children[r]
+				if !ok {
+					child = newTrieNode()
+					node.children[r] = child
+				}
+				node = child
+			}
+
+			if node.completions[c] == nil {
+				node.completions[c] = make(map[int64]bool)
+			}
+			node.completions[c][doc.ID] = true
+		}
+	}
+}
+
+func (t *Trie) remove(id int64) {
+	doc, ok := t.docs[id]
+	if !ok {
+		return
+	}
+	delete(t.docs, id)
+
+	for i, field := range doc.Fields {
+		c := completion{field: field.Name, text: field.Text, order: i}
+		for _, key := range suffixKeys(field.Text) {
+			removeKey(t.root, []rune(key), c, id)
+		}
+	}
+}
+
+// removeKey removes the document from the completion at the end of key, nodes left without completion and child are removed.
+// It returns whether node is empty now
+func removeKey(node *trieNode, key []rune, c completion, id int64) bool {
+	if len(key) == 0 {
+		delete(node.completions[c], id)
+		if len(node.completions[c]) == 0 {
+			delete(node.completions, c)
+		}
+	} else if child, ok := node.children[key[0]]; ok && removeKey(child, key[1:], c, id) {
+		delete(node.children, key[0])
+	}
+
+	return len(node.children) == 0 && len(node.completions) == 0
+}
+
+func (t *Trie) Suggest(prefix string, limit int) (results []Suggestion) {
+	key := strings.Join(terms(prefix), " ")
+	if key == "" {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	node := t.root
+	for _, r := range key {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+
+	found := make(map[completion]map[int64]bool)
+	collect(node, found)
+
+	ranked := make([]rankedCompletion, 0, len(found))
+	for c := range found {
+		ranked = append(ranked, rankedCompletion{
+			completion: c,
+			atStart:    strings.HasPrefix(strings.Join(terms(c.text), " "), key),
+			length:     len([]rune(c.text)),
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].less(ranked[j]) })
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	for _, r := range ranked {
+		ids := make([]int64, 0, len(found[r.completion]))
+		for id := range found[r.completion] {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		results = append(results, Suggestion{Text: r.text, Field: r.field, IDs: ids})
+	}
+
+	return results
+}
+
+// rankedCompletion is a completion with what Suggest ranks it by
+type rankedCompletion struct {
+	completion
+	// atStart is whether the text starts with the prefix rather than a later word of it
+	atStart bool
+	length  int
+}
+
+// less ranks the first fields first, then texts starting with the prefix, then shorter texts, then texts in alphabetical order
+func (r rankedCompletion) less(other rankedCompletion) bool {
+	if r.order != other.order {
+		return r.order < other.order
+	}
+	if r.atStart != other.atStart {
+		return r.atStart
+	}
+	if r.length != other.length {
+		return r.length < other.length
+	}
+	if r.text != other.text {
+		return r.text < other.text
+	}
+	return r.field < other.field
+}
+
+// collect adds the completions of the node and of every node below it with their documents to found,
+// a text having several words starting with the prefix is found once
+func collect(node *trieNode, found map[completion]map[int64]bool) {
+	for c, ids := range node.completions {
+		if found[c] == nil {
+			found[c] = make(map[int64]bool, len(ids))
+		}
+		for id := range ids {
+			found[c][id] = true
+		}
+	}
+
+	for _, child := range node.children {
+		collect(child, found)
+	}
+}
+
+// suffixKeys returns the keys of the text from the start of every word, words are normalized and joined by a space
+func suffixKeys(text string) (keys []string) {
+	words := terms(text)
+	for i := range words {
+		keys = append(keys, strings.Join(words[i:], " "))
+	}
+
+	return keys
+}
+
+func terms(text string) (results []string) {
+	for _, t := range tokenize(text) {
+		results = append(results, t.term)
+	}
+
+	return results
+}
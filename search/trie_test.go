@@ -0,0 +1,136 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func suggestDocument(id int64, name string, species string) Document {
+	return Document{
+		ID: id,
+		Fields: []Field{
+			{Name: "name", Text: name},
+			{Name: "species", Text: species},
+		},
+	}
+}
+
+func newTestTrie() *Trie {
+	trie := NewTrie()
+	trie.Reset([]Document{
+		suggestDocument(1, "Charmander", "Lizard Pokémon"),
+		suggestDocument(2, "Charmeleon", "Flame Pokemon"),
+		suggestDocument(3, "Charizard", "Flame Pokemon"),
+		suggestDocument(4, "Mr. Mime", "Barrier Pokemon"),
+		suggestDocument(5, "Bulbasaur", "Seed Pokemon"),
+		suggestDocument(6, "Poliwag", "Tadpole Pokemon"),
+		suggestDocument(7, "Rapidash", "Fire Horse Pokemon"),
+		suggestDocument(8, "Mr. Rime", "Comedian Pokemon"),
+	})
+
+	return trie
+}
+
+func TestTrie_Suggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []Suggestion
+	}{
+		{
+			name:   "success names in order",
+			prefix: "CHAR",
+			want: []Suggestion{
+				{Text: "Charizard", Field: "name", IDs: []int64{3}},
+				{Text: "Charmander", Field: "name", IDs: []int64{1}},
+				{Text: "Charmeleon", Field: "name", IDs: []int64{2}},
+			},
+		},
+		{
+			name:   "success limit",
+			prefix: "charm",
+			limit:  1,
+			want: []Suggestion{
+				{Text: "Charmander", Field: "name", IDs: []int64{1}},
+			},
+		},
+		{
+			name:   "success species of many pokemons",
+			prefix: "fla",
+			want: []Suggestion{
+				{Text: "Flame Pokemon", Field: "species", IDs: []int64{2, 3}},
+			},
+		},
+		{
+			name:   "success word in the middle",
+			prefix: "mime",
+			want: []Suggestion{
+				{Text: "Mr. Mime", Field: "name", IDs: []int64{4}},
+			},
+		},
+		{
+			name:   "success punctuation and accents ignored",
+			prefix: "mr mi",
+			want: []Suggestion{
+				{Text: "Mr. Mime", Field: "name", IDs: []int64{4}},
+			},
+		},
+		{
+			name:   "success names before species",
+			prefix: "po",
+			limit:  3,
+			want: []Suggestion{
+				{Text: "Poliwag", Field: "name", IDs: []int64{6}},
+				{Text: "Seed Pokemon", Field: "species", IDs: []int64{5}},
+				{Text: "Flame Pokemon", Field: "species", IDs: []int64{2, 3}},
+			},
+		},
+		{
+			name:   "success start of text before later word",
+			prefix: "r",
+			want: []Suggestion{
+				{Text: "Rapidash", Field: "name", IDs: []int64{7}},
+				{Text: "Mr. Rime", Field: "name", IDs: []int64{8}},
+			},
+		},
+		{
+			name:   "success no match",
+			prefix: "pika",
+			want:   nil,
+		},
+		{
+			name:   "success empty prefix",
+			prefix: " .",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trie := newTestTrie()
+			if got := trie.Suggest(tt.prefix, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Trie.Suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrie_Index(t *testing.T) {
+	trie := newTestTrie()
+
+	trie.Index(suggestDocument(1, "Charmy", "Lizard Pokemon"))
+	want := []Suggestion{{Text: "Charmy", Field: "name", IDs: []int64{1}}, {Text: "Charmeleon", Field: "name", IDs: []int64{2}}}
+	if got := trie.Suggest("charm", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Trie.Suggest() after replace = %v, want %v", got, want)
+	}
+
+	trie.Remove(2)
+	trie.Remove(3)
+	trie.Remove(42)
+	if got := trie.Suggest("flame", 0); got != nil {
+		t.Errorf("Trie.Suggest() of removed documents = %v, want nil", got)
+	}
+	if _, ok := trie.root.children['f'].children['l']; ok {
+		t.Errorf("Trie.Remove() kept the nodes of removed documents")
+	}
+}
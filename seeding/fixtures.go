@@ -0,0 +1,146 @@
+package seeding
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+// names of the fixture sets
+const (
+	// FixtureBasic is the three pokemons of pokedex.sql
+	FixtureBasic = "basic"
+	// FixtureEdgeCases is the pokemons at the limits of what the pokedex accepts
+	FixtureEdgeCases = "edge-cases"
+	// FixtureCatalog is a large generated pokedex for paging, search and performance
+	FixtureCatalog = "catalog"
+)
+
+// catalogSize is the number of pokemons of the catalog fixture
+const catalogSize = 500
+
+var fixtures = map[string]func() []entity.PokemonDetail{
+	FixtureBasic:     basic,
+	FixtureEdgeCases: edgeCases,
+	FixtureCatalog: func() []entity.PokemonDetail {
+		return Generator{Seed: DefaultSeed}.Generate(catalogSize)
+	},
+}
+
+// Fixture returns the pokemons of the fixture set, every call returns a new copy the caller can change
+func Fixture(name string) (results []entity.PokemonDetail, err error) {
+	fixture, ok := fixtures[name]
+	if !ok {
+		return nil, fmt.Errorf("fixture %s does not exist, fixtures are %s", name, strings.Join(FixtureNames(), ", "))
+	}
+
+	return fixture(), nil
+}
+
+// FixtureNames returns the names of the fixture sets sorted by name
+func FixtureNames() (names []string) {
+	for name := range fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func basic() []entity.PokemonDetail {
+	return []entity.PokemonDetail{
+		{
+			Name:        "Wigglytuff",
+			Species:     "Balloon Pokemon",
+			Types:       []string{"NORMAL"},
+			ImageURL:    "https://img.pokemondb.net/artwork/large/wigglytuff.jpg",
+			Description: "Wigglytuff is a Normal/Fairy type Pokémon introduced in Generation 1. It is known as the Balloon Pokemon.",
+			Weight:      12,
+			Height:      1,
+			Stats:       entity.Stats{HP: 140, Attack: 70, Def: 45, Speed: 45},
+		},
+		{
+			Name:        "Bulbasaur",
+			Species:     "Seed Pokemon",
+			Types:       []string{"NORMAL", "POISON"},
+			Catched:     1,
+			ImageURL:    "https://img.pokemondb.net/artwork/avif/bulbasaur.avif",
+			Description: "Bulbasaur is a Grass/Poison type Pokémon introduced in Generation 1. It is known as the Seed Pokemon.",
+			Weight:      6.9,
+			Height:      0.7,
+			Stats:       entity.Stats{HP: 45, Attack: 49, Def: 49, Speed: 45},
+		},
+		{
+			Name:        "Charmander",
+			Species:     "Lizard Pokemon",
+			Types:       []string{"NORMAL", "FIRE"},
+			ImageURL:    "https://img.pokemondb.net/artwork/avif/charmander.avif",
+			Description: "Charmander is a Fire type Pokémon introduced in Generation 1. It is known as the Lizard Pokemon.",
+			Weight:      8.5,
+			Height:      0.6,
+			Stats:       entity.Stats{HP: 39, Attack: 52, Def: 43, Speed: 65},
+		},
+	}
+}
+
+func edgeCases() []entity.PokemonDetail {
+	return []entity.PokemonDetail{
+		{
+			Name:    "Farfetch'd",
+			Species: "Wild Duck Pokemon",
+			Types:   []string{"NORMAL", "FLYING"},
+			Weight:  15,
+			Height:  0.8,
+			Stats:   entity.Stats{HP: 52, Attack: 90, Def: 55, Speed: 60},
+		},
+		{
+			Name:    "Flabébé",
+			Species: "Single Bloom Pokémon",
+			Types:   []string{"FAIRY"},
+			Weight:  0.1,
+			Height:  0.1,
+			Stats:   entity.Stats{HP: 44, Attack: 38, Def: 39, Speed: 42},
+		},
+		{
+			Name:    "Mr. Mime",
+			Species: "Barrier Pokemon",
+			Types:   []string{"PSYCHIC", "FAIRY"},
+			Catched: 1,
+			Weight:  54.5,
+			Height:  1.3,
+			Stats:   entity.Stats{HP: 40, Attack: 45, Def: 65, Speed: 90},
+		},
+		{
+			Name:        "Blissey",
+			Species:     "Happiness Pokemon",
+			Types:       []string{"NORMAL"},
+			Description: strings.Repeat("Blissey shares its eggs with anyone who is hurt. ", 40),
+			Weight:      46.8,
+			Height:      1.5,
+			Stats:       entity.Stats{HP: 255, Attack: 10, Def: 10, Speed: 55},
+		},
+		{
+			Name:    "Shuckle",
+			Species: "Mold Pokemon",
+			Types:   []string{"BUG", "ROCK"},
+			Weight:  20.5,
+			Height:  0.6,
+			Stats:   entity.Stats{HP: 20, Attack: 10, Def: 230, Speed: 5},
+		},
+		{
+			Name:    "Eternatus",
+			Species: "Gigantic Pokemon",
+			Types:   []string{"POISON", "DRAGON"},
+			Weight:  950,
+			Height:  20,
+			Stats:   entity.Stats{HP: 140, Attack: 85, Def: 95, Speed: 130},
+		},
+		{
+			Name:    "MissingNo",
+			Species: "Glitch Pokemon",
+			Types:   []string{},
+		},
+	}
+}
@@ -0,0 +1,184 @@
+// Package seeding generates synthetic pokemons for performance and ui testing and holds the named fixture sets
+// of integration tests. The same seed always generates the same pokemons
+package seeding
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+// DefaultSeed is the seed of the generator when none is given
+const DefaultSeed = 1
+
+// DefaultImageURL is the placeholder image of generated pokemons, %d is the number of the pokemon
+const DefaultImageURL = "https://img.pokedex.local/seed/%d.png"
+
+// weightedType is a type and how often it appears relative to other types
+type weightedType struct {
+	name   string
+	weight int
+}
+
+// types are weighted roughly by how often they appear among the main series pokemons
+var types = []weightedType{
+	{"WATER", 150}, {"NORMAL", 130}, {"GRASS", 115}, {"BUG", 90}, {"PSYCHIC", 85}, {"FIRE", 80},
+	{"FLYING", 100}, {"POISON", 70}, {"ROCK", 65}, {"ELECTRIC", 65}, {"GROUND", 65}, {"FIGHTING", 60},
+	{"DARK", 55}, {"DRAGON", 50}, {"STEEL", 55}, {"GHOST", 50}, {"FAIRY", 50}, {"ICE", 45},
+}
+
+// dualTypeRate is the share of pokemons with two types
+const dualTypeRate = 0.55
+
+var (
+	prefixes  = []string{"Bul", "Char", "Squir", "Pid", "Rat", "Spear", "Ek", "Pika", "Sand", "Nido", "Clef", "Vul", "Jiggly", "Zu", "Odd", "Par", "Veno", "Dig", "Meo", "Psy", "Mank", "Grow", "Poli", "Abra", "Mach", "Bell", "Tenta", "Geo", "Pony", "Slow", "Magne", "Dod", "Gast", "On", "Drow", "Krab", "Volt", "Exeg", "Cub", "Hitmon", "Lick", "Koff", "Rhy", "Tang", "Kang", "Hors", "Gold", "Star", "Scy", "Jyn", "Elec", "Mag", "Pin", "Tau", "Lap", "Ditt", "Eev", "Pory", "Oma", "Kabu", "Aero", "Snor", "Dra", "Mew"}
+	suffixes  = []string{"asaur", "mander", "tle", "gey", "tata", "row", "kans", "chu", "shrew", "ran", "fairy", "pix", "puff", "bat", "ish", "as", "nat", "lett", "th", "duck", "key", "lithe", "wag", "ra", "chop", "sprout", "cool", "dude", "ta", "poke", "mite", "uo", "ly", "ix", "zee", "by", "orb", "cute", "bone", "lee", "tung", "fing", "horn", "gela", "khan", "sea", "deen", "mie", "ther", "nx", "buzz", "mar", "sir", "ros", "ras", "to", "vee", "gon", "nite", "lax", "tini"}
+	genera    = []string{"Seed", "Lizard", "Flame", "Tiny Turtle", "Shellfish", "Bird", "Mouse", "Snake", "Fairy", "Fox", "Balloon", "Bat", "Weed", "Mushroom", "Insect", "Mole", "Scratch Cat", "Duck", "Pig Monkey", "Puppy", "Tadpole", "Psi", "Superpower", "Flower", "Jellyfish", "Rock", "Fire Horse", "Dopey", "Magnet", "Wild Duck", "Sea Lion", "Sludge", "Bivalve", "Gas", "Rock Snake", "Hypnosis", "River Crab", "Ball", "Egg", "Lonely", "Kicking", "Licking", "Poison Gas", "Spikes", "Vine", "Parent", "Dragon", "Goldfish", "Star Puzzle", "Mantis", "Human Shape", "Electric", "Stag Beetle", "Wild Bull", "Atrocious", "Transport", "Transform", "Evolution", "Virtual", "Spiral", "Fossil", "Sleeping", "Freeze", "Genetic", "New Species"}
+	habitats  = []string{"forests", "mountains", "caves", "the sea", "rivers", "grasslands", "cities", "volcanoes", "the tundra", "deserts", "swamps", "ancient ruins"}
+	behaviors = []string{
+		"It is rarely seen by people and hides when it senses danger.",
+		"It gathers in large groups and moves at dawn.",
+		"It stores energy in its body and releases it when it is threatened.",
+		"It is known to be very loyal to its trainer.",
+		"It sleeps most of the day and becomes active at night.",
+		"It marks its territory and fiercely drives off intruders.",
+		"It can be calmed by soft singing.",
+		"It grows stronger each time it battles.",
+	}
+)
+
+// Generator generates synthetic pokemons, the pokemons of the same seed are always the same
+type Generator struct {
+	Seed     int64
+	ImageURL string
+}
+
+// Generate returns count pokemons with unique names numbered from 1, type, stat and size distributions
+// roughly follow the ones of the main series games
+func (g Generator) Generate(count int) (results []entity.PokemonDetail) {
+	imageURL := g.ImageURL
+	if imageURL == "" {
+		imageURL = DefaultImageURL
+	}
+
+	random := rand.New(rand.NewSource(g.Seed))
+	names := make(map[string]bool, count)
+	results = make([]entity.PokemonDetail, 0, count)
+	for number := 1; number <= count; number++ {
+		name := uniqueName(random, names)
+		genus := genera[random.Intn(len(genera))]
+		pokemonTypes := pickTypes(random)
+		stats, total := pickStats(random)
+		height := round(math.Exp(random.NormFloat64()*0.6)+0.1, 1)
+		weight := round(height*height*(5+random.Float64()*40)+0.1, 1)
+
+		results = append(results, entity.PokemonDetail{
+			Name:        name,
+			Species:     genus + " Pokemon",
+			Types:       pokemonTypes,
+			ImageURL:    fmt.Sprintf(imageURL, number),
+			Description: description(random, name, genus, pokemonTypes, total),
+			Weight:      weight,
+			Height:      height,
+			Stats:       stats,
+		})
+	}
+
+	return results
+}
+
+// uniqueName combines a prefix and a suffix, a number is appended once every combination is taken
+func uniqueName(random *rand.Rand, names map[string]bool) string {
+	name := prefixes[random.Intn(len(prefixes))] + suffixes[random.Intn(len(suffixes))]
+	for i := 2; names[name]; i++ {
+		name = fmt.Sprintf("%s %d", strings.Fields(name)[0], i)
+	}
+
+	names[name] = true
+	return name
+}
+
+// pickTypes picks one or two different types by their weight
+func pickTypes(random *rand.Rand) []string {
+	primary := pickType(random)
+	if random.Float64() >= dualTypeRate {
+		return []string{primary}
+	}
+
+	secondary := pickType(random)
+	for secondary == primary {
+		secondary = pickType(random)
+	}
+
+	return []string{primary, secondary}
+}
+
+func pickType(random *rand.Rand) string {
+	total := 0
+	for _, t := range types {
+		total += t.weight
+	}
+
+	n := random.Intn(total)
+	for _, t := range types {
+		if n < t.weight {
+			return t.name
+		}
+		n -= t.weight
+	}
+
+	return types[len(types)-1].name
+}
+
+// pickStats picks the base stat total around 420 and spreads it over the stats, every stat is between 5 and 255
+func pickStats(random *rand.Rand) (stats entity.Stats, total int64) {
+	budget := clamp(random.NormFloat64()*90+420, 180, 720)
+
+	var shares [4]float64
+	var sum float64
+	for i := range shares {
+		shares[i] = 0.5 + random.Float64()
+		sum += shares[i]
+	}
+
+	// the total of the four stats is two thirds of the base stat total of six stats
+	values := make([]int64, len(shares))
+	for i, share := range shares {
+		values[i] = int64(clamp(budget*2/3*share/sum, 5, 255))
+		total += values[i]
+	}
+
+	return entity.Stats{HP: values[0], Attack: values[1], Def: values[2], Speed: values[3]}, total
+}
+
+func description(random *rand.Rand, name string, genus string, pokemonTypes []string, total int64) string {
+	strength := "weak"
+	switch {
+	case total >= 360:
+		strength = "legendary"
+	case total >= 300:
+		strength = "powerful"
+	case total >= 240:
+		strength = "sturdy"
+	}
+
+	typeNames := make([]string, 0, len(pokemonTypes))
+	for _, t := range pokemonTypes {
+		typeNames = append(typeNames, t[:1]+strings.ToLower(t[1:]))
+	}
+
+	return fmt.Sprintf("%s is a %s %s type Pokémon known as the %s Pokemon. It lives in %s. %s",
+		name, strength, strings.Join(typeNames, "/"), genus, habitats[random.Intn(len(habitats))], behaviors[random.Intn(len(behaviors))])
+}
+
+func clamp(value float64, min float64, max float64) float64 {
+	return math.Max(min, math.Min(max, value))
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,73 @@
+package seeding
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	pokemons := Generator{Seed: 42}.Generate(1000)
+	if len(pokemons) != 1000 {
+		t.Fatalf("Generator.Generate() returned %d pokemons, want 1000", len(pokemons))
+	}
+
+	if again := (Generator{Seed: 42}).Generate(1000); !reflect.DeepEqual(pokemons, again) {
+		t.Errorf("Generator.Generate() is not the same for the same seed")
+	}
+
+	if other := (Generator{Seed: 43}).Generate(1000); reflect.DeepEqual(pokemons, other) {
+		t.Errorf("Generator.Generate() is the same for different seeds")
+	}
+
+	names := make(map[string]bool)
+	dual := 0
+	for i, pokemon := range pokemons {
+		if names[pokemon.Name] {
+			t.Errorf("Generator.Generate() name %s is generated twice", pokemon.Name)
+		}
+		names[pokemon.Name] = true
+
+		if len(pokemon.Types) == 0 || len(pokemon.Types) > 2 || (len(pokemon.Types) == 2 && pokemon.Types[0] == pokemon.Types[1]) {
+			t.Errorf("Generator.Generate() [%d].Types = %v", i, pokemon.Types)
+		}
+		if len(pokemon.Types) == 2 {
+			dual++
+		}
+
+		for _, stat := range []int64{pokemon.Stats.HP, pokemon.Stats.Attack, pokemon.Stats.Def, pokemon.Stats.Speed} {
+			if stat < 5 || stat > 255 {
+				t.Errorf("Generator.Generate() [%d].Stats = %v", i, pokemon.Stats)
+			}
+		}
+
+		if pokemon.Weight <= 0 || pokemon.Height <= 0 || pokemon.Species == "" || pokemon.Description == "" || pokemon.ImageURL == "" {
+			t.Errorf("Generator.Generate() [%d] = %v", i, pokemon)
+		}
+	}
+
+	if dual < 450 || dual > 650 {
+		t.Errorf("Generator.Generate() has %d dual type pokemons of 1000, want around %v", dual, dualTypeRate*1000)
+	}
+}
+
+func TestFixture(t *testing.T) {
+	for _, name := range FixtureNames() {
+		pokemons, err := Fixture(name)
+		if err != nil {
+			t.Errorf("Fixture(%s) error = %v", name, err)
+		}
+		if len(pokemons) == 0 {
+			t.Errorf("Fixture(%s) has no pokemon", name)
+		}
+	}
+
+	pokemons, _ := Fixture(FixtureBasic)
+	pokemons[0].Name = "changed"
+	if again, _ := Fixture(FixtureBasic); again[0].Name != "Wigglytuff" {
+		t.Errorf("Fixture() returns a shared copy")
+	}
+
+	if _, err := Fixture("unknown"); err == nil {
+		t.Errorf("Fixture(unknown) error = nil, want error")
+	}
+}
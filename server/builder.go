@@ -1,21 +1,322 @@
 package server
 
-func buildQueryFilter(query map[string][]string) (result map[string]string) {
-	result = make(map[string]string)
-	for k, v := range query {
-		switch k {
-		case "name":
-			result[k] = v[0]
-		case "options":
-			result[k] = v[0]
-		case "type":
-			result[k] = v[0]
-		case "sort_by":
-			result[k] = v[0]
-		case "order_by":
-			result[k] = v[0]
-		}
-	}
-
-	return result
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	"github.com/winartodev/go-pokedex/helper"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+	"github.com/winartodev/go-pokedex/throttle"
+	"github.com/winartodev/go-pokedex/usecase"
+)
+
+// oidcFlowCookie keeps the state of the oidc login between the redirect to the provider and the callback
+const oidcFlowCookie = "oidc_flow"
+
+var (
+	errUsernameRequired = apperror.New(apperror.Validation, "username_required", "username can't be empty")
+	errPasswordRequired = apperror.New(apperror.Validation, "password_required", "password can't be empty")
+	errEmailRequired    = apperror.New(apperror.Validation, "email_required", "email can't be empty")
+	errNotLoggedIn      = apperror.New(apperror.Unauthorized, "not_logged_in", "user is not logged in")
+	errAPIKeyNotAllowed = apperror.New(apperror.Forbidden, "api_key_not_allowed", "api keys can't access user accounts")
+	errMergePatchOnly   = errors.New("only application/merge-patch+json is supported")
+	errIfMatchRequired  = apperror.New(apperror.PreconditionRequired, "if_match_required", "If-Match header with the ETag of the resource is required")
+	errInvalidBulkMode  = errors.New("mode must be atomic or best_effort")
+)
+
+// buildPokemonFilter parses the filter expression and the type filters of the pokemon listing. type_all, type_any
+// and type_not are comma separated type names or ids. The older name, options and type queries are still accepted
+// and combined with the filter, type is the same as type_any
+func buildPokemonFilter(query url.Values) (result entity.PokemonFilter, err error) {
+	var exprs []filter.Expr
+
+	if value, ok := query["filter"]; ok {
+		expr, err := filter.Parse(value[0])
+		if err != nil {
+			return result, apperror.Wrap(apperror.Validation, "invalid_filter", err)
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if name := query.Get("name"); name != "" {
+		exprs = append(exprs, filter.Comparison{Field: "name", Op: filter.OpContains, Value: name})
+	}
+
+	if options := query.Get("options"); options != "" {
+		exprs = append(exprs, filter.Comparison{Field: "catched", Op: filter.OpEqual, Value: options})
+	}
+
+	result.Expr = filter.AllOf(exprs...)
+	result.TypeAll = typeList(query["type_all"])
+	result.TypeAny = append(typeList(query["type"]), typeList(query["type_any"])...)
+	result.TypeNot = typeList(query["type_not"])
+	result.SortBy = query.Get("sort_by")
+	result.OrderBy = query.Get("order_by")
+
+	return result, nil
+}
+
+// typeList splits the comma separated types of every value, blank types are skipped
+func typeList(values []string) (results []string) {
+	for _, value := range values {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				results = append(results, t)
+			}
+		}
+	}
+
+	return results
+}
+
+// hasPokemonFilter reports whether the listing is filtered or sorted
+func hasPokemonFilter(data entity.PokemonFilter) bool {
+	return data.Expr != nil || data.SortBy != "" || len(data.TypeAll)+len(data.TypeAny)+len(data.TypeNot) > 0
+}
+
+func buildUserFilter(query url.Values) (result entity.UserFilter, err error) {
+	result.Search = query.Get("q")
+
+	if limit := query.Get("limit"); limit != "" {
+		result.Limit, err = strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		result.Offset, err = strconv.ParseInt(offset, 10, 64)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// buildAuditFilter parses the audit log filter, since is a RFC 3339 timestamp
+func buildAuditFilter(query url.Values) (result entity.AuditFilter, err error) {
+	result.Resource = query.Get("resource")
+	result.Actor = query.Get("actor")
+
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return result, err
+		}
+		result.Since = &t
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		result.Limit, err = strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		result.Offset, err = strconv.ParseInt(offset, 10, 64)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// userIDFromRequest returns the id of the logged in user stored in request context by middleware
+func userIDFromRequest(r *http.Request) (id int64, err error) {
+	claims, ok := auth.FromContext(r.Context())
+	if !ok {
+		return id, errNotLoggedIn
+	}
+
+	if claims.APIKeyID != 0 {
+		return id, errAPIKeyNotAllowed
+	}
+
+	return claims.ID, nil
+}
+
+// clientIP returns the ip address of the client that sent the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// clientFromRequest returns the device the request is sent from
+func clientFromRequest(r *http.Request) entity.Client {
+	return entity.Client{
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// loginFailedResponse writes the response of failed login, throttled attempts get Retry-After header
+func loginFailedResponse(w http.ResponseWriter, err error) {
+	var tooMany *throttle.ErrTooManyAttempts
+	if errors.As(err, &tooMany) {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(tooMany.RetryAfter.Seconds())), 10))
+		helper.FailedResponse(w, http.StatusTooManyRequests, err)
+		return
+	}
+
+	helper.ErrorResponse(w, err)
+}
+
+// isMergePatch reports whether the request body is json merge patch, plain json is accepted as merge patch too
+func isMergePatch(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/merge-patch+json" || mediaType == "application/json"
+}
+
+// bulkAtomic reports whether the bulk request runs all items in a single transaction, which is the default mode
+func bulkAtomic(r *http.Request) (atomic bool, err error) {
+	switch r.URL.Query().Get("mode") {
+	case "", "atomic":
+		return true, nil
+	case "best_effort":
+		return false, nil
+	}
+
+	return false, errInvalidBulkMode
+}
+
+// etag returns the entity tag of the version of a type
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// pokemonETag returns the entity tag of the pokemon, the pokemon shows the names of its types so the versions
+// of its types are part of the tag after the version of the pokemon like "3-1.2"
+func pokemonETag(pokemon *entity.PokemonDetail) string {
+	tag := strconv.FormatInt(pokemon.Version, 10)
+	for i, version := range pokemon.TypeVersions {
+		separator := "."
+		if i == 0 {
+			separator = "-"
+		}
+		tag += separator + strconv.FormatInt(version, 10)
+	}
+
+	return strconv.Quote(tag)
+}
+
+// ifMatchVersion returns the version the client expects from If-Match header, 0 is returned for If-Match: * so any version matches.
+// Only a single strong tag can be compared with the version, anything else never matches. The versions of the types in
+// the tag of a pokemon are not compared, a renamed type doesn't change what the client writes to the pokemon
+func ifMatchVersion(r *http.Request) (version int64, err error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return version, errIfMatchRequired
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return version, usecase.ErrVersionMismatch
+	}
+
+	tag := strings.SplitN(header[1:len(header)-1], "-", 2)[0]
+	version, err = strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, usecase.ErrVersionMismatch
+	}
+
+	return version, nil
+}
+
+// notModified reports whether If-None-Match header of the request matches the tag, weak tags match too
+func notModified(r *http.Request, tag string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dataFormat returns the format of export and import from the format query, json is the default format
+func dataFormat(r *http.Request) (format string, err error) {
+	return pokedexfile.ParseFormat(r.URL.Query().Get("format"))
+}
+
+// pokemonExporter streams the exported pokemons and flushes every pokemon to the client right away,
+// nothing is written before the first pokemon so a failed export still gets an error response
+type pokemonExporter struct {
+	w       http.ResponseWriter
+	format  string
+	encoder *pokedexfile.Encoder
+	started bool
+}
+
+func newPokemonExporter(w http.ResponseWriter, format string) *pokemonExporter {
+	return &pokemonExporter{
+		w:       w,
+		format:  format,
+		encoder: pokedexfile.NewEncoder(w, format),
+	}
+}
+
+func (e *pokemonExporter) start() {
+	e.started = true
+	e.w.Header().Set("Content-Type", pokedexfile.ContentType(e.format))
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="pokemons.%s"`, e.format))
+	e.w.WriteHeader(http.StatusOK)
+}
+
+// write is called with every exported pokemon
+func (e *pokemonExporter) write(data entity.PokemonDetail) (err error) {
+	if !e.started {
+		e.start()
+	}
+
+	err = e.encoder.Encode(data)
+	if err != nil {
+		return err
+	}
+
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// finish ends the export, an export without pokemon still has the header of the format
+func (e *pokemonExporter) finish() (err error) {
+	if !e.started {
+		e.start()
+	}
+
+	return e.encoder.Close()
 }
@@ -1,43 +1,675 @@
 package server
 
 import (
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	"github.com/winartodev/go-pokedex/pokedexfile"
+	"github.com/winartodev/go-pokedex/usecase"
 )
 
-func Test_buildQueryFilter(t *testing.T) {
+func Test_buildPokemonFilter(t *testing.T) {
 	type args struct {
-		query map[string][]string
+		query url.Values
 	}
 	tests := []struct {
 		name       string
 		args       args
-		wantResult map[string]string
+		wantResult entity.PokemonFilter
+		wantErr    bool
 	}{
 		{
-			name: "success",
+			name: "success filter",
 			args: args{
-				query: map[string][]string{
-					"name":     {"ganteng"},
-					"options":  {"1"},
-					"type":     {"1,2,3"},
+				query: url.Values{
+					"filter":   {"type:fire AND stats.speed>60"},
 					"sort_by":  {"id"},
 					"order_by": {"desc"},
 				},
 			},
-			wantResult: map[string]string{
-				"name":     "ganteng",
-				"options":  "1",
-				"order_by": "desc",
-				"sort_by":  "id",
-				"type":     "1,2,3",
+			wantResult: entity.PokemonFilter{
+				Expr: filter.And{
+					Left:  filter.Comparison{Field: "type", Op: filter.OpEqual, Value: "fire", Position: 1},
+					Right: filter.Comparison{Field: "stats.speed", Op: filter.OpGreater, Value: "60", Position: 15},
+				},
+				SortBy:  "id",
+				OrderBy: "desc",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success older queries",
+			args: args{
+				query: url.Values{
+					"name":    {"ganteng"},
+					"options": {"1"},
+					"type":    {"1, 2"},
+				},
+			},
+			wantResult: entity.PokemonFilter{
+				Expr: filter.And{
+					Left:  filter.Comparison{Field: "name", Op: filter.OpContains, Value: "ganteng"},
+					Right: filter.Comparison{Field: "catched", Op: filter.OpEqual, Value: "1"},
+				},
+				TypeAny: []string{"1", "2"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success types",
+			args: args{
+				query: url.Values{
+					"type_all": {"fire, flying", "normal"},
+					"type_any": {"5,,6"},
+					"type_not": {"water"},
+				},
+			},
+			wantResult: entity.PokemonFilter{
+				TypeAll: []string{"fire", "flying", "normal"},
+				TypeAny: []string{"5", "6"},
+				TypeNot: []string{"water"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success without filter",
+			args: args{
+				query: url.Values{},
+			},
+			wantResult: entity.PokemonFilter{},
+			wantErr:    false,
+		},
+		{
+			name: "failed invalid filter",
+			args: args{
+				query: url.Values{
+					"filter": {"type:fire AND"},
+				},
+			},
+			wantResult: entity.PokemonFilter{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResult, err := buildPokemonFilter(tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("buildPokemonFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("buildPokemonFilter() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func Test_buildUserFilter(t *testing.T) {
+	type args struct {
+		query url.Values
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult entity.UserFilter
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			args: args{
+				query: url.Values{
+					"q":      {"win"},
+					"limit":  {"10"},
+					"offset": {"20"},
+				},
+			},
+			wantResult: entity.UserFilter{Search: "win", Limit: 10, Offset: 20},
+			wantErr:    false,
+		},
+		{
+			name: "failed parse limit",
+			args: args{
+				query: url.Values{"limit": {"abc"}},
+			},
+			wantResult: entity.UserFilter{},
+			wantErr:    true,
+		},
+		{
+			name: "failed parse offset",
+			args: args{
+				query: url.Values{"offset": {"abc"}},
+			},
+			wantResult: entity.UserFilter{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResult, err := buildUserFilter(tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("buildUserFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("buildUserFilter() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func Test_buildAuditFilter(t *testing.T) {
+	since := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		query url.Values
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult entity.AuditFilter
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			args: args{
+				query: url.Values{
+					"resource": {"pokemon"},
+					"actor":    {"admin"},
+					"since":    {"2022-12-01T00:00:00Z"},
+					"limit":    {"10"},
+					"offset":   {"20"},
+				},
+			},
+			wantResult: entity.AuditFilter{Resource: "pokemon", Actor: "admin", Since: &since, Limit: 10, Offset: 20},
+			wantErr:    false,
+		},
+		{
+			name: "failed parse since",
+			args: args{
+				query: url.Values{"since": {"2022-12-01"}},
+			},
+			wantResult: entity.AuditFilter{},
+			wantErr:    true,
+		},
+		{
+			name: "failed parse limit",
+			args: args{
+				query: url.Values{"limit": {"abc"}},
+			},
+			wantResult: entity.AuditFilter{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResult, err := buildAuditFilter(tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("buildAuditFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("buildAuditFilter() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func Test_clientIP(t *testing.T) {
+	type args struct {
+		remoteAddr string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "success with port",
+			args: args{
+				remoteAddr: "192.0.2.1:1234",
+			},
+			want: "192.0.2.1",
+		},
+		{
+			name: "success ipv6 with port",
+			args: args{
+				remoteAddr: "[2001:db8::1]:1234",
+			},
+			want: "2001:db8::1",
+		},
+		{
+			name: "success without port",
+			args: args{
+				remoteAddr: "192.0.2.1",
+			},
+			want: "192.0.2.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/login", nil)
+			r.RemoteAddr = tt.args.remoteAddr
+			if got := clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isMergePatch(t *testing.T) {
+	type args struct {
+		contentType string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "success merge patch",
+			args: args{
+				contentType: "application/merge-patch+json",
+			},
+			want: true,
+		},
+		{
+			name: "success json with charset",
+			args: args{
+				contentType: "application/json; charset=utf-8",
+			},
+			want: true,
+		},
+		{
+			name: "success without content type",
+			args: args{
+				contentType: "",
+			},
+			want: true,
+		},
+		{
+			name: "failed json patch",
+			args: args{
+				contentType: "application/json-patch+json",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PATCH", "/internal/pokedex/pokemons/1", nil)
+			if tt.args.contentType != "" {
+				r.Header.Set("Content-Type", tt.args.contentType)
+			}
+			if got := isMergePatch(r); got != tt.want {
+				t.Errorf("isMergePatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ifMatchVersion(t *testing.T) {
+	type args struct {
+		ifMatch string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name: "success strong etag",
+			args: args{
+				ifMatch: `"3"`,
+			},
+			wantVersion: 3,
+			wantErr:     nil,
+		},
+		{
+			name: "success strong etag of pokemon with types",
+			args: args{
+				ifMatch: `"3-1.4"`,
+			},
+			wantVersion: 3,
+			wantErr:     nil,
+		},
+		{
+			name: "success any version",
+			args: args{
+				ifMatch: "*",
+			},
+			wantVersion: 0,
+			wantErr:     nil,
+		},
+		{
+			name: "failed header missing",
+			args: args{
+				ifMatch: "",
+			},
+			wantVersion: 0,
+			wantErr:     errIfMatchRequired,
+		},
+		{
+			name: "failed weak etag",
+			args: args{
+				ifMatch: `W/"3"`,
+			},
+			wantVersion: 0,
+			wantErr:     usecase.ErrVersionMismatch,
+		},
+		{
+			name: "failed not a version",
+			args: args{
+				ifMatch: `"abc"`,
+			},
+			wantVersion: 0,
+			wantErr:     usecase.ErrVersionMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/internal/pokedex/pokemons/1", nil)
+			if tt.args.ifMatch != "" {
+				r.Header.Set("If-Match", tt.args.ifMatch)
+			}
+			gotVersion, err := ifMatchVersion(r)
+			if err != tt.wantErr {
+				t.Errorf("ifMatchVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotVersion != tt.wantVersion {
+				t.Errorf("ifMatchVersion() = %v, want %v", gotVersion, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func Test_pokemonETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		pokemon *entity.PokemonDetail
+		want    string
+	}{
+		{
+			name:    "pokemon without types",
+			pokemon: &entity.PokemonDetail{Version: 3},
+			want:    `"3"`,
+		},
+		{
+			name:    "pokemon with types",
+			pokemon: &entity.PokemonDetail{Version: 3, Types: []string{"GRASS", "POISON"}, TypeVersions: []int64{1, 4}},
+			want:    `"3-1.4"`,
+		},
+		{
+			name:    "renamed type changes the tag",
+			pokemon: &entity.PokemonDetail{Version: 3, Types: []string{"GRASS", "TOXIC"}, TypeVersions: []int64{1, 5}},
+			want:    `"3-1.5"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pokemonETag(tt.pokemon); got != tt.want {
+				t.Errorf("pokemonETag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_notModified(t *testing.T) {
+	type args struct {
+		ifNoneMatch string
+		tag         string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "success same tag",
+			args: args{
+				ifNoneMatch: `"2"`,
+				tag:         `"2"`,
+			},
+			want: true,
+		},
+		{
+			name: "success weak tag in list",
+			args: args{
+				ifNoneMatch: `"1", W/"2"`,
+				tag:         `"2"`,
+			},
+			want: true,
+		},
+		{
+			name: "success any tag",
+			args: args{
+				ifNoneMatch: "*",
+				tag:         `"2"`,
+			},
+			want: true,
+		},
+		{
+			name: "failed tag changed",
+			args: args{
+				ifNoneMatch: `"1"`,
+				tag:         `"2"`,
+			},
+			want: false,
+		},
+		{
+			name: "failed header missing",
+			args: args{
+				ifNoneMatch: "",
+				tag:         `"2"`,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/pokedex/pokemons/1", nil)
+			if tt.args.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.args.ifNoneMatch)
+			}
+			if got := notModified(r, tt.args.tag); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bulkAtomic(t *testing.T) {
+	type args struct {
+		mode string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantAtomic bool
+		wantErr    error
+	}{
+		{
+			name: "success default",
+			args: args{
+				mode: "",
+			},
+			wantAtomic: true,
+			wantErr:    nil,
+		},
+		{
+			name: "success atomic",
+			args: args{
+				mode: "atomic",
+			},
+			wantAtomic: true,
+			wantErr:    nil,
+		},
+		{
+			name: "success best effort",
+			args: args{
+				mode: "best_effort",
+			},
+			wantAtomic: false,
+			wantErr:    nil,
+		},
+		{
+			name: "failed unknown mode",
+			args: args{
+				mode: "lazy",
+			},
+			wantAtomic: false,
+			wantErr:    errInvalidBulkMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons?mode="+tt.args.mode, nil)
+			gotAtomic, err := bulkAtomic(r)
+			if err != tt.wantErr {
+				t.Errorf("bulkAtomic() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotAtomic != tt.wantAtomic {
+				t.Errorf("bulkAtomic() = %v, want %v", gotAtomic, tt.wantAtomic)
+			}
+		})
+	}
+}
+
+func Test_dataFormat(t *testing.T) {
+	type args struct {
+		format string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantFormat string
+		wantErr    error
+	}{
+		{
+			name: "success default",
+			args: args{
+				format: "",
+			},
+			wantFormat: pokedexfile.JSON,
+			wantErr:    nil,
+		},
+		{
+			name: "success csv",
+			args: args{
+				format: "csv",
+			},
+			wantFormat: pokedexfile.CSV,
+			wantErr:    nil,
+		},
+		{
+			name: "success ndjson",
+			args: args{
+				format: "ndjson",
+			},
+			wantFormat: pokedexfile.NDJSON,
+			wantErr:    nil,
+		},
+		{
+			name: "failed unknown format",
+			args: args{
+				format: "xml",
+			},
+			wantFormat: "",
+			wantErr:    pokedexfile.ErrInvalidFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/internal/pokedex/export?format="+tt.args.format, nil)
+			gotFormat, err := dataFormat(r)
+			if err != tt.wantErr {
+				t.Errorf("dataFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotFormat != tt.wantFormat {
+				t.Errorf("dataFormat() = %v, want %v", gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func Test_pokemonExporter(t *testing.T) {
+	pokemons := []entity.PokemonDetail{
+		{ID: 1, Name: "Bulbasour", Species: "pokemon", Types: []string{"GRASS"}},
+		{ID: 2, Name: "Ivysaur", Species: "pokemon"},
+	}
+
+	type args struct {
+		format   string
+		pokemons []entity.PokemonDetail
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name: "success csv",
+			args: args{
+				format:   pokedexfile.CSV,
+				pokemons: pokemons,
+			},
+			wantBody:        "id,name,species,types,catched,image_url,description,weight,height,hp,attack,def,speed\n1,Bulbasour,pokemon,GRASS,0,,,0,0,0,0,0,0\n2,Ivysaur,pokemon,,0,,,0,0,0,0,0,0\n",
+			wantContentType: "text/csv",
+		},
+		{
+			name: "success json",
+			args: args{
+				format:   pokedexfile.JSON,
+				pokemons: pokemons,
+			},
+			wantBody:        "[{\"id\":1,\"name\":\"Bulbasour\",\"species\":\"pokemon\",\"types\":[\"GRASS\"],\"catched\":0,\"stats\":{\"hp\":0,\"attack\":0,\"def\":0,\"speed\":0}}\n,{\"id\":2,\"name\":\"Ivysaur\",\"species\":\"pokemon\",\"types\":null,\"catched\":0,\"stats\":{\"hp\":0,\"attack\":0,\"def\":0,\"speed\":0}}\n]",
+			wantContentType: "application/json",
+		},
+		{
+			name: "success json without pokemon",
+			args: args{
+				format:   pokedexfile.JSON,
+				pokemons: nil,
+			},
+			wantBody:        "[]",
+			wantContentType: "application/json",
+		},
+		{
+			name: "success ndjson",
+			args: args{
+				format:   pokedexfile.NDJSON,
+				pokemons: pokemons[:1],
 			},
+			wantBody:        "{\"id\":1,\"name\":\"Bulbasour\",\"species\":\"pokemon\",\"types\":[\"GRASS\"],\"catched\":0,\"stats\":{\"hp\":0,\"attack\":0,\"def\":0,\"speed\":0}}\n",
+			wantContentType: "application/x-ndjson",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotResult := buildQueryFilter(tt.args.query); !reflect.DeepEqual(gotResult, tt.wantResult) {
-				t.Errorf("buildQueryFilter() = %v, want %v", gotResult, tt.wantResult)
+			w := httptest.NewRecorder()
+			exporter := newPokemonExporter(w, tt.args.format)
+			for _, pokemon := range tt.args.pokemons {
+				if err := exporter.write(pokemon); err != nil {
+					t.Fatalf("pokemonExporter.write() error = %v", err)
+				}
+			}
+			if err := exporter.finish(); err != nil {
+				t.Fatalf("pokemonExporter.finish() error = %v", err)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("pokemonExporter body = %v, want %v", got, tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("pokemonExporter Content-Type = %v, want %v", got, tt.wantContentType)
 			}
 		})
 	}
@@ -3,12 +3,17 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/winartodev/go-pokedex/entity"
 	"github.com/winartodev/go-pokedex/helper"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/pokedexfile"
 	"github.com/winartodev/go-pokedex/usecase"
 )
 
@@ -17,6 +22,9 @@ type Server struct {
 	PokemonUsecase usecase.PokemonUsecaseItf
 	TypeUsecase    usecase.TypeUsecaseItf
 	UserUsecase    usecase.UserUsecaseItf
+	APIKeyUsecase  usecase.APIKeyUsecaseItf
+	TrashUsecase   usecase.TrashUsecaseItf
+	AuditUsecase   usecase.AuditUsecaseItf
 }
 
 func (s *Server) GetAllPokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -24,17 +32,22 @@ func (s *Server) GetAllPokemon(w http.ResponseWriter, r *http.Request, _ httprou
 	var err error
 	var ctx = r.Context()
 
-	filter := buildQueryFilter(r.URL.Query())
-	if len(filter) > 0 {
+	filter, err := buildPokemonFilter(r.URL.Query())
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	if hasPokemonFilter(filter) {
 		pokemons, err = s.PokemonUsecase.GetAllPokemonByFilter(ctx, filter)
 		if err != nil {
-			helper.FailedResponse(w, http.StatusBadRequest, err)
+			helper.ErrorResponse(w, err)
 			return
 		}
 	} else {
 		pokemons, err = s.PokemonUsecase.GetAllPokemon(ctx)
 		if err != nil {
-			helper.FailedResponse(w, http.StatusBadRequest, err)
+			helper.ErrorResponse(w, err)
 			return
 		}
 	}
@@ -42,6 +55,52 @@ func (s *Server) GetAllPokemon(w http.ResponseWriter, r *http.Request, _ httprou
 	helper.SuccessResponse(w, "", pokemons)
 }
 
+// SearchPokemon returns the pokemons best matching q by name, species and description, typos are tolerated
+func (s *Server) SearchPokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	query := r.URL.Query()
+
+	var limit int
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			helper.FailedResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	res, err := s.PokemonUsecase.SearchPokemon(r.Context(), query.Get("q"), limit)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+// SuggestPokemon returns the names and species starting with prefix for as-you-type suggestions
+func (s *Server) SuggestPokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	query := r.URL.Query()
+
+	var limit int
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			helper.FailedResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	res, err := s.PokemonUsecase.SuggestPokemon(r.Context(), query.Get("prefix"), limit)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
 func (s *Server) GetPokemonByID(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
 	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
@@ -51,7 +110,14 @@ func (s *Server) GetPokemonByID(w http.ResponseWriter, r *http.Request, param ht
 
 	pokemon, err := s.PokemonUsecase.GetPokemonByID(r.Context(), id)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	tag := pokemonETag(pokemon)
+	w.Header().Set("ETag", tag)
+	if notModified(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -67,7 +133,7 @@ func (s *Server) CatchPokemon(w http.ResponseWriter, r *http.Request, param http
 
 	err = s.PokemonUsecase.CatchPokemon(r.Context(), id)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
@@ -84,7 +150,7 @@ func (s *Server) CreatePokemon(w http.ResponseWriter, r *http.Request, param htt
 
 	id, err := s.PokemonUsecase.CreatePokemon(r.Context(), pokemon)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
@@ -98,6 +164,12 @@ func (s *Server) UpdatePokemon(w http.ResponseWriter, r *http.Request, param htt
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
 	var pokemon entity.Pokemon
 	decoder := json.NewDecoder(r.Body)
 	if err := decoder.Decode(&pokemon); err != nil {
@@ -105,35 +177,261 @@ func (s *Server) UpdatePokemon(w http.ResponseWriter, r *http.Request, param htt
 		return
 	}
 
-	res, err := s.PokemonUsecase.UpdatePokemon(r.Context(), id, pokemon)
+	res, err := s.PokemonUsecase.UpdatePokemon(r.Context(), id, version, pokemon)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", pokemonETag(res))
+
 	helper.SuccessResponse(w, "update pokemon success ", res)
 }
 
-func (s *Server) DeletePokemon(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+func (s *Server) PatchPokemon(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
 	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
-	err = s.PokemonUsecase.DeletePokemon(r.Context(), id)
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	if !isMergePatch(r) {
+		helper.FailedResponse(w, http.StatusUnsupportedMediaType, errMergePatchOnly)
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.PatchPokemon(r.Context(), id, version, patch)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", pokemonETag(res))
+
+	helper.SuccessResponse(w, "patch pokemon success", res)
+}
+
+func (s *Server) DeletePokemon(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	err = s.PokemonUsecase.DeletePokemon(r.Context(), id, version)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
 	helper.SuccessResponse(w, "delete pokemon success", nil)
 }
 
+func (s *Server) RestorePokemon(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.PokemonUsecase.RestorePokemon(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "restore pokemon success", nil)
+}
+
+func (s *Server) GetPokemonRevisions(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.GetPokemonRevisions(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) RestorePokemonRevision(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	revision, err := strconv.ParseInt(param.ByName("rev"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.RestorePokemonRevision(r.Context(), id, revision, version)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", pokemonETag(res))
+
+	helper.SuccessResponse(w, "restore pokemon revision success", res)
+}
+
+func (s *Server) BulkCreatePokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	atomic, err := bulkAtomic(r)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var pokemons []entity.BulkPokemon
+	if err := json.NewDecoder(r.Body).Decode(&pokemons); err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.BulkCreatePokemon(r.Context(), pokemons, atomic)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "bulk create pokemon done", res)
+}
+
+func (s *Server) BulkUpdatePokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	atomic, err := bulkAtomic(r)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var pokemons []entity.BulkPokemon
+	if err := json.NewDecoder(r.Body).Decode(&pokemons); err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.BulkUpdatePokemon(r.Context(), pokemons, atomic)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "bulk update pokemon done", res)
+}
+
+func (s *Server) BulkDeletePokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	atomic, err := bulkAtomic(r)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var pokemons []entity.BulkPokemon
+	if err := json.NewDecoder(r.Body).Decode(&pokemons); err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.BulkDeletePokemon(r.Context(), pokemons, atomic)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "bulk delete pokemon done", res)
+}
+
+// ExportPokemon streams every pokemon with its types and metadata as csv, json or ndjson
+func (s *Server) ExportPokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	format, err := dataFormat(r)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	exporter := newPokemonExporter(w, format)
+	err = s.PokemonUsecase.ExportPokemon(r.Context(), exporter.write)
+	if err != nil && !exporter.started {
+		helper.ErrorResponse(w, err)
+		return
+	}
+	if err == nil {
+		err = exporter.finish()
+	}
+	if err != nil {
+		// the status is already sent, the client only gets a truncated export
+		log.Printf("export pokemon: %v", err)
+	}
+}
+
+// ImportPokemon upserts the pokemons of csv, json or ndjson body, dry_run=true reports the result without saving anything
+func (s *Server) ImportPokemon(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	format, err := dataFormat(r)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var dryRun bool
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			helper.FailedResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	pokemons, err := pokedexfile.Decode(r.Body, format)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.PokemonUsecase.ImportPokemon(r.Context(), pokemons, dryRun)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "import pokemon done", res)
+}
+
 func (s *Server) GetAllType(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	res, err := s.TypeUsecase.GetAllType(r.Context())
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
@@ -150,7 +448,7 @@ func (s *Server) CreateType(w http.ResponseWriter, r *http.Request, _ httprouter
 
 	res, err := s.TypeUsecase.CreateType(r.Context(), types)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
@@ -166,7 +464,14 @@ func (s *Server) GetTypeByID(w http.ResponseWriter, r *http.Request, param httpr
 
 	res, err := s.TypeUsecase.GeTypeByID(r.Context(), id)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	tag := etag(res.Version)
+	w.Header().Set("ETag", tag)
+	if notModified(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -180,6 +485,12 @@ func (s *Server) UpdateType(w http.ResponseWriter, r *http.Request, param httpro
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
 	var types entity.Type
 	decoder := json.NewDecoder(r.Body)
 	if err := decoder.Decode(&types); err != nil {
@@ -187,76 +498,681 @@ func (s *Server) UpdateType(w http.ResponseWriter, r *http.Request, param httpro
 		return
 	}
 
-	err = s.TypeUsecase.UpdateType(r.Context(), id, types)
+	res, err := s.TypeUsecase.UpdateType(r.Context(), id, version, types)
 	if err != nil {
-		helper.FailedResponse(w, http.StatusBadRequest, err)
+		helper.ErrorResponse(w, err)
 		return
 	}
 
-	helper.SuccessResponse(w, "update type success", nil)
+	w.Header().Set("ETag", etag(res.Version))
+
+	helper.SuccessResponse(w, "update type success", res)
 }
 
-func (s *Server) Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	var request entity.User
-	err := json.NewDecoder(r.Body).Decode(&request)
+func (s *Server) PatchType(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
-	// validate username & password
-	if request.Username == "" {
-		helper.FailedResponse(w, http.StatusBadRequest, errors.New("username can't be empty"))
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
 		return
 	}
-	if request.Password == "" {
-		helper.FailedResponse(w, http.StatusBadRequest, errors.New("password can't be empty"))
+
+	if !isMergePatch(r) {
+		helper.FailedResponse(w, http.StatusUnsupportedMediaType, errMergePatchOnly)
 		return
 	}
 
-	id, err := s.UserUsecase.Register(r.Context(), request.Username, request.Email, request.Password, request.Role)
+	patch, err := io.ReadAll(r.Body)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
-	helper.SuccessResponse(w, "", id)
-}
+	res, err := s.TypeUsecase.PatchType(r.Context(), id, version, patch)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
 
-func (s *Server) Login(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	var request entity.User
-	err := json.NewDecoder(r.Body).Decode(&request)
+	w.Header().Set("ETag", etag(res.Version))
+
+	helper.SuccessResponse(w, "patch type success", res)
+}
 
+func (s *Server) DeleteType(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
-	// validate username & password
-	if request.Username == "" {
-		helper.FailedResponse(w, http.StatusBadRequest, errors.New("username can't be empty"))
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
 		return
 	}
-	if request.Password == "" {
-		helper.FailedResponse(w, http.StatusBadRequest, errors.New("password can't be empty"))
+
+	// cascade removes the type from pokemons that have it instead of refusing the delete
+	cascade := false
+	if value := r.URL.Query().Get("cascade"); value != "" {
+		cascade, err = strconv.ParseBool(value)
+		if err != nil {
+			helper.FailedResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	err = s.TypeUsecase.DeleteType(r.Context(), id, version, cascade)
+	if err != nil {
+		helper.ErrorResponse(w, err)
 		return
 	}
 
-	token, err := s.UserUsecase.Login(r.Context(), request.Username, request.Password)
+	helper.SuccessResponse(w, "delete type success", nil)
+}
+
+func (s *Server) RestoreType(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
 	if err != nil {
 		helper.FailedResponse(w, http.StatusBadRequest, err)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:  "token",
-		Value: token,
-	})
+	err = s.TypeUsecase.RestoreType(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
 
-	helper.SuccessResponse(w, "login success", nil)
+	helper.SuccessResponse(w, "restore type success", nil)
+}
+
+func (s *Server) GetTrash(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	res, err := s.TrashUsecase.GetTrash(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) GetAuditLogs(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	filter, err := buildAuditFilter(r.URL.Query())
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.AuditUsecase.GetAuditLogs(r.Context(), filter)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.User
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := s.UserUsecase.Register(r.Context(), request.Username, request.Email, request.Password)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", id)
+}
+
+func (s *Server) CreateUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.User
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := s.UserUsecase.CreateUser(r.Context(), request.Username, request.Email, request.Password, request.Role)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "create user success", id)
+}
+
+func (s *Server) UpdateUserRole(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var request entity.User
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.UpdateUserRole(r.Context(), id, request.Role)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "update user role success", nil)
+}
+
+func (s *Server) GetAllUsers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	filter, err := buildUserFilter(r.URL.Query())
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.UserUsecase.GetAllUsers(r.Context(), filter)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) SetUserDisabled(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var request entity.User
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.SetUserDisabled(r.Context(), id, request.Disabled)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "update user status success", nil)
+}
+
+func (s *Server) DeleteUser(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.DeleteUser(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "delete user success", nil)
+}
+
+func (s *Server) CreateAPIKey(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.CreateAPIKey
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.APIKeyUsecase.CreateAPIKey(r.Context(), request)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "create api key success, store the key safely it is only shown once", res)
+}
+
+func (s *Server) GetAllAPIKeys(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	res, err := s.APIKeyUsecase.GetAllAPIKeys(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) RevokeAPIKey(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.APIKeyUsecase.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "revoke api key success", nil)
+}
+
+func (s *Server) GetProfile(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	res, err := s.UserUsecase.GetProfile(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) UpdateProfile(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	var request entity.UpdateProfile
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.UserUsecase.UpdateProfile(r.Context(), id, request)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "update profile success", res)
+}
+
+func (s *Server) ChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	var request entity.ChangePassword
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.ChangePassword(r.Context(), id, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "change password success", nil)
+}
+
+func (s *Server) DeleteAccount(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	err = s.UserUsecase.DeleteUser(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   "token",
+		MaxAge: -1,
+	})
+
+	helper.SuccessResponse(w, "delete account success", nil)
+}
+
+func (s *Server) SendVerificationEmail(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	err = s.UserUsecase.SendVerificationEmail(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "verification email sent", nil)
+}
+
+func (s *Server) VerifyEmail(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	err := s.UserUsecase.VerifyEmail(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "email verified", nil)
+}
+
+func (s *Server) ForgotPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.User
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Email == "" {
+		helper.ErrorResponse(w, errEmailRequired)
+		return
+	}
+
+	err = s.UserUsecase.ForgotPassword(r.Context(), request.Email)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "if the email is registered a reset token has been sent", nil)
+}
+
+func (s *Server) ResetPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.ResetPassword
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.ResetPassword(r.Context(), request.Token, request.Password)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "reset password success", nil)
+}
+
+func (s *Server) Login(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.User
+	err := json.NewDecoder(r.Body).Decode(&request)
+
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// validate username & password
+	if request.Username == "" {
+		helper.ErrorResponse(w, errUsernameRequired)
+		return
+	}
+	if request.Password == "" {
+		helper.ErrorResponse(w, errPasswordRequired)
+		return
+	}
+
+	result, err := s.UserUsecase.Login(r.Context(), request.Username, request.Password, clientFromRequest(r))
+	if err != nil {
+		loginFailedResponse(w, err)
+		return
+	}
+
+	if result.Challenge != "" {
+		helper.SuccessResponse(w, "two factor authentication required", result)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:  "token",
+		Value: result.Token,
+	})
+
+	helper.SuccessResponse(w, "login success", nil)
+}
+
+func (s *Server) LoginTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request entity.TwoFactorLogin
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := s.UserUsecase.LoginTwoFactor(r.Context(), request.Challenge, request.Code, clientFromRequest(r))
+	if err != nil {
+		loginFailedResponse(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:  "token",
+		Value: result.Token,
+	})
+
+	helper.SuccessResponse(w, "login success", nil)
+}
+
+func (s *Server) OIDCLogin(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	result, err := s.UserUsecase.StartOIDCLogin(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    result.Flow,
+		Path:     "/login/oidc",
+		MaxAge:   int(usecase.OIDCFlowTTL.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, result.AuthURL, http.StatusFound)
+}
+
+func (s *Server) OIDCCallback(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		helper.FailedResponse(w, http.StatusUnauthorized, fmt.Errorf("oidc login failed: %s", query.Get("error")))
+		return
+	}
+
+	flow, err := r.Cookie(oidcFlowCookie)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, errors.New("oidc login is not started"))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   oidcFlowCookie,
+		Path:   "/login/oidc",
+		MaxAge: -1,
+	})
+
+	result, err := s.UserUsecase.FinishOIDCLogin(r.Context(), flow.Value, query.Get("state"), query.Get("code"), clientFromRequest(r))
+	if err != nil {
+		loginFailedResponse(w, err)
+		return
+	}
+
+	if result.Challenge != "" {
+		helper.SuccessResponse(w, "two factor authentication required", result)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:  "token",
+		Value: result.Token,
+	})
+
+	helper.SuccessResponse(w, "login success", nil)
+}
+
+func (s *Server) EnrollTOTP(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	result, err := s.UserUsecase.EnrollTOTP(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "scan the uri with an authenticator app and confirm with a code", result)
+}
+
+func (s *Server) ConfirmTOTP(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	var request entity.TOTPConfirmation
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	recoveryCodes, err := s.UserUsecase.ConfirmTOTP(r.Context(), id, request.Code)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "two factor authentication enabled, store the recovery codes safely", recoveryCodes)
+}
+
+func (s *Server) DisableTOTP(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	var request entity.User
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.DisableTOTP(r.Context(), id, request.Password)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "two factor authentication disabled", nil)
+}
+
+func (s *Server) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	var request entity.TOTPConfirmation
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	recoveryCodes, err := s.UserUsecase.RegenerateRecoveryCodes(r.Context(), id, request.Code)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "recovery codes regenerated", recoveryCodes)
+}
+
+func (s *Server) GetSessions(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	res, err := s.UserUsecase.GetSessions(r.Context(), id)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "", res)
+}
+
+func (s *Server) RevokeSession(w http.ResponseWriter, r *http.Request, param httprouter.Params) {
+	id, err := userIDFromRequest(r)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(param.ByName("id"), 10, 64)
+	if err != nil {
+		helper.FailedResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.UserUsecase.RevokeSession(r.Context(), id, sessionID)
+	if err != nil {
+		helper.ErrorResponse(w, err)
+		return
+	}
+
+	helper.SuccessResponse(w, "revoke session success", nil)
 }
 
 func (s *Server) Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if c, err := r.Cookie("token"); err == nil {
+		if claims, err := auth.ValidateToken(c.Value); err == nil {
+			// the session may already be revoked, the user is logged out anyway
+			err = s.UserUsecase.RevokeSession(r.Context(), claims.ID, claims.SessionID)
+			if err != nil && !errors.Is(err, usecase.ErrSessionNotFound) {
+				helper.ErrorResponse(w, err)
+				return
+			}
+		}
+	}
+
 	http.SetCookie(w, &http.Cookie{
 		Name:   "token",
 		MaxAge: -1,
@@ -2,15 +2,22 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/apperror"
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/throttle"
 	"github.com/winartodev/go-pokedex/usecase"
 	usecasemock "github.com/winartodev/go-pokedex/usecase/mocks"
 )
@@ -20,6 +27,9 @@ type mockServerProvider struct {
 	PokemonUsecase *usecasemock.PokemonUsecaseItf
 	TypeUsecase    *usecasemock.TypeUsecaseItf
 	UserUsecase    *usecasemock.UserUsecaseItf
+	APIKeyUsecase  *usecasemock.APIKeyUsecaseItf
+	TrashUsecase   *usecasemock.TrashUsecaseItf
+	AuditUsecase   *usecasemock.AuditUsecaseItf
 }
 
 func serverPorvider() mockServerProvider {
@@ -28,9 +38,29 @@ func serverPorvider() mockServerProvider {
 		PokemonUsecase: new(usecasemock.PokemonUsecaseItf),
 		TypeUsecase:    new(usecasemock.TypeUsecaseItf),
 		UserUsecase:    new(usecasemock.UserUsecaseItf),
+		APIKeyUsecase:  new(usecasemock.APIKeyUsecaseItf),
+		TrashUsecase:   new(usecasemock.TrashUsecaseItf),
+		AuditUsecase:   new(usecasemock.AuditUsecaseItf),
 	}
 }
 
+// loggedInRequest creates request that carries the claims middleware stores for a logged in user
+func loggedInRequest(method string, target string, body io.Reader) *http.Request {
+	r := httptest.NewRequest(method, target, body)
+	return r.WithContext(auth.NewContext(r.Context(), &auth.JWTClaim{ID: 1, Username: "winarto", Role: enum.User}))
+}
+
+func patchRequest(target string, body string, contentType string) *http.Request {
+	r := httptest.NewRequest("PATCH", target, bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func withHeader(r *http.Request, key string, value string) *http.Request {
+	r.Header.Set(key, value)
+	return r
+}
+
 var (
 	pokemon = entity.Pokemon{
 		Name:        "Bulbasour",
@@ -142,6 +172,21 @@ func TestServer_GetAllPokemon(t *testing.T) {
 					Return(nil, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed invalid filter",
+			fields: fields{
+				Router:         prov.Router,
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("GET", "/pokedex/pokemons?filter=type%3Afire+AND", nil),
+				in2: httprouter.Params{},
+			},
+			mock: func() {},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -229,6 +274,24 @@ func TestServer_GetPokemonByID(t *testing.T) {
 					Return(nil, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "success not modified",
+			fields: fields{
+				Router:         prov.Router,
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("GET", "/pokedex/pokemons/:id", nil), "If-None-Match", `"2"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("GetPokemonByID", mock.Anything, int64(1)).
+					Return(&entity.PokemonDetail{ID: 1, Version: 2}, nil).Times(1)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -436,11 +499,11 @@ func TestServer_UpdatePokemon(t *testing.T) {
 			},
 			args: args{
 				w:     httptest.NewRecorder(),
-				r:     httptest.NewRequest("PUT", "/pokedex/pokemons/:id", bytes.NewBuffer(body)),
+				r:     withHeader(httptest.NewRequest("PUT", "/pokedex/pokemons/:id", bytes.NewBuffer(body)), "If-Match", `"1"`),
 				param: httprouter.Params{{Key: "id", Value: "1"}},
 			},
 			mock: func() {
-				prov.PokemonUsecase.On("UpdatePokemon", mock.Anything, mock.Anything, mock.Anything).
+				prov.PokemonUsecase.On("UpdatePokemon", mock.Anything, int64(1), int64(1), mock.Anything).
 					Return(&entity.PokemonDetail{}, nil).Times(1)
 			},
 		},
@@ -469,14 +532,29 @@ func TestServer_UpdatePokemon(t *testing.T) {
 			},
 			args: args{
 				w:     httptest.NewRecorder(),
-				r:     httptest.NewRequest("PUT", "/pokedex/pokemons/:id", bytes.NewBuffer(body)),
+				r:     withHeader(httptest.NewRequest("PUT", "/pokedex/pokemons/:id", bytes.NewBuffer(body)), "If-Match", `"1"`),
 				param: httprouter.Params{{Key: "id", Value: "1"}},
 			},
 			mock: func() {
-				prov.PokemonUsecase.On("UpdatePokemon", mock.Anything, mock.Anything, mock.Anything).
+				prov.PokemonUsecase.On("UpdatePokemon", mock.Anything, int64(1), int64(1), mock.Anything).
 					Return(nil, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed if-match required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/pokedex/pokemons/:id", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -523,11 +601,11 @@ func TestServer_DeletePokemon(t *testing.T) {
 			},
 			args: args{
 				w:     httptest.NewRecorder(),
-				r:     httptest.NewRequest("DELETE", "/internal/pokedex/pokemons/:id", nil),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/pokemons/:id", nil), "If-Match", `"1"`),
 				param: httprouter.Params{{Key: "id", Value: "1"}},
 			},
 			mock: func() {
-				prov.PokemonUsecase.On("DeletePokemon", mock.Anything, mock.Anything).
+				prov.PokemonUsecase.On("DeletePokemon", mock.Anything, int64(1), int64(1)).
 					Return(nil).Times(1)
 			},
 		},
@@ -556,14 +634,29 @@ func TestServer_DeletePokemon(t *testing.T) {
 			},
 			args: args{
 				w:     httptest.NewRecorder(),
-				r:     httptest.NewRequest("DELETE", "/internal/pokedex/pokemons/:id", nil),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/pokemons/:id", nil), "If-Match", `"1"`),
 				param: httprouter.Params{{Key: "id", Value: "1"}},
 			},
 			mock: func() {
-				prov.PokemonUsecase.On("DeletePokemon", mock.Anything, mock.Anything).
+				prov.PokemonUsecase.On("DeletePokemon", mock.Anything, int64(1), int64(1)).
 					Return(errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed if-match required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/pokemons/:id", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -633,7 +726,7 @@ func TestServer_Register(t *testing.T) {
 				in2: httprouter.Params{},
 			},
 			mock: func() {
-				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(int64(1), nil).Times(1)
 			},
 		},
@@ -650,7 +743,10 @@ func TestServer_Register(t *testing.T) {
 				r:   httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyUsernameEmtpy)),
 				in2: httprouter.Params{},
 			},
-			mock: func() {},
+			mock: func() {
+				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(int64(0), apperror.New(apperror.Validation, "validation_failed", "username can't be empty")).Times(1)
+			},
 		},
 		{
 			name: "failed password empty",
@@ -665,7 +761,10 @@ func TestServer_Register(t *testing.T) {
 				r:   httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyPasswordEmpty)),
 				in2: httprouter.Params{},
 			},
-			mock: func() {},
+			mock: func() {
+				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(int64(0), apperror.New(apperror.Validation, "validation_failed", "password can't be empty")).Times(1)
+			},
 		},
 		{
 			name: "failed register user",
@@ -681,7 +780,7 @@ func TestServer_Register(t *testing.T) {
 				in2: httprouter.Params{},
 			},
 			mock: func() {
-				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				prov.UserUsecase.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(int64(0), errors.New("error")).Times(1)
 			},
 		},
@@ -754,8 +853,26 @@ func TestServer_Login(t *testing.T) {
 				in2: httprouter.Params{},
 			},
 			mock: func() {
-				prov.UserUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything).
-					Return("token", nil).Times(1)
+				prov.UserUsecase.On("Login", mock.Anything, "winarto", "123", entity.Client{IP: "192.0.2.1", UserAgent: ""}).
+					Return(entity.LoginResult{Token: "token"}, nil).Times(1)
+			},
+		},
+		{
+			name: "success two factor required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/login", bytes.NewBuffer(bodyCorrectUser)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("Login", mock.Anything, "winarto", "123", entity.Client{IP: "192.0.2.1", UserAgent: ""}).
+					Return(entity.LoginResult{Challenge: "challenge"}, nil).Times(1)
 			},
 		},
 		{
@@ -802,8 +919,44 @@ func TestServer_Login(t *testing.T) {
 				in2: httprouter.Params{},
 			},
 			mock: func() {
-				prov.UserUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything).
-					Return("", errors.New("error")).Times(1)
+				prov.UserUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.LoginResult{}, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed invalid credentials",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/login", bytes.NewBuffer(bodyCorrectUser)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.LoginResult{}, usecase.ErrInvalidCredentials).Times(1)
+			},
+		},
+		{
+			name: "failed too many attempts",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/login", bytes.NewBuffer(bodyCorrectUser)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.LoginResult{}, &throttle.ErrTooManyAttempts{RetryAfter: time.Minute}).Times(1)
 			},
 		},
 	}
@@ -824,6 +977,14 @@ func TestServer_Login(t *testing.T) {
 
 func TestServer_Logout(t *testing.T) {
 	prov := serverPorvider()
+
+	token, _ := auth.GenerateJWT(entity.User{ID: 1, Username: "winarto"}, 2)
+	logoutRequest := func() *http.Request {
+		r := httptest.NewRequest("POST", "/logout", nil)
+		r.AddCookie(&http.Cookie{Name: "token", Value: token})
+		return r
+	}
+
 	type fields struct {
 		Router         *httprouter.Router
 		PokemonUsecase usecase.PokemonUsecaseItf
@@ -839,6 +1000,7 @@ func TestServer_Logout(t *testing.T) {
 		name   string
 		fields fields
 		args   args
+		mock   func()
 	}{
 		{
 			name: "success",
@@ -853,9 +1015,63 @@ func TestServer_Logout(t *testing.T) {
 				r:   httptest.NewRequest("POST", "/logout", nil),
 				in2: httprouter.Params{},
 			},
+			mock: func() {},
+		},
+		{
+			name: "success revoke session",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   logoutRequest(),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RevokeSession", mock.Anything, int64(1), int64(2)).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success session already revoked",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   logoutRequest(),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RevokeSession", mock.Anything, int64(1), int64(2)).Return(usecase.ErrSessionNotFound).Times(1)
+			},
+		},
+		{
+			name: "failed revoke session",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   logoutRequest(),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RevokeSession", mock.Anything, int64(1), int64(2)).Return(errors.New("db down")).Times(1)
+			},
 		},
 	}
 	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Server{
 				Router:         tt.fields.Router,
@@ -914,3 +1130,4114 @@ func TestServer_Healthz(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_CreateUser(t *testing.T) {
+	prov := serverPorvider()
+
+	usernameEmpty := entity.User{
+		Username: "",
+		Password: "123",
+		Role:     2,
+	}
+
+	passwordEmpty := entity.User{
+		Username: "winarto",
+		Password: "",
+		Role:     2,
+	}
+
+	correctUser := entity.User{
+		Username: "winarto",
+		Password: "123",
+		Role:     2,
+	}
+
+	bodyUsernameEmtpy, _ := json.Marshal(usernameEmpty)
+	bodyPasswordEmpty, _ := json.Marshal(passwordEmpty)
+	bodyCorrectUser, _ := json.Marshal(correctUser)
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w   http.ResponseWriter
+		r   *http.Request
+		in2 httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/internal/users", bytes.NewBuffer(bodyCorrectUser)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("CreateUser", mock.Anything, "winarto", mock.Anything, "123", int64(2)).
+					Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/internal/users", bytes.NewBufferString("{")),
+				in2: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed username empty",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/internal/users", bytes.NewBuffer(bodyUsernameEmtpy)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(int64(0), apperror.New(apperror.Validation, "validation_failed", "username can't be empty")).Times(1)
+			},
+		},
+		{
+			name: "failed password empty",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/internal/users", bytes.NewBuffer(bodyPasswordEmpty)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(int64(0), apperror.New(apperror.Validation, "validation_failed", "password can't be empty")).Times(1)
+			},
+		},
+		{
+			name: "failed create user",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				r:   httptest.NewRequest("POST", "/internal/users", bytes.NewBuffer(bodyCorrectUser)),
+				in2: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(int64(0), errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.CreateUser(tt.args.w, tt.args.r, tt.args.in2)
+		})
+	}
+}
+
+func TestServer_UpdateUserRole(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.User{Role: 2})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/role", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("UpdateUserRole", mock.Anything, int64(2), int64(2)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/role", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/role", bytes.NewBufferString("{")),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed update role",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/role", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("UpdateUserRole", mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.UpdateUserRole(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetAllUsers(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/users?q=win&limit=10", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetAllUsers", mock.Anything, entity.UserFilter{Search: "win", Limit: 10}).
+					Return([]entity.UserProfile{{ID: 1, Username: "winarto"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse limit",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/users?limit=abc", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed get users",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/users", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetAllUsers", mock.Anything, mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.GetAllUsers(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_SetUserDisabled(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.User{Disabled: true})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/status", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("SetUserDisabled", mock.Anything, int64(2), true).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/status", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/status", bytes.NewBufferString("{")),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed set user disabled",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/users/:id/status", bytes.NewBuffer(body)),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("SetUserDisabled", mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.SetUserDisabled(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_DeleteUser(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/users/:id", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DeleteUser", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/users/:id", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed delete user",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/users/:id", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DeleteUser", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.DeleteUser(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_CreateAPIKey(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonRead}})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+		APIKeyUsecase  usecase.APIKeyUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/internal/api-keys", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("CreateAPIKey", mock.Anything, mock.Anything).
+					Return(entity.CreatedAPIKey{APIKey: entity.APIKey{ID: 1}, Key: "pk_abcd1234_secret"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/internal/api-keys", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed create api key",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/internal/api-keys", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("CreateAPIKey", mock.Anything, mock.Anything).
+					Return(entity.CreatedAPIKey{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+				APIKeyUsecase:  tt.fields.APIKeyUsecase,
+			}
+			s.CreateAPIKey(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetAllAPIKeys(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+		APIKeyUsecase  usecase.APIKeyUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/api-keys", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("GetAllAPIKeys", mock.Anything).
+					Return([]entity.APIKey{{ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/api-keys", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("GetAllAPIKeys", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+				APIKeyUsecase:  tt.fields.APIKeyUsecase,
+			}
+			s.GetAllAPIKeys(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RevokeAPIKey(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+		APIKeyUsecase  usecase.APIKeyUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/api-keys/1", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("RevokeAPIKey", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid id",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/api-keys/a", nil),
+				param: httprouter.Params{{Key: "id", Value: "a"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed revoke",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				APIKeyUsecase:  prov.APIKeyUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/api-keys/2", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.APIKeyUsecase.On("RevokeAPIKey", mock.Anything, int64(2)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+				APIKeyUsecase:  tt.fields.APIKeyUsecase,
+			}
+			s.RevokeAPIKey(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetProfile(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("GET", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetProfile", mock.Anything, int64(1)).
+					Return(entity.UserProfile{ID: 1, Username: "winarto"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed get profile",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("GET", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetProfile", mock.Anything, mock.Anything).
+					Return(entity.UserProfile{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.GetProfile(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_UpdateProfile(t *testing.T) {
+	prov := serverPorvider()
+
+	body := []byte(`{"display_name":"Budi"}`)
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PATCH", "/user/me", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("UpdateProfile", mock.Anything, int64(1), mock.Anything).
+					Return(entity.UserProfile{ID: 1, DisplayName: "Budi"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PATCH", "/user/me", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PATCH", "/user/me", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed update profile",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PATCH", "/user/me", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("UpdateProfile", mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.UserProfile{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.UpdateProfile(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ChangePassword(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.ChangePassword{CurrentPassword: "123", NewPassword: "456"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PUT", "/user/me/password", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ChangePassword", mock.Anything, int64(1), "123", "456").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/user/me/password", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PUT", "/user/me/password", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed change password",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("PUT", "/user/me/password", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ChangePassword(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_DeleteAccount(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DeleteUser", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed delete account",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/me", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DeleteUser", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.DeleteAccount(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_SendVerificationEmail(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/verify-email", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("SendVerificationEmail", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/user/me/verify-email", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed send verification email",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/verify-email", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("SendVerificationEmail", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.SendVerificationEmail(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_VerifyEmail(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/verify-email?token=token", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("VerifyEmail", mock.Anything, "token").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed verify email",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/verify-email?token=invalid", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("VerifyEmail", mock.Anything, "invalid").
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.VerifyEmail(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ForgotPassword(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.User{Email: "user@mail"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/forgot", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ForgotPassword", mock.Anything, "user@mail").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/forgot", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed email empty",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/forgot", bytes.NewBufferString("{}")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed forgot password",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/forgot", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ForgotPassword", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ForgotPassword(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ResetPassword(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.ResetPassword{Token: "token", Password: "456"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ResetPassword", mock.Anything, "token", "456").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/reset", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed reset password",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ResetPassword", mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ResetPassword(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_LoginTwoFactor(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.TwoFactorLogin{Challenge: "challenge", Code: "123456"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/login/2fa", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("LoginTwoFactor", mock.Anything, "challenge", "123456", entity.Client{IP: "192.0.2.1", UserAgent: ""}).
+					Return(entity.LoginResult{Token: "token"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/login/2fa", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed invalid code",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/login/2fa", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("LoginTwoFactor", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.LoginResult{}, usecase.ErrInvalidTwoFactor).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.LoginTwoFactor(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_OIDCLogin(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/login/oidc", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("StartOIDCLogin", mock.Anything).
+					Return(entity.OIDCLogin{AuthURL: "https://sso.mail.com/authorize", Flow: "flow"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not configured",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/login/oidc", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("StartOIDCLogin", mock.Anything).
+					Return(entity.OIDCLogin{}, usecase.ErrOIDCNotConfigured).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.OIDCLogin(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_OIDCCallback(t *testing.T) {
+	prov := serverPorvider()
+
+	callbackRequest := func(query string) *http.Request {
+		r := httptest.NewRequest("GET", "/login/oidc/callback"+query, nil)
+		r.AddCookie(&http.Cookie{Name: oidcFlowCookie, Value: "flow"})
+		return r
+	}
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     callbackRequest("?state=state&code=code"),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("FinishOIDCLogin", mock.Anything, "flow", "state", "code", entity.Client{IP: "192.0.2.1", UserAgent: ""}).
+					Return(entity.LoginResult{Token: "token"}, nil).Times(1)
+			},
+		},
+		{
+			name: "success two factor required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     callbackRequest("?state=state&code=code-totp"),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("FinishOIDCLogin", mock.Anything, "flow", "state", "code-totp", mock.Anything).
+					Return(entity.LoginResult{Challenge: "challenge"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed provider error",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     callbackRequest("?error=access_denied"),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed login not started",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/login/oidc/callback?state=state&code=code", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed invalid login",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     callbackRequest("?state=other&code=code"),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("FinishOIDCLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.LoginResult{}, usecase.ErrInvalidOIDCLogin).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.OIDCCallback(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_EnrollTOTP(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("EnrollTOTP", mock.Anything, int64(1)).
+					Return(entity.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/Pokedex:user?secret=SECRET"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/user/me/2fa", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed enroll totp",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("EnrollTOTP", mock.Anything, mock.Anything).
+					Return(entity.TOTPEnrollment{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.EnrollTOTP(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ConfirmTOTP(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.TOTPConfirmation{Code: "123456"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/confirm", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ConfirmTOTP", mock.Anything, int64(1), "123456").
+					Return([]string{"abcde-fghjk"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/user/me/2fa/confirm", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/confirm", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed confirm totp",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/confirm", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("ConfirmTOTP", mock.Anything, mock.Anything, mock.Anything).
+					Return([]string(nil), errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ConfirmTOTP(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_DisableTOTP(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.User{Password: "123"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/me/2fa", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DisableTOTP", mock.Anything, int64(1), "123").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/user/me/2fa", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/me/2fa", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed disable totp",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/me/2fa", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("DisableTOTP", mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.DisableTOTP(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RegenerateRecoveryCodes(t *testing.T) {
+	prov := serverPorvider()
+
+	body, _ := json.Marshal(entity.TOTPConfirmation{Code: "123456"})
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/recovery-codes", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RegenerateRecoveryCodes", mock.Anything, int64(1), "123456").
+					Return([]string{"abcde-fghjk"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/user/me/2fa/recovery-codes", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/recovery-codes", bytes.NewBufferString("{")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed regenerate recovery codes",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("POST", "/user/me/2fa/recovery-codes", bytes.NewBuffer(body)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RegenerateRecoveryCodes", mock.Anything, mock.Anything, mock.Anything).
+					Return([]string(nil), errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.RegenerateRecoveryCodes(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetSessions(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("GET", "/user/sessions", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetSessions", mock.Anything, int64(1)).
+					Return([]entity.Session{{ID: 1, Current: true}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get sessions",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("GET", "/user/sessions", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.UserUsecase.On("GetSessions", mock.Anything, int64(1)).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "not logged in",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/user/sessions", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.GetSessions(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RevokeSession(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/sessions/2", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RevokeSession", mock.Anything, int64(1), int64(2)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "session not found",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/sessions/3", nil),
+				param: httprouter.Params{{Key: "id", Value: "3"}},
+			},
+			mock: func() {
+				prov.UserUsecase.On("RevokeSession", mock.Anything, int64(1), int64(3)).
+					Return(errors.New("session not found")).Times(1)
+			},
+		},
+		{
+			name: "invalid id",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     loggedInRequest("DELETE", "/user/sessions/abc", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.RevokeSession(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_PatchPokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/pokemons/1", `{"name":"Ivysaur"}`, "application/merge-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("PatchPokemon", mock.Anything, int64(1), int64(1), []byte(`{"name":"Ivysaur"}`)).
+					Return(&entity.PokemonDetail{ID: 1, Name: "Ivysaur"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parsing param",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     patchRequest("/internal/pokedex/pokemons/asdf", `{"name":"Ivysaur"}`, "application/merge-patch+json"),
+				param: httprouter.Params{{Key: "id", Value: "asdf"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed json patch is not supported",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/pokemons/1", `[{"op":"remove","path":"/name"}]`, "application/json-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed patch pokemon",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/pokemons/1", `{"name":""}`, "application/merge-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("PatchPokemon", mock.Anything, int64(1), int64(1), []byte(`{"name":""}`)).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed weak etag never matches",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/pokemons/1", `{"name":"Ivysaur"}`, "application/merge-patch+json"), "If-Match", `W/"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.PatchPokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_UpdateType(t *testing.T) {
+	prov := serverPorvider()
+
+	putRequest := func(body string, ifMatch string) *http.Request {
+		return withHeader(httptest.NewRequest("PUT", "/internal/pokedex/types/1", bytes.NewBufferString(body)), "If-Match", ifMatch)
+	}
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     *httptest.ResponseRecorder
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantCode int
+		wantETag string
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     putRequest(`{"name":"ICE"}`, `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			wantCode: http.StatusOK,
+			wantETag: `"2"`,
+			mock: func() {
+				prov.TypeUsecase.On("UpdateType", mock.Anything, int64(1), int64(1), entity.Type{Name: "ICE"}).
+					Return(entity.Type{ID: 1, Name: "ICE", Version: 2}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed update type",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     putRequest(`{"name":"ICE"}`, `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mock: func() {
+				prov.TypeUsecase.On("UpdateType", mock.Anything, int64(1), int64(1), entity.Type{Name: "ICE"}).
+					Return(entity.Type{}, usecase.ErrVersionMismatch).Times(1)
+			},
+		},
+		{
+			name: "failed if match missing",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/types/1", bytes.NewBufferString(`{"name":"ICE"}`)),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			wantCode: http.StatusPreconditionRequired,
+			mock:     func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.UpdateType(tt.args.w, tt.args.r, tt.args.param)
+			if tt.args.w.Code != tt.wantCode {
+				t.Errorf("Server.UpdateType() code = %v, want %v", tt.args.w.Code, tt.wantCode)
+			}
+			if got := tt.args.w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("Server.UpdateType() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestServer_PatchType(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/types/1", `{"name":"ICE"}`, "application/merge-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("PatchType", mock.Anything, int64(1), int64(1), []byte(`{"name":"ICE"}`)).
+					Return(entity.Type{ID: 1, Name: "ICE"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed json patch is not supported",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/types/1", `[{"op":"remove","path":"/name"}]`, "application/json-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed patch type",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/types/1", `{"name":""}`, "application/merge-patch+json"), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("PatchType", mock.Anything, int64(1), int64(1), []byte(`{"name":""}`)).
+					Return(entity.Type{}, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed weak etag never matches",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(patchRequest("/internal/pokedex/types/1", `{"name":"ICE"}`, "application/merge-patch+json"), "If-Match", `W/"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.PatchType(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RestorePokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/pokemons/1/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("RestorePokemon", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/pokemons/abc/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed restore pokemon",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/pokemons/2/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("RestorePokemon", mock.Anything, int64(2)).
+					Return(usecase.ErrPokemonNotInTrash).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.RestorePokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetPokemonRevisions(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/pokemons/1/revisions", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("GetPokemonRevisions", mock.Anything, int64(1)).
+					Return([]entity.PokemonRevision{{PokemonID: 1, Revision: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse id",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/pokemons/abc/revisions", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/pokemons/2/revisions", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("GetPokemonRevisions", mock.Anything, int64(2)).
+					Return(nil, usecase.ErrPokemonNotFound).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.GetPokemonRevisions(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RestorePokemonRevision(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("POST", "/internal/pokedex/pokemons/1/revisions/2/restore", nil), "If-Match", `"3"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "2"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("RestorePokemonRevision", mock.Anything, int64(1), int64(2), int64(3)).
+					Return(&entity.PokemonDetail{ID: 1, Version: 4}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse revision",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("POST", "/internal/pokedex/pokemons/1/revisions/abc/restore", nil), "If-Match", `"3"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed if match required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/pokemons/1/revisions/2/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "2"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed revision not found",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("POST", "/internal/pokedex/pokemons/1/revisions/2/restore", nil), "If-Match", "*"),
+				param: httprouter.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "2"}},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("RestorePokemonRevision", mock.Anything, int64(1), int64(2), int64(0)).
+					Return(nil, usecase.ErrRevisionNotFound).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.RestorePokemonRevision(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_DeleteType(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/types/1", nil), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("DeleteType", mock.Anything, int64(1), int64(1), false).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success cascade",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/types/1?cascade=true", nil), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("DeleteType", mock.Anything, int64(1), int64(1), true).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/types/abc", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed if-match required",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/types/1", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed parse cascade",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/types/1?cascade=maybe", nil), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed type in use",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     withHeader(httptest.NewRequest("DELETE", "/internal/pokedex/types/2", nil), "If-Match", `"1"`),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("DeleteType", mock.Anything, int64(2), int64(1), false).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.DeleteType(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_RestoreType(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/types/1/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "1"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("RestoreType", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse int",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/types/abc/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "abc"}},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed restore type",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/types/2/restore", nil),
+				param: httprouter.Params{{Key: "id", Value: "2"}},
+			},
+			mock: func() {
+				prov.TypeUsecase.On("RestoreType", mock.Anything, int64(2)).
+					Return(usecase.ErrTypeNotInTrash).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.RestoreType(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetTrash(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+		TrashUsecase   usecase.TrashUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				TrashUsecase:   prov.TrashUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/trash", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.TrashUsecase.On("GetTrash", mock.Anything).
+					Return([]entity.TrashItem{{Kind: usecase.TrashPokemon, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				TrashUsecase:   prov.TrashUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/trash", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.TrashUsecase.On("GetTrash", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+				TrashUsecase:   tt.fields.TrashUsecase,
+			}
+			s.GetTrash(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_GetAuditLogs(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+		AuditUsecase   usecase.AuditUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				AuditUsecase:   prov.AuditUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/audit?resource=pokemon&actor=admin", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.AuditUsecase.On("GetAuditLogs", mock.Anything, entity.AuditFilter{Resource: usecase.AuditPokemon, Actor: "admin"}).
+					Return([]entity.AuditLog{{ID: 1, Actor: "admin", Action: usecase.AuditCreate, Resource: usecase.AuditPokemon, ResourceID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse since",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				AuditUsecase:   prov.AuditUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/audit?since=yesterday", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+				AuditUsecase:   prov.AuditUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/audit", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.AuditUsecase.On("GetAuditLogs", mock.Anything, entity.AuditFilter{}).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+				AuditUsecase:   tt.fields.AuditUsecase,
+			}
+			s.GetAuditLogs(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_BulkCreatePokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[{"name":"Bulbasour","species":"pokemon"}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkCreatePokemon", mock.Anything, mock.Anything, true).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success best effort",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons?mode=best_effort", bytes.NewBufferString(`[{"name":"Bulbasour","species":"pokemon"}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkCreatePokemon", mock.Anything, mock.Anything, false).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid mode",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons?mode=lazy", bytes.NewBufferString(`[{"name":"Bulbasour","species":"pokemon"}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`{`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkCreatePokemon", mock.Anything, []entity.BulkPokemon{}, true).
+					Return(nil, usecase.ErrBulkEmpty).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.BulkCreatePokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_BulkUpdatePokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[{"id":1,"name":"Bulbasour","species":"pokemon","version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkUpdatePokemon", mock.Anything, mock.Anything, true).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success best effort",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/bulk/pokemons?mode=best_effort", bytes.NewBufferString(`[{"id":1,"name":"Bulbasour","species":"pokemon","version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkUpdatePokemon", mock.Anything, mock.Anything, false).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid mode",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/bulk/pokemons?mode=lazy", bytes.NewBufferString(`[{"id":1,"name":"Bulbasour","species":"pokemon","version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`{`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("PUT", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkUpdatePokemon", mock.Anything, []entity.BulkPokemon{}, true).
+					Return(nil, usecase.ErrBulkEmpty).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.BulkUpdatePokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_BulkDeletePokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[{"id":1,"version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkDeletePokemon", mock.Anything, mock.Anything, true).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success best effort",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/bulk/pokemons?mode=best_effort", bytes.NewBufferString(`[{"id":1,"version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkDeletePokemon", mock.Anything, mock.Anything, false).
+					Return([]entity.BulkResult{{Index: 0, ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid mode",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/bulk/pokemons?mode=lazy", bytes.NewBufferString(`[{"id":1,"version":1}]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`{`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("DELETE", "/internal/pokedex/bulk/pokemons", bytes.NewBufferString(`[]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("BulkDeletePokemon", mock.Anything, []entity.BulkPokemon{}, true).
+					Return(nil, usecase.ErrBulkEmpty).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.BulkDeletePokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ExportPokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/export?format=csv", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("ExportPokemon", mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, fn func(data entity.PokemonDetail) error) error {
+						return fn(entity.PokemonDetail{ID: 1, Name: "Bulbasour"})
+					}).Times(1)
+			},
+		},
+		{
+			name: "failed invalid format",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/export?format=xml", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/internal/pokedex/export", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("ExportPokemon", mock.Anything, mock.Anything).
+					Return(errors.New("errors")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ExportPokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_ImportPokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/import?format=csv&dry_run=true", bytes.NewBufferString("name,types\nBulbasour,GRASS\n")),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("ImportPokemon", mock.Anything, []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Bulbasour", Types: []string{"GRASS"}}}}, true).
+					Return(entity.ImportReport{DryRun: true, Created: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid format",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/import?format=xml", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed invalid dry run",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/import?dry_run=maybe", bytes.NewBufferString("name,types\nBulbasour,GRASS\n")),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed decode body",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/import", bytes.NewBufferString(`{`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("POST", "/internal/pokedex/import", bytes.NewBufferString(`[]`)),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("ImportPokemon", mock.Anything, []entity.ImportRow{}, false).
+					Return(entity.ImportReport{}, usecase.ErrImportEmpty).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.ImportPokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_SearchPokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/search?q=bulbsaur&limit=5", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("SearchPokemon", mock.Anything, "bulbsaur", 5).
+					Return([]entity.PokemonSearchResult{{PokemonList: entity.PokemonList{ID: 1, Name: "Bulbasaur"}, Score: 1.5, Highlights: map[string]string{"name": "<em>Bulbasaur</em>"}}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse limit",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/search?q=bulbsaur&limit=x", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed SearchPokemon",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/search", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("SearchPokemon", mock.Anything, "", 0).
+					Return(nil, usecase.ErrSearchQueryRequired).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.SearchPokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
+
+func TestServer_SuggestPokemon(t *testing.T) {
+	prov := serverPorvider()
+
+	type fields struct {
+		Router         *httprouter.Router
+		PokemonUsecase usecase.PokemonUsecaseItf
+		TypeUsecase    usecase.TypeUsecaseItf
+		UserUsecase    usecase.UserUsecaseItf
+	}
+	type args struct {
+		w     http.ResponseWriter
+		r     *http.Request
+		param httprouter.Params
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/suggest?prefix=char&limit=5", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("SuggestPokemon", mock.Anything, "char", 5).
+					Return([]entity.PokemonSuggestion{{Text: "Charmander", Field: "name", IDs: []int64{1}}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed parse limit",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/suggest?prefix=char&limit=x", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {},
+		},
+		{
+			name: "failed SuggestPokemon",
+			fields: fields{
+				Router:         httprouter.New(),
+				PokemonUsecase: prov.PokemonUsecase,
+				TypeUsecase:    prov.TypeUsecase,
+				UserUsecase:    prov.UserUsecase,
+			},
+			args: args{
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest(http.MethodGet, "/pokedex/suggest", nil),
+				param: httprouter.Params{},
+			},
+			mock: func() {
+				prov.PokemonUsecase.On("SuggestPokemon", mock.Anything, "", 0).
+					Return(nil, usecase.ErrSuggestPrefixRequired).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				Router:         tt.fields.Router,
+				PokemonUsecase: tt.fields.PokemonUsecase,
+				TypeUsecase:    tt.fields.TypeUsecase,
+				UserUsecase:    tt.fields.UserUsecase,
+			}
+			s.SuggestPokemon(tt.args.w, tt.args.r, tt.args.param)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package throttle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Throttle counts failed attempts per key in memory, after FreeAttempts failures every further attempt
+// has to wait a delay that doubles on each failure, after LockoutAttempts failures the key is locked
+type Throttle struct {
+	FreeAttempts    int64
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAttempts int64
+	LockoutDuration time.Duration
+
+	mu      *sync.Mutex
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+// maxEntries is the number of tracked keys after which forgotten entries are removed
+const maxEntries = 10000
+
+type entry struct {
+	failures     int64
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// ErrTooManyAttempts is returned while a key has to wait before the next attempt
+type ErrTooManyAttempts struct {
+	RetryAfter time.Duration
+}
+
+func (e *ErrTooManyAttempts) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func NewThrottle(throttle Throttle) *Throttle {
+	return &Throttle{
+		FreeAttempts:    throttle.FreeAttempts,
+		BaseDelay:       throttle.BaseDelay,
+		MaxDelay:        throttle.MaxDelay,
+		LockoutAttempts: throttle.LockoutAttempts,
+		LockoutDuration: throttle.LockoutDuration,
+		mu:              &sync.Mutex{},
+		entries:         make(map[string]*entry),
+		now:             time.Now,
+	}
+}
+
+// Check returns ErrTooManyAttempts when the key is still waiting for its backoff or lockout to end, nil throttle allows everything
+func (t *Throttle) Check(key string) (err error) {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e := t.entry(key)
+	if e == nil {
+		return nil
+	}
+
+	if now := t.now(); now.Before(e.blockedUntil) {
+		return &ErrTooManyAttempts{RetryAfter: e.blockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+// Fail records failed attempt of the key
+func (t *Throttle) Fail(key string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.entries) >= maxEntries {
+		t.prune()
+	}
+
+	now := t.now()
+	e := t.entry(key)
+	if e == nil {
+		e = &entry{}
+		t.entries[key] = e
+	}
+
+	e.failures++
+	e.lastFailure = now
+
+	switch {
+	case t.LockoutAttempts > 0 && e.failures >= t.LockoutAttempts:
+		e.blockedUntil = now.Add(t.LockoutDuration)
+	case e.failures > t.FreeAttempts:
+		e.blockedUntil = now.Add(t.delay(e.failures - t.FreeAttempts))
+	}
+}
+
+// Reset forgets failed attempts of the key, it is called after successful attempt
+func (t *Throttle) Reset(key string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+}
+
+// entry returns the entry of the key, entries without failure for longer than the lockout duration are forgotten
+func (t *Throttle) entry(key string) *entry {
+	e, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if t.now().Sub(e.lastFailure) >= t.LockoutDuration && !t.now().Before(e.blockedUntil) {
+		delete(t.entries, key)
+		return nil
+	}
+
+	return e
+}
+
+// prune removes every forgotten entry
+func (t *Throttle) prune() {
+	for key := range t.entries {
+		t.entry(key)
+	}
+}
+
+// delay returns BaseDelay doubled for every failure after the first one, capped by MaxDelay
+func (t *Throttle) delay(failures int64) time.Duration {
+	delay := t.BaseDelay
+	for i := int64(1); i < failures; i++ {
+		delay *= 2
+		if t.MaxDelay > 0 && delay >= t.MaxDelay {
+			return t.MaxDelay
+		}
+	}
+
+	if t.MaxDelay > 0 && delay > t.MaxDelay {
+		return t.MaxDelay
+	}
+
+	return delay
+}
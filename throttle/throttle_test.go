@@ -0,0 +1,136 @@
+package throttle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestThrottle(now *time.Time) *Throttle {
+	t := NewThrottle(Throttle{
+		FreeAttempts:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		LockoutAttempts: 6,
+		LockoutDuration: time.Minute,
+	})
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestThrottle_Check(t *testing.T) {
+	type args struct {
+		failures int
+		wait     time.Duration
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantRetryAfter time.Duration
+		wantErr        bool
+	}{
+		{
+			name: "success free attempts",
+			args: args{
+				failures: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed first backoff",
+			args: args{
+				failures: 3,
+			},
+			wantRetryAfter: time.Second,
+			wantErr:        true,
+		},
+		{
+			name: "failed backoff doubles",
+			args: args{
+				failures: 4,
+			},
+			wantRetryAfter: 2 * time.Second,
+			wantErr:        true,
+		},
+		{
+			name: "failed backoff capped",
+			args: args{
+				failures: 5,
+			},
+			wantRetryAfter: 4 * time.Second,
+			wantErr:        true,
+		},
+		{
+			name: "success backoff ended",
+			args: args{
+				failures: 4,
+				wait:     2 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed locked",
+			args: args{
+				failures: 6,
+				wait:     30 * time.Second,
+			},
+			wantRetryAfter: 30 * time.Second,
+			wantErr:        true,
+		},
+		{
+			name: "success lockout ended",
+			args: args{
+				failures: 6,
+				wait:     time.Minute,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			th := newTestThrottle(&now)
+			for i := 0; i < tt.args.failures; i++ {
+				th.Fail("key")
+			}
+			now = now.Add(tt.args.wait)
+
+			err := th.Check("key")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Throttle.Check() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var tooMany *ErrTooManyAttempts
+			if err != nil && (!errors.As(err, &tooMany) || tooMany.RetryAfter != tt.wantRetryAfter) {
+				t.Errorf("Throttle.Check() error = %v, want retry after %v", err, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestThrottle_Reset(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	th := newTestThrottle(&now)
+	for i := 0; i < 6; i++ {
+		th.Fail("key")
+	}
+
+	if err := th.Check("other"); err != nil {
+		t.Errorf("Throttle.Check() error = %v, want other keys not throttled", err)
+	}
+
+	th.Reset("key")
+	if err := th.Check("key"); err != nil {
+		t.Errorf("Throttle.Check() error = %v, want nil after reset", err)
+	}
+}
+
+func TestThrottle_Nil(t *testing.T) {
+	var th *Throttle
+	th.Fail("key")
+	th.Reset("key")
+	if err := th.Check("key"); err != nil {
+		t.Errorf("Throttle.Check() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,43 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package transactionmock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TransactorItf is an autogenerated mock type for the TransactorItf type
+type TransactorItf struct {
+	mock.Mock
+}
+
+// WithinTransaction provides a mock function with given fields: ctx, fn
+func (_m *TransactorItf) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTransactorItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTransactorItf creates a new instance of TransactorItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTransactorItf(t mockConstructorTestingTNewTransactorItf) *TransactorItf {
+	mock := &TransactorItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,104 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor runs queries either directly on the database or in a transaction
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type contextKey struct{}
+
+// state is the transaction ctx runs in and what has to run once it is committed
+type state struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// NewContext returns a copy of ctx that runs in tx
+func NewContext(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, contextKey{}, &state{tx: tx})
+}
+
+// FromContext returns the transaction stored in ctx by NewContext, if any
+func FromContext(ctx context.Context) (tx *sql.Tx, ok bool) {
+	s, ok := ctx.Value(contextKey{}).(*state)
+	if !ok {
+		return nil, false
+	}
+
+	return s.tx, true
+}
+
+// AfterCommit runs fn once the transaction of ctx is committed and never when it is rolled back,
+// fn runs right away when ctx doesn't run in a transaction
+func AfterCommit(ctx context.Context, fn func()) {
+	s, ok := ctx.Value(contextKey{}).(*state)
+	if !ok {
+		fn()
+		return
+	}
+
+	s.afterCommit = append(s.afterCommit, fn)
+}
+
+// Conn returns the transaction ctx runs in or db when it doesn't run in one,
+// repositories run their queries on it so they take part in the transaction of the usecase
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := FromContext(ctx); ok {
+		return tx
+	}
+
+	return db
+}
+
+type Transactor struct {
+	DB *sql.DB
+}
+
+type TransactorItf interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error)
+}
+
+func NewTransactor(db *sql.DB) TransactorItf {
+	return &Transactor{
+		DB: db,
+	}
+}
+
+// WithinTransaction runs fn in a transaction that is committed when fn returns nil and rolled back otherwise,
+// fn that is called with ctx already running in a transaction joins that transaction and its AfterCommit
+// functions wait for the outer transaction
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := FromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	txCtx := NewContext(ctx, tx)
+	err = fn(txCtx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, after := range txCtx.Value(contextKey{}).(*state).afterCommit {
+		after()
+	}
+
+	return nil
+}
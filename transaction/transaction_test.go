@@ -0,0 +1,284 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	return db, mock
+}
+
+func TestNewTransactor(t *testing.T) {
+	db, _ := NewMock()
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want TransactorItf
+	}{
+		{
+			name: "success",
+			args: args{
+				db: db,
+			},
+			want: &Transactor{
+				DB: db,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTransactor(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTransactor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConn(t *testing.T) {
+	db, dbmock := NewMock()
+	dbmock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	type args struct {
+		ctx context.Context
+		db  *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want Executor
+	}{
+		{
+			name: "success without transaction",
+			args: args{
+				ctx: context.Background(),
+				db:  db,
+			},
+			want: db,
+		},
+		{
+			name: "success with transaction",
+			args: args{
+				ctx: NewContext(context.Background(), tx),
+				db:  db,
+			},
+			want: tx,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Conn(tt.args.ctx, tt.args.db); got != tt.want {
+				t.Errorf("Conn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactor_WithinTransaction(t *testing.T) {
+	db, dbmock := NewMock()
+	ctx := context.Background()
+
+	dbmock.ExpectBegin()
+	outer, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx context.Context
+		fn  func(ctx context.Context) error
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success commit",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				fn: func(ctx context.Context) error {
+					if _, ok := FromContext(ctx); !ok {
+						return errors.New("not in transaction")
+					}
+					return nil
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name: "success join transaction of ctx",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: NewContext(ctx, outer),
+				fn: func(ctx context.Context) error {
+					if tx, _ := FromContext(ctx); tx != outer {
+						return errors.New("not in outer transaction")
+					}
+					return nil
+				},
+			},
+			wantErr: false,
+			mock:    func() {},
+		},
+		{
+			name: "failed rollback",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				fn: func(ctx context.Context) error {
+					return errors.New("error")
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectRollback()
+			},
+		},
+		{
+			name: "failed begin",
+			fields: fields{
+				DB: db,
+			},
+			args: args{
+				ctx: ctx,
+				fn: func(ctx context.Context) error {
+					return nil
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				dbmock.ExpectBegin().WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			tr := &Transactor{
+				DB: tt.fields.DB,
+			}
+			if err := tr.WithinTransaction(tt.args.ctx, tt.args.fn); (err != nil) != tt.wantErr {
+				t.Errorf("Transactor.WithinTransaction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := dbmock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	db, dbmock := NewMock()
+	tr := &Transactor{DB: db}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context, ran *bool) error
+		wantRan bool
+		mock    func()
+	}{
+		{
+			name: "success run after commit",
+			fn: func(ctx context.Context, ran *bool) error {
+				AfterCommit(ctx, func() { *ran = true })
+				if *ran {
+					return errors.New("ran before commit")
+				}
+				return nil
+			},
+			wantRan: true,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name: "success not run on rollback",
+			fn: func(ctx context.Context, ran *bool) error {
+				AfterCommit(ctx, func() { *ran = true })
+				return errors.New("error")
+			},
+			wantRan: false,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectRollback()
+			},
+		},
+		{
+			name: "success wait for outer transaction",
+			fn: func(ctx context.Context, ran *bool) error {
+				err := tr.WithinTransaction(ctx, func(ctx context.Context) error {
+					AfterCommit(ctx, func() { *ran = true })
+					return nil
+				})
+				if *ran {
+					return errors.New("ran before outer commit")
+				}
+				return err
+			},
+			wantRan: true,
+			mock: func() {
+				dbmock.ExpectBegin()
+				dbmock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			ran := false
+			tr.WithinTransaction(ctx, func(ctx context.Context) error {
+				return tt.fn(ctx, &ran)
+			})
+			if ran != tt.wantRan {
+				t.Errorf("AfterCommit() ran = %v, want %v", ran, tt.wantRan)
+			}
+			if err := dbmock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+
+	ran := false
+	AfterCommit(ctx, func() { ran = true })
+	if !ran {
+		t.Errorf("AfterCommit() without transaction did not run")
+	}
+}
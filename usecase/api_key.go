@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	apikeyrepository "github.com/winartodev/go-pokedex/repository/apikey"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	"github.com/winartodev/go-pokedex/transaction"
+	"github.com/winartodev/go-pokedex/util"
+)
+
+const (
+	// APIKeyPrefix starts every api key so leaked keys are easy to find by secret scanners
+	APIKeyPrefix = "pk_"
+	// APIKeyLastUsedInterval limits how often the last used timestamp of a key is written
+	APIKeyLastUsedInterval = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey  = apperror.New(apperror.Unauthorized, "invalid_api_key", "api key not valid")
+	ErrAPIKeyNotFound = apperror.New(apperror.NotFound, "api_key_not_found", "api key not found or already revoked")
+)
+
+type APIKeyUsecase struct {
+	APIKeyRepository apikeyrepository.APIKeyRepositoryItf
+	AuditRepository  auditrepository.AuditRepositoryItf
+	Transactor       transaction.TransactorItf
+	TokenSecret      string
+	Policy           *auth.Policy
+}
+
+type APIKeyUsecaseItf interface {
+	CreateAPIKey(ctx context.Context, data entity.CreateAPIKey) (result entity.CreatedAPIKey, err error)
+	GetAllAPIKeys(ctx context.Context) (results []entity.APIKey, err error)
+	RevokeAPIKey(ctx context.Context, id int64) (err error)
+	Authenticate(ctx context.Context, key string) (claims *auth.JWTClaim, err error)
+}
+
+func NewAPIKeyUsecase(apiKeyUsecase APIKeyUsecase) APIKeyUsecaseItf {
+	return &APIKeyUsecase{
+		APIKeyRepository: apiKeyUsecase.APIKeyRepository,
+		AuditRepository:  apiKeyUsecase.AuditRepository,
+		Transactor:       apiKeyUsecase.Transactor,
+		TokenSecret:      apiKeyUsecase.TokenSecret,
+		Policy:           apiKeyUsecase.Policy,
+	}
+}
+
+// CreateAPIKey creates key for a service account, the logged in user can only grant scopes of its own role
+func (ak *APIKeyUsecase) CreateAPIKey(ctx context.Context, data entity.CreateAPIKey) (result entity.CreatedAPIKey, err error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok || claims.APIKeyID != 0 {
+		return result, apperror.New(apperror.Forbidden, "api_key_not_allowed", "api keys can only be created by users")
+	}
+
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		return result, apperror.New(apperror.Validation, "name_required", "name can't be empty")
+	}
+
+	if len(data.Scopes) == 0 {
+		return result, apperror.New(apperror.Validation, "scopes_required", "scopes can't be empty")
+	}
+
+	for _, scope := range data.Scopes {
+		if !scope.IsValid() {
+			return result, apperror.Newf(apperror.Validation, "invalid_scope", "scope %s is not valid", scope)
+		}
+
+		if !ak.Policy.Can(claims.Role, scope) {
+			return result, apperror.Newf(apperror.Forbidden, "scope_not_granted", "scope %s is not granted to your role", scope)
+		}
+	}
+
+	if data.ExpiresAt != nil && !data.ExpiresAt.After(time.Now()) {
+		return result, apperror.New(apperror.Validation, "invalid_expires_at", "expires_at must be in the future")
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return result, err
+	}
+
+	secret, err := util.GenerateToken()
+	if err != nil {
+		return result, err
+	}
+
+	result.Prefix = APIKeyPrefix + prefix
+	result.Key = result.Prefix + "_" + secret
+	result.Name = data.Name
+	result.KeyHash = util.SignToken(ak.TokenSecret, result.Key)
+	result.Scopes = data.Scopes
+	result.CreatedBy = claims.ID
+	result.ExpiresAt = data.ExpiresAt
+	result.CreatedAt = time.Now()
+
+	err = ak.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		result.ID, err = ak.APIKeyRepository.CreateAPIKeyDB(ctx, result.APIKey)
+		if err != nil {
+			return err
+		}
+
+		// the key itself is never logged, only the hash is kept and it is not serialized
+		return recordAudit(ctx, ak.AuditRepository, AuditCreate, AuditAPIKey, result.ID, nil, result.APIKey)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (ak *APIKeyUsecase) GetAllAPIKeys(ctx context.Context) (results []entity.APIKey, err error) {
+	results, err = ak.APIKeyRepository.GetAllAPIKeysDB(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	return results, err
+}
+
+func (ak *APIKeyUsecase) RevokeAPIKey(ctx context.Context, id int64) (err error) {
+	return ak.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		before, err := ak.APIKeyRepository.GetAPIKeyByIDDB(ctx, id)
+		if err == sql.ErrNoRows {
+			return ErrAPIKeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		err = ak.APIKeyRepository.RevokeAPIKeyDB(ctx, id)
+		if err == sql.ErrNoRows {
+			return ErrAPIKeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		after := before
+		revokedAt := time.Now()
+		after.RevokedAt = &revokedAt
+		return recordAudit(ctx, ak.AuditRepository, AuditRevoke, AuditAPIKey, id, before, after)
+	})
+}
+
+// Authenticate returns the claims of the service account of the key, revoked and expired keys are rejected
+func (ak *APIKeyUsecase) Authenticate(ctx context.Context, key string) (claims *auth.JWTClaim, err error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return claims, ErrInvalidAPIKey
+	}
+
+	apiKey, err := ak.APIKeyRepository.GetAPIKeyByHashDB(ctx, util.SignToken(ak.TokenSecret, key))
+	if err == sql.ErrNoRows {
+		return claims, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return claims, err
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return claims, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > APIKeyLastUsedInterval {
+		err = ak.APIKeyRepository.UpdateAPIKeyLastUsedDB(ctx, apiKey.ID)
+		if err != nil {
+			return claims, err
+		}
+	}
+
+	return &auth.JWTClaim{
+		Username: apiKey.Name,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
+
+func randomHex(size int) (string, error) {
+	bytes := make([]byte, size)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
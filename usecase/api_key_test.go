@@ -0,0 +1,618 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	apikeyrepository "github.com/winartodev/go-pokedex/repository/apikey"
+	apikeyrepositorymock "github.com/winartodev/go-pokedex/repository/apikey/mocks"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
+	"github.com/winartodev/go-pokedex/transaction"
+	transactionmock "github.com/winartodev/go-pokedex/transaction/mocks"
+	"github.com/winartodev/go-pokedex/util"
+)
+
+type mockAPIKeyProvider struct {
+	APIKeyRepository *apikeyrepositorymock.APIKeyRepositoryItf
+	AuditRepository  *auditrepositorymock.AuditRepositoryItf
+	Transactor       *transactionmock.TransactorItf
+}
+
+func apiKeyProvider() mockAPIKeyProvider {
+	return mockAPIKeyProvider{
+		APIKeyRepository: new(apikeyrepositorymock.APIKeyRepositoryItf),
+		AuditRepository:  new(auditrepositorymock.AuditRepositoryItf),
+		Transactor:       new(transactionmock.TransactorItf),
+	}
+}
+
+func TestNewAPIKeyUsecase(t *testing.T) {
+	apiKeyUsecase := APIKeyUsecase{
+		APIKeyRepository: new(apikeyrepositorymock.APIKeyRepositoryItf),
+		TokenSecret:      "secret",
+	}
+	type args struct {
+		apiKeyUsecase APIKeyUsecase
+	}
+	tests := []struct {
+		name string
+		args args
+		want APIKeyUsecaseItf
+	}{
+		{
+			name: "success",
+			args: args{
+				apiKeyUsecase: apiKeyUsecase,
+			},
+			want: &apiKeyUsecase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAPIKeyUsecase(tt.args.apiKeyUsecase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAPIKeyUsecase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+	prov := apiKeyProvider()
+	policy, _ := auth.ParsePolicy("", "")
+	adminCtx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 1, Role: enum.Admin})
+	userCtx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 2, Role: enum.User})
+	apiKeyCtx := auth.NewContext(context.Background(), &auth.JWTClaim{APIKeyID: 1, Scopes: []enum.Permission{enum.APIKeyManage}})
+	past := time.Now().Add(-time.Hour)
+
+	type fields struct {
+		APIKeyRepository apikeyrepository.APIKeyRepositoryItf
+		AuditRepository  auditrepository.AuditRepositoryItf
+		Transactor       transaction.TransactorItf
+	}
+	type args struct {
+		ctx  context.Context
+		data entity.CreateAPIKey
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: " pokemon sync ", Scopes: []enum.Permission{enum.PokemonRead, enum.PokemonWrite}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("CreateAPIKeyDB", mock.Anything, mock.MatchedBy(func(data entity.APIKey) bool {
+					return data.Name == "pokemon sync" && data.CreatedBy == 1 && strings.HasPrefix(data.Prefix, APIKeyPrefix) && data.KeyHash != ""
+				})).Return(int64(1), nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.MatchedBy(func(data entity.AuditLog) bool {
+					return data.Action == AuditCreate && data.Resource == AuditAPIKey && data.ResourceID == 1 && !strings.Contains(string(data.After), "key_hash")
+				})).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed repository",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonRead}},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("CreateAPIKeyDB", mock.Anything, mock.Anything).
+					Return(int64(0), errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed create audit",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonRead}},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("CreateAPIKeyDB", mock.Anything, mock.Anything).
+					Return(int64(2), nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed scope not granted to role",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  userCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonWrite}},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed unknown scope",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{"pokemon:delete"}},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed empty scopes",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync"},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed empty name",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Scopes: []enum.Permission{enum.PokemonRead}},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed expired",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  adminCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonRead}, ExpiresAt: &past},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed created by api key",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx:  apiKeyCtx,
+				data: entity.CreateAPIKey{Name: "pokemon sync", Scopes: []enum.Permission{enum.PokemonRead}},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyUsecase{
+				APIKeyRepository: tt.fields.APIKeyRepository,
+				TokenSecret:      "secret",
+				Policy:           policy,
+				AuditRepository:  tt.fields.AuditRepository,
+				Transactor:       tt.fields.Transactor,
+			}
+			gotResult, err := ak.CreateAPIKey(tt.args.ctx, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyUsecase.CreateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.HasPrefix(gotResult.Key, gotResult.Prefix+"_") || gotResult.KeyHash != util.SignToken("secret", gotResult.Key) {
+				t.Errorf("APIKeyUsecase.CreateAPIKey() = %v", gotResult)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUsecase_GetAllAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	prov := apiKeyProvider()
+	keys := []entity.APIKey{{ID: 1, Name: "pokemon sync", Prefix: "pk_abcd1234"}}
+
+	type fields struct {
+		APIKeyRepository apikeyrepository.APIKeyRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.APIKey
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: keys,
+			wantErr:     false,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAllAPIKeysDB", mock.Anything).
+					Return(keys, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAllAPIKeysDB", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyUsecase{
+				APIKeyRepository: tt.fields.APIKeyRepository,
+			}
+			gotResults, err := ak.GetAllAPIKeys(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyUsecase.GetAllAPIKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("APIKeyUsecase.GetAllAPIKeys() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUsecase_RevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+	prov := apiKeyProvider()
+
+	type fields struct {
+		APIKeyRepository apikeyrepository.APIKeyRepositoryItf
+		AuditRepository  auditrepository.AuditRepositoryItf
+		Transactor       transaction.TransactorItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("GetAPIKeyByIDDB", mock.Anything, int64(1)).
+					Return(entity.APIKey{ID: 1, Name: "pokemon sync"}, nil).Times(1)
+
+				prov.APIKeyRepository.On("RevokeAPIKeyDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.MatchedBy(func(data entity.AuditLog) bool {
+					return data.Action == AuditRevoke && data.Resource == AuditAPIKey && data.ResourceID == 1
+				})).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not found",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("GetAPIKeyByIDDB", mock.Anything, int64(2)).
+					Return(entity.APIKey{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed already revoked",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  3,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("GetAPIKeyByIDDB", mock.Anything, int64(3)).
+					Return(entity.APIKey{ID: 3}, nil).Times(1)
+
+				prov.APIKeyRepository.On("RevokeAPIKeyDB", mock.Anything, int64(3)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed create audit",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+				AuditRepository:  prov.AuditRepository,
+				Transactor:       prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  4,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.APIKeyRepository.On("GetAPIKeyByIDDB", mock.Anything, int64(4)).
+					Return(entity.APIKey{ID: 4}, nil).Times(1)
+
+				prov.APIKeyRepository.On("RevokeAPIKeyDB", mock.Anything, int64(4)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyUsecase{
+				APIKeyRepository: tt.fields.APIKeyRepository,
+				AuditRepository:  tt.fields.AuditRepository,
+				Transactor:       tt.fields.Transactor,
+			}
+			if err := ak.RevokeAPIKey(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyUsecase.RevokeAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	prov := apiKeyProvider()
+	secret := "secret"
+	key := "pk_abcd1234_secret"
+	keyHash := util.SignToken(secret, key)
+	scopes := []enum.Permission{enum.PokemonRead}
+	recently := time.Now().Add(-time.Second)
+	past := time.Now().Add(-time.Hour)
+
+	type fields struct {
+		APIKeyRepository apikeyrepository.APIKeyRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		key string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantClaims *auth.JWTClaim
+		wantErr    bool
+		wantErrIs  error
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: key,
+			},
+			wantClaims: &auth.JWTClaim{Username: "pokemon sync", APIKeyID: 1, Scopes: scopes},
+			wantErr:    false,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAPIKeyByHashDB", mock.Anything, keyHash).
+					Return(entity.APIKey{ID: 1, Name: "pokemon sync", Scopes: scopes}, nil).Times(1)
+
+				prov.APIKeyRepository.On("UpdateAPIKeyLastUsedDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success recently used",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: key,
+			},
+			wantClaims: &auth.JWTClaim{Username: "pokemon sync", APIKeyID: 1, Scopes: scopes},
+			wantErr:    false,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAPIKeyByHashDB", mock.Anything, keyHash).
+					Return(entity.APIKey{ID: 1, Name: "pokemon sync", Scopes: scopes, LastUsedAt: &recently}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed unknown key",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: key,
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidAPIKey,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAPIKeyByHashDB", mock.Anything, keyHash).
+					Return(entity.APIKey{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed revoked key",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: key,
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidAPIKey,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAPIKeyByHashDB", mock.Anything, keyHash).
+					Return(entity.APIKey{ID: 1, RevokedAt: &past}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed expired key",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: key,
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidAPIKey,
+			mock: func() {
+				prov.APIKeyRepository.On("GetAPIKeyByHashDB", mock.Anything, keyHash).
+					Return(entity.APIKey{ID: 1, ExpiresAt: &past}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed malformed key",
+			fields: fields{
+				APIKeyRepository: prov.APIKeyRepository,
+			},
+			args: args{
+				ctx: ctx,
+				key: "secret",
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidAPIKey,
+			mock:      func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			ak := &APIKeyUsecase{
+				APIKeyRepository: tt.fields.APIKeyRepository,
+				TokenSecret:      secret,
+			}
+			gotClaims, err := ak.Authenticate(tt.args.ctx, tt.args.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("APIKeyUsecase.Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("APIKeyUsecase.Authenticate() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if !reflect.DeepEqual(gotClaims, tt.wantClaims) {
+				t.Errorf("APIKeyUsecase.Authenticate() = %v, want %v", gotClaims, tt.wantClaims)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+	AuditRevoke  = "revoke"
+
+	AuditPokemon = "pokemon"
+	AuditType    = "type"
+	AuditUser    = "user"
+	AuditAPIKey  = "api_key"
+)
+
+type AuditUsecase struct {
+	AuditRepository auditrepository.AuditRepositoryItf
+}
+
+type AuditUsecaseItf interface {
+	GetAuditLogs(ctx context.Context, filter entity.AuditFilter) (results []entity.AuditLog, err error)
+}
+
+func NewAuditUsecase(auditUsecase AuditUsecase) AuditUsecaseItf {
+	return &AuditUsecase{
+		AuditRepository: auditUsecase.AuditRepository,
+	}
+}
+
+func (au *AuditUsecase) GetAuditLogs(ctx context.Context, filter entity.AuditFilter) (results []entity.AuditLog, err error) {
+	results, err = au.AuditRepository.GetAuditLogsDB(ctx, filter)
+	if err != nil {
+		return results, err
+	}
+
+	return results, err
+}
+
+// recordAudit appends the mutation of the resource to the audit log, the actor is taken from the claims in ctx.
+// before is nil for created resources and after is nil for deleted ones
+func recordAudit(ctx context.Context, repository auditrepository.AuditRepositoryItf, action string, resource string, resourceID int64, before interface{}, after interface{}) (err error) {
+	data := entity.AuditLog{
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+	}
+
+	data.ActorID, data.APIKeyID, data.Actor = actorFromContext(ctx)
+
+	data.Before, err = json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	data.After, err = json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	return repository.CreateAuditLogDB(ctx, data)
+}
+
+// actorFromContext returns who makes the request, apiKeyID is only set for api keys and name is the username or the name of the api key
+func actorFromContext(ctx context.Context) (id int64, apiKeyID int64, name string) {
+	if claims, ok := auth.FromContext(ctx); ok {
+		return claims.ID, claims.APIKeyID, claims.Username
+	}
+
+	return id, apiKeyID, name
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
+)
+
+type mockAuditProvider struct {
+	AuditRepository *auditrepositorymock.AuditRepositoryItf
+}
+
+func auditProvider() mockAuditProvider {
+	return mockAuditProvider{
+		AuditRepository: new(auditrepositorymock.AuditRepositoryItf),
+	}
+}
+
+func TestNewAuditUsecase(t *testing.T) {
+	auditUsecase := AuditUsecase{
+		AuditRepository: new(auditrepositorymock.AuditRepositoryItf),
+	}
+
+	type args struct {
+		auditUsecase AuditUsecase
+	}
+	tests := []struct {
+		name string
+		args args
+		want AuditUsecaseItf
+	}{
+		{
+			name: "success",
+			args: args{
+				auditUsecase: auditUsecase,
+			},
+			want: &auditUsecase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAuditUsecase(tt.args.auditUsecase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAuditUsecase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditUsecase_GetAuditLogs(t *testing.T) {
+	ctx := context.Background()
+	prov := auditProvider()
+	logs := []entity.AuditLog{{ID: 1, Actor: "admin", Action: AuditCreate, Resource: AuditType, ResourceID: 1}}
+
+	type fields struct {
+		AuditRepository auditrepository.AuditRepositoryItf
+	}
+	type args struct {
+		ctx    context.Context
+		filter entity.AuditFilter
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.AuditLog
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.AuditFilter{Resource: AuditType},
+			},
+			wantResults: logs,
+			wantErr:     false,
+			mock: func() {
+				prov.AuditRepository.On("GetAuditLogsDB", mock.Anything, entity.AuditFilter{Resource: AuditType}).
+					Return(logs, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.AuditFilter{Resource: AuditPokemon},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.AuditRepository.On("GetAuditLogsDB", mock.Anything, entity.AuditFilter{Resource: AuditPokemon}).
+					Return(nil, errors.New("errors")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			au := &AuditUsecase{
+				AuditRepository: tt.fields.AuditRepository,
+			}
+			gotResults, err := au.GetAuditLogs(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AuditUsecase.GetAuditLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("AuditUsecase.GetAuditLogs() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func Test_recordAudit(t *testing.T) {
+	prov := auditProvider()
+	adminCtx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 1, Username: "admin"})
+	apiKeyCtx := auth.NewContext(context.Background(), &auth.JWTClaim{Username: "importer", APIKeyID: 3})
+
+	type args struct {
+		ctx        context.Context
+		action     string
+		resource   string
+		resourceID int64
+		before     interface{}
+		after      interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success user",
+			args: args{
+				ctx:        adminCtx,
+				action:     AuditUpdate,
+				resource:   AuditType,
+				resourceID: 1,
+				before:     entity.Type{ID: 1, Name: "FIRE"},
+				after:      entity.Type{ID: 1, Name: "ICE"},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, entity.AuditLog{
+					ActorID:    1,
+					Actor:      "admin",
+					Action:     AuditUpdate,
+					Resource:   AuditType,
+					ResourceID: 1,
+					Before:     json.RawMessage(`{"id":1,"name":"FIRE"}`),
+					After:      json.RawMessage(`{"id":1,"name":"ICE"}`),
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success api key",
+			args: args{
+				ctx:        apiKeyCtx,
+				action:     AuditDelete,
+				resource:   AuditType,
+				resourceID: 2,
+				before:     entity.Type{ID: 2, Name: "WATER"},
+				after:      nil,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, entity.AuditLog{
+					APIKeyID:   3,
+					Actor:      "importer",
+					Action:     AuditDelete,
+					Resource:   AuditType,
+					ResourceID: 2,
+					Before:     json.RawMessage(`{"id":2,"name":"WATER"}`),
+					After:      json.RawMessage(`null`),
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			args: args{
+				ctx:        adminCtx,
+				action:     AuditRestore,
+				resource:   AuditPokemon,
+				resourceID: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(errors.New("errors")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			if err := recordAudit(tt.args.ctx, prov.AuditRepository, tt.args.action, tt.args.resource, tt.args.resourceID, tt.args.before, tt.args.after); (err != nil) != tt.wantErr {
+				t.Errorf("recordAudit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
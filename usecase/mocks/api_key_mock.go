@@ -0,0 +1,114 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package usecasemock
+
+import (
+	context "context"
+
+	auth "github.com/winartodev/go-pokedex/middleware/auth"
+
+	entity "github.com/winartodev/go-pokedex/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyUsecaseItf is an autogenerated mock type for the APIKeyUsecaseItf type
+type APIKeyUsecaseItf struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, key
+func (_m *APIKeyUsecaseItf) Authenticate(ctx context.Context, key string) (*auth.JWTClaim, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 *auth.JWTClaim
+	if rf, ok := ret.Get(0).(func(context.Context, string) *auth.JWTClaim); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.JWTClaim)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, data
+func (_m *APIKeyUsecaseItf) CreateAPIKey(ctx context.Context, data entity.CreateAPIKey) (entity.CreatedAPIKey, error) {
+	ret := _m.Called(ctx, data)
+
+	var r0 entity.CreatedAPIKey
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CreateAPIKey) entity.CreatedAPIKey); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Get(0).(entity.CreatedAPIKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CreateAPIKey) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllAPIKeys provides a mock function with given fields: ctx
+func (_m *APIKeyUsecaseItf) GetAllAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.APIKey
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, id
+func (_m *APIKeyUsecaseItf) RevokeAPIKey(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyUsecaseItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyUsecaseItf creates a new instance of APIKeyUsecaseItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyUsecaseItf(t mockConstructorTestingTNewAPIKeyUsecaseItf) *APIKeyUsecaseItf {
+	mock := &APIKeyUsecaseItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
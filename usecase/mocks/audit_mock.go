@@ -0,0 +1,53 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package usecasemock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+)
+
+// AuditUsecaseItf is an autogenerated mock type for the AuditUsecaseItf type
+type AuditUsecaseItf struct {
+	mock.Mock
+}
+
+// GetAuditLogs provides a mock function with given fields: ctx, filter
+func (_m *AuditUsecaseItf) GetAuditLogs(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditLog, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.AuditLog
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AuditFilter) []entity.AuditLog); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AuditLog)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditUsecaseItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditUsecaseItf creates a new instance of AuditUsecaseItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditUsecaseItf(t mockConstructorTestingTNewAuditUsecaseItf) *AuditUsecaseItf {
+	mock := &AuditUsecaseItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,75 @@ type PokemonUsecaseItf struct {
 	mock.Mock
 }
 
+// BulkCreatePokemon provides a mock function with given fields: ctx, data, atomic
+func (_m *PokemonUsecaseItf) BulkCreatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) ([]entity.BulkResult, error) {
+	ret := _m.Called(ctx, data, atomic)
+
+	var r0 []entity.BulkResult
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.BulkPokemon, bool) []entity.BulkResult); ok {
+		r0 = rf(ctx, data, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BulkResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.BulkPokemon, bool) error); ok {
+		r1 = rf(ctx, data, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BulkDeletePokemon provides a mock function with given fields: ctx, data, atomic
+func (_m *PokemonUsecaseItf) BulkDeletePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) ([]entity.BulkResult, error) {
+	ret := _m.Called(ctx, data, atomic)
+
+	var r0 []entity.BulkResult
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.BulkPokemon, bool) []entity.BulkResult); ok {
+		r0 = rf(ctx, data, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BulkResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.BulkPokemon, bool) error); ok {
+		r1 = rf(ctx, data, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BulkUpdatePokemon provides a mock function with given fields: ctx, data, atomic
+func (_m *PokemonUsecaseItf) BulkUpdatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) ([]entity.BulkResult, error) {
+	ret := _m.Called(ctx, data, atomic)
+
+	var r0 []entity.BulkResult
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.BulkPokemon, bool) []entity.BulkResult); ok {
+		r0 = rf(ctx, data, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BulkResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.BulkPokemon, bool) error); ok {
+		r1 = rf(ctx, data, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CatchPokemon provides a mock function with given fields: ctx, id
 func (_m *PokemonUsecaseItf) CatchPokemon(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -49,13 +118,41 @@ func (_m *PokemonUsecaseItf) CreatePokemon(ctx context.Context, data entity.Poke
 	return r0, r1
 }
 
-// DeletePokemon provides a mock function with given fields: ctx, id
-func (_m *PokemonUsecaseItf) DeletePokemon(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// DeletePokemon provides a mock function with given fields: ctx, id, version
+func (_m *PokemonUsecaseItf) DeletePokemon(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachType provides a mock function with given fields: ctx, typeID
+func (_m *PokemonUsecaseItf) DetachType(ctx context.Context, typeID int64) error {
+	ret := _m.Called(ctx, typeID)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+		r0 = rf(ctx, typeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportPokemon provides a mock function with given fields: ctx, fn
+func (_m *PokemonUsecaseItf) ExportPokemon(ctx context.Context, fn func(entity.PokemonDetail) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(entity.PokemonDetail) error) error); ok {
+		r0 = rf(ctx, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -86,13 +183,13 @@ func (_m *PokemonUsecaseItf) GetAllPokemon(ctx context.Context) ([]entity.Pokemo
 	return r0, r1
 }
 
-// GetAllPokemonByFilter provides a mock function with given fields: ctx, filter
-func (_m *PokemonUsecaseItf) GetAllPokemonByFilter(ctx context.Context, filter map[string]string) ([]entity.PokemonList, error) {
-	ret := _m.Called(ctx, filter)
+// GetAllPokemonByFilter provides a mock function with given fields: ctx, data
+func (_m *PokemonUsecaseItf) GetAllPokemonByFilter(ctx context.Context, data entity.PokemonFilter) ([]entity.PokemonList, error) {
+	ret := _m.Called(ctx, data)
 
 	var r0 []entity.PokemonList
-	if rf, ok := ret.Get(0).(func(context.Context, map[string]string) []entity.PokemonList); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PokemonFilter) []entity.PokemonList); ok {
+		r0 = rf(ctx, data)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.PokemonList)
@@ -100,8 +197,8 @@ func (_m *PokemonUsecaseItf) GetAllPokemonByFilter(ctx context.Context, filter m
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, map[string]string) error); ok {
-		r1 = rf(ctx, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PokemonFilter) error); ok {
+		r1 = rf(ctx, data)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -132,13 +229,177 @@ func (_m *PokemonUsecaseItf) GetPokemonByID(ctx context.Context, id int64) (*ent
 	return r0, r1
 }
 
-// UpdatePokemon provides a mock function with given fields: ctx, id, data
-func (_m *PokemonUsecaseItf) UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (*entity.PokemonDetail, error) {
-	ret := _m.Called(ctx, id, data)
+// GetPokemonRevisions provides a mock function with given fields: ctx, id
+func (_m *PokemonUsecaseItf) GetPokemonRevisions(ctx context.Context, id int64) ([]entity.PokemonRevision, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []entity.PokemonRevision
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.PokemonRevision); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportPokemon provides a mock function with given fields: ctx, data, dryRun
+func (_m *PokemonUsecaseItf) ImportPokemon(ctx context.Context, data []entity.ImportRow, dryRun bool) (entity.ImportReport, error) {
+	ret := _m.Called(ctx, data, dryRun)
+
+	var r0 entity.ImportReport
+	if rf, ok := ret.Get(0).(func(context.Context, []entity.ImportRow, bool) entity.ImportReport); ok {
+		r0 = rf(ctx, data, dryRun)
+	} else {
+		r0 = ret.Get(0).(entity.ImportReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []entity.ImportRow, bool) error); ok {
+		r1 = rf(ctx, data, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchPokemon provides a mock function with given fields: ctx, id, version, patch
+func (_m *PokemonUsecaseItf) PatchPokemon(ctx context.Context, id int64, version int64, patch []byte) (*entity.PokemonDetail, error) {
+	ret := _m.Called(ctx, id, version, patch)
+
+	var r0 *entity.PokemonDetail
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, []byte) *entity.PokemonDetail); ok {
+		r0 = rf(ctx, id, version, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PokemonDetail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, []byte) error); ok {
+		r1 = rf(ctx, id, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReindexPokemon provides a mock function with given fields: ctx
+func (_m *PokemonUsecaseItf) ReindexPokemon(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestorePokemon provides a mock function with given fields: ctx, id
+func (_m *PokemonUsecaseItf) RestorePokemon(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestorePokemonRevision provides a mock function with given fields: ctx, id, revision, version
+func (_m *PokemonUsecaseItf) RestorePokemonRevision(ctx context.Context, id int64, revision int64, version int64) (*entity.PokemonDetail, error) {
+	ret := _m.Called(ctx, id, revision, version)
+
+	var r0 *entity.PokemonDetail
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) *entity.PokemonDetail); ok {
+		r0 = rf(ctx, id, revision, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PokemonDetail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, revision, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchPokemon provides a mock function with given fields: ctx, query, limit
+func (_m *PokemonUsecaseItf) SearchPokemon(ctx context.Context, query string, limit int) ([]entity.PokemonSearchResult, error) {
+	ret := _m.Called(ctx, query, limit)
+
+	var r0 []entity.PokemonSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.PokemonSearchResult); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonSearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestPokemon provides a mock function with given fields: ctx, prefix, limit
+func (_m *PokemonUsecaseItf) SuggestPokemon(ctx context.Context, prefix string, limit int) ([]entity.PokemonSuggestion, error) {
+	ret := _m.Called(ctx, prefix, limit)
+
+	var r0 []entity.PokemonSuggestion
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.PokemonSuggestion); ok {
+		r0 = rf(ctx, prefix, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PokemonSuggestion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, prefix, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePokemon provides a mock function with given fields: ctx, id, version, data
+func (_m *PokemonUsecaseItf) UpdatePokemon(ctx context.Context, id int64, version int64, data entity.Pokemon) (*entity.PokemonDetail, error) {
+	ret := _m.Called(ctx, id, version, data)
 
 	var r0 *entity.PokemonDetail
-	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.Pokemon) *entity.PokemonDetail); ok {
-		r0 = rf(ctx, id, data)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, entity.Pokemon) *entity.PokemonDetail); ok {
+		r0 = rf(ctx, id, version, data)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.PokemonDetail)
@@ -146,8 +407,8 @@ func (_m *PokemonUsecaseItf) UpdatePokemon(ctx context.Context, id int64, data e
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.Pokemon) error); ok {
-		r1 = rf(ctx, id, data)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, entity.Pokemon) error); ok {
+		r1 = rf(ctx, id, version, data)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package usecasemock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	entity "github.com/winartodev/go-pokedex/entity"
+
+	time "time"
+)
+
+// TrashUsecaseItf is an autogenerated mock type for the TrashUsecaseItf type
+type TrashUsecaseItf struct {
+	mock.Mock
+}
+
+// GetTrash provides a mock function with given fields: ctx
+func (_m *TrashUsecaseItf) GetTrash(ctx context.Context) ([]entity.TrashItem, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.TrashItem
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.TrashItem); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.TrashItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, now
+func (_m *TrashUsecaseItf) Purge(ctx context.Context, now time.Time) error {
+	ret := _m.Called(ctx, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTrashUsecaseItf interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTrashUsecaseItf creates a new instance of TrashUsecaseItf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTrashUsecaseItf(t mockConstructorTestingTNewTrashUsecaseItf) *TrashUsecaseItf {
+	mock := &TrashUsecaseItf{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
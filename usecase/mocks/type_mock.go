@@ -35,6 +35,20 @@ func (_m *TypeUsecaseItf) CreateType(ctx context.Context, data entity.Type) (int
 	return r0, r1
 }
 
+// DeleteType provides a mock function with given fields: ctx, id, version, cascade
+func (_m *TypeUsecaseItf) DeleteType(ctx context.Context, id int64, version int64, cascade bool) error {
+	ret := _m.Called(ctx, id, version, cascade)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, bool) error); ok {
+		r0 = rf(ctx, id, version, cascade)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GeTypeByID provides a mock function with given fields: ctx, id
 func (_m *TypeUsecaseItf) GeTypeByID(ctx context.Context, id int64) (entity.Type, error) {
 	ret := _m.Called(ctx, id)
@@ -79,13 +93,34 @@ func (_m *TypeUsecaseItf) GetAllType(ctx context.Context) ([]entity.Type, error)
 	return r0, r1
 }
 
-// UpdateType provides a mock function with given fields: ctx, id, data
-func (_m *TypeUsecaseItf) UpdateType(ctx context.Context, id int64, data entity.Type) error {
-	ret := _m.Called(ctx, id, data)
+// PatchType provides a mock function with given fields: ctx, id, version, patch
+func (_m *TypeUsecaseItf) PatchType(ctx context.Context, id int64, version int64, patch []byte) (entity.Type, error) {
+	ret := _m.Called(ctx, id, version, patch)
+
+	var r0 entity.Type
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, []byte) entity.Type); ok {
+		r0 = rf(ctx, id, version, patch)
+	} else {
+		r0 = ret.Get(0).(entity.Type)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, []byte) error); ok {
+		r1 = rf(ctx, id, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreType provides a mock function with given fields: ctx, id
+func (_m *TypeUsecaseItf) RestoreType(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.Type) error); ok {
-		r0 = rf(ctx, id, data)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -93,6 +128,27 @@ func (_m *TypeUsecaseItf) UpdateType(ctx context.Context, id int64, data entity.
 	return r0
 }
 
+// UpdateType provides a mock function with given fields: ctx, id, version, data
+func (_m *TypeUsecaseItf) UpdateType(ctx context.Context, id int64, version int64, data entity.Type) (entity.Type, error) {
+	ret := _m.Called(ctx, id, version, data)
+
+	var r0 entity.Type
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, entity.Type) entity.Type); ok {
+		r0 = rf(ctx, id, version, data)
+	} else {
+		r0 = ret.Get(0).(entity.Type)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, entity.Type) error); ok {
+		r1 = rf(ctx, id, version, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewTypeUsecaseItf interface {
 	mock.TestingT
 	Cleanup(func())
@@ -5,6 +5,10 @@ package usecasemock
 import (
 	context "context"
 
+	auth "github.com/winartodev/go-pokedex/middleware/auth"
+
+	entity "github.com/winartodev/go-pokedex/entity"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,20 +17,36 @@ type UserUsecaseItf struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: ctx, username, password
-func (_m *UserUsecaseItf) Login(ctx context.Context, username string, password string) (string, error) {
-	ret := _m.Called(ctx, username, password)
+// ChangePassword provides a mock function with given fields: ctx, id, currentPassword, newPassword
+func (_m *UserUsecaseItf) ChangePassword(ctx context.Context, id int64, currentPassword string, newPassword string) error {
+	ret := _m.Called(ctx, id, currentPassword, newPassword)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, username, password)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, id, currentPassword, newPassword)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, id, code
+func (_m *UserUsecaseItf) ConfirmTOTP(ctx context.Context, id int64, code string) ([]string, error) {
+	ret := _m.Called(ctx, id, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []string); ok {
+		r0 = rf(ctx, id, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, username, password)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,8 +54,8 @@ func (_m *UserUsecaseItf) Login(ctx context.Context, username string, password s
 	return r0, r1
 }
 
-// Register provides a mock function with given fields: ctx, username, email, password, role
-func (_m *UserUsecaseItf) Register(ctx context.Context, username string, email string, password string, role int64) (int64, error) {
+// CreateUser provides a mock function with given fields: ctx, username, email, password, role
+func (_m *UserUsecaseItf) CreateUser(ctx context.Context, username string, email string, password string, role int64) (int64, error) {
 	ret := _m.Called(ctx, username, email, password, role)
 
 	var r0 int64
@@ -55,6 +75,383 @@ func (_m *UserUsecaseItf) Register(ctx context.Context, username string, email s
 	return r0, r1
 }
 
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserUsecaseItf) DeleteUser(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, id, password
+func (_m *UserUsecaseItf) DisableTOTP(ctx context.Context, id int64, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollTOTP provides a mock function with given fields: ctx, id
+func (_m *UserUsecaseItf) EnrollTOTP(ctx context.Context, id int64) (entity.TOTPEnrollment, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.TOTPEnrollment
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.TOTPEnrollment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.TOTPEnrollment)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishOIDCLogin provides a mock function with given fields: ctx, flow, state, code, client
+func (_m *UserUsecaseItf) FinishOIDCLogin(ctx context.Context, flow string, state string, code string, client entity.Client) (entity.LoginResult, error) {
+	ret := _m.Called(ctx, flow, state, code, client)
+
+	var r0 entity.LoginResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, entity.Client) entity.LoginResult); ok {
+		r0 = rf(ctx, flow, state, code, client)
+	} else {
+		r0 = ret.Get(0).(entity.LoginResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, flow, state, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *UserUsecaseItf) ForgotPassword(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllUsers provides a mock function with given fields: ctx, filter
+func (_m *UserUsecaseItf) GetAllUsers(ctx context.Context, filter entity.UserFilter) ([]entity.UserProfile, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.UserProfile
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserFilter) []entity.UserProfile); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserProfile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfile provides a mock function with given fields: ctx, id
+func (_m *UserUsecaseItf) GetProfile(ctx context.Context, id int64) (entity.UserProfile, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.UserProfile
+	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.UserProfile); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.UserProfile)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: ctx, userID
+func (_m *UserUsecaseItf) GetSessions(ctx context.Context, userID int64) ([]entity.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []entity.Session
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, username, password, client
+func (_m *UserUsecaseItf) Login(ctx context.Context, username string, password string, client entity.Client) (entity.LoginResult, error) {
+	ret := _m.Called(ctx, username, password, client)
+
+	var r0 entity.LoginResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) entity.LoginResult); ok {
+		r0 = rf(ctx, username, password, client)
+	} else {
+		r0 = ret.Get(0).(entity.LoginResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, username, password, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginTwoFactor provides a mock function with given fields: ctx, challenge, code, client
+func (_m *UserUsecaseItf) LoginTwoFactor(ctx context.Context, challenge string, code string, client entity.Client) (entity.LoginResult, error) {
+	ret := _m.Called(ctx, challenge, code, client)
+
+	var r0 entity.LoginResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) entity.LoginResult); ok {
+		r0 = rf(ctx, challenge, code, client)
+	} else {
+		r0 = ret.Get(0).(entity.LoginResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, challenge, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, id, code
+func (_m *UserUsecaseItf) RegenerateRecoveryCodes(ctx context.Context, id int64, code string) ([]string, error) {
+	ret := _m.Called(ctx, id, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []string); ok {
+		r0 = rf(ctx, id, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: ctx, username, email, password
+func (_m *UserUsecaseItf) Register(ctx context.Context, username string, email string, password string) (int64, error) {
+	ret := _m.Called(ctx, username, email, password)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int64); ok {
+		r0 = rf(ctx, username, email, password)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, username, email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *UserUsecaseItf) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *UserUsecaseItf) RevokeSession(ctx context.Context, userID int64, sessionID int64) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationEmail provides a mock function with given fields: ctx, id
+func (_m *UserUsecaseItf) SendVerificationEmail(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserDisabled provides a mock function with given fields: ctx, id, disabled
+func (_m *UserUsecaseItf) SetUserDisabled(ctx context.Context, id int64, disabled bool) error {
+	ret := _m.Called(ctx, id, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, id, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartOIDCLogin provides a mock function with given fields: ctx
+func (_m *UserUsecaseItf) StartOIDCLogin(ctx context.Context) (entity.OIDCLogin, error) {
+	ret := _m.Called(ctx)
+
+	var r0 entity.OIDCLogin
+	if rf, ok := ret.Get(0).(func(context.Context) entity.OIDCLogin); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(entity.OIDCLogin)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, id, data
+func (_m *UserUsecaseItf) UpdateProfile(ctx context.Context, id int64, data entity.UpdateProfile) (entity.UserProfile, error) {
+	ret := _m.Called(ctx, id, data)
+
+	var r0 entity.UserProfile
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.UpdateProfile) entity.UserProfile); ok {
+		r0 = rf(ctx, id, data)
+	} else {
+		r0 = ret.Get(0).(entity.UserProfile)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.UpdateProfile) error); ok {
+		r1 = rf(ctx, id, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUserRole provides a mock function with given fields: ctx, id, role
+func (_m *UserUsecaseItf) UpdateUserRole(ctx context.Context, id int64, role int64) error {
+	ret := _m.Called(ctx, id, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateSession provides a mock function with given fields: ctx, claims
+func (_m *UserUsecaseItf) ValidateSession(ctx context.Context, claims *auth.JWTClaim) error {
+	ret := _m.Called(ctx, claims)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *auth.JWTClaim) error); ok {
+		r0 = rf(ctx, claims)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *UserUsecaseItf) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserUsecaseItf interface {
 	mock.TestingT
 	Cleanup(func())
@@ -3,25 +3,55 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
+	"github.com/winartodev/go-pokedex/apperror"
 	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
 	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
 	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/search"
+	"github.com/winartodev/go-pokedex/transaction"
+	"github.com/winartodev/go-pokedex/util"
 )
 
 type PokemonUsecase struct {
 	PokemonRepository     pokemonrepository.PokemonRepositoryItf
 	PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+	TypesRepository       typesrepository.TypeRepositoryItf
+	AuditRepository       auditrepository.AuditRepositoryItf
+	// PokemonRevisionRepository keeps every saved version of the pokemons
+	PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+	Transactor                transaction.TransactorItf
+	// SearchIndex is kept in sync with the saved pokemons, nil disables search
+	SearchIndex search.Index
+	// Suggester completes pokemon names and species, it is kept in sync like SearchIndex and nil disables suggestions
+	Suggester search.Suggester
 }
 
 type PokemonUsecaseItf interface {
 	GetAllPokemon(ctx context.Context) (results []entity.PokemonList, err error)
-	GetAllPokemonByFilter(ctx context.Context, filter map[string]string) (results []entity.PokemonList, err error)
+	GetAllPokemonByFilter(ctx context.Context, data entity.PokemonFilter) (results []entity.PokemonList, err error)
 	CatchPokemon(ctx context.Context, id int64) (err error)
 	CreatePokemon(ctx context.Context, data entity.Pokemon) (pokemonID int64, err error)
 	GetPokemonByID(ctx context.Context, id int64) (result *entity.PokemonDetail, err error)
-	UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (result *entity.PokemonDetail, err error)
-	DeletePokemon(ctx context.Context, id int64) (err error)
+	UpdatePokemon(ctx context.Context, id int64, version int64, data entity.Pokemon) (result *entity.PokemonDetail, err error)
+	PatchPokemon(ctx context.Context, id int64, version int64, patch []byte) (result *entity.PokemonDetail, err error)
+	DeletePokemon(ctx context.Context, id int64, version int64) (err error)
+	RestorePokemon(ctx context.Context, id int64) (err error)
+	GetPokemonRevisions(ctx context.Context, id int64) (results []entity.PokemonRevision, err error)
+	RestorePokemonRevision(ctx context.Context, id int64, revision int64, version int64) (result *entity.PokemonDetail, err error)
+	BulkCreatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error)
+	BulkUpdatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error)
+	BulkDeletePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error)
+	ExportPokemon(ctx context.Context, fn func(data entity.PokemonDetail) error) (err error)
+	ImportPokemon(ctx context.Context, data []entity.ImportRow, dryRun bool) (report entity.ImportReport, err error)
+	SearchPokemon(ctx context.Context, query string, limit int) (results []entity.PokemonSearchResult, err error)
+	SuggestPokemon(ctx context.Context, prefix string, limit int) (results []entity.PokemonSuggestion, err error)
+	ReindexPokemon(ctx context.Context) (err error)
+	DetachType(ctx context.Context, typeID int64) (err error)
 }
 
 const (
@@ -29,10 +59,23 @@ const (
 	DELETED = 0
 )
 
+var (
+	ErrPokemonNotFound   = apperror.New(apperror.NotFound, "pokemon_not_found", "pokemon not found")
+	ErrPokemonNotInTrash = apperror.New(apperror.NotFound, "pokemon_not_in_trash", "pokemon is not in the trash")
+	// ErrVersionMismatch is returned when the pokemon or type was changed by someone else since the client fetched it
+	ErrVersionMismatch = apperror.New(apperror.PreconditionFailed, "version_mismatch", "resource was changed since it was fetched, fetch it again and retry")
+)
+
 func NewPokemonUsecase(pokemonUsecase PokemonUsecase) PokemonUsecaseItf {
 	return &PokemonUsecase{
-		PokemonRepository:     pokemonUsecase.PokemonRepository,
-		PokemonTypeRepository: pokemonUsecase.PokemonTypeRepository,
+		PokemonRepository:         pokemonUsecase.PokemonRepository,
+		PokemonTypeRepository:     pokemonUsecase.PokemonTypeRepository,
+		TypesRepository:           pokemonUsecase.TypesRepository,
+		AuditRepository:           pokemonUsecase.AuditRepository,
+		PokemonRevisionRepository: pokemonUsecase.PokemonRevisionRepository,
+		Transactor:                pokemonUsecase.Transactor,
+		SearchIndex:               pokemonUsecase.SearchIndex,
+		Suggester:                 pokemonUsecase.Suggester,
 	}
 }
 
@@ -45,7 +88,17 @@ func (pu *PokemonUsecase) GetAllPokemon(ctx context.Context) (results []entity.P
 	return pu.buildResponsePokemonList(ctx, res)
 }
 
-func (pu *PokemonUsecase) GetAllPokemonByFilter(ctx context.Context, filter map[string]string) (results []entity.PokemonList, err error) {
+func (pu *PokemonUsecase) GetAllPokemonByFilter(ctx context.Context, data entity.PokemonFilter) (results []entity.PokemonList, err error) {
+	err = validatePokemonFilter(data)
+	if err != nil {
+		return results, err
+	}
+
+	filter, err := pu.buildPokemonFilterDB(ctx, data)
+	if err != nil {
+		return results, err
+	}
+
 	res, err := pu.PokemonRepository.GetAllPokemonByFilterDB(ctx, filter)
 	if err != nil {
 		return results, err
@@ -55,54 +108,122 @@ func (pu *PokemonUsecase) GetAllPokemonByFilter(ctx context.Context, filter map[
 }
 
 func (pu *PokemonUsecase) CreatePokemon(ctx context.Context, data entity.Pokemon) (pokemonID int64, err error) {
-	pokemon, err := pu.buildPokemonFromRequest(data)
+	err = pu.validatePokemon(ctx, 0, data)
 	if err != nil {
 		return pokemonID, err
 	}
 
-	pokemonID, err = pu.PokemonRepository.CreatePokemonDB(ctx, pokemon)
+	pokemon, err := pu.buildPokemonFromRequest(data)
 	if err != nil {
 		return pokemonID, err
 	}
 
-	for _, typeID := range data.Types {
-		err = pu.PokemonTypeRepository.CreatePokemonTypeDB(ctx, entity.PokemonType{PokemonID: pokemonID, TypeID: typeID})
+	// the pokemon is saved together with its types, its first revision and the audit log or not at all
+	err = pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		pokemonID, err = pu.PokemonRepository.CreatePokemonDB(ctx, pokemon)
+		if isDuplicate(err) {
+			return pokemonNameTaken(data.Name)
+		}
 		if err != nil {
-			return pokemonID, err
+			return err
+		}
+
+		for _, typeID := range data.Types {
+			err = pu.PokemonTypeRepository.CreatePokemonTypeDB(ctx, entity.PokemonType{PokemonID: pokemonID, TypeID: typeID})
+			if err != nil {
+				return err
+			}
 		}
-	}
+
+		data.ID = pokemonID
+		err = pu.recordRevision(ctx, pokemonID, 1, data)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, pu.AuditRepository, AuditCreate, AuditPokemon, pokemonID, nil, data)
+		if err != nil {
+			return err
+		}
+
+		pu.indexPokemon(ctx, data)
+		return nil
+	})
 
 	return pokemonID, err
 }
 
 func (pu *PokemonUsecase) GetPokemonByID(ctx context.Context, id int64) (result *entity.PokemonDetail, err error) {
-	pokemon, err := pu.PokemonRepository.GetPokemonByIDDB(ctx, id)
+	pokemon, err := pu.getPokemonByID(ctx, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return result, nil
-		}
 		return result, err
 	}
 
 	return pu.buildResponsePokemonDetail(ctx, pokemon)
 }
 
-func (pu *PokemonUsecase) UpdatePokemon(ctx context.Context, id int64, data entity.Pokemon) (result *entity.PokemonDetail, err error) {
+// UpdatePokemon replaces the pokemon when it is still at the version, version 0 updates any version
+func (pu *PokemonUsecase) UpdatePokemon(ctx context.Context, id int64, version int64, data entity.Pokemon) (result *entity.PokemonDetail, err error) {
 	pokemonData, err := pu.buildPokemonFromRequest(data)
 	if err != nil {
 		return result, err
 	}
 
-	err = pu.PokemonRepository.UpdatePokemonDB(ctx, id, pokemonData)
+	current, err := pu.getPokemonByID(ctx, id)
 	if err != nil {
 		return result, err
 	}
 
-	pokemonType, err := pu.PokemonTypeRepository.GetPokemonTypeByPokemonIDDB(ctx, id)
+	err = checkVersion(current.Version, version)
 	if err != nil {
 		return result, err
 	}
 
+	err = pu.validatePokemon(ctx, id, data)
+	if err != nil {
+		return result, err
+	}
+
+	err = pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return pu.updatePokemon(ctx, id, current, pokemonData, data)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	pokemon, err := pu.getPokemonByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	return pu.buildResponsePokemonDetail(ctx, pokemon)
+}
+
+// updatePokemon saves the pokemon with its types, the revision and the audit log of the update,
+// current is the pokemon before the update
+func (pu *PokemonUsecase) updatePokemon(ctx context.Context, id int64, current entity.PokemonDB, pokemonData entity.PokemonDB, data entity.Pokemon) (err error) {
+	pokemonData.Version = current.Version
+	err = pu.PokemonRepository.UpdatePokemonDB(ctx, id, pokemonData)
+	if err == sql.ErrNoRows {
+		return ErrVersionMismatch
+	}
+	if isDuplicate(err) {
+		return pokemonNameTaken(data.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	pokemonType, err := pu.PokemonTypeRepository.GetPokemonTypeByPokemonIDDB(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	before, err := buildPokemon(current, pokemonType)
+	if err != nil {
+		return err
+	}
+
 	// will update and insert new data if length request is greather than or equal length data pokemon type that obtained from database.
 	if len(data.Types) >= len(pokemonType) {
 		for i := 0; i < len(data.Types); i++ {
@@ -110,13 +231,13 @@ func (pu *PokemonUsecase) UpdatePokemon(ctx context.Context, id int64, data enti
 				if pokemonType[i].TypeID != data.Types[i] {
 					err = pu.PokemonTypeRepository.UpdatePokemonTypeDB(ctx, pokemonType[i].ID, entity.PokemonType{PokemonID: id, TypeID: data.Types[i]})
 					if err != nil {
-						return result, err
+						return err
 					}
 				}
 			} else {
 				err = pu.PokemonTypeRepository.CreatePokemonTypeDB(ctx, entity.PokemonType{PokemonID: id, TypeID: data.Types[i]})
 				if err != nil {
-					return result, err
+					return err
 				}
 			}
 		}
@@ -128,51 +249,271 @@ func (pu *PokemonUsecase) UpdatePokemon(ctx context.Context, id int64, data enti
 			if len(data.Types) > i {
 				err = pu.PokemonTypeRepository.UpdatePokemonTypeDB(ctx, pokemonType[i].ID, entity.PokemonType{PokemonID: id, TypeID: data.Types[i]})
 				if err != nil {
-					return result, err
+					return err
 				}
 			} else {
 				err = pu.PokemonTypeRepository.UpdatePokemonTypeDB(ctx, pokemonType[i].ID, entity.PokemonType{PokemonID: id, TypeID: DELETED})
 				if err != nil {
-					return result, err
+					return err
 				}
 			}
 		}
 	}
 
-	pokemon, err := pu.PokemonRepository.GetPokemonByIDDB(ctx, id)
+	data.ID = id
+	err = pu.recordRevision(ctx, id, current.Version+1, data)
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(ctx, pu.AuditRepository, AuditUpdate, AuditPokemon, id, before, data)
+	if err != nil {
+		return err
+	}
+
+	pu.indexPokemon(ctx, data)
+	return nil
+}
+
+// PatchPokemon applies json merge patch to the pokemon, fields missing from the patch keep their current value
+func (pu *PokemonUsecase) PatchPokemon(ctx context.Context, id int64, version int64, patch []byte) (result *entity.PokemonDetail, err error) {
+	pokemon, err := pu.getPokemonByID(ctx, id)
 	if err != nil {
 		return result, err
 	}
 
-	return pu.buildResponsePokemonDetail(ctx, pokemon)
+	err = checkVersion(pokemon.Version, version)
+	if err != nil {
+		return result, err
+	}
+
+	current, err := pu.buildPokemonFromDB(ctx, pokemon)
+	if err != nil {
+		return result, err
+	}
+
+	var data entity.Pokemon
+	err = applyMergePatch(current, patch, &data)
+	if err != nil {
+		return result, err
+	}
+	data.ID = id
+
+	// the patch was applied to this version so it must not have changed in the meantime
+	return pu.UpdatePokemon(ctx, id, pokemon.Version, data)
 }
 
-func (pu *PokemonUsecase) DeletePokemon(ctx context.Context, id int64) (err error) {
-	err = pu.PokemonRepository.DeletePokemonByIDDB(ctx, id)
+// DeletePokemon moves the pokemon to the trash when it is still at the version, version 0 deletes any version.
+// Types of the pokemon are kept so they come back when the pokemon is restored
+func (pu *PokemonUsecase) DeletePokemon(ctx context.Context, id int64, version int64) (err error) {
+	pokemon, err := pu.getPokemonByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	err = pu.PokemonTypeRepository.DeletePokemonTypeByPokemonIDDB(ctx, id)
+	err = checkVersion(pokemon.Version, version)
 	if err != nil {
 		return err
 	}
 
-	return err
+	before, err := pu.buildPokemonFromDB(ctx, pokemon)
+	if err != nil {
+		return err
+	}
+
+	return pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		err = pu.PokemonRepository.DeletePokemonByIDDB(ctx, id, pokemon.Version)
+		if err == sql.ErrNoRows {
+			return ErrVersionMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		// the delete bumps the version, the revision keeps the history of versions without gaps
+		err = pu.recordRevision(ctx, id, pokemon.Version+1, before)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, pu.AuditRepository, AuditDelete, AuditPokemon, id, before, nil)
+		if err != nil {
+			return err
+		}
+
+		pu.unindexPokemon(ctx, id)
+		return nil
+	})
+}
+
+// RestorePokemon takes the pokemon out of the trash
+func (pu *PokemonUsecase) RestorePokemon(ctx context.Context, id int64) (err error) {
+	return pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		err = pu.PokemonRepository.RestorePokemonDB(ctx, id)
+		if err == sql.ErrNoRows {
+			return ErrPokemonNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		pokemon, err := pu.getPokemonByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		data, err := pu.buildPokemonFromDB(ctx, pokemon)
+		if err != nil {
+			return err
+		}
+
+		// the restore already bumped the version, the pokemon is saved as the revision of that version
+		err = pu.recordRevision(ctx, id, pokemon.Version, data)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, pu.AuditRepository, AuditRestore, AuditPokemon, id, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		pu.indexPokemon(ctx, data)
+		return nil
+	})
 }
 
+// CatchPokemon marks the pokemon as catched, it is audited as an update of the pokemon
 func (pu *PokemonUsecase) CatchPokemon(ctx context.Context, id int64) (err error) {
-	pokemon, err := pu.PokemonRepository.GetPokemonByIDDB(ctx, id)
+	pokemon, err := pu.getPokemonByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	before, err := pu.buildPokemonFromDB(ctx, pokemon)
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.Catched = CATCH
 	pokemon.Catched = CATCH
 
-	err = pu.PokemonRepository.UpdatePokemonDB(ctx, id, pokemon)
+	return pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		err = pu.PokemonRepository.UpdatePokemonDB(ctx, id, pokemon)
+		if err == sql.ErrNoRows {
+			return ErrVersionMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		err = pu.recordRevision(ctx, id, pokemon.Version+1, after)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, pu.AuditRepository, AuditUpdate, AuditPokemon, id, before, after)
+	})
+}
+
+// DetachType removes the type from every pokemon that has it, in the trash or not.
+// Each pokemon is saved as a new version with its revision and audit entry like any other update
+func (pu *PokemonUsecase) DetachType(ctx context.Context, typeID int64) (err error) {
+	return pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		pokemons, err := pu.PokemonRepository.GetPokemonByTypeIDDB(ctx, typeID)
+		if err != nil {
+			return err
+		}
+
+		befores := make([]entity.Pokemon, 0, len(pokemons))
+		for _, pokemon := range pokemons {
+			before, err := pu.buildPokemonFromDB(ctx, pokemon)
+			if err != nil {
+				return err
+			}
+
+			befores = append(befores, before)
+		}
+
+		err = pu.PokemonTypeRepository.DetachPokemonTypeByTypeIDDB(ctx, typeID)
+		if err != nil {
+			return err
+		}
+
+		for i, pokemon := range pokemons {
+			before := befores[i]
+			after := before
+			after.Types = []int64{}
+			for _, id := range before.Types {
+				if id != typeID {
+					after.Types = append(after.Types, id)
+				}
+			}
+
+			err = pu.PokemonRepository.UpdatePokemonVersionDB(ctx, pokemon.ID, pokemon.Version)
+			if err == sql.ErrNoRows {
+				return ErrVersionMismatch
+			}
+			if err != nil {
+				return err
+			}
+
+			err = pu.recordRevision(ctx, pokemon.ID, pokemon.Version+1, after)
+			if err != nil {
+				return err
+			}
+
+			err = recordAudit(ctx, pu.AuditRepository, AuditUpdate, AuditPokemon, pokemon.ID, before, after)
+			if err != nil {
+				return err
+			}
+
+			// pokemons in the trash are not in the search index until they are restored
+			if pokemon.DeletedAt == nil {
+				pu.indexPokemon(ctx, after)
+			}
+		}
+
+		return nil
+	})
+}
+
+// getPokemonByID returns the pokemon, ErrPokemonNotFound is returned when the pokemon does not exist
+func (pu *PokemonUsecase) getPokemonByID(ctx context.Context, id int64) (result entity.PokemonDB, err error) {
+	result, err = pu.PokemonRepository.GetPokemonByIDDB(ctx, id)
+	if err == sql.ErrNoRows {
+		return result, ErrPokemonNotFound
+	}
+
+	return result, err
+}
+
+// applyMergePatch applies json merge patch to the json of current and stores the patched value in result
+func applyMergePatch(current interface{}, patch []byte, result interface{}) (err error) {
+	document, err := json.Marshal(current)
 	if err != nil {
 		return err
 	}
 
-	return err
+	patched, err := util.MergePatch(document, patch)
+	if err != nil {
+		return apperror.Wrap(apperror.Validation, "invalid_patch", err)
+	}
+
+	err = json.Unmarshal(patched, result)
+	if err != nil {
+		return apperror.Newf(apperror.Validation, "invalid_patch", "patched document is not valid: %v", err)
+	}
+
+	return nil
+}
+
+// checkVersion returns ErrVersionMismatch when the current version is not the expected one, expected 0 matches any version
+func checkVersion(current int64, expected int64) (err error) {
+	if expected != 0 && expected != current {
+		return ErrVersionMismatch
+	}
+
+	return nil
 }
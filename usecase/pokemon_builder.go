@@ -3,8 +3,12 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/validation"
 )
 
 type metadata struct {
@@ -55,9 +59,11 @@ func (pu *PokemonUsecase) buildResponsePokemonDetail(ctx context.Context, data e
 	}
 
 	var types []string
+	var typeVersions []int64
 	for i := range pokemonTypes {
 		if pokemonTypes[i].TypeID > 0 {
 			types = append(types, pokemonTypes[i].Name)
+			typeVersions = append(typeVersions, pokemonTypes[i].TypeVersion)
 		}
 	}
 
@@ -68,16 +74,18 @@ func (pu *PokemonUsecase) buildResponsePokemonDetail(ctx context.Context, data e
 	}
 
 	return &entity.PokemonDetail{
-		ID:          data.ID,
-		Name:        data.Name,
-		Species:     data.Species,
-		Types:       types,
-		Catched:     data.Catched,
-		ImageURL:    metadata.ImageURL,
-		Description: metadata.Description,
-		Weight:      metadata.Weight,
-		Height:      metadata.Height,
-		Stats:       metadata.Stats,
+		ID:           data.ID,
+		Name:         data.Name,
+		Species:      data.Species,
+		Types:        types,
+		Catched:      data.Catched,
+		ImageURL:     metadata.ImageURL,
+		Description:  metadata.Description,
+		Weight:       metadata.Weight,
+		Height:       metadata.Height,
+		Stats:        metadata.Stats,
+		Version:      data.Version,
+		TypeVersions: typeVersions,
 	}, err
 }
 
@@ -103,3 +111,85 @@ func (pu *PokemonUsecase) buildPokemonFromRequest(data entity.Pokemon) (result e
 		Metadata: string(metadata),
 	}, nil
 }
+
+// buildPokemonFromDB is function to build the request body of the stored pokemon, only linked types are included
+func (pu *PokemonUsecase) buildPokemonFromDB(ctx context.Context, data entity.PokemonDB) (result entity.Pokemon, err error) {
+	pokemonTypes, err := pu.PokemonTypeRepository.GetPokemonTypeByPokemonIDDB(ctx, data.ID)
+	if err != nil {
+		return result, err
+	}
+
+	return buildPokemon(data, pokemonTypes)
+}
+
+// buildPokemon is function to build the request body of the pokemon with its already fetched types
+func buildPokemon(data entity.PokemonDB, pokemonTypes []entity.PokemonType) (result entity.Pokemon, err error) {
+	types := []int64{}
+	for i := range pokemonTypes {
+		if pokemonTypes[i].TypeID > 0 {
+			types = append(types, pokemonTypes[i].TypeID)
+		}
+	}
+
+	var metadata metadata
+	err = json.Unmarshal([]byte(data.Metadata), &metadata)
+	if err != nil {
+		return result, err
+	}
+
+	return entity.Pokemon{
+		ID:          data.ID,
+		Name:        data.Name,
+		Species:     data.Species,
+		Types:       types,
+		Catched:     data.Catched,
+		ImageURL:    metadata.ImageURL,
+		Description: metadata.Description,
+		Weight:      metadata.Weight,
+		Height:      metadata.Height,
+		Stats:       metadata.Stats,
+	}, nil
+}
+
+// buildPokemonFilterDB resolves the type names and ids of the filter to the ids of the types, every type must exist
+func (pu *PokemonUsecase) buildPokemonFilterDB(ctx context.Context, data entity.PokemonFilter) (result entity.PokemonFilterDB, err error) {
+	result = entity.PokemonFilterDB{
+		Expr:    data.Expr,
+		SortBy:  data.SortBy,
+		OrderBy: data.OrderBy,
+	}
+
+	if len(data.TypeAll)+len(data.TypeAny)+len(data.TypeNot) == 0 {
+		return result, nil
+	}
+
+	types, err := pu.TypesRepository.GetAllTypeDB(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	typeIDs := make(map[string]int64, 2*len(types))
+	for _, t := range types {
+		typeIDs[strings.ToUpper(t.Name)] = t.ID
+		typeIDs[strconv.FormatInt(t.ID, 10)] = t.ID
+	}
+
+	var v validation.Validator
+	result.TypeAll = resolveTypeIDs(&v, "type_all", data.TypeAll, typeIDs)
+	result.TypeAny = resolveTypeIDs(&v, "type_any", data.TypeAny, typeIDs)
+	result.TypeNot = resolveTypeIDs(&v, "type_not", data.TypeNot, typeIDs)
+
+	return result, v.Err()
+}
+
+// resolveTypeIDs returns the ids of the type names or ids, unknown types are recorded as errors of the field
+func resolveTypeIDs(v *validation.Validator, field string, values []string, typeIDs map[string]int64) (results []int64) {
+	for i, value := range values {
+		typeID, ok := typeIDs[strings.ToUpper(strings.TrimSpace(value))]
+		if v.Check(ok, fmt.Sprintf("%s[%d]", field, i), "not_found", fmt.Sprintf("type %s does not exist", value)) {
+			results = append(results, typeID)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+// MaxBulkItems is the most pokemons a single bulk request can have
+const MaxBulkItems = 500
+
+var (
+	ErrBulkEmpty    = apperror.New(apperror.Validation, "bulk_empty", "at least one pokemon is required")
+	ErrBulkTooLarge = apperror.Newf(apperror.Validation, "bulk_too_large", "at most %d pokemons can be sent at once", MaxBulkItems)
+	// ErrBulkVersionRequired fails an item without version, bulk has no If-Match header so every item brings its own version
+	ErrBulkVersionRequired = apperror.New(apperror.PreconditionRequired, "precondition_required", "version of the pokemon is required, fetch the pokemon and send its version")
+)
+
+// BulkCreatePokemon creates the pokemons, see bulk for how atomic changes the result
+func (pu *PokemonUsecase) BulkCreatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error) {
+	return pu.bulk(ctx, len(data), atomic, func(ctx context.Context, i int) (id int64, err error) {
+		return pu.CreatePokemon(ctx, data[i].Pokemon)
+	})
+}
+
+// BulkUpdatePokemon replaces the pokemons that are still at their version, see bulk for how atomic changes the result
+func (pu *PokemonUsecase) BulkUpdatePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error) {
+	return pu.bulk(ctx, len(data), atomic, func(ctx context.Context, i int) (id int64, err error) {
+		if data[i].Version <= 0 {
+			return data[i].ID, ErrBulkVersionRequired
+		}
+
+		_, err = pu.UpdatePokemon(ctx, data[i].ID, data[i].Version, data[i].Pokemon)
+		return data[i].ID, err
+	})
+}
+
+// BulkDeletePokemon moves the pokemons that are still at their version to the trash, see bulk for how atomic changes the result
+func (pu *PokemonUsecase) BulkDeletePokemon(ctx context.Context, data []entity.BulkPokemon, atomic bool) (results []entity.BulkResult, err error) {
+	return pu.bulk(ctx, len(data), atomic, func(ctx context.Context, i int) (id int64, err error) {
+		if data[i].Version <= 0 {
+			return data[i].ID, ErrBulkVersionRequired
+		}
+
+		return data[i].ID, pu.DeletePokemon(ctx, data[i].ID, data[i].Version)
+	})
+}
+
+// bulk calls fn for every item of the request. Atomic runs all items in a single transaction,
+// the first failing item rolls back all of them and is returned as the error.
+// Otherwise every item runs in its own transaction and failed items only have the error in their result
+func (pu *PokemonUsecase) bulk(ctx context.Context, count int, atomic bool, fn func(ctx context.Context, i int) (id int64, err error)) (results []entity.BulkResult, err error) {
+	if count == 0 {
+		return results, ErrBulkEmpty
+	}
+
+	if count > MaxBulkItems {
+		return results, ErrBulkTooLarge
+	}
+
+	results = make([]entity.BulkResult, count)
+	if atomic {
+		err = pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			for i := range results {
+				id, err := fn(ctx, i)
+				if err != nil {
+					return bulkItemError(i, err)
+				}
+
+				results[i] = entity.BulkResult{Index: i, ID: id}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return results, nil
+	}
+
+	for i := range results {
+		var id int64
+		itemErr := pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+			id, err = fn(ctx, i)
+			return err
+		})
+
+		results[i] = entity.BulkResult{Index: i}
+		if itemErr != nil {
+			results[i].Error = newBulkError(itemErr)
+			continue
+		}
+		results[i].ID = id
+	}
+
+	return results, nil
+}
+
+// bulkItemError reports which item failed the atomic bulk request, the fields of the item are prefixed with its index
+func bulkItemError(index int, err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return err
+	}
+
+	fields := []apperror.FieldError{{Field: fmt.Sprintf("[%d]", index), Code: appErr.Code, Message: appErr.Message}}
+	if len(appErr.Fields) > 0 {
+		fields = make([]apperror.FieldError, 0, len(appErr.Fields))
+		for _, field := range appErr.Fields {
+			field.Field = fmt.Sprintf("[%d].%s", index, field.Field)
+			fields = append(fields, field)
+		}
+	}
+
+	return &apperror.Error{
+		Kind:    appErr.Kind,
+		Code:    appErr.Code,
+		Message: fmt.Sprintf("pokemon at index %d: %s", index, appErr.Message),
+		Fields:  fields,
+		Err:     err,
+	}
+}
+
+// newBulkError returns the error of a failed item, internal errors are logged and only a generic message is returned
+func newBulkError(err error) *entity.BulkError {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		log.Printf("internal error: %v", err)
+		return &entity.BulkError{Code: "internal_error", Message: "internal server error"}
+	}
+
+	return &entity.BulkError{
+		Code:    appErr.Code,
+		Message: appErr.Message,
+		Errors:  appErr.Fields,
+	}
+}
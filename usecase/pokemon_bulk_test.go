@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+func withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestPokemonUsecase_BulkCreatePokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	invalid := entity.BulkPokemon{Pokemon: entity.Pokemon{Species: "pokemon"}}
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
+	}
+	type args struct {
+		ctx    context.Context
+		data   []entity.BulkPokemon
+		atomic bool
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.BulkResult
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success atomic",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{{Pokemon: entity.Pokemon{Name: "Bulbasour", Species: "pokemon"}}},
+				atomic: true,
+			},
+			wantResults: []entity.BulkResult{{Index: 0, ID: 1}},
+			wantErr:     false,
+			mock: func() {
+				// the bulk transaction and the one of the created pokemon, the real transactor lets it join the bulk transaction
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(2)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Bulbasour").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
+					Return(int64(1), nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed atomic invalid pokemon",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{invalid},
+				atomic: true,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+			},
+		},
+		{
+			name: "success best effort with failed item",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{invalid, {Pokemon: entity.Pokemon{Name: "Ivysaur", Species: "pokemon"}}},
+				atomic: false,
+			},
+			wantResults: []entity.BulkResult{
+				{Index: 0, Error: &entity.BulkError{
+					Code:    "validation_failed",
+					Message: "name can't be empty",
+					Errors:  []apperror.FieldError{{Field: "name", Code: "required", Message: "name can't be empty"}},
+				}},
+				{Index: 1, ID: 2},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(3)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Ivysaur").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
+					Return(int64(2), nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed empty",
+			fields: fields{
+				Transactor: prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   nil,
+				atomic: true,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+		{
+			name: "failed too large",
+			fields: fields{
+				Transactor: prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   make([]entity.BulkPokemon, MaxBulkItems+1),
+				atomic: true,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
+			}
+			gotResults, err := pu.BulkCreatePokemon(tt.args.ctx, tt.args.data, tt.args.atomic)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.BulkCreatePokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonUsecase.BulkCreatePokemon() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_BulkDeletePokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		Transactor                transaction.TransactorItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+	}
+	type args struct {
+		ctx    context.Context
+		data   []entity.BulkPokemon
+		atomic bool
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.BulkResult
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success best effort",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{{Pokemon: entity.Pokemon{ID: 1}, Version: 1}, {Pokemon: entity.Pokemon{ID: 2}, Version: 1}},
+				atomic: false,
+			},
+			wantResults: []entity.BulkResult{
+				{Index: 0, ID: 1},
+				{Index: 1, Error: &entity.BulkError{Code: ErrPokemonNotFound.Code, Message: ErrPokemonNotFound.Message}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(3)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1, Metadata: "{}", Version: 1}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{}, nil).Times(1)
+
+				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, int64(1), int64(1)).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed atomic",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{{Pokemon: entity.Pokemon{ID: 3}, Version: 1}},
+				atomic: true,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(3)).
+					Return(entity.PokemonDB{ID: 3, Metadata: "{}", Version: 2}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed best effort version required",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{{Pokemon: entity.Pokemon{ID: 4}}},
+				atomic: false,
+			},
+			wantResults: []entity.BulkResult{
+				{Index: 0, ID: 0, Error: &entity.BulkError{Code: "precondition_required", Message: ErrBulkVersionRequired.Message}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+			},
+		},
+		{
+			name: "failed atomic version required",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.BulkPokemon{{Pokemon: entity.Pokemon{ID: 5}}},
+				atomic: true,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				Transactor:                tt.fields.Transactor,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+			}
+			gotResults, err := pu.BulkDeletePokemon(tt.args.ctx, tt.args.data, tt.args.atomic)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.BulkDeletePokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonUsecase.BulkDeletePokemon() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func Test_bulkItemError(t *testing.T) {
+	errDB := errors.New("errors")
+
+	type args struct {
+		index int
+		err   error
+	}
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{
+			name: "success with fields",
+			args: args{
+				index: 2,
+				err:   &apperror.Error{Kind: apperror.Validation, Code: "validation_failed", Message: "name can't be empty", Fields: []apperror.FieldError{{Field: "name", Code: "required", Message: "name can't be empty"}}},
+			},
+			want: &apperror.Error{
+				Kind:    apperror.Validation,
+				Code:    "validation_failed",
+				Message: "pokemon at index 2: name can't be empty",
+				Fields:  []apperror.FieldError{{Field: "[2].name", Code: "required", Message: "name can't be empty"}},
+				Err:     &apperror.Error{Kind: apperror.Validation, Code: "validation_failed", Message: "name can't be empty", Fields: []apperror.FieldError{{Field: "name", Code: "required", Message: "name can't be empty"}}},
+			},
+		},
+		{
+			name: "success without fields",
+			args: args{
+				index: 0,
+				err:   ErrPokemonNotFound,
+			},
+			want: &apperror.Error{
+				Kind:    ErrPokemonNotFound.Kind,
+				Code:    ErrPokemonNotFound.Code,
+				Message: "pokemon at index 0: " + ErrPokemonNotFound.Message,
+				Fields:  []apperror.FieldError{{Field: "[0]", Code: ErrPokemonNotFound.Code, Message: ErrPokemonNotFound.Message}},
+				Err:     ErrPokemonNotFound,
+			},
+		},
+		{
+			name: "success internal error",
+			args: args{
+				index: 1,
+				err:   errDB,
+			},
+			want: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bulkItemError(tt.args.index, tt.args.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bulkItemError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/validation"
+)
+
+// MaxImportRows is the most pokemons a single import can have
+const MaxImportRows = 5000
+
+const (
+	ImportCreate    = "create"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+)
+
+var (
+	ErrImportEmpty    = apperror.New(apperror.Validation, "import_empty", "at least one pokemon is required")
+	ErrImportTooLarge = apperror.Newf(apperror.Validation, "import_too_large", "at most %d pokemons can be imported at once", MaxImportRows)
+	// errDryRun rolls back the transaction of the row once the row is saved
+	errDryRun = errors.New("dry run")
+)
+
+// ExportPokemon calls fn with every pokemon that is not in the trash, the pokemons are built one at a time so they can be streamed
+func (pu *PokemonUsecase) ExportPokemon(ctx context.Context, fn func(data entity.PokemonDetail) error) (err error) {
+	pokemons, err := pu.PokemonRepository.GetAllPokemonDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, pokemon := range pokemons {
+		detail, err := pu.buildResponsePokemonDetail(ctx, pokemon)
+		if err != nil {
+			return err
+		}
+
+		err = fn(*detail)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportPokemon upserts the pokemons by name, see importPokemon for how the id is used. The types of the rows are type names.
+// Every row is saved in its own transaction so a failed row doesn't stop the others, on dry run every transaction is rolled back
+func (pu *PokemonUsecase) ImportPokemon(ctx context.Context, data []entity.ImportRow, dryRun bool) (report entity.ImportReport, err error) {
+	if len(data) == 0 {
+		return report, ErrImportEmpty
+	}
+
+	if len(data) > MaxImportRows {
+		return report, ErrImportTooLarge
+	}
+
+	types, err := pu.TypesRepository.GetAllTypeDB(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	typeIDs := make(map[string]int64, len(types))
+	for _, t := range types {
+		typeIDs[strings.ToUpper(t.Name)] = t.ID
+	}
+
+	report = entity.ImportReport{DryRun: dryRun, Rows: make([]entity.ImportResult, 0, len(data))}
+	for i, row := range data {
+		result := entity.ImportResult{Row: i + 1, Name: row.Name}
+		if row.Err != nil {
+			report.Rows = append(report.Rows, entity.ImportResult{Row: result.Row, Name: result.Name, Error: newBulkError(row.Err)})
+			report.Failed++
+			continue
+		}
+
+		err := pu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+			result.Action, result.ID, err = pu.importPokemon(ctx, row.PokemonDetail, typeIDs)
+			if err == nil && dryRun {
+				return errDryRun
+			}
+
+			return err
+		})
+		if err != nil && err != errDryRun {
+			report.Rows = append(report.Rows, entity.ImportResult{Row: result.Row, Name: result.Name, Error: newBulkError(err)})
+			report.Failed++
+			continue
+		}
+
+		switch result.Action {
+		case ImportCreate:
+			report.Created++
+			// the id of the rolled back pokemon is never used
+			if dryRun {
+				result.ID = 0
+			}
+		case ImportUpdate:
+			report.Updated++
+		case ImportUnchanged:
+			report.Unchanged++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// importPokemon saves the row and returns what was done with it, the stored pokemon is not updated when the row has no change.
+// The row matches the pokemon of its id so a renamed pokemon is updated, a row without a known id matches the pokemon of its name.
+// The pokemon is created when nothing matches
+func (pu *PokemonUsecase) importPokemon(ctx context.Context, row entity.PokemonDetail, typeIDs map[string]int64) (action string, id int64, err error) {
+	pokemon, err := buildPokemonFromImport(row, typeIDs)
+	if err != nil {
+		return action, id, err
+	}
+
+	current, err := pu.matchImportPokemon(ctx, row)
+	if err == sql.ErrNoRows {
+		pokemon.ID = 0
+		id, err = pu.CreatePokemon(ctx, pokemon)
+		return ImportCreate, id, err
+	}
+	if err != nil {
+		return action, id, err
+	}
+
+	stored, err := pu.buildPokemonFromDB(ctx, current)
+	if err != nil {
+		return action, id, err
+	}
+
+	pokemon.ID = current.ID
+	if reflect.DeepEqual(stored, pokemon) {
+		return ImportUnchanged, current.ID, nil
+	}
+
+	_, err = pu.UpdatePokemon(ctx, current.ID, current.Version, pokemon)
+	return ImportUpdate, current.ID, err
+}
+
+// matchImportPokemon returns the stored pokemon of the row, sql.ErrNoRows is returned when no pokemon has the id or the name of the row.
+// A name of a pokemon in the trash is reported instead of creating a duplicate or silently restoring it
+func (pu *PokemonUsecase) matchImportPokemon(ctx context.Context, row entity.PokemonDetail) (result entity.PokemonDB, err error) {
+	if row.ID > 0 {
+		result, err = pu.PokemonRepository.GetPokemonByIDDB(ctx, row.ID)
+		if err != sql.ErrNoRows {
+			return result, err
+		}
+	}
+
+	result, err = pu.PokemonRepository.GetPokemonByNameDB(ctx, strings.TrimSpace(row.Name))
+	if err == nil && result.DeletedAt != nil {
+		return result, apperror.Newf(apperror.Conflict, "pokemon_in_trash", "pokemon %s is in the trash, restore or purge it before importing it", result.Name)
+	}
+
+	return result, err
+}
+
+// buildPokemonFromImport is function to build the request body of the row, every type name must be a known type
+func buildPokemonFromImport(row entity.PokemonDetail, typeIDs map[string]int64) (result entity.Pokemon, err error) {
+	var v validation.Validator
+
+	types := make([]int64, 0, len(row.Types))
+	for i, name := range row.Types {
+		typeID, ok := typeIDs[strings.ToUpper(strings.TrimSpace(name))]
+		if v.Check(ok, fmt.Sprintf("types[%d]", i), "not_found", fmt.Sprintf("type %s does not exist", name)) {
+			types = append(types, typeID)
+		}
+	}
+
+	return entity.Pokemon{
+		ID:          row.ID,
+		Name:        row.Name,
+		Species:     row.Species,
+		Types:       types,
+		Catched:     row.Catched,
+		ImageURL:    row.ImageURL,
+		Description: row.Description,
+		Weight:      row.Weight,
+		Height:      row.Height,
+		Stats:       row.Stats,
+	}, v.Err()
+}
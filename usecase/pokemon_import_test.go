@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+func TestPokemonUsecase_ExportPokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	errWrite := errors.New("errors")
+
+	type fields struct {
+		PokemonRepository     pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		fn  func(data entity.PokemonDetail) error
+	}
+	var exported []entity.PokemonDetail
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		wantExported []entity.PokemonDetail
+		wantErr      error
+		mock         func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+			},
+			args: args{
+				ctx: ctx,
+				fn: func(data entity.PokemonDetail) error {
+					exported = append(exported, data)
+					return nil
+				},
+			},
+			wantExported: []entity.PokemonDetail{{ID: 1, Name: "Bulbasour", Species: "pokemon", Types: []string{"FIRE"}, Weight: 6.9, Version: 1, TypeVersions: []int64{0}}},
+			wantErr:      nil,
+			mock: func() {
+				prov.PokemonRepository.On("GetAllPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 1, Name: "Bulbasour", Species: "pokemon", Metadata: `{"weight":6.9}`, Version: 1}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed write",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+			},
+			args: args{
+				ctx: ctx,
+				fn: func(data entity.PokemonDetail) error {
+					return errWrite
+				},
+			},
+			wantExported: nil,
+			wantErr:      errWrite,
+			mock: func() {
+				prov.PokemonRepository.On("GetAllPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 2, Name: "Ivysaur", Species: "pokemon", Metadata: "{}", Version: 1}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(2)).
+					Return([]entity.PokemonType{}, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			exported = nil
+			pu := &PokemonUsecase{
+				PokemonRepository:     tt.fields.PokemonRepository,
+				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+			}
+			if err := pu.ExportPokemon(tt.args.ctx, tt.args.fn); err != tt.wantErr {
+				t.Errorf("PokemonUsecase.ExportPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(exported, tt.wantExported) {
+				t.Errorf("PokemonUsecase.ExportPokemon() exported = %v, want %v", exported, tt.wantExported)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_ImportPokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	deletedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository           typesrepository.TypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
+	}
+	type args struct {
+		ctx    context.Context
+		data   []entity.ImportRow
+		dryRun bool
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantReport entity.ImportReport
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success create",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Bulbasour", Species: "pokemon", Types: []string{"fire"}}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Created: 1,
+				Rows:    []entity.ImportResult{{Row: 1, Name: "Bulbasour", Action: ImportCreate, ID: 1}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(2)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(2)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Bulbasour").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(2)
+
+				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
+					Return(int64(1), nil).Times(1)
+
+				prov.PokemonTypeRepository.On("CreatePokemonTypeDB", mock.Anything, entity.PokemonType{PokemonID: 1, TypeID: 1}).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success unchanged by id",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				TypesRepository:       prov.TypesRepository,
+				Transactor:            prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{ID: 2, Name: "Ivysaur", Species: "pokemon", Types: []string{"WATER"}}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Unchanged: 1,
+				Rows:      []entity.ImportResult{{Row: 1, Name: "Ivysaur", Action: ImportUnchanged, ID: 2}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{ID: 2, Name: "Ivysaur", Species: "pokemon", Metadata: "{}", Version: 1}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(2)).
+					Return([]entity.PokemonType{{ID: 2, PokemonID: 2, TypeID: 2, Name: "WATER"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success unchanged by name when id is unknown",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				TypesRepository:       prov.TypesRepository,
+				Transactor:            prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{ID: 9, Name: "Ivysaur", Species: "pokemon", Types: []string{"WATER"}}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Unchanged: 1,
+				Rows:      []entity.ImportResult{{Row: 1, Name: "Ivysaur", Action: ImportUnchanged, ID: 2}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(9)).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Ivysaur").
+					Return(entity.PokemonDB{ID: 2, Name: "Ivysaur", Species: "pokemon", Metadata: "{}", Version: 1}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(2)).
+					Return([]entity.PokemonType{{ID: 2, PokemonID: 2, TypeID: 2, Name: "WATER"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success dry run update renamed pokemon by id",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{ID: 5, Name: "Pidgeotto", Species: "bird"}}},
+				dryRun: true,
+			},
+			wantReport: entity.ImportReport{
+				DryRun:  true,
+				Updated: 1,
+				Rows:    []entity.ImportResult{{Row: 1, Name: "Pidgeotto", Action: ImportUpdate, ID: 5}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(2)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(5)).
+					Return(entity.PokemonDB{ID: 5, Name: "Pidgey", Species: "bird", Metadata: "{}", Version: 1}, nil).Times(3)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(5)).
+					Return([]entity.PokemonType{}, nil).Times(3)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Pidgeotto").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(5), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success with row of pokemon in the trash",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Pidgey", Species: "bird"}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Failed: 1,
+				Rows: []entity.ImportResult{{Row: 1, Name: "Pidgey", Error: &entity.BulkError{
+					Code:    "pokemon_in_trash",
+					Message: "pokemon Pidgey is in the trash, restore or purge it before importing it",
+				}}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Pidgey").
+					Return(entity.PokemonDB{ID: 6, Name: "Pidgey", Species: "bird", Metadata: "{}", Version: 2, DeletedAt: &deletedAt}, nil).Times(1)
+			},
+		},
+		{
+			name: "success dry run update by name",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Charmander", Species: "lizard"}}},
+				dryRun: true,
+			},
+			wantReport: entity.ImportReport{
+				DryRun:  true,
+				Updated: 1,
+				Rows:    []entity.ImportResult{{Row: 1, Name: "Charmander", Action: ImportUpdate, ID: 4}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(2)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Charmander").
+					Return(entity.PokemonDB{ID: 4, Name: "Charmander", Species: "pokemon", Metadata: "{}", Version: 3}, nil).Times(2)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(4)).
+					Return([]entity.PokemonType{}, nil).Times(3)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(4)).
+					Return(entity.PokemonDB{ID: 4, Name: "Charmander", Species: "pokemon", Metadata: "{}", Version: 3}, nil).Times(2)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(4), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success with failed row",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Squirtle", Species: "pokemon", Types: []string{"SHADOW"}}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Failed: 1,
+				Rows: []entity.ImportResult{{Row: 1, Name: "Squirtle", Error: &entity.BulkError{
+					Code:    "validation_failed",
+					Message: "type SHADOW does not exist",
+					Errors:  []apperror.FieldError{{Field: "types[0]", Code: "not_found", Message: "type SHADOW does not exist"}},
+				}}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+			},
+		},
+		{
+			name: "success with undecoded row",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				data: []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Wartortle"}, Err: &apperror.Error{
+					Kind:    apperror.Validation,
+					Code:    "validation_failed",
+					Message: "weight must be a number",
+					Fields:  []apperror.FieldError{{Field: "weight", Code: "invalid", Message: "weight must be a number"}},
+				}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{
+				Failed: 1,
+				Rows: []entity.ImportResult{{Row: 1, Name: "Wartortle", Error: &entity.BulkError{
+					Code:    "validation_failed",
+					Message: "weight must be a number",
+					Errors:  []apperror.FieldError{{Field: "weight", Code: "invalid", Message: "weight must be a number"}},
+				}}},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get types",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				data:   []entity.ImportRow{{PokemonDetail: entity.PokemonDetail{Name: "Pikachu"}}},
+				dryRun: false,
+			},
+			wantReport: entity.ImportReport{},
+			wantErr:    true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(nil, errors.New("errors")).Times(1)
+			},
+		},
+		{
+			name:       "failed empty",
+			fields:     fields{},
+			args:       args{ctx: ctx},
+			wantReport: entity.ImportReport{},
+			wantErr:    true,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				TypesRepository:           tt.fields.TypesRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
+			}
+			gotReport, err := pu.ImportPokemon(tt.args.ctx, tt.args.data, tt.args.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.ImportPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotReport, tt.wantReport) {
+				t.Errorf("PokemonUsecase.ImportPokemon() = %v, want %v", gotReport, tt.wantReport)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/util"
+)
+
+var ErrRevisionNotFound = apperror.New(apperror.NotFound, "revision_not_found", "revision of the pokemon not found")
+
+// GetPokemonRevisions returns the revisions of the pokemon, the oldest first, each with the changes from the revision before it
+func (pu *PokemonUsecase) GetPokemonRevisions(ctx context.Context, id int64) (results []entity.PokemonRevision, err error) {
+	_, err = pu.getPokemonByID(ctx, id)
+	if err != nil {
+		return results, err
+	}
+
+	results, err = pu.PokemonRevisionRepository.GetPokemonRevisionsDB(ctx, id)
+	if err != nil {
+		return results, err
+	}
+
+	for i := 1; i < len(results); i++ {
+		results[i].Changes, err = diffPokemon(results[i-1].Pokemon, results[i].Pokemon)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// RestorePokemonRevision saves the pokemon as it was at the revision, the restore is an update so it becomes a new revision
+func (pu *PokemonUsecase) RestorePokemonRevision(ctx context.Context, id int64, revision int64, version int64) (result *entity.PokemonDetail, err error) {
+	_, err = pu.getPokemonByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	rev, err := pu.PokemonRevisionRepository.GetPokemonRevisionDB(ctx, id, revision)
+	if err == sql.ErrNoRows {
+		return result, ErrRevisionNotFound
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return pu.UpdatePokemon(ctx, id, version, rev.Pokemon)
+}
+
+// recordRevision stores the pokemon saved as the revision together with who saved it
+func (pu *PokemonUsecase) recordRevision(ctx context.Context, id int64, revision int64, data entity.Pokemon) (err error) {
+	actorID, _, actor := actorFromContext(ctx)
+
+	return pu.PokemonRevisionRepository.CreatePokemonRevisionDB(ctx, entity.PokemonRevision{
+		PokemonID: id,
+		Revision:  revision,
+		Pokemon:   data,
+		ActorID:   actorID,
+		Actor:     actor,
+	})
+}
+
+func diffPokemon(before entity.Pokemon, after entity.Pokemon) (results []entity.FieldChange, err error) {
+	b, err := json.Marshal(before)
+	if err != nil {
+		return results, err
+	}
+
+	a, err := json.Marshal(after)
+	if err != nil {
+		return results, err
+	}
+
+	changes, err := util.DiffJSON(b, a)
+	if err != nil {
+		return results, err
+	}
+
+	for _, change := range changes {
+		results = append(results, entity.FieldChange{
+			Field: change.Path,
+			From:  change.From,
+			To:    change.To,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+func TestPokemonUsecase_GetPokemonRevisions(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	first := entity.Pokemon{ID: 1, Name: "Bulbasour", Species: "pokemon", Types: []int64{1}}
+	second := entity.Pokemon{ID: 1, Name: "Ivysaur", Species: "pokemon", Types: []int64{1, 2}}
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonRevision
+		wantErr     error
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResults: []entity.PokemonRevision{
+				{PokemonID: 1, Revision: 1, Pokemon: first},
+				{PokemonID: 1, Revision: 2, Pokemon: second, Changes: []entity.FieldChange{
+					{Field: "name", From: "Bulbasour", To: "Ivysaur"},
+					{Field: "types", From: []interface{}{float64(1)}, To: []interface{}{float64(1), float64(2)}},
+				}},
+			},
+			wantErr: nil,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1}, nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("GetPokemonRevisionsDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonRevision{{PokemonID: 1, Revision: 1, Pokemon: first}, {PokemonID: 1, Revision: 2, Pokemon: second}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed pokemon not found",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantResults: nil,
+			wantErr:     ErrPokemonNotFound,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+			}
+			gotResults, err := pu.GetPokemonRevisions(tt.args.ctx, tt.args.id)
+			if err != tt.wantErr {
+				t.Errorf("PokemonUsecase.GetPokemonRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonUsecase.GetPokemonRevisions() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_RestorePokemonRevision(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	errRevision := errors.New("errors")
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository           typesrepository.TypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		revision int64
+		version  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult *entity.PokemonDetail
+		wantErr    error
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				revision: 1,
+				version:  2,
+			},
+			wantResult: &entity.PokemonDetail{
+				ID:           1,
+				Name:         "Bulbasour",
+				Species:      "pokemon",
+				Types:        []string{"FIRE"},
+				TypeVersions: []int64{0},
+				Version:      3,
+			},
+			wantErr: nil,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1, Name: "Ivysaur", Species: "pokemon", Metadata: "{}", Version: 2}, nil).Times(2)
+
+				prov.PokemonRevisionRepository.On("GetPokemonRevisionDB", mock.Anything, int64(1), int64(1)).
+					Return(entity.PokemonRevision{PokemonID: 1, Revision: 1, Pokemon: entity.Pokemon{ID: 1, Name: "Bulbasour", Species: "pokemon", Types: []int64{1}}}, nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Bulbasour").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}, nil).Times(2)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.MatchedBy(func(data entity.PokemonRevision) bool {
+					return data.Revision == 3 && data.Pokemon.Name == "Bulbasour"
+				})).Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Metadata: "{}", Version: 3}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed revision not found",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       2,
+				revision: 9,
+				version:  1,
+			},
+			wantResult: nil,
+			wantErr:    ErrRevisionNotFound,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{ID: 2, Metadata: "{}", Version: 1}, nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("GetPokemonRevisionDB", mock.Anything, int64(2), int64(9)).
+					Return(entity.PokemonRevision{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed get revision",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       3,
+				revision: 1,
+				version:  1,
+			},
+			wantResult: nil,
+			wantErr:    errRevision,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(3)).
+					Return(entity.PokemonDB{ID: 3, Metadata: "{}", Version: 1}, nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("GetPokemonRevisionDB", mock.Anything, int64(3), int64(1)).
+					Return(entity.PokemonRevision{}, errRevision).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				TypesRepository:           tt.fields.TypesRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
+			}
+			gotResult, err := pu.RestorePokemonRevision(tt.args.ctx, tt.args.id, tt.args.revision, tt.args.version)
+			if err != tt.wantErr {
+				t.Errorf("PokemonUsecase.RestorePokemonRevision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("PokemonUsecase.RestorePokemonRevision() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/search"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+// limits of the pokemons returned by search
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// limits of the suggestions returned by suggest
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 50
+)
+
+// boosts of the searchable fields, a name match ranks above the same match in species or description
+const (
+	searchNameBoost        = 3
+	searchSpeciesBoost     = 2
+	searchDescriptionBoost = 1
+)
+
+var (
+	ErrSearchQueryRequired   = apperror.New(apperror.Validation, "query_required", "search query can't be empty")
+	ErrSearchDisabled        = apperror.New(apperror.Internal, "search_disabled", "search is not enabled")
+	ErrSuggestPrefixRequired = apperror.New(apperror.Validation, "prefix_required", "suggest prefix can't be empty")
+	ErrSuggestDisabled       = apperror.New(apperror.Internal, "suggest_disabled", "suggestions are not enabled")
+)
+
+// SearchPokemon returns the pokemons best matching the query, limit 0 returns DefaultSearchLimit pokemons
+// and a limit above MaxSearchLimit returns MaxSearchLimit pokemons
+func (pu *PokemonUsecase) SearchPokemon(ctx context.Context, query string, limit int) (results []entity.PokemonSearchResult, err error) {
+	if pu.SearchIndex == nil {
+		return results, ErrSearchDisabled
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return results, ErrSearchQueryRequired
+	}
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	results = []entity.PokemonSearchResult{}
+	for _, hit := range pu.SearchIndex.Search(query, limit) {
+		pokemon, err := pu.getPokemonByID(ctx, hit.ID)
+		if err == ErrPokemonNotFound {
+			// the pokemon was deleted by another process since the index was built
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := pu.buildResponsePokemonList(ctx, []entity.PokemonDB{pokemon})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, entity.PokemonSearchResult{
+			PokemonList: list[0],
+			Score:       hit.Score,
+			Highlights:  hit.Highlights,
+		})
+	}
+
+	return results, nil
+}
+
+// SuggestPokemon returns the names and species starting with the prefix, any word of them can start with the prefix.
+// Suggestions come from memory only so they are fast enough to be called on every keystroke
+func (pu *PokemonUsecase) SuggestPokemon(ctx context.Context, prefix string, limit int) (results []entity.PokemonSuggestion, err error) {
+	if pu.Suggester == nil {
+		return results, ErrSuggestDisabled
+	}
+
+	if strings.TrimSpace(prefix) == "" {
+		return results, ErrSuggestPrefixRequired
+	}
+
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit {
+		limit = MaxSuggestLimit
+	}
+
+	results = []entity.PokemonSuggestion{}
+	for _, suggestion := range pu.Suggester.Suggest(prefix, limit) {
+		results = append(results, entity.PokemonSuggestion{
+			Text:  suggestion.Text,
+			Field: suggestion.Field,
+			IDs:   suggestion.IDs,
+		})
+	}
+
+	return results, nil
+}
+
+// ReindexPokemon builds the search index and the suggester again from the saved pokemons, so changes made by other processes
+// like the importer are found as well
+func (pu *PokemonUsecase) ReindexPokemon(ctx context.Context) (err error) {
+	if pu.SearchIndex == nil && pu.Suggester == nil {
+		return ErrSearchDisabled
+	}
+
+	pokemons, err := pu.PokemonRepository.GetAllPokemonDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]search.Document, 0, len(pokemons))
+	for _, pokemon := range pokemons {
+		var metadata metadata
+		err = json.Unmarshal([]byte(pokemon.Metadata), &metadata)
+		if err != nil {
+			return err
+		}
+
+		docs = append(docs, searchDocument(pokemon.ID, pokemon.Name, pokemon.Species, metadata.Description))
+	}
+
+	if pu.SearchIndex != nil {
+		pu.SearchIndex.Reset(docs)
+	}
+	if pu.Suggester != nil {
+		pu.Suggester.Reset(suggestDocuments(docs))
+	}
+
+	return nil
+}
+
+// indexPokemon adds the saved pokemon to the search index and the suggester once the transaction of ctx is committed
+func (pu *PokemonUsecase) indexPokemon(ctx context.Context, data entity.Pokemon) {
+	if pu.SearchIndex == nil && pu.Suggester == nil {
+		return
+	}
+
+	doc := searchDocument(data.ID, data.Name, data.Species, data.Description)
+	transaction.AfterCommit(ctx, func() {
+		if pu.SearchIndex != nil {
+			pu.SearchIndex.Index(doc)
+		}
+		if pu.Suggester != nil {
+			pu.Suggester.Index(suggestDocument(doc))
+		}
+	})
+}
+
+// unindexPokemon removes the deleted pokemon from the search index and the suggester once the transaction of ctx is committed
+func (pu *PokemonUsecase) unindexPokemon(ctx context.Context, id int64) {
+	if pu.SearchIndex == nil && pu.Suggester == nil {
+		return
+	}
+
+	transaction.AfterCommit(ctx, func() {
+		if pu.SearchIndex != nil {
+			pu.SearchIndex.Remove(id)
+		}
+		if pu.Suggester != nil {
+			pu.Suggester.Remove(id)
+		}
+	})
+}
+
+func searchDocument(id int64, name string, species string, description string) search.Document {
+	return search.Document{
+		ID: id,
+		Fields: []search.Field{
+			{Name: "name", Text: name, Boost: searchNameBoost},
+			{Name: "species", Text: species, Boost: searchSpeciesBoost},
+			{Name: "description", Text: description, Boost: searchDescriptionBoost},
+		},
+	}
+}
+
+// suggestDocument keeps the fields of the search document that are suggested, descriptions are too long to complete
+func suggestDocument(doc search.Document) search.Document {
+	fields := make([]search.Field, 0, len(doc.Fields))
+	for _, field := range doc.Fields {
+		if field.Name != "description" {
+			fields = append(fields, field)
+		}
+	}
+
+	return search.Document{ID: doc.ID, Fields: fields}
+}
+
+func suggestDocuments(docs []search.Document) []search.Document {
+	results := make([]search.Document, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, suggestDocument(doc))
+	}
+
+	return results
+}
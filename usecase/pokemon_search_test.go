@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	"github.com/winartodev/go-pokedex/search"
+)
+
+func newSearchIndex() search.Index {
+	index := search.NewMemoryIndex()
+	index.Reset([]search.Document{
+		searchDocument(1, "Bulbasaur", "Seed Pokemon", "A strange seed was planted on its back at birth."),
+		searchDocument(2, "Charmander", "Lizard Pokemon", "The flame on its tail shows its life force."),
+	})
+
+	return index
+}
+
+func TestPokemonUsecase_SearchPokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+
+	type fields struct {
+		PokemonRepository     pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		SearchIndex           search.Index
+	}
+	type args struct {
+		ctx   context.Context
+		query string
+		limit int
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonSearchResult
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				SearchIndex:           newSearchIndex(),
+			},
+			args: args{
+				ctx:   ctx,
+				query: "bulbsaur",
+			},
+			wantResults: []entity.PokemonSearchResult{{
+				PokemonList: entity.PokemonList{ID: 1, Name: "Bulbasaur", Species: "Seed Pokemon", Types: []string{"GRASS"}},
+				Highlights:  map[string]string{"name": "<em>Bulbasaur</em>"},
+			}},
+			wantErr: false,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasaur", Species: "Seed Pokemon", Metadata: "{}"}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, TypeID: 2, Name: "GRASS"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "success skip pokemon deleted since indexed",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				SearchIndex:           newSearchIndex(),
+			},
+			args: args{
+				ctx:   ctx,
+				query: "charmander",
+				limit: 1000,
+			},
+			wantResults: []entity.PokemonSearchResult{},
+			wantErr:     false,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed empty query",
+			fields: fields{
+				SearchIndex: newSearchIndex(),
+			},
+			args: args{
+				ctx:   ctx,
+				query: "  ",
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+		{
+			name:   "failed search disabled",
+			fields: fields{},
+			args: args{
+				ctx:   ctx,
+				query: "bulbasaur",
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+		{
+			name: "failed GetPokemonByIDDB",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				SearchIndex:           newSearchIndex(),
+			},
+			args: args{
+				ctx:   ctx,
+				query: "bulbasaur",
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:     tt.fields.PokemonRepository,
+				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				SearchIndex:           tt.fields.SearchIndex,
+			}
+
+			gotResults, err := pu.SearchPokemon(tt.args.ctx, tt.args.query, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.SearchPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// scores depend on the ranking of the index, only the pokemons and highlights are compared
+			for i := range gotResults {
+				gotResults[i].Score = 0
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonUsecase.SearchPokemon() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_SuggestPokemon(t *testing.T) {
+	ctx := context.Background()
+	suggester := search.NewTrie()
+	suggester.Reset([]search.Document{
+		suggestDocument(searchDocument(1, "Charmander", "Lizard Pokemon", "")),
+		suggestDocument(searchDocument(2, "Charmeleon", "Flame Pokemon", "It has a burning tail.")),
+	})
+
+	type fields struct {
+		Suggester search.Suggester
+	}
+	type args struct {
+		ctx    context.Context
+		prefix string
+		limit  int
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.PokemonSuggestion
+		wantErr     bool
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Suggester: suggester,
+			},
+			args: args{
+				ctx:    ctx,
+				prefix: "charm",
+				limit:  1000,
+			},
+			wantResults: []entity.PokemonSuggestion{
+				{Text: "Charmander", Field: "name", IDs: []int64{1}},
+				{Text: "Charmeleon", Field: "name", IDs: []int64{2}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success description is not suggested",
+			fields: fields{
+				Suggester: suggester,
+			},
+			args: args{
+				ctx:    ctx,
+				prefix: "burning",
+			},
+			wantResults: []entity.PokemonSuggestion{},
+			wantErr:     false,
+		},
+		{
+			name: "failed empty prefix",
+			fields: fields{
+				Suggester: suggester,
+			},
+			args: args{
+				ctx:    ctx,
+				prefix: "",
+			},
+			wantResults: nil,
+			wantErr:     true,
+		},
+		{
+			name:   "failed suggest disabled",
+			fields: fields{},
+			args: args{
+				ctx:    ctx,
+				prefix: "charm",
+			},
+			wantResults: nil,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				Suggester: tt.fields.Suggester,
+			}
+
+			gotResults, err := pu.SuggestPokemon(tt.args.ctx, tt.args.prefix, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.SuggestPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("PokemonUsecase.SuggestPokemon() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_ReindexPokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+
+	type fields struct {
+		PokemonRepository pokemonrepository.PokemonRepositoryItf
+		SearchIndex       search.Index
+		Suggester         search.Suggester
+	}
+	tests := []struct {
+		name            string
+		fields          fields
+		wantIDs         []int64
+		wantSuggestions []search.Suggestion
+		wantErr         bool
+		mock            func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				SearchIndex:       newSearchIndex(),
+				Suggester:         search.NewTrie(),
+			},
+			wantIDs:         []int64{3},
+			wantSuggestions: []search.Suggestion{{Text: "Squirtle", Field: "name", IDs: []int64{3}}},
+			wantErr:         false,
+			mock: func() {
+				prov.PokemonRepository.On("GetAllPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 3, Name: "Squirtle", Species: "Tiny Turtle Pokemon", Metadata: `{"description":"It shelters itself in its shell."}`}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed GetAllPokemonDB",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				SearchIndex:       newSearchIndex(),
+				Suggester:         search.NewTrie(),
+			},
+			wantIDs:         []int64{},
+			wantSuggestions: nil,
+			wantErr:         true,
+			mock: func() {
+				prov.PokemonRepository.On("GetAllPokemonDB", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository: tt.fields.PokemonRepository,
+				SearchIndex:       tt.fields.SearchIndex,
+				Suggester:         tt.fields.Suggester,
+			}
+
+			err := pu.ReindexPokemon(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.ReindexPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			gotIDs := []int64{}
+			for _, hit := range tt.fields.SearchIndex.Search("squirtle shell", 0) {
+				gotIDs = append(gotIDs, hit.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("PokemonUsecase.ReindexPokemon() indexed %v, want %v", gotIDs, tt.wantIDs)
+			}
+			if got := tt.fields.Suggester.Suggest("squ", 0); !reflect.DeepEqual(got, tt.wantSuggestions) {
+				t.Errorf("PokemonUsecase.ReindexPokemon() suggestions = %v, want %v", got, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_indexPokemon(t *testing.T) {
+	ctx := context.Background()
+	index := newSearchIndex()
+	suggester := search.NewTrie()
+	pu := &PokemonUsecase{SearchIndex: index, Suggester: suggester}
+
+	pu.indexPokemon(ctx, entity.Pokemon{ID: 2, Name: "Charmeleon", Species: "Flame Pokemon"})
+	if hits := index.Search("charmeleon", 0); len(hits) != 1 || hits[0].ID != 2 {
+		t.Errorf("PokemonUsecase.indexPokemon() hits = %v, want pokemon 2", hits)
+	}
+	if suggestions := suggester.Suggest("flame", 0); len(suggestions) != 1 {
+		t.Errorf("PokemonUsecase.indexPokemon() suggestions = %v, want Flame Pokemon", suggestions)
+	}
+
+	pu.unindexPokemon(ctx, 2)
+	if hits := index.Search("charmeleon", 0); len(hits) != 0 {
+		t.Errorf("PokemonUsecase.unindexPokemon() hits = %v, want none", hits)
+	}
+	if suggestions := suggester.Suggest("flame", 0); len(suggestions) != 0 {
+		t.Errorf("PokemonUsecase.unindexPokemon() suggestions = %v, want none", suggestions)
+	}
+}
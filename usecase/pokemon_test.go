@@ -5,32 +5,58 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/mock"
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
 	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
 	pokemonrepositorymock "github.com/winartodev/go-pokedex/repository/pokemon/mocks"
+	pokemonrevisionrepository "github.com/winartodev/go-pokedex/repository/pokemonrevision"
+	pokemonrevisionrepositorymock "github.com/winartodev/go-pokedex/repository/pokemonrevision/mocks"
 	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
 	pokemontyperepositorymock "github.com/winartodev/go-pokedex/repository/pokemontypes/mocks"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	typesrepositorymock "github.com/winartodev/go-pokedex/repository/types/mocks"
+	"github.com/winartodev/go-pokedex/transaction"
+	transactionmock "github.com/winartodev/go-pokedex/transaction/mocks"
 )
 
 type mockPokemonProvider struct {
-	PokemonRepository     *pokemonrepositorymock.PokemonRepositoryItf
-	PokemonTypeRepository *pokemontyperepositorymock.PokemonTypeRepositoryItf
+	PokemonRepository         *pokemonrepositorymock.PokemonRepositoryItf
+	PokemonTypeRepository     *pokemontyperepositorymock.PokemonTypeRepositoryItf
+	TypesRepository           *typesrepositorymock.TypeRepositoryItf
+	AuditRepository           *auditrepositorymock.AuditRepositoryItf
+	PokemonRevisionRepository *pokemonrevisionrepositorymock.PokemonRevisionRepositoryItf
+	Transactor                *transactionmock.TransactorItf
 }
 
 func pokemonProvider() mockPokemonProvider {
 	return mockPokemonProvider{
-		PokemonRepository:     new(pokemonrepositorymock.PokemonRepositoryItf),
-		PokemonTypeRepository: new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		PokemonRepository:         new(pokemonrepositorymock.PokemonRepositoryItf),
+		PokemonTypeRepository:     new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		TypesRepository:           new(typesrepositorymock.TypeRepositoryItf),
+		AuditRepository:           new(auditrepositorymock.AuditRepositoryItf),
+		PokemonRevisionRepository: new(pokemonrevisionrepositorymock.PokemonRevisionRepositoryItf),
+		Transactor:                new(transactionmock.TransactorItf),
 	}
 }
 
+var pokemonTypes = []entity.Type{{ID: 1, Name: "FIRE"}, {ID: 2, Name: "WATER"}, {ID: 3, Name: "ICE"}}
+
 func TestNewPokemonUsecase(t *testing.T) {
 	pokemonUsecase := PokemonUsecase{
-		PokemonRepository:     new(pokemonrepositorymock.PokemonRepositoryItf),
-		PokemonTypeRepository: new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		PokemonRepository:         new(pokemonrepositorymock.PokemonRepositoryItf),
+		PokemonTypeRepository:     new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		TypesRepository:           new(typesrepositorymock.TypeRepositoryItf),
+		AuditRepository:           new(auditrepositorymock.AuditRepositoryItf),
+		PokemonRevisionRepository: new(pokemonrevisionrepositorymock.PokemonRevisionRepositoryItf),
+		Transactor:                new(transactionmock.TransactorItf),
 	}
 
 	type args struct {
@@ -137,14 +163,17 @@ func TestPokemonUsecase_GetAllPokemon(t *testing.T) {
 func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 	ctx := context.Background()
 	prov := pokemonProvider()
+	// the types of pokedex.sql, bulbasaur is NORMAL and POISON and charmander is NORMAL and FIRE
+	types := []entity.Type{{ID: 1, Name: "NORMAL"}, {ID: 5, Name: "FIRE"}, {ID: 9, Name: "POISON"}}
 
 	type fields struct {
 		PokemonRepository     pokemonrepository.PokemonRepositoryItf
 		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository       typesrepository.TypeRepositoryItf
 	}
 	type args struct {
 		ctx    context.Context
-		filter map[string]string
+		filter entity.PokemonFilter
 	}
 	tests := []struct {
 		name        string
@@ -162,8 +191,8 @@ func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 			},
 			args: args{
 				ctx: ctx,
-				filter: map[string]string{
-					"name": "bulbasour",
+				filter: entity.PokemonFilter{
+					Expr: filter.Comparison{Field: "name", Op: filter.OpContains, Value: "bulbasour"},
 				},
 			},
 			wantResults: []entity.PokemonList{{ID: 1}},
@@ -176,6 +205,73 @@ func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 					Return([]entity.PokemonType{{ID: 1}}, nil).Times(1)
 			},
 		},
+		{
+			name: "success types by name or id",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				TypesRepository:       prov.TypesRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					TypeAll: []string{"normal", "5"},
+					TypeNot: []string{"Poison"},
+				},
+			},
+			wantResults: []entity.PokemonList{{ID: 3, Types: []string{"NORMAL", "FIRE"}}},
+			wantErr:     false,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(types, nil).Times(1)
+
+				prov.PokemonRepository.On("GetAllPokemonByFilterDB", mock.Anything, entity.PokemonFilterDB{TypeAll: []int64{1, 5}, TypeNot: []int64{9}}).
+					Return([]entity.PokemonDB{{ID: 3, Metadata: "{}"}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(3)).
+					Return([]entity.PokemonType{{TypeID: 1, Name: "NORMAL"}, {TypeID: 5, Name: "FIRE"}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed unknown type",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				TypesRepository:       prov.TypesRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					TypeAny: []string{"fire", "shadow"},
+				},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(types, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get types",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				TypesRepository:       prov.TypesRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					TypeAny: []string{"fire"},
+				},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
 		{
 			name: "failed",
 			fields: fields{
@@ -184,8 +280,8 @@ func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 			},
 			args: args{
 				ctx: ctx,
-				filter: map[string]string{
-					"name": "bulbasour",
+				filter: entity.PokemonFilter{
+					Expr: filter.Comparison{Field: "name", Op: filter.OpContains, Value: "bulbasour"},
 				},
 			},
 			wantResults: nil,
@@ -195,6 +291,56 @@ func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 					Return(nil, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed unknown filter field",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					Expr: filter.Comparison{Field: "color", Op: filter.OpEqual, Value: "red", Position: 1},
+				},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+		{
+			name: "failed invalid sort",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					SortBy:  "id; DROP TABLE pokemons",
+					OrderBy: "desc",
+				},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
+		{
+			name: "failed invalid order",
+			fields: fields{
+				PokemonRepository:     prov.PokemonRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+			},
+			args: args{
+				ctx: ctx,
+				filter: entity.PokemonFilter{
+					SortBy:  "id",
+					OrderBy: "sideways",
+				},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock:        func() {},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -203,6 +349,7 @@ func TestPokemonUsecase_GetAllPokemonByFilter(t *testing.T) {
 			pu := &PokemonUsecase{
 				PokemonRepository:     tt.fields.PokemonRepository,
 				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				TypesRepository:       tt.fields.TypesRepository,
 			}
 			gotResults, err := pu.GetAllPokemonByFilter(tt.args.ctx, tt.args.filter)
 			if (err != nil) != tt.wantErr {
@@ -228,8 +375,12 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 	}
 
 	type fields struct {
-		PokemonRepository     pokemonrepository.PokemonRepositoryItf
-		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository           typesrepository.TypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
 	}
 	type args struct {
 		ctx  context.Context
@@ -246,8 +397,12 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 		{
 			name: "success",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx:  ctx,
@@ -256,18 +411,117 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 			wantPokemonID: 1,
 			wantErr:       false,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
 					Return(int64(1), nil).Times(1)
 
 				prov.PokemonTypeRepository.On("CreatePokemonTypeDB", mock.Anything, mock.Anything).
 					Return(nil).Times(3)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed validation",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				data: entity.Pokemon{
+					Name:    "Bulbasour",
+					Species: "",
+					Types:   []int64{1, 1, 9},
+					Weight:  -1,
+				},
+			},
+			wantPokemonID: 0,
+			wantErr:       true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+			},
+		},
+		{
+			name: "failed name taken",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				data: data,
+			},
+			wantPokemonID: 0,
+			wantErr:       true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 2, Name: "Bulbasour"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed name taken by another request",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				data: data,
+			},
+			wantPokemonID: 0,
+			wantErr:       true,
+			mock: func() {
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
+					Return(int64(0), &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Bulbasour' for key 'uq_pokemons_name'"}).Times(1)
 			},
 		},
 		{
 			name: "failed create pokemonDB",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx:  ctx,
@@ -276,6 +530,15 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 			wantPokemonID: 0,
 			wantErr:       true,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
 					Return(int64(0), errors.New("errors")).Times(1)
 			},
@@ -283,8 +546,12 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 		{
 			name: "failed create pokemonDB",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx:  ctx,
@@ -293,6 +560,15 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 			wantPokemonID: 1,
 			wantErr:       true,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("CreatePokemonDB", mock.Anything, mock.Anything).
 					Return(int64(1), nil).Times(1)
 
@@ -306,8 +582,12 @@ func TestPokemonUsecase_CreatePokemon(t *testing.T) {
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			pu := &PokemonUsecase{
-				PokemonRepository:     tt.fields.PokemonRepository,
-				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				TypesRepository:           tt.fields.TypesRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
 			}
 
 			gotPokemonID, err := pu.CreatePokemon(tt.args.ctx, tt.args.data)
@@ -373,7 +653,7 @@ func TestPokemonUsecase_GetPokemonByID(t *testing.T) {
 				id:  1,
 			},
 			wantResult: nil,
-			wantErr:    false,
+			wantErr:    true,
 			mock: func() {
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
 					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
@@ -423,13 +703,18 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 	prov := pokemonProvider()
 
 	type fields struct {
-		PokemonRepository     pokemonrepository.PokemonRepositoryItf
-		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository           typesrepository.TypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
 	}
 	type args struct {
-		ctx  context.Context
-		id   int64
-		data entity.Pokemon
+		ctx     context.Context
+		id      int64
+		version int64
+		data    entity.Pokemon
 	}
 	tests := []struct {
 		name       string
@@ -442,8 +727,12 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 		{
 			name: "success update pokemon",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -455,13 +744,23 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 				},
 			},
 			wantResult: &entity.PokemonDetail{
-				ID:      1,
-				Name:    "Bulbasour",
-				Species: "pokemon",
-				Types:   []string{"FIRE"},
+				ID:           1,
+				Name:         "Bulbasour",
+				Species:      "pokemon",
+				Types:        []string{"FIRE"},
+				TypeVersions: []int64{0},
 			},
 			wantErr: false,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 
@@ -469,18 +768,28 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}, nil).Times(1)
 
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(1)
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(2)
 
 				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
 					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
 			},
 		},
 		// case when length request is greather than or equal length data pokemon type that obtained from database.
 		{
 			name: "success update pokemon type",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -492,13 +801,23 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 				},
 			},
 			wantResult: &entity.PokemonDetail{
-				ID:      1,
-				Name:    "Bulbasour",
-				Species: "pokemon",
-				Types:   []string{"WATER"},
+				ID:           1,
+				Name:         "Bulbasour",
+				Species:      "pokemon",
+				Types:        []string{"WATER"},
+				TypeVersions: []int64{0},
 			},
 			wantErr: false,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 
@@ -509,17 +828,27 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 					Return(nil).Times(1)
 
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(1)
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(2)
 
 				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
 					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 2, Name: "WATER"}}, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
 			},
 		},
 		{
 			name: "success update and create new pokemon type",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -531,13 +860,23 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 				},
 			},
 			wantResult: &entity.PokemonDetail{
-				ID:      1,
-				Name:    "Bulbasour",
-				Species: "pokemon",
-				Types:   []string{"WATER", "ICE"},
+				ID:           1,
+				Name:         "Bulbasour",
+				Species:      "pokemon",
+				Types:        []string{"WATER", "ICE"},
+				TypeVersions: []int64{0, 0},
 			},
 			wantErr: false,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 
@@ -551,18 +890,28 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 					Return(nil).Times(1)
 
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(1)
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(2)
 
 				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
 					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 2, Name: "WATER"}, {ID: 1, PokemonID: 1, TypeID: 3, Name: "ICE"}}, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
 			},
 		},
 		// case when length request less than length data pokemon type that obtained from database.
 		{
 			name: "success update and create new pokemon type",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -574,13 +923,23 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 				},
 			},
 			wantResult: &entity.PokemonDetail{
-				ID:      1,
-				Name:    "Bulbasour",
-				Species: "pokemon",
-				Types:   []string{"ICE"},
+				ID:           1,
+				Name:         "Bulbasour",
+				Species:      "pokemon",
+				Types:        []string{"ICE"},
+				TypeVersions: []int64{0},
 			},
 			wantErr: false,
 			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 
@@ -594,10 +953,43 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 					Return(nil).Times(1)
 
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(1)
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Catched: 0, Metadata: "{}"}, nil).Times(2)
 
 				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
 					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 3, Name: "ICE"}}, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed version mismatch",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 1,
+				data: entity.Pokemon{
+					Name:    "Bulbasour",
+					Species: "pokemon",
+					Types:   []int64{1},
+				},
+			},
+			wantResult: nil,
+			wantErr:    true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Species: "pokemon", Metadata: "{}", Version: 2}, nil).Times(1)
 			},
 		},
 	}
@@ -606,11 +998,15 @@ func TestPokemonUsecase_UpdatePokemon(t *testing.T) {
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			pu := &PokemonUsecase{
-				PokemonRepository:     tt.fields.PokemonRepository,
-				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				TypesRepository:           tt.fields.TypesRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
 			}
 
-			gotResult, err := pu.UpdatePokemon(tt.args.ctx, tt.args.id, tt.args.data)
+			gotResult, err := pu.UpdatePokemon(tt.args.ctx, tt.args.id, tt.args.version, tt.args.data)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PokemonUsecase.UpdatePokemon() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -627,12 +1023,16 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 	prov := pokemonProvider()
 
 	type fields struct {
-		PokemonRepository     pokemonrepository.PokemonRepositoryItf
-		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		Transactor                transaction.TransactorItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
 	}
 	type args struct {
-		ctx context.Context
-		id  int64
+		ctx     context.Context
+		id      int64
+		version int64
 	}
 	tests := []struct {
 		name    string
@@ -644,8 +1044,11 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 		{
 			name: "success delete pokemon",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -653,18 +1056,33 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, mock.Anything).
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 1, Metadata: "{}"}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1}}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 
-				prov.PokemonTypeRepository.On("DeletePokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
 					Return(nil).Times(1)
 			},
 		},
 		{
-			name: "failed delete pokemon",
+			name: "failed pokemon not found",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -672,15 +1090,18 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, mock.Anything).
-					Return(errors.New("error")).Times(1)
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
 			},
 		},
 		{
-			name: "failed delete type pokemon",
+			name: "failed delete pokemon",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -688,24 +1109,82 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, mock.Anything).
-					Return(nil).Times(1)
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 1, Metadata: "{}"}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1}}, nil).Times(1)
 
-				prov.PokemonTypeRepository.On("DeletePokemonTypeByPokemonIDDB", mock.Anything, mock.Anything).
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed version mismatch",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 1, Version: 2}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed changed while deleting",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(entity.PokemonDB{ID: 1, Metadata: "{}", Version: 2}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("DeletePokemonByIDDB", mock.Anything, int64(1), int64(2)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			pu := &PokemonUsecase{
-				PokemonRepository:     tt.fields.PokemonRepository,
-				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				Transactor:                tt.fields.Transactor,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
 			}
 
-			if err := pu.DeletePokemon(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			if err := pu.DeletePokemon(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
 				t.Errorf("PokemonUsecase.DeletePokemon() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -715,10 +1194,20 @@ func TestPokemonUsecase_DeletePokemon(t *testing.T) {
 func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 	ctx := context.Background()
 	prov := pokemonProvider()
+	stored := entity.PokemonDB{ID: 1, Name: "Bulbasour", Catched: 0, Metadata: "{}", Version: 1}
+	links := []entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}
+
+	catchAudit := mock.MatchedBy(func(data entity.AuditLog) bool {
+		return data.Action == AuditUpdate && data.Resource == AuditPokemon && data.ResourceID == 1 &&
+			strings.Contains(string(data.Before), `"catched":0`) && strings.Contains(string(data.After), `"catched":1`)
+	})
 
 	type fields struct {
-		PokemonRepository     pokemonrepository.PokemonRepositoryItf
-		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		Transactor                transaction.TransactorItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
 	}
 	type args struct {
 		ctx context.Context
@@ -734,8 +1223,11 @@ func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 		{
 			name: "success",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -744,17 +1236,32 @@ func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 			wantErr: false,
 			mock: func() {
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Catched: 0}, nil).Times(1)
+					Return(stored, nil).Times(1)
 
-				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, catchAudit).
 					Return(nil).Times(1)
 			},
 		},
 		{
 			name: "failed get pokemon by id",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -769,8 +1276,11 @@ func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 		{
 			name: "failed update pokemon",
 			fields: fields{
-				PokemonRepository:     prov.PokemonRepository,
-				PokemonTypeRepository: prov.PokemonTypeRepository,
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
 			},
 			args: args{
 				ctx: ctx,
@@ -779,20 +1289,63 @@ func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 			wantErr: true,
 			mock: func() {
 				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
-					Return(entity.PokemonDB{ID: 1, Name: "Bulbasour", Catched: 0}, nil).Times(1)
+					Return(stored, nil).Times(1)
 
-				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, mock.Anything, mock.Anything).
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(1), mock.Anything).
 					Return(errors.New("errors")).Times(1)
 			},
 		},
+		{
+			name: "failed create audit log",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, mock.Anything).
+					Return(stored, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, catchAudit).
+					Return(errors.New("error")).Times(1)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			pu := &PokemonUsecase{
-				PokemonRepository:     tt.fields.PokemonRepository,
-				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				Transactor:                tt.fields.Transactor,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
 			}
 
 			if err := pu.CatchPokemon(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
@@ -801,3 +1354,413 @@ func TestPokemonUsecase_CatchPokemon(t *testing.T) {
 		})
 	}
 }
+
+func TestPokemonUsecase_PatchPokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	stored := entity.PokemonDB{ID: 1, Name: "Bulbasaur", Species: "Seed Pokemon", Metadata: `{"weight":6.9,"stats":{"hp":45}}`, Version: 1}
+	links := []entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1, Name: "FIRE"}}
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		TypesRepository           typesrepository.TypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+		patch   []byte
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success only patched fields change",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 1,
+				patch:   []byte(`{"name":"Ivysaur","stats":{"attack":62}}`),
+			},
+			wantErr: false,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(stored, nil).Times(3)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(3)
+
+				prov.PokemonRepository.On("GetPokemonByNameDB", mock.Anything, "Ivysaur").
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+
+				prov.TypesRepository.On("GetAllTypeDB", mock.Anything).
+					Return(pokemonTypes, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonDB", mock.Anything, int64(1), entity.PokemonDB{
+					ID:       1,
+					Name:     "Ivysaur",
+					Species:  "Seed Pokemon",
+					Metadata: `{"image_url":"","description":"","weight":6.9,"height":0,"stats":{"hp":45,"attack":62,"def":0,"speed":0}}`,
+					Version:  1,
+				}).Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed pokemon not found",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				id:    2,
+				patch: []byte(`{"name":"Ivysaur"}`),
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(2)).
+					Return(entity.PokemonDB{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed patch is not valid json",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				id:    1,
+				patch: []byte(`{"name":`),
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(stored, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(1)
+			},
+		},
+		{
+			name: "failed patched field has wrong type",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				id:    1,
+				patch: []byte(`{"weight":"heavy"}`),
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(stored, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return(links, nil).Times(1)
+			},
+		},
+		{
+			name: "failed version mismatch",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				TypesRepository:           prov.TypesRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+				patch:   []byte(`{"name":"Ivysaur"}`),
+			},
+			wantErr: true,
+			mock: func() {
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(stored, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				TypesRepository:           tt.fields.TypesRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
+			}
+
+			_, err := pu.PatchPokemon(tt.args.ctx, tt.args.id, tt.args.version, tt.args.patch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PokemonUsecase.PatchPokemon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_RestorePokemon(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		Transactor                transaction.TransactorItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: nil,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("RestorePokemonDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByIDDB", mock.Anything, int64(1)).
+					Return(entity.PokemonDB{ID: 1, Name: "Pikachu", Metadata: "{}", Version: 3}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 1}}, nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.MatchedBy(func(data entity.PokemonRevision) bool {
+					return data.PokemonID == 1 && data.Revision == 3 && data.Pokemon.Name == "Pikachu"
+				})).Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed pokemon not in trash",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				AuditRepository:           prov.AuditRepository,
+				Transactor:                prov.Transactor,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantErr: ErrPokemonNotInTrash,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("RestorePokemonDB", mock.Anything, int64(2)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				Transactor:                tt.fields.Transactor,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+			}
+
+			if err := pu.RestorePokemon(tt.args.ctx, tt.args.id); err != tt.wantErr {
+				t.Errorf("PokemonUsecase.RestorePokemon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPokemonUsecase_DetachType(t *testing.T) {
+	ctx := context.Background()
+	prov := pokemonProvider()
+	deletedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		PokemonRepository         pokemonrepository.PokemonRepositoryItf
+		PokemonTypeRepository     pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository           auditrepository.AuditRepositoryItf
+		PokemonRevisionRepository pokemonrevisionrepository.PokemonRevisionRepositoryItf
+		Transactor                transaction.TransactorItf
+	}
+	type args struct {
+		ctx    context.Context
+		typeID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success pokemons in and out of the trash get a new version",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 2,
+			},
+			wantErr: nil,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByTypeIDDB", mock.Anything, int64(2)).
+					Return([]entity.PokemonDB{{ID: 1, Name: "Squirtle", Metadata: "{}", Version: 3}, {ID: 2, Name: "Lapras", Metadata: "{}", Version: 1, DeletedAt: &deletedAt}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(1)).
+					Return([]entity.PokemonType{{ID: 1, PokemonID: 1, TypeID: 2}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(2)).
+					Return([]entity.PokemonType{{ID: 2, PokemonID: 2, TypeID: 2}, {ID: 3, PokemonID: 2, TypeID: 3}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("DetachPokemonTypeByTypeIDDB", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonVersionDB", mock.Anything, int64(1), int64(3)).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.MatchedBy(func(data entity.PokemonRevision) bool {
+					return data.PokemonID == 1 && data.Revision == 4 && reflect.DeepEqual(data.Pokemon.Types, []int64{})
+				})).Return(nil).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonVersionDB", mock.Anything, int64(2), int64(1)).
+					Return(nil).Times(1)
+
+				prov.PokemonRevisionRepository.On("CreatePokemonRevisionDB", mock.Anything, mock.MatchedBy(func(data entity.PokemonRevision) bool {
+					return data.PokemonID == 2 && data.Revision == 2 && reflect.DeepEqual(data.Pokemon.Types, []int64{3})
+				})).Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.MatchedBy(func(data entity.AuditLog) bool {
+					return data.Action == AuditUpdate && data.Resource == AuditPokemon
+				})).Return(nil).Times(2)
+			},
+		},
+		{
+			name: "failed pokemon changed in the meantime",
+			fields: fields{
+				PokemonRepository:         prov.PokemonRepository,
+				PokemonTypeRepository:     prov.PokemonTypeRepository,
+				AuditRepository:           prov.AuditRepository,
+				PokemonRevisionRepository: prov.PokemonRevisionRepository,
+				Transactor:                prov.Transactor,
+			},
+			args: args{
+				ctx:    ctx,
+				typeID: 3,
+			},
+			wantErr: ErrVersionMismatch,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetPokemonByTypeIDDB", mock.Anything, int64(3)).
+					Return([]entity.PokemonDB{{ID: 3, Name: "Articuno", Metadata: "{}", Version: 1}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("GetPokemonTypeByPokemonIDDB", mock.Anything, int64(3)).
+					Return([]entity.PokemonType{{ID: 4, PokemonID: 3, TypeID: 3}}, nil).Times(1)
+
+				prov.PokemonTypeRepository.On("DetachPokemonTypeByTypeIDDB", mock.Anything, int64(3)).
+					Return(nil).Times(1)
+
+				prov.PokemonRepository.On("UpdatePokemonVersionDB", mock.Anything, int64(3), int64(1)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &PokemonUsecase{
+				PokemonRepository:         tt.fields.PokemonRepository,
+				PokemonTypeRepository:     tt.fields.PokemonTypeRepository,
+				AuditRepository:           tt.fields.AuditRepository,
+				PokemonRevisionRepository: tt.fields.PokemonRevisionRepository,
+				Transactor:                tt.fields.Transactor,
+			}
+
+			if err := pu.DetachType(tt.args.ctx, tt.args.typeID); err != tt.wantErr {
+				t.Errorf("PokemonUsecase.DetachType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
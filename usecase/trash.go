@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/transaction"
+)
+
+const (
+	TrashPokemon = "pokemon"
+	TrashType    = "type"
+)
+
+type TrashUsecase struct {
+	PokemonRepository pokemonrepository.PokemonRepositoryItf
+	TypesRepository   typesrepository.TypeRepositoryItf
+	AuditRepository   auditrepository.AuditRepositoryItf
+	Transactor        transaction.TransactorItf
+	// Retention is how long deleted items stay in the trash before they are purged
+	Retention time.Duration
+}
+
+type TrashUsecaseItf interface {
+	GetTrash(ctx context.Context) (results []entity.TrashItem, err error)
+	Purge(ctx context.Context, now time.Time) (err error)
+}
+
+func NewTrashUsecase(trashUsecase TrashUsecase) TrashUsecaseItf {
+	return &TrashUsecase{
+		PokemonRepository: trashUsecase.PokemonRepository,
+		TypesRepository:   trashUsecase.TypesRepository,
+		AuditRepository:   trashUsecase.AuditRepository,
+		Transactor:        trashUsecase.Transactor,
+		Retention:         trashUsecase.Retention,
+	}
+}
+
+// GetTrash returns deleted pokemons and types, the most recently deleted first
+func (tu *TrashUsecase) GetTrash(ctx context.Context) (results []entity.TrashItem, err error) {
+	pokemons, err := tu.PokemonRepository.GetDeletedPokemonDB(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	types, err := tu.TypesRepository.GetDeletedTypeDB(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	results = make([]entity.TrashItem, 0, len(pokemons)+len(types))
+	for _, pokemon := range pokemons {
+		results = append(results, tu.buildTrashItem(TrashPokemon, pokemon.ID, pokemon.Name, pokemon.DeletedAt))
+	}
+
+	for _, t := range types {
+		results = append(results, tu.buildTrashItem(TrashType, t.ID, t.Name, t.DeletedAt))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].DeletedAt.After(results[j].DeletedAt)
+	})
+
+	return results, nil
+}
+
+// Purge permanently deletes pokemons and types that have been in the trash longer than the retention,
+// every purged item is written to the audit log in the same transaction
+func (tu *TrashUsecase) Purge(ctx context.Context, now time.Time) (err error) {
+	before := now.Add(-tu.Retention)
+
+	return tu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		items, err := tu.GetTrash(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if !item.DeletedAt.Before(before) {
+				continue
+			}
+
+			err = recordAudit(ctx, tu.AuditRepository, AuditPurge, item.Kind, item.ID, item, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tu.PokemonRepository.PurgePokemonDB(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		return tu.TypesRepository.PurgeTypeDB(ctx, before)
+	})
+}
+
+func (tu *TrashUsecase) buildTrashItem(kind string, id int64, name string, deletedAt *time.Time) entity.TrashItem {
+	item := entity.TrashItem{
+		Kind: kind,
+		ID:   id,
+		Name: name,
+	}
+
+	if deletedAt != nil {
+		item.DeletedAt = *deletedAt
+		item.PurgeAt = deletedAt.Add(tu.Retention)
+	}
+
+	return item
+}
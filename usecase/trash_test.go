@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	pokemonrepositorymock "github.com/winartodev/go-pokedex/repository/pokemon/mocks"
+	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	typesrepositorymock "github.com/winartodev/go-pokedex/repository/types/mocks"
+	"github.com/winartodev/go-pokedex/transaction"
+	transactionmock "github.com/winartodev/go-pokedex/transaction/mocks"
+)
+
+type mockTrashProvider struct {
+	PokemonRepository *pokemonrepositorymock.PokemonRepositoryItf
+	TypesRepository   *typesrepositorymock.TypeRepositoryItf
+	AuditRepository   *auditrepositorymock.AuditRepositoryItf
+	Transactor        *transactionmock.TransactorItf
+}
+
+func trashProvider() mockTrashProvider {
+	return mockTrashProvider{
+		PokemonRepository: new(pokemonrepositorymock.PokemonRepositoryItf),
+		TypesRepository:   new(typesrepositorymock.TypeRepositoryItf),
+		AuditRepository:   new(auditrepositorymock.AuditRepositoryItf),
+		Transactor:        new(transactionmock.TransactorItf),
+	}
+}
+
+func TestNewTrashUsecase(t *testing.T) {
+	trashUsecase := TrashUsecase{
+		PokemonRepository: new(pokemonrepositorymock.PokemonRepositoryItf),
+		TypesRepository:   new(typesrepositorymock.TypeRepositoryItf),
+		Retention:         time.Hour,
+	}
+
+	type args struct {
+		trashUsecase TrashUsecase
+	}
+	tests := []struct {
+		name string
+		args args
+		want TrashUsecaseItf
+	}{
+		{
+			name: "success",
+			args: args{
+				trashUsecase: trashUsecase,
+			},
+			want: &trashUsecase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTrashUsecase(tt.args.trashUsecase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTrashUsecase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrashUsecase_GetTrash(t *testing.T) {
+	ctx := context.Background()
+	prov := trashProvider()
+	retention := 24 * time.Hour
+	older := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	type fields struct {
+		PokemonRepository pokemonrepository.PokemonRepositoryItf
+		TypesRepository   typesrepository.TypeRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.TrashItem
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success most recently deleted first",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: []entity.TrashItem{
+				{Kind: TrashType, ID: 5, Name: "FIRE", DeletedAt: newer, PurgeAt: newer.Add(retention)},
+				{Kind: TrashPokemon, ID: 1, Name: "Bulbasaur", DeletedAt: older, PurgeAt: older.Add(retention)},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.PokemonRepository.On("GetDeletedPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 1, Name: "Bulbasaur", DeletedAt: &older}}, nil).Times(1)
+
+				prov.TypesRepository.On("GetDeletedTypeDB", mock.Anything).
+					Return([]entity.Type{{ID: 5, Name: "FIRE", DeletedAt: &newer}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get deleted types",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.PokemonRepository.On("GetDeletedPokemonDB", mock.Anything).
+					Return(nil, nil).Times(1)
+
+				prov.TypesRepository.On("GetDeletedTypeDB", mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tu := &TrashUsecase{
+				PokemonRepository: tt.fields.PokemonRepository,
+				TypesRepository:   tt.fields.TypesRepository,
+				Retention:         retention,
+			}
+			gotResults, err := tu.GetTrash(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TrashUsecase.GetTrash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("TrashUsecase.GetTrash() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestTrashUsecase_Purge(t *testing.T) {
+	ctx := context.Background()
+	prov := trashProvider()
+	now := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2022, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		PokemonRepository pokemonrepository.PokemonRepositoryItf
+		TypesRepository   typesrepository.TypeRepositoryItf
+		AuditRepository   auditrepository.AuditRepositoryItf
+		Transactor        transaction.TransactorItf
+	}
+	type args struct {
+		ctx context.Context
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				now: now,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetDeletedPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 1, Name: "Pikachu", DeletedAt: &expired}, {ID: 2, Name: "Bulbasaur", DeletedAt: &recent}}, nil).Times(1)
+
+				prov.TypesRepository.On("GetDeletedTypeDB", mock.Anything).
+					Return([]entity.Type{{ID: 3, Name: "FIRE", DeletedAt: &expired}}, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.MatchedBy(func(data entity.AuditLog) bool {
+					return data.Action == AuditPurge && data.Resource == AuditPokemon && data.ResourceID == 1
+				})).Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.MatchedBy(func(data entity.AuditLog) bool {
+					return data.Action == AuditPurge && data.Resource == AuditType && data.ResourceID == 3
+				})).Return(nil).Times(1)
+
+				prov.PokemonRepository.On("PurgePokemonDB", mock.Anything, before).
+					Return(nil).Times(1)
+
+				prov.TypesRepository.On("PurgeTypeDB", mock.Anything, before).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed create audit",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				now: now,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetDeletedPokemonDB", mock.Anything).
+					Return([]entity.PokemonDB{{ID: 1, Name: "Pikachu", DeletedAt: &expired}}, nil).Times(1)
+
+				prov.TypesRepository.On("GetDeletedTypeDB", mock.Anything).
+					Return(nil, nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed purge pokemons",
+			fields: fields{
+				PokemonRepository: prov.PokemonRepository,
+				TypesRepository:   prov.TypesRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				now: now,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonRepository.On("GetDeletedPokemonDB", mock.Anything).
+					Return(nil, nil).Times(1)
+
+				prov.TypesRepository.On("GetDeletedTypeDB", mock.Anything).
+					Return(nil, nil).Times(1)
+
+				prov.PokemonRepository.On("PurgePokemonDB", mock.Anything, before).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tu := &TrashUsecase{
+				PokemonRepository: tt.fields.PokemonRepository,
+				TypesRepository:   tt.fields.TypesRepository,
+				Retention:         30 * 24 * time.Hour,
+				AuditRepository:   tt.fields.AuditRepository,
+				Transactor:        tt.fields.Transactor,
+			}
+			if err := tu.Purge(tt.args.ctx, tt.args.now); (err != nil) != tt.wantErr {
+				t.Errorf("TrashUsecase.Purge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,34 +2,69 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 
+	"github.com/winartodev/go-pokedex/apperror"
 	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
 	typesrepository "github.com/winartodev/go-pokedex/repository/types"
+	"github.com/winartodev/go-pokedex/transaction"
 )
 
 type TypeUsecase struct {
-	TypesRepository typesrepository.TypeRepositoryItf
+	TypesRepository       typesrepository.TypeRepositoryItf
+	PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+	AuditRepository       auditrepository.AuditRepositoryItf
+	Transactor            transaction.TransactorItf
+	// PokemonUsecase removes a deleted type from the pokemons that have it
+	PokemonUsecase PokemonUsecaseItf
 }
 
 type TypeUsecaseItf interface {
 	CreateType(ctx context.Context, data entity.Type) (id int64, err error)
 	GetAllType(ctx context.Context) (results []entity.Type, err error)
 	GeTypeByID(ctx context.Context, id int64) (result entity.Type, err error)
-	UpdateType(ctx context.Context, id int64, data entity.Type) (err error)
+	UpdateType(ctx context.Context, id int64, version int64, data entity.Type) (result entity.Type, err error)
+	PatchType(ctx context.Context, id int64, version int64, patch []byte) (result entity.Type, err error)
+	DeleteType(ctx context.Context, id int64, version int64, cascade bool) (err error)
+	RestoreType(ctx context.Context, id int64) (err error)
 }
 
+var (
+	ErrTypeNotFound   = apperror.New(apperror.NotFound, "type_not_found", "type not found")
+	ErrTypeNotInTrash = apperror.New(apperror.NotFound, "type_not_in_trash", "type is not in the trash")
+)
+
 func NewTypeUsecase(typeUsecase TypeUsecase) TypeUsecaseItf {
 	return &TypeUsecase{
-		TypesRepository: typeUsecase.TypesRepository,
+		TypesRepository:       typeUsecase.TypesRepository,
+		PokemonTypeRepository: typeUsecase.PokemonTypeRepository,
+		AuditRepository:       typeUsecase.AuditRepository,
+		Transactor:            typeUsecase.Transactor,
+		PokemonUsecase:        typeUsecase.PokemonUsecase,
 	}
 }
 
 func (tr *TypeUsecase) CreateType(ctx context.Context, data entity.Type) (id int64, err error) {
-	id, err = tr.TypesRepository.CreateTypeDB(ctx, data)
+	err = tr.validateType(ctx, 0, data)
 	if err != nil {
 		return id, err
 	}
 
+	err = tr.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		id, err = tr.TypesRepository.CreateTypeDB(ctx, data)
+		if isDuplicate(err) {
+			return typeNameTaken(data.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		data.ID = id
+		return recordAudit(ctx, tr.AuditRepository, AuditCreate, AuditType, id, nil, data)
+	})
+
 	return id, err
 }
 
@@ -44,6 +79,9 @@ func (tr *TypeUsecase) GetAllType(ctx context.Context) (results []entity.Type, e
 
 func (tr *TypeUsecase) GeTypeByID(ctx context.Context, id int64) (result entity.Type, err error) {
 	result, err = tr.TypesRepository.GeTypeByIDDB(ctx, id)
+	if err == sql.ErrNoRows {
+		return result, ErrTypeNotFound
+	}
 	if err != nil {
 		return result, err
 	}
@@ -51,11 +89,124 @@ func (tr *TypeUsecase) GeTypeByID(ctx context.Context, id int64) (result entity.
 	return result, err
 }
 
-func (tr *TypeUsecase) UpdateType(ctx context.Context, id int64, data entity.Type) (err error) {
-	err = tr.TypesRepository.UpdateTypeDB(ctx, id, data)
+// UpdateType replaces the type when it is still at the version, version 0 updates any version.
+// The type is returned with its new version
+func (tr *TypeUsecase) UpdateType(ctx context.Context, id int64, version int64, data entity.Type) (result entity.Type, err error) {
+	current, err := tr.GeTypeByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkVersion(current.Version, version)
+	if err != nil {
+		return result, err
+	}
+
+	err = tr.validateType(ctx, id, data)
+	if err != nil {
+		return result, err
+	}
+
+	err = tr.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		data.Version = current.Version
+		err = tr.TypesRepository.UpdateTypeDB(ctx, id, data)
+		if err == sql.ErrNoRows {
+			return ErrVersionMismatch
+		}
+		if isDuplicate(err) {
+			return typeNameTaken(data.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		data.ID = id
+		return recordAudit(ctx, tr.AuditRepository, AuditUpdate, AuditType, id, current, data)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	data.Version = current.Version + 1
+	return data, nil
+}
+
+// PatchType applies json merge patch to the type, fields missing from the patch keep their current value
+func (tr *TypeUsecase) PatchType(ctx context.Context, id int64, version int64, patch []byte) (result entity.Type, err error) {
+	current, err := tr.GeTypeByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkVersion(current.Version, version)
+	if err != nil {
+		return result, err
+	}
+
+	err = applyMergePatch(current, patch, &result)
+	if err != nil {
+		return result, err
+	}
+	result.ID = id
+
+	// the patch was applied to this version so it must not have changed in the meantime
+	return tr.UpdateType(ctx, id, current.Version, result)
+}
+
+// DeleteType moves the type to the trash when it is still at the version, version 0 deletes any version.
+// A type some pokemons have is only deleted with cascade, which removes the type from those pokemons
+func (tr *TypeUsecase) DeleteType(ctx context.Context, id int64, version int64, cascade bool) (err error) {
+	current, err := tr.GeTypeByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion(current.Version, version)
 	if err != nil {
 		return err
 	}
 
-	return err
+	// the type must not stay on pokemons once it is in the trash, it is deleted and removed from them together
+	return tr.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		count, err := tr.PokemonTypeRepository.CountPokemonTypeByTypeIDDB(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 && !cascade {
+			return apperror.Newf(apperror.Conflict, "type_in_use", "type %s is used by %d pokemons, including pokemons in the trash, delete it with cascade to remove it from them", current.Name, count)
+		}
+
+		err = tr.TypesRepository.DeleteTypeDB(ctx, id, current.Version)
+		if err == sql.ErrNoRows {
+			return ErrVersionMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			err = tr.PokemonUsecase.DetachType(ctx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, tr.AuditRepository, AuditDelete, AuditType, id, current, nil)
+	})
+}
+
+// RestoreType takes the type out of the trash, pokemons the type was removed from by cascade don't get it back
+func (tr *TypeUsecase) RestoreType(ctx context.Context, id int64) (err error) {
+	return tr.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		err = tr.TypesRepository.RestoreTypeDB(ctx, id)
+		if err == sql.ErrNoRows {
+			return ErrTypeNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tr.AuditRepository, AuditRestore, AuditType, id, nil, nil)
+	})
 }
@@ -2,29 +2,48 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/winartodev/go-pokedex/entity"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
+	pokemontyperepository "github.com/winartodev/go-pokedex/repository/pokemontypes"
+	pokemontyperepositorymock "github.com/winartodev/go-pokedex/repository/pokemontypes/mocks"
 	typesrepository "github.com/winartodev/go-pokedex/repository/types"
 	typesrepositorymock "github.com/winartodev/go-pokedex/repository/types/mocks"
+	"github.com/winartodev/go-pokedex/transaction"
+	transactionmock "github.com/winartodev/go-pokedex/transaction/mocks"
+	usecasemock "github.com/winartodev/go-pokedex/usecase/mocks"
 )
 
 type mockTypeProvider struct {
-	TypesRepository *typesrepositorymock.TypeRepositoryItf
+	TypesRepository       *typesrepositorymock.TypeRepositoryItf
+	PokemonTypeRepository *pokemontyperepositorymock.PokemonTypeRepositoryItf
+	AuditRepository       *auditrepositorymock.AuditRepositoryItf
+	Transactor            *transactionmock.TransactorItf
+	PokemonUsecase        *usecasemock.PokemonUsecaseItf
 }
 
 func typeProvider() mockTypeProvider {
 	return mockTypeProvider{
-		TypesRepository: new(typesrepositorymock.TypeRepositoryItf),
+		TypesRepository:       new(typesrepositorymock.TypeRepositoryItf),
+		PokemonTypeRepository: new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		AuditRepository:       new(auditrepositorymock.AuditRepositoryItf),
+		Transactor:            new(transactionmock.TransactorItf),
+		PokemonUsecase:        new(usecasemock.PokemonUsecaseItf),
 	}
 }
 
 func TestNewTypeUsecase(t *testing.T) {
 	typeRepository := TypeUsecase{
-		TypesRepository: new(typesrepositorymock.TypeRepositoryItf),
+		TypesRepository:       new(typesrepositorymock.TypeRepositoryItf),
+		PokemonTypeRepository: new(pokemontyperepositorymock.PokemonTypeRepositoryItf),
+		AuditRepository:       new(auditrepositorymock.AuditRepositoryItf),
+		Transactor:            new(transactionmock.TransactorItf),
 	}
 
 	type args struct {
@@ -58,6 +77,8 @@ func TestTypeUsecase_CreateType(t *testing.T) {
 
 	type fields struct {
 		TypesRepository typesrepository.TypeRepositoryItf
+		AuditRepository auditrepository.AuditRepositoryItf
+		Transactor      transaction.TransactorItf
 	}
 	type args struct {
 		ctx  context.Context
@@ -75,6 +96,8 @@ func TestTypeUsecase_CreateType(t *testing.T) {
 			name: "success",
 			fields: fields{
 				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -85,14 +108,25 @@ func TestTypeUsecase_CreateType(t *testing.T) {
 			wantId:  1,
 			wantErr: false,
 			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.TypesRepository.On("CreateTypeDB", mock.Anything, mock.Anything).
 					Return(int64(1), nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
 			},
 		},
 		{
 			name: "failed",
 			fields: fields{
 				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
 			},
 			args: args{
 				ctx: ctx,
@@ -103,10 +137,45 @@ func TestTypeUsecase_CreateType(t *testing.T) {
 			wantId:  0,
 			wantErr: true,
 			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.TypesRepository.On("CreateTypeDB", mock.Anything, mock.Anything).
 					Return(int64(0), errors.New("errors")).Times(1)
 			},
 		},
+		{
+			name: "failed create audit log",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				data: entity.Type{
+					Name: "FIRE",
+				},
+			},
+			wantId:  1,
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("CreateTypeDB", mock.Anything, mock.Anything).
+					Return(int64(1), nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(errors.New("errors")).Times(1)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -114,6 +183,8 @@ func TestTypeUsecase_CreateType(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tr := &TypeUsecase{
 				TypesRepository: tt.fields.TypesRepository,
+				AuditRepository: tt.fields.AuditRepository,
+				Transactor:      tt.fields.Transactor,
 			}
 
 			gotId, err := tr.CreateType(tt.args.ctx, tt.args.data)
@@ -271,39 +342,78 @@ func TestTypeUsecase_UpdateType(t *testing.T) {
 
 	type fields struct {
 		TypesRepository typesrepository.TypeRepositoryItf
+		AuditRepository auditrepository.AuditRepositoryItf
+		Transactor      transaction.TransactorItf
 	}
 	type args struct {
-		ctx  context.Context
-		id   int64
-		data entity.Type
+		ctx     context.Context
+		id      int64
+		version int64
+		data    entity.Type
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-		mock    func()
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.Type
+		wantErr    bool
+		mock       func()
 	}{
 		{
 			name: "success",
 			fields: fields{
 				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
 			},
 			args: args{
 				ctx:  ctx,
 				id:   1,
 				data: entity.Type{ID: 1, Name: "FIRE"},
 			},
-			wantErr: false,
+			wantResult: entity.Type{ID: 1, Name: "FIRE", Version: 3},
+			wantErr:    false,
 			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, mock.Anything).
+					Return(entity.Type{ID: 1, Name: "FIRE", Version: 2}, nil).Times(1)
+
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.TypesRepository.On("UpdateTypeDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed type not found",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.Type{ID: 1, Name: "FIRE"},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
 			},
 		},
 		{
 			name: "failed",
 			fields: fields{
 				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
 			},
 			args: args{
 				ctx:  ctx,
@@ -312,10 +422,70 @@ func TestTypeUsecase_UpdateType(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, mock.Anything).
+					Return(entity.Type{ID: 1, Name: "FIRE"}, nil).Times(1)
+
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, mock.Anything).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
 				prov.TypesRepository.On("UpdateTypeDB", mock.Anything, mock.Anything, mock.Anything).
 					Return(errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed version mismatch",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 1,
+				data: entity.Type{
+					Name: "ICE",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, mock.Anything).
+					Return(entity.Type{ID: 1, Name: "FIRE", Version: 2}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed changed while updating",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+				data: entity.Type{
+					Name: "ICE",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, mock.Anything).
+					Return(entity.Type{ID: 1, Name: "FIRE", Version: 2}, nil).Times(1)
+
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, "ICE").
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("UpdateTypeDB", mock.Anything, int64(1), entity.Type{Name: "ICE", Version: 2}).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
@@ -323,9 +493,402 @@ func TestTypeUsecase_UpdateType(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tr := &TypeUsecase{
 				TypesRepository: tt.fields.TypesRepository,
+				AuditRepository: tt.fields.AuditRepository,
+				Transactor:      tt.fields.Transactor,
 			}
-			if err := tr.UpdateType(tt.args.ctx, tt.args.id, tt.args.data); (err != nil) != tt.wantErr {
+			gotResult, err := tr.UpdateType(tt.args.ctx, tt.args.id, tt.args.version, tt.args.data)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("TypeUsecase.UpdateType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("TypeUsecase.UpdateType() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestTypeUsecase_PatchType(t *testing.T) {
+	ctx := context.Background()
+	prov := typeProvider()
+
+	type fields struct {
+		TypesRepository typesrepository.TypeRepositoryItf
+		AuditRepository auditrepository.AuditRepositoryItf
+		Transactor      transaction.TransactorItf
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+		patch   []byte
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.Type
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 3,
+				patch:   []byte(`{"name":"ICE"}`),
+			},
+			wantResult: entity.Type{ID: 1, Name: "ICE", Version: 4},
+			wantErr:    false,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(1)).
+					Return(entity.Type{ID: 1, Name: "FIRE", Version: 3}, nil).Times(2)
+
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, "ICE").
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("UpdateTypeDB", mock.Anything, int64(1), entity.Type{ID: 1, Name: "ICE", Version: 3}).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed type not found",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				id:    2,
+				patch: []byte(`{"name":"ICE"}`),
+			},
+			wantResult: entity.Type{},
+			wantErr:    true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(2)).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeUsecase{
+				TypesRepository: tt.fields.TypesRepository,
+				AuditRepository: tt.fields.AuditRepository,
+				Transactor:      tt.fields.Transactor,
+			}
+			gotResult, err := tr.PatchType(tt.args.ctx, tt.args.id, tt.args.version, tt.args.patch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TypeUsecase.PatchType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("TypeUsecase.PatchType() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestTypeUsecase_DeleteType(t *testing.T) {
+	ctx := context.Background()
+	prov := typeProvider()
+
+	type fields struct {
+		TypesRepository       typesrepository.TypeRepositoryItf
+		PokemonTypeRepository pokemontyperepository.PokemonTypeRepositoryItf
+		AuditRepository       auditrepository.AuditRepositoryItf
+		Transactor            transaction.TransactorItf
+		PokemonUsecase        PokemonUsecaseItf
+	}
+	type args struct {
+		ctx     context.Context
+		id      int64
+		version int64
+		cascade bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success type not in use",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      1,
+				version: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(1)).
+					Return(entity.Type{ID: 1, Name: "FIRE", Version: 2}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonTypeRepository.On("CountPokemonTypeByTypeIDDB", mock.Anything, int64(1)).
+					Return(int64(0), nil).Times(1)
+
+				prov.TypesRepository.On("DeleteTypeDB", mock.Anything, int64(1), int64(2)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success cascade removes type from pokemons",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      2,
+				version: 2,
+				cascade: true,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(2)).
+					Return(entity.Type{ID: 2, Name: "WATER", Version: 2}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonTypeRepository.On("CountPokemonTypeByTypeIDDB", mock.Anything, int64(2)).
+					Return(int64(3), nil).Times(1)
+
+				prov.TypesRepository.On("DeleteTypeDB", mock.Anything, int64(2), int64(2)).
+					Return(nil).Times(1)
+
+				prov.PokemonUsecase.On("DetachType", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed cascade keeps type when removing it from pokemons fails",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      6,
+				version: 2,
+				cascade: true,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(6)).
+					Return(entity.Type{ID: 6, Name: "ROCK", Version: 2}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonTypeRepository.On("CountPokemonTypeByTypeIDDB", mock.Anything, int64(6)).
+					Return(int64(2), nil).Times(1)
+
+				prov.TypesRepository.On("DeleteTypeDB", mock.Anything, int64(6), int64(2)).
+					Return(nil).Times(1)
+
+				prov.PokemonUsecase.On("DetachType", mock.Anything, int64(6)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed type in use",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      3,
+				version: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(3)).
+					Return(entity.Type{ID: 3, Name: "ICE", Version: 2}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.PokemonTypeRepository.On("CountPokemonTypeByTypeIDDB", mock.Anything, int64(3)).
+					Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "failed version mismatch",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      4,
+				version: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(4)).
+					Return(entity.Type{ID: 4, Name: "BUG", Version: 2}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed type not found",
+			fields: fields{
+				TypesRepository:       prov.TypesRepository,
+				PokemonTypeRepository: prov.PokemonTypeRepository,
+				AuditRepository:       prov.AuditRepository,
+				Transactor:            prov.Transactor,
+				PokemonUsecase:        prov.PokemonUsecase,
+			},
+			args: args{
+				ctx:     ctx,
+				id:      5,
+				version: 1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.TypesRepository.On("GeTypeByIDDB", mock.Anything, int64(5)).
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeUsecase{
+				TypesRepository:       tt.fields.TypesRepository,
+				PokemonTypeRepository: tt.fields.PokemonTypeRepository,
+				AuditRepository:       tt.fields.AuditRepository,
+				Transactor:            tt.fields.Transactor,
+				PokemonUsecase:        tt.fields.PokemonUsecase,
+			}
+			if err := tr.DeleteType(tt.args.ctx, tt.args.id, tt.args.version, tt.args.cascade); (err != nil) != tt.wantErr {
+				t.Errorf("TypeUsecase.DeleteType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeUsecase_RestoreType(t *testing.T) {
+	ctx := context.Background()
+	prov := typeProvider()
+
+	type fields struct {
+		TypesRepository typesrepository.TypeRepositoryItf
+		AuditRepository auditrepository.AuditRepositoryItf
+		Transactor      transaction.TransactorItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: nil,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("RestoreTypeDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed type not in trash",
+			fields: fields{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantErr: ErrTypeNotInTrash,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("RestoreTypeDB", mock.Anything, int64(2)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TypeUsecase{
+				TypesRepository: tt.fields.TypesRepository,
+				AuditRepository: tt.fields.AuditRepository,
+				Transactor:      tt.fields.Transactor,
+			}
+			if err := tr.RestoreType(tt.args.ctx, tt.args.id); err != tt.wantErr {
+				t.Errorf("TypeUsecase.RestoreType() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
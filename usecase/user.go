@@ -2,39 +2,1117 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
-	"errors"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
 	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/mailer"
 	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/oidc"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	recoverycoderepository "github.com/winartodev/go-pokedex/repository/recoverycode"
+	sessionrepository "github.com/winartodev/go-pokedex/repository/session"
 	userrepository "github.com/winartodev/go-pokedex/repository/user"
+	usertokenrepository "github.com/winartodev/go-pokedex/repository/usertoken"
+	"github.com/winartodev/go-pokedex/throttle"
+	"github.com/winartodev/go-pokedex/transaction"
 	"github.com/winartodev/go-pokedex/util"
+	"github.com/winartodev/go-pokedex/validation"
 )
 
 type UserUsecase struct {
-	UserRepository userrepository.UserRepositoryItf
+	UserRepository         userrepository.UserRepositoryItf
+	UserTokenRepository    usertokenrepository.UserTokenRepositoryItf
+	RecoveryCodeRepository recoverycoderepository.RecoveryCodeRepositoryItf
+	Mailer                 mailer.Mailer
+	TokenSecret            string
+	PublicURL              string
+	PasswordPolicy         util.PasswordPolicy
+	UsernameThrottle       *throttle.Throttle
+	IPThrottle             *throttle.Throttle
+	TOTPIssuer             string
+	RequireAdminTwoFactor  bool
+	OIDCProvider           oidc.ProviderItf
+	OIDCGroupRoles         map[string]enum.Role
+	OIDCDefaultRole        enum.Role
+	SessionRepository      sessionrepository.SessionRepositoryItf
+	AuditRepository        auditrepository.AuditRepositoryItf
+	Transactor             transaction.TransactorItf
 }
 
 type UserUsecaseItf interface {
-	Register(ctx context.Context, username string, email string, password string, role int64) (id int64, err error)
-	Login(ctx context.Context, username string, password string) (token string, err error)
+	Register(ctx context.Context, username string, email string, password string) (id int64, err error)
+	Login(ctx context.Context, username string, password string, client entity.Client) (result entity.LoginResult, err error)
+	LoginTwoFactor(ctx context.Context, challenge string, code string, client entity.Client) (result entity.LoginResult, err error)
+	CreateUser(ctx context.Context, username string, email string, password string, role int64) (id int64, err error)
+	UpdateUserRole(ctx context.Context, id int64, role int64) (err error)
+	GetProfile(ctx context.Context, id int64) (result entity.UserProfile, err error)
+	UpdateProfile(ctx context.Context, id int64, data entity.UpdateProfile) (result entity.UserProfile, err error)
+	ChangePassword(ctx context.Context, id int64, currentPassword string, newPassword string) (err error)
+	GetAllUsers(ctx context.Context, filter entity.UserFilter) (results []entity.UserProfile, err error)
+	SetUserDisabled(ctx context.Context, id int64, disabled bool) (err error)
+	DeleteUser(ctx context.Context, id int64) (err error)
+	SendVerificationEmail(ctx context.Context, id int64) (err error)
+	VerifyEmail(ctx context.Context, token string) (err error)
+	ForgotPassword(ctx context.Context, email string) (err error)
+	ResetPassword(ctx context.Context, token string, password string) (err error)
+	EnrollTOTP(ctx context.Context, id int64) (result entity.TOTPEnrollment, err error)
+	ConfirmTOTP(ctx context.Context, id int64, code string) (recoveryCodes []string, err error)
+	DisableTOTP(ctx context.Context, id int64, password string) (err error)
+	RegenerateRecoveryCodes(ctx context.Context, id int64, code string) (recoveryCodes []string, err error)
+	StartOIDCLogin(ctx context.Context) (result entity.OIDCLogin, err error)
+	FinishOIDCLogin(ctx context.Context, flow string, state string, code string, client entity.Client) (result entity.LoginResult, err error)
+	GetSessions(ctx context.Context, userID int64) (results []entity.Session, err error)
+	RevokeSession(ctx context.Context, userID int64, sessionID int64) (err error)
+	ValidateSession(ctx context.Context, claims *auth.JWTClaim) (err error)
+}
+
+const (
+	PasswordResetTokenTTL     = 1 * time.Hour
+	EmailVerificationTokenTTL = 24 * time.Hour
+	LoginChallengeTTL         = 5 * time.Minute
+	RecoveryCodeCount         = 10
+	OIDCFlowTTL               = 10 * time.Minute
+	SessionLastSeenInterval   = time.Minute
+)
+
+// maxUserAgentLength is the size of the user_agent column of sessions
+const maxUserAgentLength = 512
+
+// dummyPasswordHash is compared when the username does not exist so the response time does not reveal it
+const dummyPasswordHash = "$2a$14$WGg93OYF1QyTeGvdx1E5z.MKMqkXmHSl8voDZv6oDm1mVvLkyp2Ey"
+
+var (
+	ErrInvalidToken       = apperror.New(apperror.Unauthorized, "invalid_token", "token is not valid or has expired")
+	ErrInvalidCredentials = apperror.New(apperror.Unauthorized, "invalid_credentials", "username or password not valid")
+	ErrInvalidTwoFactor   = apperror.New(apperror.Unauthorized, "invalid_two_factor", "two factor code not valid")
+	ErrOIDCNotConfigured  = apperror.New(apperror.NotFound, "oidc_not_configured", "oidc login is not configured")
+	ErrInvalidOIDCLogin   = apperror.New(apperror.Unauthorized, "invalid_oidc_login", "oidc login is not valid or has expired")
+	ErrSessionRevoked     = apperror.New(apperror.Unauthorized, "session_revoked", "session has been revoked, please login again")
+	ErrAccountDisabled    = apperror.New(apperror.Forbidden, "account_disabled", "user account is disabled")
+	ErrRoleChanged        = apperror.New(apperror.Unauthorized, "role_changed", "role of the user has changed, please login again")
+	ErrUserNotFound       = apperror.New(apperror.NotFound, "user_not_found", "user not found")
+	ErrSessionNotFound    = apperror.New(apperror.NotFound, "session_not_found", "session not found")
+	ErrInvalidPassword    = apperror.New(apperror.Validation, "invalid_password", "password not valid")
+)
+
+// oidcFlow is the state of an oidc login kept by the browser between StartOIDCLogin and FinishOIDCLogin
+type oidcFlow struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	ExpiresAt    int64  `json:"expires_at"`
 }
 
 func NewUserUsecase(userUsecase UserUsecase) UserUsecaseItf {
 	return &UserUsecase{
-		userUsecase.UserRepository,
+		UserRepository:         userUsecase.UserRepository,
+		UserTokenRepository:    userUsecase.UserTokenRepository,
+		RecoveryCodeRepository: userUsecase.RecoveryCodeRepository,
+		Mailer:                 userUsecase.Mailer,
+		TokenSecret:            userUsecase.TokenSecret,
+		PublicURL:              userUsecase.PublicURL,
+		PasswordPolicy:         userUsecase.PasswordPolicy,
+		UsernameThrottle:       userUsecase.UsernameThrottle,
+		IPThrottle:             userUsecase.IPThrottle,
+		TOTPIssuer:             userUsecase.TOTPIssuer,
+		RequireAdminTwoFactor:  userUsecase.RequireAdminTwoFactor,
+		OIDCProvider:           userUsecase.OIDCProvider,
+		OIDCGroupRoles:         userUsecase.OIDCGroupRoles,
+		OIDCDefaultRole:        userUsecase.OIDCDefaultRole,
+		SessionRepository:      userUsecase.SessionRepository,
+		AuditRepository:        userUsecase.AuditRepository,
+		Transactor:             userUsecase.Transactor,
+	}
+}
+
+// Register creates a new account through public registration, it always has the user role
+func (uu *UserUsecase) Register(ctx context.Context, username string, email string, password string) (id int64, err error) {
+	id, err = uu.createUser(ctx, username, email, password, int64(enum.User))
+	if err != nil {
+		return id, err
+	}
+
+	// the account is usable without verified email, so a mail failure must not fail the registration
+	err = uu.SendVerificationEmail(ctx, id)
+	if err != nil {
+		log.Printf("failed to send verification email to user %d: %v", id, err)
+	}
+
+	return id, nil
+}
+
+// CreateUser creates a new account with the given role, it is only available to admins
+func (uu *UserUsecase) CreateUser(ctx context.Context, username string, email string, password string, role int64) (id int64, err error) {
+	if !enum.Role(role).IsValid() {
+		return id, apperror.Newf(apperror.Validation, "invalid_role", "role %d is not valid", role)
+	}
+
+	err = uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		id, err = uu.createUser(ctx, username, email, password, role)
+		if err != nil {
+			return err
+		}
+
+		return uu.auditRoleChange(ctx, entity.User{ID: id, Username: username, Email: email, Role: int64(enum.Public)}, role)
+	})
+	if err != nil {
+		return id, err
 	}
+
+	return id, nil
 }
 
-func (uu *UserUsecase) Register(ctx context.Context, username string, email string, password string, role int64) (id int64, err error) {
+// UpdateUserRole changes the role of an existing user and records who changed it
+func (uu *UserUsecase) UpdateUserRole(ctx context.Context, id int64, role int64) (err error) {
+	if !enum.Role(role).IsValid() {
+		return apperror.Newf(apperror.Validation, "invalid_role", "role %d is not valid", role)
+	}
+
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uu.getUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == role {
+			return nil
+		}
+
+		err = uu.UserRepository.UpdateUserRole(ctx, id, role)
+		if err != nil {
+			return err
+		}
+
+		return uu.auditRoleChange(ctx, user, role)
+	})
+}
+
+// Login returns jwt token of the user, failed attempts are throttled per username and per ip
+// and every credential failure returns ErrInvalidCredentials so it can't be used to find accounts.
+// Users with totp enabled get a challenge instead of the token that has to be passed to LoginTwoFactor
+func (uu *UserUsecase) Login(ctx context.Context, username string, password string, client entity.Client) (result entity.LoginResult, err error) {
+	usernameKey := strings.ToLower(username)
+
+	err = uu.UsernameThrottle.Check(usernameKey)
+	if err != nil {
+		return result, err
+	}
+
+	err = uu.IPThrottle.Check(client.IP)
+	if err != nil {
+		return result, err
+	}
+
 	user, err := uu.UserRepository.GetUserByUsername(ctx, username)
 	if err != nil && err != sql.ErrNoRows {
-		return id, err
+		return result, err
+	}
+
+	if err == sql.ErrNoRows {
+		util.CheckPasswordHash(password, dummyPasswordHash)
+		uu.UsernameThrottle.Fail(usernameKey)
+		uu.IPThrottle.Fail(client.IP)
+		return result, ErrInvalidCredentials
+	}
+
+	isValid := util.CheckPasswordHash(password, user.Password)
+	if !isValid {
+		uu.UsernameThrottle.Fail(usernameKey)
+		uu.IPThrottle.Fail(client.IP)
+		return result, ErrInvalidCredentials
+	}
+
+	if !user.TOTPEnabled {
+		uu.UsernameThrottle.Reset(usernameKey)
+	}
+
+	return uu.completeLogin(ctx, user, client)
+}
+
+// LoginTwoFactor exchanges the challenge of Login and a totp or recovery code for jwt token,
+// the challenge is single use so a wrong code requires the password again
+func (uu *UserUsecase) LoginTwoFactor(ctx context.Context, challenge string, code string, client entity.Client) (result entity.LoginResult, err error) {
+	err = uu.IPThrottle.Check(client.IP)
+	if err != nil {
+		return result, err
+	}
+
+	userToken, err := uu.consumeToken(ctx, challenge, enum.LoginChallenge)
+	if err != nil {
+		return result, err
+	}
+
+	user, err := uu.getUserByID(ctx, userToken.UserID)
+	if err != nil {
+		return result, err
+	}
+
+	usernameKey := strings.ToLower(user.Username)
+	if user.Disabled || !user.TOTPEnabled {
+		return result, ErrInvalidToken
+	}
+
+	isValid, err := uu.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return result, err
+	}
+
+	if !isValid {
+		uu.UsernameThrottle.Fail(usernameKey)
+		uu.IPThrottle.Fail(client.IP)
+		return result, ErrInvalidTwoFactor
+	}
+
+	uu.UsernameThrottle.Reset(usernameKey)
+
+	result.Token, err = uu.createSession(ctx, user, client)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// EnrollTOTP creates new totp secret for the user, it is not used for login until ConfirmTOTP
+func (uu *UserUsecase) EnrollTOTP(ctx context.Context, id int64) (result entity.TOTPEnrollment, err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	if user.TOTPEnabled {
+		return result, apperror.New(apperror.Conflict, "two_factor_enabled", "two factor authentication already enabled")
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return result, err
+	}
+
+	err = uu.UserRepository.UpdateUserTOTP(ctx, id, secret, false)
+	if err != nil {
+		return result, err
+	}
+
+	return entity.TOTPEnrollment{
+		Secret: secret,
+		URI:    util.TOTPURI(uu.TOTPIssuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables totp after the user proves the authenticator app generates valid codes, it returns the recovery codes
+func (uu *UserUsecase) ConfirmTOTP(ctx context.Context, id int64, code string) (recoveryCodes []string, err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return recoveryCodes, err
+	}
+
+	if user.TOTPEnabled {
+		return recoveryCodes, apperror.New(apperror.Conflict, "two_factor_enabled", "two factor authentication already enabled")
+	}
+
+	if user.TOTPSecret == "" {
+		return recoveryCodes, apperror.New(apperror.Conflict, "two_factor_not_enrolled", "two factor authentication is not enrolled")
+	}
+
+	isValid, err := uu.useTOTPCode(ctx, user, code)
+	if err != nil {
+		return recoveryCodes, err
+	}
+	if !isValid {
+		return recoveryCodes, ErrInvalidTwoFactor
+	}
+
+	recoveryCodes, err = uu.replaceRecoveryCodes(ctx, id)
+	if err != nil {
+		return recoveryCodes, err
+	}
+
+	err = uu.UserRepository.UpdateUserTOTP(ctx, id, user.TOTPSecret, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTOTP turns off totp and removes the recovery codes after verifying the password
+func (uu *UserUsecase) DisableTOTP(ctx context.Context, id int64, password string) (err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !util.CheckPasswordHash(password, user.Password) {
+		return ErrInvalidPassword
+	}
+
+	if uu.RequireAdminTwoFactor && enum.Role(user.Role) == enum.Admin {
+		return apperror.New(apperror.Forbidden, "two_factor_required", "two factor authentication is required for admin accounts")
+	}
+
+	err = uu.UserRepository.UpdateUserTOTP(ctx, id, "", false)
+	if err != nil {
+		return err
+	}
+
+	return uu.RecoveryCodeRepository.DeleteRecoveryCodesDB(ctx, id)
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user, it requires a valid totp code
+func (uu *UserUsecase) RegenerateRecoveryCodes(ctx context.Context, id int64, code string) (recoveryCodes []string, err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return recoveryCodes, err
+	}
+
+	if !user.TOTPEnabled {
+		return recoveryCodes, apperror.New(apperror.Conflict, "two_factor_not_enabled", "two factor authentication is not enabled")
+	}
+
+	isValid, err := uu.useTOTPCode(ctx, user, code)
+	if err != nil {
+		return recoveryCodes, err
+	}
+	if !isValid {
+		return recoveryCodes, ErrInvalidTwoFactor
+	}
+
+	return uu.replaceRecoveryCodes(ctx, id)
+}
+
+// StartOIDCLogin returns the url of the identity provider and the flow that has to be kept by the browser until the callback
+func (uu *UserUsecase) StartOIDCLogin(ctx context.Context) (result entity.OIDCLogin, err error) {
+	if uu.OIDCProvider == nil {
+		return result, ErrOIDCNotConfigured
+	}
+
+	flow := oidcFlow{ExpiresAt: time.Now().Add(OIDCFlowTTL).Unix()}
+	for _, value := range []*string{&flow.State, &flow.Nonce, &flow.CodeVerifier} {
+		*value, err = util.GenerateToken()
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.AuthURL, err = uu.OIDCProvider.AuthCodeURL(ctx, flow.State, flow.Nonce, oidc.CodeChallengeS256(flow.CodeVerifier))
+	if err != nil {
+		return result, err
+	}
+
+	result.Flow, err = uu.encodeOIDCFlow(flow)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// FinishOIDCLogin redeems the code of the identity provider, the user is found by the provider subject,
+// linked by verified email or provisioned, and gets the role mapped from the provider groups
+func (uu *UserUsecase) FinishOIDCLogin(ctx context.Context, flow string, state string, code string, client entity.Client) (result entity.LoginResult, err error) {
+	if uu.OIDCProvider == nil {
+		return result, ErrOIDCNotConfigured
+	}
+
+	oidcFlow, err := uu.decodeOIDCFlow(flow)
+	if err != nil {
+		return result, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state), []byte(oidcFlow.State)) != 1 {
+		return result, ErrInvalidOIDCLogin
+	}
+
+	claims, err := uu.OIDCProvider.Exchange(ctx, code, oidcFlow.CodeVerifier)
+	if err != nil {
+		return result, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(oidcFlow.Nonce)) != 1 {
+		return result, ErrInvalidOIDCLogin
+	}
+
+	role := uu.oidcRole(claims.Groups)
+	if !role.IsValid() {
+		return result, apperror.New(apperror.Forbidden, "login_not_allowed", "user is not allowed to login to pokedex")
+	}
+
+	// the account, its role and the audit of the role are saved together so no role change is left unaudited
+	var user entity.User
+	err = uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		user, err = uu.UserRepository.GetUserByIdentity(ctx, claims.Issuer, claims.Subject)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if err == sql.ErrNoRows {
+			user, err = uu.linkOIDCUser(ctx, claims, role)
+			if err != nil {
+				return err
+			}
+		}
+
+		if user.Role != int64(role) {
+			err = uu.UserRepository.UpdateUserRole(ctx, user.ID, int64(role))
+			if err != nil {
+				return err
+			}
+
+			err = uu.auditRoleChange(ctx, user, int64(role))
+			if err != nil {
+				return err
+			}
+			user.Role = int64(role)
+		}
+
+		if claims.EmailVerified && !user.EmailVerified && claims.Email == user.Email {
+			err = uu.UserRepository.VerifyUserEmail(ctx, user.ID)
+			if err != nil {
+				return err
+			}
+			user.EmailVerified = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	return uu.completeLogin(ctx, user, client)
+}
+
+// GetSessions returns the active sessions of the user, the session of the request is marked as current
+func (uu *UserUsecase) GetSessions(ctx context.Context, userID int64) (results []entity.Session, err error) {
+	results, err = uu.SessionRepository.GetActiveSessionsByUserIDDB(ctx, userID)
+	if err != nil {
+		return results, err
+	}
+
+	if claims, ok := auth.FromContext(ctx); ok {
+		for i := range results {
+			results[i].Current = results[i].ID == claims.SessionID
+		}
+	}
+
+	return results, err
+}
+
+// RevokeSession logs the user out of the session, its token is rejected from the next request
+func (uu *UserUsecase) RevokeSession(ctx context.Context, userID int64, sessionID int64) (err error) {
+	err = uu.SessionRepository.RevokeSessionDB(ctx, sessionID, userID)
+	if err == sql.ErrNoRows {
+		return ErrSessionNotFound
+	}
+
+	return err
+}
+
+// ValidateSession checks the session of the token is not revoked, its user is not disabled and still has the role
+// of the token so a changed role takes effect right away. It records the user is still active
+func (uu *UserUsecase) ValidateSession(ctx context.Context, claims *auth.JWTClaim) (err error) {
+	session, err := uu.SessionRepository.GetSessionByIDDB(ctx, claims.SessionID)
+	if err == sql.ErrNoRows {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if session.UserID != claims.ID || session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	// disabling a user revokes the sessions, the user is checked too so access ends even when revoking failed
+	user, err := uu.UserRepository.GetUserByID(ctx, session.UserID)
+	if err == sql.ErrNoRows {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if user.Disabled {
+		return ErrAccountDisabled
+	}
+
+	if int64(claims.Role) != user.Role {
+		return ErrRoleChanged
+	}
+
+	if time.Since(session.LastSeenAt) > SessionLastSeenInterval {
+		err = uu.SessionRepository.UpdateSessionLastSeenDB(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetProfile returns the profile of the user without the password
+func (uu *UserUsecase) GetProfile(ctx context.Context, id int64) (result entity.UserProfile, err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	return buildUserProfile(user), nil
+}
+
+// UpdateProfile changes the email and display name of the user, nil fields are left unchanged
+func (uu *UserUsecase) UpdateProfile(ctx context.Context, id int64, data entity.UpdateProfile) (result entity.UserProfile, err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return result, err
 	}
 
-	if user.Username == username {
-		err = fmt.Errorf("username %s already taken", username)
+	// emails are compared case insensitive like the email column
+	emailChanged := false
+	if data.Email != nil {
+		var v validation.Validator
+		if v.Required("email", *data.Email) && v.MaxLength("email", *data.Email, maxNameLength) {
+			v.Email("email", *data.Email)
+		}
+		if err = v.Err(); err != nil {
+			return result, err
+		}
+		emailChanged = !strings.EqualFold(user.Email, *data.Email)
+		user.Email = *data.Email
+	}
+
+	if data.DisplayName != nil {
+		user.DisplayName = *data.DisplayName
+	}
+
+	// tokens sent to the old email are revoked with the change so they can't verify the new one
+	err = uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uu.UserRepository.UpdateUserProfile(ctx, id, user.Email, user.DisplayName)
+		if err != nil || !emailChanged {
+			return err
+		}
+
+		return uu.UserTokenRepository.UseUserTokensDB(ctx, id, enum.EmailVerification.String())
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// the new email has to be verified again, the profile is already changed so a mail failure must not fail the update
+	if emailChanged {
+		user.EmailVerified = false
+		err = uu.sendVerificationEmail(ctx, user)
+		if err != nil {
+			log.Printf("failed to send verification email to user %d: %v", id, err)
+		}
+	}
+
+	return buildUserProfile(user), nil
+}
+
+// ChangePassword replaces the password of the user after verifying the current one,
+// every other session of the user is revoked so only the session changing the password stays logged in
+func (uu *UserUsecase) ChangePassword(ctx context.Context, id int64, currentPassword string, newPassword string) (err error) {
+	err = uu.PasswordPolicy.Validate(newPassword)
+	if err != nil {
+		return apperror.Wrap(apperror.Validation, "weak_password", err)
+	}
+
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !util.CheckPasswordHash(currentPassword, user.Password) {
+		return apperror.New(apperror.Validation, "invalid_password", "current password not valid")
+	}
+
+	passwordHash, err := util.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	var sessionID int64
+	if claims, ok := auth.FromContext(ctx); ok {
+		sessionID = claims.SessionID
+	}
+
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uu.UserRepository.UpdateUserPassword(ctx, id, passwordHash)
+		if err != nil {
+			return err
+		}
+
+		return uu.SessionRepository.RevokeOtherUserSessionsDB(ctx, id, sessionID)
+	})
+}
+
+// GetAllUsers returns users matching the filter, it is only available to admins
+func (uu *UserUsecase) GetAllUsers(ctx context.Context, filter entity.UserFilter) (results []entity.UserProfile, err error) {
+	users, err := uu.UserRepository.GetAllUsers(ctx, filter)
+	if err != nil {
+		return results, err
+	}
+
+	for _, user := range users {
+		results = append(results, buildUserProfile(user))
+	}
+
+	return results, nil
+}
+
+// SetUserDisabled disables or enables the account of a user, disabled users can not login
+func (uu *UserUsecase) SetUserDisabled(ctx context.Context, id int64, disabled bool) (err error) {
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uu.getUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = uu.UserRepository.UpdateUserDisabled(ctx, id, disabled)
+		if err != nil {
+			return err
+		}
+
+		// a disabled user is logged out everywhere, enabling the user doesn't bring the sessions back
+		if disabled {
+			err = uu.SessionRepository.RevokeUserSessionsDB(ctx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		after := buildUserProfile(user)
+		after.Disabled = disabled
+		return recordAudit(ctx, uu.AuditRepository, AuditUpdate, AuditUser, id, buildUserProfile(user), after)
+	})
+}
+
+// DeleteUser permanently removes the account of a user
+func (uu *UserUsecase) DeleteUser(ctx context.Context, id int64) (err error) {
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uu.getUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = uu.UserRepository.DeleteUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, uu.AuditRepository, AuditDelete, AuditUser, id, buildUserProfile(user), nil)
+	})
+}
+
+// SendVerificationEmail sends link to verify the email address of the user
+func (uu *UserUsecase) SendVerificationEmail(ctx context.Context, id int64) (err error) {
+	user, err := uu.getUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return apperror.New(apperror.Conflict, "email_already_verified", "email already verified")
+	}
+
+	return uu.sendVerificationEmail(ctx, user)
+}
+
+// sendVerificationEmail sends link to verify the email address of the user
+func (uu *UserUsecase) sendVerificationEmail(ctx context.Context, user entity.User) (err error) {
+	token, err := uu.issueToken(ctx, user, enum.EmailVerification, EmailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return uu.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your pokedex email",
+		Body:    fmt.Sprintf("Hi %s,\n\nverify your email by opening %s/verify-email?token=%s\n\nThe link expires in %s.", user.Username, uu.PublicURL, token, EmailVerificationTokenTTL),
+	})
+}
+
+// VerifyEmail marks the email of the token owner as verified, the token only verifies the email it was sent to
+func (uu *UserUsecase) VerifyEmail(ctx context.Context, token string) (err error) {
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userToken, err := uu.consumeToken(ctx, token, enum.EmailVerification)
+		if err != nil {
+			return err
+		}
+
+		user, err := uu.UserRepository.GetUserByID(ctx, userToken.UserID)
+		if err == sql.ErrNoRows {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+
+		// emails are compared case insensitive like the email column
+		if !strings.EqualFold(userToken.Email, user.Email) {
+			return ErrInvalidToken
+		}
+
+		return uu.UserRepository.VerifyUserEmail(ctx, user.ID)
+	})
+}
+
+// ForgotPassword sends password reset link, it does not report unknown emails so it can't be used to find accounts
+func (uu *UserUsecase) ForgotPassword(ctx context.Context, email string) (err error) {
+	user, err := uu.UserRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if user.Disabled {
+		return nil
+	}
+
+	token, err := uu.issueToken(ctx, user, enum.PasswordReset, PasswordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return uu.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your pokedex password",
+		Body:    fmt.Sprintf("Hi %s,\n\nuse this token to reset your password: %s\n\nThe token expires in %s. If you did not ask for it you can ignore this email.", user.Username, token, PasswordResetTokenTTL),
+	})
+}
+
+// ResetPassword replaces the password of the token owner. Whoever knew the old password could have a session
+// or another reset token, so they are revoked with the change
+func (uu *UserUsecase) ResetPassword(ctx context.Context, token string, password string) (err error) {
+	err = uu.PasswordPolicy.Validate(password)
+	if err != nil {
+		return apperror.Wrap(apperror.Validation, "weak_password", err)
+	}
+
+	return uu.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userToken, err := uu.consumeToken(ctx, token, enum.PasswordReset)
+		if err != nil {
+			return err
+		}
+
+		passwordHash, err := util.HashPassword(password)
+		if err != nil {
+			return err
+		}
+
+		err = uu.UserRepository.UpdateUserPassword(ctx, userToken.UserID, passwordHash)
+		if err != nil {
+			return err
+		}
+
+		err = uu.UserTokenRepository.UseUserTokensDB(ctx, userToken.UserID, enum.PasswordReset.String())
+		if err != nil {
+			return err
+		}
+
+		return uu.SessionRepository.RevokeUserSessionsDB(ctx, userToken.UserID)
+	})
+}
+
+// issueToken creates single use token for the user and their current email, only the signature of the token is stored
+func (uu *UserUsecase) issueToken(ctx context.Context, user entity.User, purpose enum.TokenPurpose, ttl time.Duration) (token string, err error) {
+	token, err = util.GenerateToken()
+	if err != nil {
+		return token, err
+	}
+
+	_, err = uu.UserTokenRepository.CreateUserTokenDB(ctx, entity.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose.String(),
+		TokenHash: util.SignToken(uu.TokenSecret, token),
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeToken validates token and marks it as used, it returns the stored token
+func (uu *UserUsecase) consumeToken(ctx context.Context, token string, purpose enum.TokenPurpose) (userToken entity.UserToken, err error) {
+	if token == "" {
+		return userToken, ErrInvalidToken
+	}
+
+	userToken, err = uu.UserTokenRepository.GetUserTokenByHashDB(ctx, util.SignToken(uu.TokenSecret, token), purpose.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return userToken, ErrInvalidToken
+		}
+		return userToken, err
+	}
+
+	if userToken.UsedAt.Valid || time.Now().After(userToken.ExpiresAt) {
+		return userToken, ErrInvalidToken
+	}
+
+	used, err := uu.UserTokenRepository.UseUserTokenDB(ctx, userToken.ID)
+	if err != nil {
+		return userToken, err
+	}
+
+	if !used {
+		return userToken, ErrInvalidToken
+	}
+
+	return userToken, nil
+}
+
+// completeLogin returns jwt token for the user whose first factor is verified, or a challenge when totp is enabled
+func (uu *UserUsecase) completeLogin(ctx context.Context, user entity.User, client entity.Client) (result entity.LoginResult, err error) {
+	if user.Disabled {
+		return result, ErrAccountDisabled
+	}
+
+	if user.TOTPEnabled {
+		result.Challenge, err = uu.issueToken(ctx, user, enum.LoginChallenge, LoginChallengeTTL)
+		if err != nil {
+			return result, err
+		}
+
+		return result, nil
+	}
+
+	result.Token, err = uu.createSession(ctx, user, client)
+	if err != nil {
+		return result, err
+	}
+
+	return result, err
+}
+
+// createSession records the login of the user from the client and returns the jwt token of the session
+func (uu *UserUsecase) createSession(ctx context.Context, user entity.User, client entity.Client) (token string, err error) {
+	// the column counts characters, cutting the runes keeps the last character whole
+	if runes := []rune(client.UserAgent); len(runes) > maxUserAgentLength {
+		client.UserAgent = string(runes[:maxUserAgentLength])
+	}
+
+	sessionID, err := uu.SessionRepository.CreateSessionDB(ctx, entity.Session{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(auth.TokenTTL),
+	})
+	if err != nil {
+		return token, err
+	}
+
+	return auth.GenerateJWT(user, sessionID)
+}
+
+// oidcRole returns the highest role mapped from the groups of the user, or the default role when no group is mapped
+func (uu *UserUsecase) oidcRole(groups []string) enum.Role {
+	role := enum.Public
+	for _, group := range groups {
+		if groupRole, ok := uu.OIDCGroupRoles[group]; ok && groupRole > role {
+			role = groupRole
+		}
+	}
+
+	if role == enum.Public {
+		return uu.OIDCDefaultRole
+	}
+
+	return role
+}
+
+// linkOIDCUser links the provider subject to the local account with the same verified email, or provisions a new account
+func (uu *UserUsecase) linkOIDCUser(ctx context.Context, claims oidc.Claims, role enum.Role) (user entity.User, err error) {
+	if claims.Email != "" && claims.EmailVerified {
+		user, err = uu.UserRepository.GetUserByEmail(ctx, claims.Email)
+		if err != nil && err != sql.ErrNoRows {
+			return user, err
+		}
+
+		if err == nil {
+			// an unverified local account could have been registered by someone else with this email
+			if !user.EmailVerified {
+				return user, apperror.New(apperror.Conflict, "account_exists", "an account with this email already exists, verify its email before login with sso")
+			}
+
+			err = uu.UserRepository.CreateUserIdentity(ctx, user.ID, claims.Issuer, claims.Subject)
+			if err != nil {
+				return user, err
+			}
+
+			return user, nil
+		}
+	}
+
+	username, err := uu.availableUsername(ctx, claims)
+	if err != nil {
+		return user, err
+	}
+
+	// provisioned accounts have no password, they can only login with sso until a password is reset
+	id, err := uu.UserRepository.CreateUser(ctx, username, claims.Email, "", int64(role))
+	if isDuplicate(err) {
+		return user, usernameTaken(username)
+	}
+	if err != nil {
+		return user, err
+	}
+
+	err = uu.auditRoleChange(ctx, entity.User{ID: id, Username: username, Email: claims.Email, Role: int64(enum.Public)}, int64(role))
+	if err != nil {
+		return user, err
+	}
+
+	if claims.Name != "" {
+		err = uu.UserRepository.UpdateUserProfile(ctx, id, claims.Email, claims.Name)
+		if err != nil {
+			return user, err
+		}
+	}
+
+	err = uu.UserRepository.CreateUserIdentity(ctx, id, claims.Issuer, claims.Subject)
+	if err != nil {
+		return user, err
+	}
+
+	return entity.User{
+		ID:          id,
+		Username:    username,
+		Email:       claims.Email,
+		Role:        int64(role),
+		DisplayName: claims.Name,
+	}, nil
+}
+
+// availableUsername returns the preferred username of the claims, with a number appended when it is already taken
+func (uu *UserUsecase) availableUsername(ctx context.Context, claims oidc.Claims) (username string, err error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	for i := 1; i <= 20; i++ {
+		username = base
+		if i > 1 {
+			username = fmt.Sprintf("%s%d", base, i)
+		}
+
+		_, err = uu.UserRepository.GetUserByUsername(ctx, username)
+		if err == sql.ErrNoRows {
+			return username, nil
+		}
+		if err != nil {
+			return username, err
+		}
+	}
+
+	return "", apperror.Newf(apperror.Conflict, "username_taken", "username %s already taken", base)
+}
+
+// encodeOIDCFlow signs the flow so it can be stored by the browser without being modified
+func (uu *UserUsecase) encodeOIDCFlow(flow oidcFlow) (value string, err error) {
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return value, err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + util.SignToken(uu.TokenSecret, payload), nil
+}
+
+// decodeOIDCFlow verifies the signature and expiry of the flow
+func (uu *UserUsecase) decodeOIDCFlow(value string) (flow oidcFlow, err error) {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 || !hmacEqual(parts[1], util.SignToken(uu.TokenSecret, parts[0])) {
+		return flow, ErrInvalidOIDCLogin
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return flow, ErrInvalidOIDCLogin
+	}
+
+	err = json.Unmarshal(data, &flow)
+	if err != nil || time.Now().Unix() > flow.ExpiresAt {
+		return flow, ErrInvalidOIDCLogin
+	}
+
+	return flow, nil
+}
+
+func hmacEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// verifySecondFactor checks the code as totp code first and then as single use recovery code
+func (uu *UserUsecase) verifySecondFactor(ctx context.Context, user entity.User, code string) (isValid bool, err error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return false, nil
+	}
+
+	isValid, err = uu.useTOTPCode(ctx, user, code)
+	if err != nil || isValid {
+		return isValid, err
+	}
+
+	return uu.RecoveryCodeRepository.UseRecoveryCodeDB(ctx, user.ID, util.SignToken(uu.TokenSecret, code))
+}
+
+// useTOTPCode checks the totp code and records its time step, a code is accepted only once and codes older than
+// the last accepted one are rejected so an observed code can't be replayed
+func (uu *UserUsecase) useTOTPCode(ctx context.Context, user entity.User, code string) (isValid bool, err error) {
+	step, isValid := util.ValidateTOTPCode(user.TOTPSecret, code, time.Now())
+	if !isValid {
+		return false, nil
+	}
+
+	return uu.UserRepository.UseTOTPStep(ctx, user.ID, step)
+}
+
+// replaceRecoveryCodes creates new recovery codes for the user, only their signatures are stored
+func (uu *UserUsecase) replaceRecoveryCodes(ctx context.Context, userID int64) (recoveryCodes []string, err error) {
+	codeHashes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		code, err := util.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+		codeHashes = append(codeHashes, util.SignToken(uu.TokenSecret, code))
+	}
+
+	err = uu.RecoveryCodeRepository.ReplaceRecoveryCodesDB(ctx, userID, codeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (uu *UserUsecase) createUser(ctx context.Context, username string, email string, password string, role int64) (id int64, err error) {
+	err = uu.validateNewUser(ctx, username, email, password)
+	if err != nil {
 		return id, err
 	}
 
@@ -45,6 +1123,9 @@ func (uu *UserUsecase) Register(ctx context.Context, username string, email stri
 	}
 
 	id, err = uu.UserRepository.CreateUser(ctx, username, email, passwordHash, role)
+	if isDuplicate(err) {
+		return id, usernameTaken(username)
+	}
 	if err != nil {
 		return id, err
 	}
@@ -52,21 +1133,47 @@ func (uu *UserUsecase) Register(ctx context.Context, username string, email stri
 	return id, nil
 }
 
-func (uu *UserUsecase) Login(ctx context.Context, username string, password string) (token string, err error) {
-	user, err := uu.UserRepository.GetUserByUsername(ctx, username)
-	if err != nil {
-		return token, err
+// getUserByID returns the user, ErrUserNotFound is returned when the user does not exist
+func (uu *UserUsecase) getUserByID(ctx context.Context, id int64) (user entity.User, err error) {
+	user, err = uu.UserRepository.GetUserByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return user, ErrUserNotFound
 	}
 
-	isValid := util.CheckPasswordHash(password, user.Password)
-	if !isValid {
-		return token, errors.New("username or password not valid")
+	return user, err
+}
+
+// auditRoleChange records the role change of a user together with the admin who made it, user holds the old role
+func (uu *UserUsecase) auditRoleChange(ctx context.Context, user entity.User, newRole int64) (err error) {
+	var actorID int64
+	if claims, ok := auth.FromContext(ctx); ok {
+		actorID = claims.ID
 	}
 
-	token, err = auth.GenerateJWT(user.Username, user.Email, enum.Role(user.Role))
+	err = uu.UserRepository.CreateUserRoleAudit(ctx, entity.UserRoleAudit{
+		UserID:  user.ID,
+		ActorID: actorID,
+		OldRole: user.Role,
+		NewRole: newRole,
+	})
 	if err != nil {
-		return token, err
+		return err
 	}
 
-	return token, err
+	after := buildUserProfile(user)
+	after.Role = newRole
+	return recordAudit(ctx, uu.AuditRepository, AuditUpdate, AuditUser, user.ID, buildUserProfile(user), after)
+}
+
+func buildUserProfile(user entity.User) entity.UserProfile {
+	return entity.UserProfile{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		DisplayName:   user.DisplayName,
+		Role:          user.Role,
+		Disabled:      user.Disabled,
+		EmailVerified: user.EmailVerified,
+		TOTPEnabled:   user.TOTPEnabled,
+	}
 }
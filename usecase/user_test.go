@@ -2,23 +2,59 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/enum"
+	"github.com/winartodev/go-pokedex/mailer"
+	mailermock "github.com/winartodev/go-pokedex/mailer/mocks"
+	"github.com/winartodev/go-pokedex/middleware/auth"
+	"github.com/winartodev/go-pokedex/oidc"
+	oidcmock "github.com/winartodev/go-pokedex/oidc/mocks"
+	"github.com/winartodev/go-pokedex/oidc/oidctest"
+	auditrepository "github.com/winartodev/go-pokedex/repository/audit"
+	auditrepositorymock "github.com/winartodev/go-pokedex/repository/audit/mocks"
+	recoverycoderepository "github.com/winartodev/go-pokedex/repository/recoverycode"
+	recoverycoderepositorymock "github.com/winartodev/go-pokedex/repository/recoverycode/mocks"
+	sessionrepository "github.com/winartodev/go-pokedex/repository/session"
+	sessionrepositorymock "github.com/winartodev/go-pokedex/repository/session/mocks"
 	userrepository "github.com/winartodev/go-pokedex/repository/user"
 	userrepositorymocks "github.com/winartodev/go-pokedex/repository/user/mocks"
+	usertokenrepository "github.com/winartodev/go-pokedex/repository/usertoken"
+	usertokenrepositorymock "github.com/winartodev/go-pokedex/repository/usertoken/mocks"
+	"github.com/winartodev/go-pokedex/throttle"
+	"github.com/winartodev/go-pokedex/transaction"
+	transactionmock "github.com/winartodev/go-pokedex/transaction/mocks"
+	"github.com/winartodev/go-pokedex/util"
 )
 
 type mockUserProvider struct {
-	UserRepository *userrepositorymocks.UserRepositoryItf
+	UserRepository         *userrepositorymocks.UserRepositoryItf
+	UserTokenRepository    *usertokenrepositorymock.UserTokenRepositoryItf
+	RecoveryCodeRepository *recoverycoderepositorymock.RecoveryCodeRepositoryItf
+	Mailer                 *mailermock.Mailer
+	OIDCProvider           *oidcmock.ProviderItf
+	SessionRepository      *sessionrepositorymock.SessionRepositoryItf
+	AuditRepository        *auditrepositorymock.AuditRepositoryItf
+	Transactor             *transactionmock.TransactorItf
 }
 
 func userProvider() mockUserProvider {
 	return mockUserProvider{
-		UserRepository: new(userrepositorymocks.UserRepositoryItf),
+		UserRepository:         new(userrepositorymocks.UserRepositoryItf),
+		UserTokenRepository:    new(usertokenrepositorymock.UserTokenRepositoryItf),
+		RecoveryCodeRepository: new(recoverycoderepositorymock.RecoveryCodeRepositoryItf),
+		Mailer:                 new(mailermock.Mailer),
+		OIDCProvider:           new(oidcmock.ProviderItf),
+		SessionRepository:      new(sessionrepositorymock.SessionRepositoryItf),
+		AuditRepository:        new(auditrepositorymock.AuditRepositoryItf),
+		Transactor:             new(transactionmock.TransactorItf),
 	}
 }
 
@@ -56,14 +92,16 @@ func TestUserUsecase_Register(t *testing.T) {
 	prov := userProvider()
 
 	type fields struct {
-		UserRepository userrepository.UserRepositoryItf
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		Mailer              mailer.Mailer
+		PasswordPolicy      util.PasswordPolicy
 	}
 	type args struct {
 		ctx      context.Context
 		username string
 		email    string
 		password string
-		role     int64
 	}
 	tests := []struct {
 		name    string
@@ -76,36 +114,79 @@ func TestUserUsecase_Register(t *testing.T) {
 		{
 			name: "success",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "budi",
 				email:    "budi@mail.com",
 				password: "123",
-				role:     1,
 			},
 			wantId:  1,
 			wantErr: false,
 			mock: func() {
 				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
-					Return(entity.User{ID: 1, Username: "winarto"}, nil).Times(1)
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
 
-				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, int64(enum.User)).
+					Return(int64(1), nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.Anything).
+					Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success when verification email failed",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "budi",
+				email:    "budi@mail.com",
+				password: "123",
+			},
+			wantId:  1,
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, int64(enum.User)).
+					Return(int64(1), nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.Anything).
 					Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
 			},
 		},
 		{
 			name: "failed username already taken",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "winarto",
 				email:    "winarto@mail.com",
 				password: "123",
-				role:     1,
 			},
 			wantId:  0,
 			wantErr: true,
@@ -117,14 +198,15 @@ func TestUserUsecase_Register(t *testing.T) {
 		{
 			name: "failed user data",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "winarto",
 				email:    "winarto@mail.com",
 				password: "123",
-				role:     1,
 			},
 			wantId:  0,
 			wantErr: true,
@@ -133,23 +215,42 @@ func TestUserUsecase_Register(t *testing.T) {
 					Return(entity.User{}, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed password policy",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+				PasswordPolicy:      util.PasswordPolicy{MinLength: 8, Breached: map[string]bool{"password": true}},
+			},
+			args: args{
+				ctx:      ctx,
+				username: "budi",
+				email:    "budi@mail.com",
+				password: "password",
+			},
+			wantId:  0,
+			wantErr: true,
+			mock:    func() {},
+		},
 		{
 			name: "failed create user",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "budi",
 				email:    "budi@mail.com",
 				password: "123",
-				role:     1,
 			},
 			wantId:  0,
 			wantErr: true,
 			mock: func() {
 				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
-					Return(entity.User{ID: 1, Username: "winarto"}, nil).Times(1)
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
 
 				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(int64(0), errors.New("error")).Times(1)
@@ -161,9 +262,12 @@ func TestUserUsecase_Register(t *testing.T) {
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			uu := &UserUsecase{
-				UserRepository: tt.fields.UserRepository,
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				Mailer:              tt.fields.Mailer,
+				PasswordPolicy:      tt.fields.PasswordPolicy,
 			}
-			gotId, err := uu.Register(tt.args.ctx, tt.args.username, tt.args.email, tt.args.password, tt.args.role)
+			gotId, err := uu.Register(tt.args.ctx, tt.args.username, tt.args.email, tt.args.password)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UserUsecase.Register() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -179,46 +283,113 @@ func TestUserUsecase_Login(t *testing.T) {
 	ctx := context.Background()
 	prov := userProvider()
 
+	lockedThrottle := func(key string) *throttle.Throttle {
+		th := throttle.NewThrottle(throttle.Throttle{LockoutAttempts: 1, LockoutDuration: time.Minute})
+		th.Fail(key)
+		return th
+	}
+
 	type fields struct {
-		UserRepository userrepository.UserRepositoryItf
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		UsernameThrottle    *throttle.Throttle
+		IPThrottle          *throttle.Throttle
+		SessionRepository   sessionrepository.SessionRepositoryItf
 	}
 	type args struct {
 		ctx      context.Context
 		username string
 		password string
+		client   entity.Client
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-		mock    func()
+		name          string
+		fields        fields
+		args          args
+		wantChallenge bool
+		wantErr       bool
+		wantErrIs     error
+		mock          func()
 	}{
 		{
 			name: "success",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1}, nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 1 && session.IP == "127.0.0.1" && session.UserAgent == ""
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success long user agent is cut between characters",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "winarto",
 				password: "123",
+				client:   entity.Client{IP: "127.0.0.1", UserAgent: strings.Repeat("ポ", maxUserAgentLength+1)},
 			},
 			wantErr: false,
 			mock: func() {
 				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
 					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1}, nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserAgent == strings.Repeat("ポ", maxUserAgentLength)
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success two factor required",
+			fields: fields{
+				SessionRepository:   prov.SessionRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantChallenge: true,
+			wantErr:       false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 2, TOTPEnabled: true}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.MatchedBy(func(token entity.UserToken) bool {
+					return token.UserID == 1 && token.Purpose == enum.LoginChallenge.String()
+				})).Return(int64(1), nil).Times(1)
 			},
 		},
 		{
 			name: "failed get user data",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "winarto",
 				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
 			},
 			wantErr: true,
 			mock: func() {
@@ -226,35 +397,3394 @@ func TestUserUsecase_Login(t *testing.T) {
 					Return(entity.User{}, errors.New("error")).Times(1)
 			},
 		},
+		{
+			name: "failed user not found",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidCredentials,
+			mock: func() {
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed user disabled",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1, Disabled: true}, nil).Times(1)
+			},
+		},
 		{
 			name: "failed password not valid",
 			fields: fields{
-				UserRepository: prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
 			},
 			args: args{
 				ctx:      ctx,
 				username: "winarto",
 				password: "123333",
+				client:   entity.Client{IP: "127.0.0.1"},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: ErrInvalidCredentials,
 			mock: func() {
 				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
 					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1}, nil).Times(1)
 			},
 		},
+		{
+			name: "failed username throttled",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				UsernameThrottle:  lockedThrottle("winarto"),
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed ip throttled",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				IPThrottle:        lockedThrottle("127.0.0.1"),
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				password: "123",
+				client:   entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
 	}
 	for _, tt := range tests {
 		tt.mock()
 		defer tt.mock()
 		t.Run(tt.name, func(t *testing.T) {
 			uu := &UserUsecase{
-				UserRepository: tt.fields.UserRepository,
+				SessionRepository:   tt.fields.SessionRepository,
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				UsernameThrottle:    tt.fields.UsernameThrottle,
+				IPThrottle:          tt.fields.IPThrottle,
 			}
-			_, err := uu.Login(tt.args.ctx, tt.args.username, tt.args.password)
+			gotResult, err := uu.Login(tt.args.ctx, tt.args.username, tt.args.password, tt.args.client)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UserUsecase.Login() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("UserUsecase.Login() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if !tt.wantErr && (gotResult.Challenge != "") != tt.wantChallenge {
+				t.Errorf("UserUsecase.Login() = %v, wantChallenge %v", gotResult, tt.wantChallenge)
+			}
+			if !tt.wantErr && (gotResult.Token == "") != tt.wantChallenge {
+				t.Errorf("UserUsecase.Login() = %v, want token only without challenge", gotResult)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_LoginThrottle(t *testing.T) {
+	prov := userProvider()
+	uu := &UserUsecase{
+		UserRepository:   prov.UserRepository,
+		UsernameThrottle: throttle.NewThrottle(throttle.Throttle{LockoutAttempts: 2, LockoutDuration: time.Minute}),
+	}
+
+	prov.UserRepository.On("GetUserByUsername", mock.Anything, "winarto").
+		Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1}, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := uu.Login(context.Background(), "winarto", "wrong", entity.Client{IP: "127.0.0.1"})
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("UserUsecase.Login() error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+
+	_, err := uu.Login(context.Background(), "Winarto", "123", entity.Client{IP: "127.0.0.1"})
+	var tooMany *throttle.ErrTooManyAttempts
+	if !errors.As(err, &tooMany) {
+		t.Errorf("UserUsecase.Login() error = %v, want too many attempts", err)
+	}
+}
+
+func TestUserUsecase_CreateUser(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 1, Role: enum.Admin})
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository  userrepository.UserRepositoryItf
+		Transactor      transaction.TransactorItf
+		AuditRepository auditrepository.AuditRepositoryItf
+	}
+	type args struct {
+		ctx      context.Context
+		username string
+		email    string
+		password string
+		role     int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantId  int64
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "budi",
+				email:    "budi@mail.com",
+				password: "123",
+				role:     2,
+			},
+			wantId:  2,
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, int64(2)).
+					Return(int64(2), nil).Times(1)
+
+				prov.UserRepository.On("CreateUserRoleAudit", mock.Anything, entity.UserRoleAudit{UserID: 2, ActorID: 1, OldRole: 0, NewRole: 2}).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed role not valid",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "budi",
+				email:    "budi@mail.com",
+				password: "123",
+				role:     99,
+			},
+			wantId:  0,
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed username already taken",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "winarto",
+				email:    "winarto@mail.com",
+				password: "123",
+				role:     2,
+			},
+			wantId:  0,
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{ID: 1, Username: "winarto"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed create audit",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				username: "budi",
+				email:    "budi@mail.com",
+				password: "123",
+				role:     2,
+			},
+			wantId:  2,
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, mock.Anything).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, int64(2)).
+					Return(int64(2), nil).Times(1)
+
+				prov.UserRepository.On("CreateUserRoleAudit", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:  tt.fields.UserRepository,
+				Transactor:      tt.fields.Transactor,
+				AuditRepository: tt.fields.AuditRepository,
+			}
+			gotId, err := uu.CreateUser(tt.args.ctx, tt.args.username, tt.args.email, tt.args.password, tt.args.role)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("UserUsecase.CreateUser() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_UpdateUserRole(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 1, Role: enum.Admin})
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository  userrepository.UserRepositoryItf
+		Transactor      transaction.TransactorItf
+		AuditRepository auditrepository.AuditRepositoryItf
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		role int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   2,
+				role: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2, Username: "budi", Role: 1}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserRole", mock.Anything, int64(2), int64(2)).
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("CreateUserRoleAudit", mock.Anything, entity.UserRoleAudit{UserID: 2, ActorID: 1, OldRole: 1, NewRole: 2}).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success role unchanged",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   2,
+				role: 1,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2, Username: "budi", Role: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed role not valid",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   2,
+				role: 0,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed get user",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   2,
+				role: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{}, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed update role",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				Transactor:      prov.Transactor,
+				AuditRepository: prov.AuditRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   2,
+				role: 2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2, Username: "budi", Role: 1}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserRole", mock.Anything, int64(2), int64(2)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:  tt.fields.UserRepository,
+				Transactor:      tt.fields.Transactor,
+				AuditRepository: tt.fields.AuditRepository,
+			}
+			if err := uu.UpdateUserRole(tt.args.ctx, tt.args.id, tt.args.role); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.UpdateUserRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_GetProfile(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository userrepository.UserRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.UserProfile
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: entity.UserProfile{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1},
+			wantErr:    false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "hash", Role: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantResult: entity.UserProfile{},
+			wantErr:    true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository: tt.fields.UserRepository,
+			}
+			gotResult, err := uu.GetProfile(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.GetProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserUsecase.GetProfile() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	email := "budi@mail.com"
+	upperEmail := "Winarto@mail.com"
+	emptyEmail := ""
+	displayName := "Budi"
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		Mailer              mailer.Mailer
+		Transactor          transaction.TransactorItf
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		data entity.UpdateProfile
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantResult entity.UserProfile
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success update display name only",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+				Transactor:     prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{DisplayName: &displayName},
+			},
+			wantResult: entity.UserProfile{ID: 1, Username: "winarto", Email: "winarto@mail.com", DisplayName: "Budi", Role: 1},
+			wantErr:    false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(1), "winarto@mail.com", "Budi").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success update email unverifies it and sends verification email",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{Email: &email},
+			},
+			wantResult: entity.UserProfile{ID: 1, Username: "winarto", Email: "budi@mail.com", Role: 1},
+			wantErr:    false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(1), "budi@mail.com", "").
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokensDB", mock.Anything, int64(1), enum.EmailVerification.String()).
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.MatchedBy(func(data entity.UserToken) bool {
+					return data.UserID == 1 && data.Purpose == enum.EmailVerification.String() && data.Email == "budi@mail.com"
+				})).Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					return message.To == "budi@mail.com"
+				})).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success update email case keeps it verified",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+				Transactor:     prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{Email: &upperEmail},
+			},
+			wantResult: entity.UserProfile{ID: 1, Username: "winarto", Email: "Winarto@mail.com", Role: 1, EmailVerified: true},
+			wantErr:    false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(1), "Winarto@mail.com", "").
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success update email when verification email fails",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{Email: &email},
+			},
+			wantResult: entity.UserProfile{ID: 1, Username: "winarto", Email: "budi@mail.com", Role: 1},
+			wantErr:    false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(1), "budi@mail.com", "").
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokensDB", mock.Anything, int64(1), enum.EmailVerification.String()).
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.Anything).
+					Return(int64(0), errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed revoke verification tokens of the old email",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{Email: &email},
+			},
+			wantResult: entity.UserProfile{},
+			wantErr:    true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(1), "budi@mail.com", "").
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokensDB", mock.Anything, int64(1), enum.EmailVerification.String()).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed email empty",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+				Transactor:     prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{Email: &emptyEmail},
+			},
+			wantResult: entity.UserProfile{},
+			wantErr:    true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed update profile",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+				Transactor:     prov.Transactor,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				data: entity.UpdateProfile{DisplayName: &displayName},
+			},
+			wantResult: entity.UserProfile{},
+			wantErr:    true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Role: 1}, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				Mailer:              tt.fields.Mailer,
+				Transactor:          tt.fields.Transactor,
+			}
+			gotResult, err := uu.UpdateProfile(tt.args.ctx, tt.args.id, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("UserUsecase.UpdateProfile() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	sessionCtx := auth.NewContext(ctx, &auth.JWTClaim{ID: 1, SessionID: 7})
+	user := entity.User{ID: 1, Username: "winarto", Email: "winarto@mail.com", Password: "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96", Role: 1}
+
+	type fields struct {
+		UserRepository    userrepository.UserRepositoryItf
+		SessionRepository sessionrepository.SessionRepositoryItf
+		Transactor        transaction.TransactorItf
+	}
+	type args struct {
+		ctx             context.Context
+		id              int64
+		currentPassword string
+		newPassword     string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success keeps the current session",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:             sessionCtx,
+				id:              1,
+				currentPassword: "123",
+				newPassword:     "456",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserPassword", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeOtherUserSessionsDB", mock.Anything, int64(1), int64(7)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed revoke other sessions",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:             ctx,
+				id:              1,
+				currentPassword: "123",
+				newPassword:     "456",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("UpdateUserPassword", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeOtherUserSessionsDB", mock.Anything, int64(1), int64(0)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed new password empty",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx:             ctx,
+				id:              1,
+				currentPassword: "123",
+				newPassword:     "",
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed current password not valid",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx:             ctx,
+				id:              1,
+				currentPassword: "999",
+				newPassword:     "456",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get user",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx:             ctx,
+				id:              1,
+				currentPassword: "123",
+				newPassword:     "456",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:    tt.fields.UserRepository,
+				SessionRepository: tt.fields.SessionRepository,
+				Transactor:        tt.fields.Transactor,
+			}
+			if err := uu.ChangePassword(tt.args.ctx, tt.args.id, tt.args.currentPassword, tt.args.newPassword); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_GetAllUsers(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository userrepository.UserRepositoryItf
+	}
+	type args struct {
+		ctx    context.Context
+		filter entity.UserFilter
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.UserProfile
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.UserFilter{Search: "win"},
+			},
+			wantResults: []entity.UserProfile{{ID: 1, Username: "winarto", Role: 1}},
+			wantErr:     false,
+			mock: func() {
+				prov.UserRepository.On("GetAllUsers", mock.Anything, entity.UserFilter{Search: "win"}).
+					Return([]entity.User{{ID: 1, Username: "winarto", Password: "hash", Role: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				filter: entity.UserFilter{},
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.UserRepository.On("GetAllUsers", mock.Anything, mock.Anything).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository: tt.fields.UserRepository,
+			}
+			gotResults, err := uu.GetAllUsers(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.GetAllUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("UserUsecase.GetAllUsers() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_SetUserDisabled(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository    userrepository.UserRepositoryItf
+		SessionRepository sessionrepository.SessionRepositoryItf
+		AuditRepository   auditrepository.AuditRepositoryItf
+		Transactor        transaction.TransactorItf
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		disabled bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success disable revokes sessions",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       2,
+				disabled: true,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserDisabled", mock.Anything, int64(2), true).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeUserSessionsDB", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success enable",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       2,
+				disabled: false,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserDisabled", mock.Anything, int64(2), false).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed revoke sessions",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+				AuditRepository:   prov.AuditRepository,
+				Transactor:        prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       2,
+				disabled: true,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserDisabled", mock.Anything, int64(2), true).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeUserSessionsDB", mock.Anything, int64(2)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed user not found",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       2,
+				disabled: true,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:    tt.fields.UserRepository,
+				SessionRepository: tt.fields.SessionRepository,
+				AuditRepository:   tt.fields.AuditRepository,
+				Transactor:        tt.fields.Transactor,
+			}
+			if err := uu.SetUserDisabled(tt.args.ctx, tt.args.id, tt.args.disabled); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.SetUserDisabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository  userrepository.UserRepositoryItf
+		AuditRepository auditrepository.AuditRepositoryItf
+		Transactor      transaction.TransactorItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2}, nil).Times(1)
+
+				prov.UserRepository.On("DeleteUserByID", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed delete user",
+			fields: fields{
+				UserRepository:  prov.UserRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			},
+			args: args{
+				ctx: ctx,
+				id:  2,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2}, nil).Times(1)
+
+				prov.UserRepository.On("DeleteUserByID", mock.Anything, int64(2)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:  tt.fields.UserRepository,
+				AuditRepository: tt.fields.AuditRepository,
+				Transactor:      tt.fields.Transactor,
+			}
+			if err := uu.DeleteUser(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_SendVerificationEmail(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		Mailer              mailer.Mailer
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.MatchedBy(func(data entity.UserToken) bool {
+					return data.UserID == 1 && data.Purpose == enum.EmailVerification.String() && data.TokenHash != ""
+				})).Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					return message.To == "budi@mail.com"
+				})).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed email already verified",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com", EmailVerified: true}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed create token",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.Anything).
+					Return(int64(0), errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				Mailer:              tt.fields.Mailer,
+			}
+			if err := uu.SendVerificationEmail(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.SendVerificationEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	secret := "secret"
+	tokenHash := util.SignToken(secret, "token")
+	validToken := entity.UserToken{ID: 1, UserID: 2, Purpose: enum.EmailVerification.String(), TokenHash: tokenHash, Email: "budi@mail.com", ExpiresAt: time.Now().Add(time.Hour)}
+	expiredToken := entity.UserToken{ID: 1, UserID: 2, Purpose: enum.EmailVerification.String(), TokenHash: tokenHash, ExpiresAt: time.Now().Add(-time.Hour)}
+	usedToken := entity.UserToken{ID: 1, UserID: 2, Purpose: enum.EmailVerification.String(), TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour), UsedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		Transactor          transaction.TransactorItf
+	}
+	type args struct {
+		ctx   context.Context
+		token string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(validToken, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2, Username: "budi", Email: "Budi@mail.com"}, nil).Times(1)
+
+				prov.UserRepository.On("VerifyUserEmail", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed email changed after the token was sent",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(validToken, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(2)).
+					Return(entity.User{ID: 2, Username: "budi", Email: "someone@mail.com"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed empty token",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+			},
+		},
+		{
+			name: "failed token not found",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(entity.UserToken{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed token expired",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(expiredToken, nil).Times(1)
+			},
+		},
+		{
+			name: "failed token already used",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(usedToken, nil).Times(1)
+			},
+		},
+		{
+			name: "failed token used concurrently",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:   ctx,
+				token: "token",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.EmailVerification.String()).
+					Return(validToken, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(false, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				TokenSecret:         secret,
+				Transactor:          tt.fields.Transactor,
+			}
+			if err := uu.VerifyEmail(tt.args.ctx, tt.args.token); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		Mailer              mailer.Mailer
+	}
+	type args struct {
+		ctx   context.Context
+		email string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx:   ctx,
+				email: "budi@mail.com",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "budi@mail.com").
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.MatchedBy(func(data entity.UserToken) bool {
+					return data.UserID == 1 && data.Purpose == enum.PasswordReset.String()
+				})).Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success unknown email is not reported",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx:   ctx,
+				email: "unknown@mail.com",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "unknown@mail.com").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed send email",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Mailer:              prov.Mailer,
+			},
+			args: args{
+				ctx:   ctx,
+				email: "budi@mail.com",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "budi@mail.com").
+					Return(entity.User{ID: 1, Username: "budi", Email: "budi@mail.com"}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.Anything).
+					Return(int64(1), nil).Times(1)
+
+				prov.Mailer.On("Send", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				Mailer:              tt.fields.Mailer,
+			}
+			if err := uu.ForgotPassword(tt.args.ctx, tt.args.email); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.ForgotPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	secret := "secret"
+	tokenHash := util.SignToken(secret, "token")
+	validToken := entity.UserToken{ID: 1, UserID: 2, Purpose: enum.PasswordReset.String(), TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		SessionRepository   sessionrepository.SessionRepositoryItf
+		Transactor          transaction.TransactorItf
+	}
+	type args struct {
+		ctx      context.Context
+		token    string
+		password string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				SessionRepository:   prov.SessionRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				token:    "token",
+				password: "456",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.PasswordReset.String()).
+					Return(validToken, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserPassword", mock.Anything, int64(2), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokensDB", mock.Anything, int64(2), enum.PasswordReset.String()).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeUserSessionsDB", mock.Anything, int64(2)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed revoke sessions",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				SessionRepository:   prov.SessionRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				token:    "token",
+				password: "456",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.PasswordReset.String()).
+					Return(validToken, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserPassword", mock.Anything, int64(2), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokensDB", mock.Anything, int64(2), enum.PasswordReset.String()).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("RevokeUserSessionsDB", mock.Anything, int64(2)).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed password empty",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				token:    "token",
+				password: "",
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "failed get token",
+			fields: fields{
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				Transactor:          prov.Transactor,
+			},
+			args: args{
+				ctx:      ctx,
+				token:    "token",
+				password: "456",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, tokenHash, enum.PasswordReset.String()).
+					Return(entity.UserToken{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				SessionRepository:   tt.fields.SessionRepository,
+				TokenSecret:         secret,
+				Transactor:          tt.fields.Transactor,
+			}
+			if err := uu.ResetPassword(tt.args.ctx, tt.args.token, tt.args.password); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_LoginTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	secret := "secret"
+	totpSecret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now()
+	code, _ := util.GenerateTOTPCode(totpSecret, now)
+	step := now.Unix() / int64(util.TOTPPeriod.Seconds())
+	challengeHash := util.SignToken(secret, "challenge")
+	challenge := entity.UserToken{ID: 1, UserID: 1, Purpose: enum.LoginChallenge.String(), TokenHash: challengeHash, ExpiresAt: time.Now().Add(time.Minute)}
+	user := entity.User{ID: 1, Username: "winarto", Role: 2, TOTPSecret: totpSecret, TOTPEnabled: true}
+
+	type fields struct {
+		UserRepository         userrepository.UserRepositoryItf
+		UserTokenRepository    usertokenrepository.UserTokenRepositoryItf
+		RecoveryCodeRepository recoverycoderepository.RecoveryCodeRepositoryItf
+		SessionRepository      sessionrepository.SessionRepositoryItf
+	}
+	type args struct {
+		ctx       context.Context
+		challenge string
+		code      string
+		client    entity.Client
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		wantErrIs error
+		mock      func()
+	}{
+		{
+			name: "success totp code",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      code,
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(challenge, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(true, nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 1 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success recovery code",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      " ABCDE-FGHJK ",
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(challenge, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("UseRecoveryCodeDB", mock.Anything, int64(1), util.SignToken(secret, "abcde-fghjk")).
+					Return(true, nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 1 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid challenge",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      code,
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidToken,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(entity.UserToken{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed invalid code",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      "wrong",
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidTwoFactor,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(challenge, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("UseRecoveryCodeDB", mock.Anything, int64(1), util.SignToken(secret, "wrong")).
+					Return(false, nil).Times(1)
+			},
+		},
+		{
+			name: "failed replayed totp code",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      code,
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidTwoFactor,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(challenge, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(user, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), mock.Anything).
+					Return(false, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("UseRecoveryCodeDB", mock.Anything, int64(1), util.SignToken(secret, code)).
+					Return(false, nil).Times(1)
+			},
+		},
+		{
+			name: "failed get user",
+			fields: fields{
+				SessionRepository:      prov.SessionRepository,
+				UserRepository:         prov.UserRepository,
+				UserTokenRepository:    prov.UserTokenRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				challenge: "challenge",
+				code:      code,
+				client:    entity.Client{IP: "127.0.0.1"},
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserTokenRepository.On("GetUserTokenByHashDB", mock.Anything, challengeHash, enum.LoginChallenge.String()).
+					Return(challenge, nil).Times(1)
+
+				prov.UserTokenRepository.On("UseUserTokenDB", mock.Anything, int64(1)).
+					Return(true, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{}, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				SessionRepository:      tt.fields.SessionRepository,
+				UserRepository:         tt.fields.UserRepository,
+				UserTokenRepository:    tt.fields.UserTokenRepository,
+				RecoveryCodeRepository: tt.fields.RecoveryCodeRepository,
+				TokenSecret:            secret,
+			}
+			gotResult, err := uu.LoginTwoFactor(tt.args.ctx, tt.args.challenge, tt.args.code, tt.args.client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.LoginTwoFactor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("UserUsecase.LoginTwoFactor() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if !tt.wantErr && gotResult.Token == "" {
+				t.Errorf("UserUsecase.LoginTwoFactor() token is empty")
+			}
+		})
+	}
+}
+
+func TestUserUsecase_EnrollTOTP(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		UserRepository userrepository.UserRepositoryItf
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto"}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserTOTP", mock.Anything, int64(1), mock.Anything, false).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed already enabled",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto", TOTPEnabled: true}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed update user totp",
+			fields: fields{
+				UserRepository: prov.UserRepository,
+			},
+			args: args{
+				ctx: ctx,
+				id:  1,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Username: "winarto"}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserTOTP", mock.Anything, int64(1), mock.Anything, false).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository: tt.fields.UserRepository,
+				TOTPIssuer:     "Pokedex",
+			}
+			gotResult, err := uu.EnrollTOTP(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.EnrollTOTP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (gotResult.Secret == "" || gotResult.URI != util.TOTPURI("Pokedex", "winarto", gotResult.Secret)) {
+				t.Errorf("UserUsecase.EnrollTOTP() = %v", gotResult)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_ConfirmTOTP(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	totpSecret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now()
+	code, _ := util.GenerateTOTPCode(totpSecret, now)
+	step := now.Unix() / int64(util.TOTPPeriod.Seconds())
+
+	type fields struct {
+		UserRepository         userrepository.UserRepositoryItf
+		RecoveryCodeRepository recoverycoderepository.RecoveryCodeRepositoryItf
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		code string
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantCodes int
+		wantErr   bool
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantCodes: RecoveryCodeCount,
+			wantErr:   false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret}, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(true, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("ReplaceRecoveryCodesDB", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserTOTP", mock.Anything, int64(1), totpSecret, true).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not enrolled",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed already enabled",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret, TOTPEnabled: true}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid code",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: "000000x",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed replayed code",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret}, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(false, nil).Times(1)
+			},
+		},
+		{
+			name: "failed replace recovery codes",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret}, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(true, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("ReplaceRecoveryCodesDB", mock.Anything, int64(1), mock.Anything).
+					Return(errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:         tt.fields.UserRepository,
+				RecoveryCodeRepository: tt.fields.RecoveryCodeRepository,
+				TokenSecret:            "secret",
+			}
+			gotCodes, err := uu.ConfirmTOTP(tt.args.ctx, tt.args.id, tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.ConfirmTOTP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(gotCodes) != tt.wantCodes {
+				t.Errorf("UserUsecase.ConfirmTOTP() = %v codes, want %v", len(gotCodes), tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_DisableTOTP(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	passwordHash := "$2a$12$EuMhNWuTVUF9G8tYSgH5BuL.8JYvrCRiKEx3flcemaIDa7INrei96"
+
+	type fields struct {
+		UserRepository         userrepository.UserRepositoryItf
+		RecoveryCodeRepository recoverycoderepository.RecoveryCodeRepositoryItf
+		RequireAdminTwoFactor  bool
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		password string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				password: "123",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Password: passwordHash, Role: int64(enum.Admin), TOTPEnabled: true}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserTOTP", mock.Anything, int64(1), "", false).
+					Return(nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("DeleteRecoveryCodesDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed password not valid",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				password: "wrong",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Password: passwordHash, TOTPEnabled: true}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed required for admin",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+				RequireAdminTwoFactor:  true,
+			},
+			args: args{
+				ctx:      ctx,
+				id:       1,
+				password: "123",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Password: passwordHash, Role: int64(enum.Admin), TOTPEnabled: true}, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:         tt.fields.UserRepository,
+				RecoveryCodeRepository: tt.fields.RecoveryCodeRepository,
+				RequireAdminTwoFactor:  tt.fields.RequireAdminTwoFactor,
+			}
+			if err := uu.DisableTOTP(tt.args.ctx, tt.args.id, tt.args.password); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.DisableTOTP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_RegenerateRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	totpSecret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now()
+	code, _ := util.GenerateTOTPCode(totpSecret, now)
+	step := now.Unix() / int64(util.TOTPPeriod.Seconds())
+
+	type fields struct {
+		UserRepository         userrepository.UserRepositoryItf
+		RecoveryCodeRepository recoverycoderepository.RecoveryCodeRepositoryItf
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		code string
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantCodes int
+		wantErr   bool
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantCodes: RecoveryCodeCount,
+			wantErr:   false,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret, TOTPEnabled: true}, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(true, nil).Times(1)
+
+				prov.RecoveryCodeRepository.On("ReplaceRecoveryCodesDB", mock.Anything, int64(1), mock.Anything).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed not enabled",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed replayed code",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: code,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret, TOTPEnabled: true}, nil).Times(1)
+
+				prov.UserRepository.On("UseTOTPStep", mock.Anything, int64(1), step).
+					Return(false, nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid code",
+			fields: fields{
+				UserRepository:         prov.UserRepository,
+				RecoveryCodeRepository: prov.RecoveryCodeRepository,
+			},
+			args: args{
+				ctx:  ctx,
+				id:   1,
+				code: "000000x",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, TOTPSecret: totpSecret, TOTPEnabled: true}, nil).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:         tt.fields.UserRepository,
+				RecoveryCodeRepository: tt.fields.RecoveryCodeRepository,
+				TokenSecret:            "secret",
+			}
+			gotCodes, err := uu.RegenerateRecoveryCodes(tt.args.ctx, tt.args.id, tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.RegenerateRecoveryCodes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(gotCodes) != tt.wantCodes {
+				t.Errorf("UserUsecase.RegenerateRecoveryCodes() = %v codes, want %v", len(gotCodes), tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_StartOIDCLogin(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		OIDCProvider oidc.ProviderItf
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		wantErrIs error
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				OIDCProvider: prov.OIDCProvider,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.OIDCProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return("https://sso.mail.com/authorize", nil).Times(1)
+			},
+		},
+		{
+			name: "failed provider",
+			fields: fields{
+				OIDCProvider: prov.OIDCProvider,
+			},
+			args: args{
+				ctx: ctx,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.OIDCProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return("", errors.New("error")).Times(1)
+			},
+		},
+		{
+			name:   "failed not configured",
+			fields: fields{},
+			args: args{
+				ctx: ctx,
+			},
+			wantErr:   true,
+			wantErrIs: ErrOIDCNotConfigured,
+			mock:      func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				OIDCProvider: tt.fields.OIDCProvider,
+				TokenSecret:  "secret",
+			}
+			gotResult, err := uu.StartOIDCLogin(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.StartOIDCLogin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("UserUsecase.StartOIDCLogin() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			flow, err := uu.decodeOIDCFlow(gotResult.Flow)
+			if err != nil {
+				t.Errorf("UserUsecase.StartOIDCLogin() flow error = %v", err)
+			}
+			if flow.State == "" || flow.Nonce == "" || flow.CodeVerifier == "" {
+				t.Errorf("UserUsecase.StartOIDCLogin() flow = %v", flow)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_FinishOIDCLogin(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	secret := "secret"
+	issuer := "https://sso.mail.com"
+
+	flowUsecase := &UserUsecase{TokenSecret: secret}
+	flow, _ := flowUsecase.encodeOIDCFlow(oidcFlow{State: "state", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	expiredFlow, _ := flowUsecase.encodeOIDCFlow(oidcFlow{State: "state", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	claims := oidc.Claims{Issuer: issuer, Subject: "ash", Email: "ash@mail.com", EmailVerified: true, Name: "Ash Ketchum", PreferredUsername: "ash", Groups: []string{"trainers"}, Nonce: "nonce"}
+	adminClaims := oidc.Claims{Issuer: issuer, Subject: "oak", Email: "oak@mail.com", EmailVerified: true, Groups: []string{"trainers", "professors"}, Nonce: "nonce"}
+	groupRoles := map[string]enum.Role{"trainers": enum.User, "professors": enum.Admin}
+
+	type fields struct {
+		UserRepository      userrepository.UserRepositoryItf
+		UserTokenRepository usertokenrepository.UserTokenRepositoryItf
+		OIDCProvider        oidc.ProviderItf
+		OIDCDefaultRole     enum.Role
+		SessionRepository   sessionrepository.SessionRepositoryItf
+		Transactor          transaction.TransactorItf
+		AuditRepository     auditrepository.AuditRepositoryItf
+	}
+	type args struct {
+		ctx   context.Context
+		flow  string
+		state string
+		code  string
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		args          args
+		wantChallenge bool
+		wantErr       bool
+		wantErrIs     error
+		mock          func()
+	}{
+		{
+			name: "success linked identity",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-linked",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-linked", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{ID: 1, Username: "ash", Email: "ash@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 1 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success link account with verified email",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-email",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-email", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "ash@mail.com").
+					Return(entity.User{ID: 1, Username: "ash", Email: "ash@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.UserRepository.On("CreateUserIdentity", mock.Anything, int64(1), issuer, "ash").
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 1 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success provision account",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-provision",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-provision", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "ash@mail.com").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, "ash").
+					Return(entity.User{ID: 2, Username: "ash"}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByUsername", mock.Anything, "ash2").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("CreateUser", mock.Anything, "ash2", "ash@mail.com", "", int64(enum.User)).
+					Return(int64(3), nil).Times(1)
+
+				prov.UserRepository.On("CreateUserRoleAudit", mock.Anything, entity.UserRoleAudit{UserID: 3, OldRole: int64(enum.Public), NewRole: int64(enum.User)}).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserProfile", mock.Anything, int64(3), "ash@mail.com", "Ash Ketchum").
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("CreateUserIdentity", mock.Anything, int64(3), issuer, "ash").
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("VerifyUserEmail", mock.Anything, int64(3)).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 3 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success role synced from groups",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-admin",
+			},
+			wantErr: false,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-admin", "verifier").
+					Return(adminClaims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "oak").
+					Return(entity.User{ID: 4, Username: "oak", Email: "oak@mail.com", Role: 1, EmailVerified: true}, nil).Times(1)
+
+				prov.UserRepository.On("UpdateUserRole", mock.Anything, int64(4), int64(enum.Admin)).
+					Return(nil).Times(1)
+
+				prov.UserRepository.On("CreateUserRoleAudit", mock.Anything, entity.UserRoleAudit{UserID: 4, OldRole: int64(enum.User), NewRole: int64(enum.Admin)}).
+					Return(nil).Times(1)
+
+				prov.AuditRepository.On("CreateAuditLogDB", mock.Anything, mock.Anything).
+					Return(nil).Times(1)
+
+				prov.SessionRepository.On("CreateSessionDB", mock.Anything, mock.MatchedBy(func(session entity.Session) bool {
+					return session.UserID == 4 && session.IP == "127.0.0.1"
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "success two factor required",
+			fields: fields{
+				SessionRepository:   prov.SessionRepository,
+				UserRepository:      prov.UserRepository,
+				UserTokenRepository: prov.UserTokenRepository,
+				OIDCProvider:        prov.OIDCProvider,
+				Transactor:          prov.Transactor,
+				AuditRepository:     prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-totp",
+			},
+			wantChallenge: true,
+			wantErr:       false,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-totp", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{ID: 5, Username: "ash", Email: "ash@mail.com", Role: 1, EmailVerified: true, TOTPEnabled: true}, nil).Times(1)
+
+				prov.UserTokenRepository.On("CreateUserTokenDB", mock.Anything, mock.MatchedBy(func(token entity.UserToken) bool {
+					return token.UserID == 5 && token.Purpose == enum.LoginChallenge.String()
+				})).Return(int64(1), nil).Times(1)
+			},
+		},
+		{
+			name: "failed invalid flow",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow + "x",
+				state: "state",
+				code:  "code",
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidOIDCLogin,
+			mock:      func() {},
+		},
+		{
+			name: "failed expired flow",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  expiredFlow,
+				state: "state",
+				code:  "code",
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidOIDCLogin,
+			mock:      func() {},
+		},
+		{
+			name: "failed state mismatch",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "other",
+				code:  "code",
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidOIDCLogin,
+			mock:      func() {},
+		},
+		{
+			name: "failed exchange",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-error",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-error", "verifier").
+					Return(oidc.Claims{}, errors.New("error")).Times(1)
+			},
+		},
+		{
+			name: "failed nonce mismatch",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-nonce",
+			},
+			wantErr:   true,
+			wantErrIs: ErrInvalidOIDCLogin,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-nonce", "verifier").
+					Return(oidc.Claims{Issuer: issuer, Subject: "ash", Nonce: "other"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed no role for groups",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-guest",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-guest", "verifier").
+					Return(oidc.Claims{Issuer: issuer, Subject: "gary", Groups: []string{"rivals"}, Nonce: "nonce"}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed unverified account with same email",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-unverified",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-unverified", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+
+				prov.UserRepository.On("GetUserByEmail", mock.Anything, "ash@mail.com").
+					Return(entity.User{ID: 1, Username: "ash", Email: "ash@mail.com", Role: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed user disabled",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+				UserRepository:    prov.UserRepository,
+				OIDCProvider:      prov.OIDCProvider,
+				Transactor:        prov.Transactor,
+				AuditRepository:   prov.AuditRepository,
+			},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code-disabled",
+			},
+			wantErr: true,
+			mock: func() {
+				prov.OIDCProvider.On("Exchange", mock.Anything, "code-disabled", "verifier").
+					Return(claims, nil).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.UserRepository.On("GetUserByIdentity", mock.Anything, issuer, "ash").
+					Return(entity.User{ID: 1, Username: "ash", Email: "ash@mail.com", Role: 1, EmailVerified: true, Disabled: true}, nil).Times(1)
+			},
+		},
+		{
+			name:   "failed not configured",
+			fields: fields{},
+			args: args{
+				ctx:   ctx,
+				flow:  flow,
+				state: "state",
+				code:  "code",
+			},
+			wantErr:   true,
+			wantErrIs: ErrOIDCNotConfigured,
+			mock:      func() {},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				SessionRepository:   tt.fields.SessionRepository,
+				UserRepository:      tt.fields.UserRepository,
+				UserTokenRepository: tt.fields.UserTokenRepository,
+				OIDCProvider:        tt.fields.OIDCProvider,
+				OIDCGroupRoles:      groupRoles,
+				OIDCDefaultRole:     tt.fields.OIDCDefaultRole,
+				TokenSecret:         secret,
+				AuditRepository:     tt.fields.AuditRepository,
+				Transactor:          tt.fields.Transactor,
+			}
+			gotResult, err := uu.FinishOIDCLogin(tt.args.ctx, tt.args.flow, tt.args.state, tt.args.code, entity.Client{IP: "127.0.0.1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.FinishOIDCLogin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("UserUsecase.FinishOIDCLogin() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantChallenge != (gotResult.Challenge != "") || tt.wantChallenge == (gotResult.Token != "") {
+				t.Errorf("UserUsecase.FinishOIDCLogin() = %v, wantChallenge %v", gotResult, tt.wantChallenge)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_OIDCLoginWithMockProvider(t *testing.T) {
+	server := oidctest.NewServer("pokedex", "secret")
+	defer server.Close()
+	server.SetIdentity(oidctest.Identity{Subject: "oak", Email: "oak@mail.com", EmailVerified: true, Groups: []string{"professors"}})
+
+	userRepository := new(userrepositorymocks.UserRepositoryItf)
+	userRepository.On("GetUserByIdentity", mock.Anything, server.Issuer(), "oak").
+		Return(entity.User{ID: 1, Username: "oak", Email: "oak@mail.com", Role: int64(enum.Admin), EmailVerified: true}, nil).Times(1)
+
+	sessionRepository := new(sessionrepositorymock.SessionRepositoryItf)
+	sessionRepository.On("CreateSessionDB", mock.Anything, mock.Anything).
+		Return(int64(1), nil).Times(1)
+
+	transactor := new(transactionmock.TransactorItf)
+	transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(withinTransaction).Times(1)
+
+	uu := &UserUsecase{
+		UserRepository:    userRepository,
+		SessionRepository: sessionRepository,
+		Transactor:        transactor,
+		OIDCProvider: oidc.NewProvider(oidc.Provider{
+			Issuer:       server.Issuer(),
+			ClientID:     "pokedex",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:8080/login/oidc/callback",
+		}),
+		OIDCGroupRoles: map[string]enum.Role{"professors": enum.Admin},
+		TokenSecret:    "secret",
+	}
+
+	login, err := uu.StartOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("UserUsecase.StartOIDCLogin() error = %v", err)
+	}
+
+	code, state, err := server.Authorize(login.AuthURL)
+	if err != nil {
+		t.Fatalf("Server.Authorize() error = %v", err)
+	}
+
+	result, err := uu.FinishOIDCLogin(context.Background(), login.Flow, state, code, entity.Client{})
+	if err != nil {
+		t.Fatalf("UserUsecase.FinishOIDCLogin() error = %v", err)
+	}
+	if result.Token == "" {
+		t.Errorf("UserUsecase.FinishOIDCLogin() token is empty")
+	}
+}
+
+func TestUserUsecase_GetSessions(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), &auth.JWTClaim{ID: 1, SessionID: 2})
+	prov := userProvider()
+	sessions := []entity.Session{{ID: 2, UserID: 1}, {ID: 3, UserID: 1}}
+
+	type fields struct {
+		SessionRepository sessionrepository.SessionRepositoryItf
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantResults []entity.Session
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantResults: []entity.Session{{ID: 2, UserID: 1, Current: true}, {ID: 3, UserID: 1}},
+			wantErr:     false,
+			mock: func() {
+				prov.SessionRepository.On("GetActiveSessionsByUserIDDB", mock.Anything, int64(1)).
+					Return(sessions, nil).Times(1)
+			},
+		},
+		{
+			name: "failed",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				userID: 1,
+			},
+			wantResults: nil,
+			wantErr:     true,
+			mock: func() {
+				prov.SessionRepository.On("GetActiveSessionsByUserIDDB", mock.Anything, int64(1)).
+					Return(nil, errors.New("error")).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				SessionRepository: tt.fields.SessionRepository,
+			}
+			gotResults, err := uu.GetSessions(tt.args.ctx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.GetSessions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("UserUsecase.GetSessions() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+
+	type fields struct {
+		SessionRepository sessionrepository.SessionRepositoryItf
+	}
+	type args struct {
+		ctx       context.Context
+		userID    int64
+		sessionID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				userID:    1,
+				sessionID: 2,
+			},
+			wantErr: false,
+			mock: func() {
+				prov.SessionRepository.On("RevokeSessionDB", mock.Anything, int64(2), int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed session not found",
+			fields: fields{
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:       ctx,
+				userID:    1,
+				sessionID: 3,
+			},
+			wantErr: true,
+			mock: func() {
+				prov.SessionRepository.On("RevokeSessionDB", mock.Anything, int64(3), int64(1)).
+					Return(sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				SessionRepository: tt.fields.SessionRepository,
+			}
+			if err := uu.RevokeSession(tt.args.ctx, tt.args.userID, tt.args.sessionID); (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.RevokeSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserUsecase_ValidateSession(t *testing.T) {
+	ctx := context.Background()
+	prov := userProvider()
+	revokedAt := time.Now().Add(-time.Minute)
+
+	type fields struct {
+		UserRepository    userrepository.UserRepositoryItf
+		SessionRepository sessionrepository.SessionRepositoryItf
+	}
+	type args struct {
+		ctx    context.Context
+		claims *auth.JWTClaim
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantErr   bool
+		wantErrIs error
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 1},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(1)).
+					Return(entity.Session{ID: 1, UserID: 1, LastSeenAt: time.Now().Add(-time.Hour)}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1}, nil).Times(1)
+
+				prov.SessionRepository.On("UpdateSessionLastSeenDB", mock.Anything, int64(1)).
+					Return(nil).Times(1)
+			},
+		},
+		{
+			name: "success recently seen",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 2},
+			},
+			wantErr: false,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(2)).
+					Return(entity.Session{ID: 2, UserID: 1, LastSeenAt: time.Now()}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed revoked session",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 3},
+			},
+			wantErr:   true,
+			wantErrIs: ErrSessionRevoked,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(3)).
+					Return(entity.Session{ID: 3, UserID: 1, RevokedAt: &revokedAt}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed session of other user",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 2, SessionID: 4},
+			},
+			wantErr:   true,
+			wantErrIs: ErrSessionRevoked,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(4)).
+					Return(entity.Session{ID: 4, UserID: 1}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed disabled user",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 5},
+			},
+			wantErr:   true,
+			wantErrIs: ErrAccountDisabled,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(5)).
+					Return(entity.Session{ID: 5, UserID: 1}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Disabled: true}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed role changed",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 8, Role: enum.Admin},
+			},
+			wantErr:   true,
+			wantErrIs: ErrRoleChanged,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(8)).
+					Return(entity.Session{ID: 8, UserID: 1}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{ID: 1, Role: int64(enum.User)}, nil).Times(1)
+			},
+		},
+		{
+			name: "failed deleted user",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1, SessionID: 6},
+			},
+			wantErr:   true,
+			wantErrIs: ErrSessionRevoked,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(6)).
+					Return(entity.Session{ID: 6, UserID: 1}, nil).Times(1)
+
+				prov.UserRepository.On("GetUserByID", mock.Anything, int64(1)).
+					Return(entity.User{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name: "failed session not found",
+			fields: fields{
+				UserRepository:    prov.UserRepository,
+				SessionRepository: prov.SessionRepository,
+			},
+			args: args{
+				ctx:    ctx,
+				claims: &auth.JWTClaim{ID: 1},
+			},
+			wantErr:   true,
+			wantErrIs: ErrSessionRevoked,
+			mock: func() {
+				prov.SessionRepository.On("GetSessionByIDDB", mock.Anything, int64(0)).
+					Return(entity.Session{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			uu := &UserUsecase{
+				UserRepository:    tt.fields.UserRepository,
+				SessionRepository: tt.fields.SessionRepository,
+			}
+			err := uu.ValidateSession(tt.args.ctx, tt.args.claims)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserUsecase.ValidateSession() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("UserUsecase.ValidateSession() error = %v, want %v", err, tt.wantErrIs)
+			}
 		})
 	}
 }
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+	"github.com/winartodev/go-pokedex/filter"
+	pokemonrepository "github.com/winartodev/go-pokedex/repository/pokemon"
+	"github.com/winartodev/go-pokedex/validation"
+)
+
+// maxNameLength is the size of the varchar name columns
+const maxNameLength = 255
+
+// errDuplicateEntry is the error number of mysql for a row that breaks a unique index
+const errDuplicateEntry = 1062
+
+// validatePokemon checks the payload of pokemon with the id, id is 0 for a new pokemon
+func (pu *PokemonUsecase) validatePokemon(ctx context.Context, id int64, data entity.Pokemon) (err error) {
+	var v validation.Validator
+
+	if v.Required("name", data.Name) {
+		v.MaxLength("name", data.Name, maxNameLength)
+	}
+
+	if v.Required("species", data.Species) {
+		v.MaxLength("species", data.Species, maxNameLength)
+	}
+
+	v.Check(data.Catched == 0 || data.Catched == CATCH, "catched", "invalid", "catched must be 0 or 1")
+	v.Min("weight", data.Weight, 0)
+	v.Min("height", data.Height, 0)
+	v.Min("stats.hp", float64(data.Stats.HP), 0)
+	v.Min("stats.attack", float64(data.Stats.Attack), 0)
+	v.Min("stats.def", float64(data.Stats.Def), 0)
+	v.Min("stats.speed", float64(data.Stats.Speed), 0)
+
+	if len(data.Types) > 0 {
+		types, err := pu.TypesRepository.GetAllTypeDB(ctx)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool, len(types))
+		for _, t := range types {
+			known[t.ID] = true
+		}
+
+		seen := make(map[int64]bool, len(data.Types))
+		for i, typeID := range data.Types {
+			field := fmt.Sprintf("types[%d]", i)
+			if !v.Check(!seen[typeID], field, "duplicate", fmt.Sprintf("type %d is listed more than once", typeID)) {
+				continue
+			}
+			seen[typeID] = true
+
+			v.Check(typeID > 0 && known[typeID], field, "not_found", fmt.Sprintf("type %d does not exist", typeID))
+		}
+	}
+
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	// pokemons in the trash keep their name so it is still unique when they are restored
+	pokemon, err := pu.PokemonRepository.GetPokemonByNameDB(ctx, strings.TrimSpace(data.Name))
+	if err == sql.ErrNoRows || (err == nil && pokemon.ID == id) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if pokemon.DeletedAt != nil {
+		return taken("name", fmt.Sprintf("name %s is used by a pokemon in the trash, restore or purge it first", data.Name))
+	}
+
+	return pokemonNameTaken(data.Name)
+}
+
+func pokemonNameTaken(name string) error {
+	return taken("name", fmt.Sprintf("pokemon %s already exists", name))
+}
+
+// validatePokemonFilter checks the filter uses only the fields, sort columns and orders pokemons can be listed by
+func validatePokemonFilter(data entity.PokemonFilter) (err error) {
+	if data.Expr != nil {
+		err = filter.Validate(data.Expr, pokemonrepository.FilterFields)
+		if err != nil {
+			return apperror.Wrap(apperror.Validation, "invalid_filter", err)
+		}
+	}
+
+	if _, ok := pokemonrepository.SortColumns[data.SortBy]; data.SortBy != "" && !ok {
+		return apperror.Newf(apperror.Validation, "invalid_sort", "pokemons can't be sorted by %s", data.SortBy)
+	}
+
+	if order := strings.ToLower(data.OrderBy); order != "" && order != "asc" && order != "desc" {
+		return apperror.Newf(apperror.Validation, "invalid_order", "order %s is not valid, use asc or desc", data.OrderBy)
+	}
+
+	return nil
+}
+
+// validateType checks the payload of type with the id, id is 0 for a new type
+func (tr *TypeUsecase) validateType(ctx context.Context, id int64, data entity.Type) (err error) {
+	var v validation.Validator
+
+	if v.Required("name", data.Name) {
+		v.MaxLength("name", data.Name, maxNameLength)
+	}
+
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	existing, err := tr.TypesRepository.GetTypeByNameDB(ctx, strings.TrimSpace(data.Name))
+	if err == sql.ErrNoRows || (err == nil && existing.ID == id) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.DeletedAt != nil {
+		return taken("name", fmt.Sprintf("name %s is used by a type in the trash, restore or purge it first", data.Name))
+	}
+
+	return typeNameTaken(data.Name)
+}
+
+func typeNameTaken(name string) error {
+	return taken("name", fmt.Sprintf("type %s already exists", name))
+}
+
+// validateNewUser checks the payload of an account created with username and password
+func (uu *UserUsecase) validateNewUser(ctx context.Context, username string, email string, password string) (err error) {
+	var v validation.Validator
+
+	if v.Required("username", username) {
+		v.MaxLength("username", username, maxNameLength)
+	}
+
+	if v.Required("email", email) && v.MaxLength("email", email, maxNameLength) {
+		v.Email("email", email)
+	}
+
+	if err := uu.PasswordPolicy.Validate(password); err != nil {
+		v.Add("password", "weak_password", err.Error())
+	}
+
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	_, err = uu.UserRepository.GetUserByUsername(ctx, username)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return usernameTaken(username)
+}
+
+func usernameTaken(username string) error {
+	return taken("username", fmt.Sprintf("username %s already taken", username))
+}
+
+// taken returns the conflict of a field whose value another row already has, unlike a validation error
+// the payload is valid and may be accepted once the other row is changed
+func taken(field string, message string) error {
+	return &apperror.Error{
+		Kind:    apperror.Conflict,
+		Code:    field + "_taken",
+		Message: message,
+		Fields:  []apperror.FieldError{{Field: field, Code: "taken", Message: message}},
+	}
+}
+
+// isDuplicate reports whether the write was refused by a unique index, that happens when another request
+// took the name between the check and the write
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry
+}
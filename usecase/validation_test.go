@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/mock"
+	"github.com/winartodev/go-pokedex/apperror"
+	"github.com/winartodev/go-pokedex/entity"
+)
+
+func TestIsDuplicate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "duplicate entry",
+			err:  &mysql.MySQLError{Number: errDuplicateEntry, Message: "Duplicate entry 'FIRE' for key 'uq_types_name'"},
+			want: true,
+		},
+		{
+			name: "wrapped duplicate entry",
+			err:  fmt.Errorf("create type: %w", &mysql.MySQLError{Number: errDuplicateEntry}),
+			want: true,
+		},
+		{
+			name: "other mysql error",
+			err:  &mysql.MySQLError{Number: 1146, Message: "Table 'pokedex.types' doesn't exist"},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("error"),
+			want: false,
+		},
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDuplicate(tt.err); got != tt.want {
+				t.Errorf("isDuplicate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeUsecase_CreateType_Taken(t *testing.T) {
+	prov := typeProvider()
+
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		message string
+	}{
+		{
+			name: "name of another type",
+			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, "FIRE").
+					Return(entity.Type{ID: 5, Name: "FIRE"}, nil).Times(1)
+			},
+			message: "type FIRE already exists",
+		},
+		{
+			name: "name of a type in the trash",
+			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, "FIRE").
+					Return(entity.Type{ID: 5, Name: "FIRE", DeletedAt: &deletedAt}, nil).Times(1)
+			},
+			message: "name FIRE is used by a type in the trash, restore or purge it first",
+		},
+		{
+			name: "name created by another request in the meantime",
+			mock: func() {
+				prov.TypesRepository.On("GetTypeByNameDB", mock.Anything, "FIRE").
+					Return(entity.Type{}, sql.ErrNoRows).Times(1)
+
+				prov.Transactor.On("WithinTransaction", mock.Anything, mock.Anything).
+					Return(withinTransaction).Times(1)
+
+				prov.TypesRepository.On("CreateTypeDB", mock.Anything, mock.Anything).
+					Return(int64(0), &mysql.MySQLError{Number: errDuplicateEntry}).Times(1)
+			},
+			message: "type FIRE already exists",
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+		defer tt.mock()
+		t.Run(tt.name, func(t *testing.T) {
+			tu := &TypeUsecase{
+				TypesRepository: prov.TypesRepository,
+				AuditRepository: prov.AuditRepository,
+				Transactor:      prov.Transactor,
+			}
+			_, err := tu.CreateType(context.Background(), entity.Type{Name: "FIRE"})
+
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Kind != apperror.Conflict || appErr.Code != "name_taken" {
+				t.Errorf("TypeUsecase.CreateType() error = %#v, want conflict name_taken", err)
+				return
+			}
+			if len(appErr.Fields) != 1 || appErr.Fields[0].Field != "name" || appErr.Fields[0].Code != "taken" {
+				t.Errorf("TypeUsecase.CreateType() fields = %v, want name taken", appErr.Fields)
+			}
+			if appErr.Message != tt.message {
+				t.Errorf("TypeUsecase.CreateType() message = %q, want %q", appErr.Message, tt.message)
+			}
+		})
+	}
+}
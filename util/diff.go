@@ -0,0 +1,66 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change is a member that differs between two json documents, nested objects are compared member by member
+// and their path is joined with a dot, From is nil when the member was added and To is nil when it was removed
+type Change struct {
+	Path string
+	From interface{}
+	To   interface{}
+}
+
+// DiffJSON returns the changes from the before document to the after document sorted by path, arrays are compared as a whole
+func DiffJSON(before []byte, after []byte) (changes []Change, err error) {
+	var b, a interface{}
+	if err = json.Unmarshal(before, &b); err != nil {
+		return changes, err
+	}
+
+	if err = json.Unmarshal(after, &a); err != nil {
+		return changes, err
+	}
+
+	changes = diffValue("", b, a, changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func diffValue(path string, before interface{}, after interface{}, changes []Change) []Change {
+	beforeObject, beforeOK := before.(map[string]interface{})
+	afterObject, afterOK := after.(map[string]interface{})
+	if !beforeOK || !afterOK {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, Change{Path: path, From: before, To: after})
+		}
+
+		return changes
+	}
+
+	for name, value := range beforeObject {
+		changes = diffValue(joinPath(path, name), value, afterObject[name], changes)
+	}
+
+	for name, value := range afterObject {
+		if _, ok := beforeObject[name]; !ok {
+			changes = diffValue(joinPath(path, name), nil, value, changes)
+		}
+	}
+
+	return changes
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	type args struct {
+		before string
+		after  string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantChanges []Change
+		wantErr     bool
+	}{
+		{
+			name: "same document",
+			args: args{
+				before: `{"name":"Bulbasaur","types":[1,9]}`,
+				after:  `{"types":[1,9],"name":"Bulbasaur"}`,
+			},
+			wantChanges: nil,
+		},
+		{
+			name: "changed member",
+			args: args{
+				before: `{"name":"Bulbasaur","species":"Seed Pokemon"}`,
+				after:  `{"name":"Ivysaur","species":"Seed Pokemon"}`,
+			},
+			wantChanges: []Change{{Path: "name", From: "Bulbasaur", To: "Ivysaur"}},
+		},
+		{
+			name: "nested object",
+			args: args{
+				before: `{"stats":{"hp":45,"attack":49}}`,
+				after:  `{"stats":{"hp":60,"attack":49}}`,
+			},
+			wantChanges: []Change{{Path: "stats.hp", From: float64(45), To: float64(60)}},
+		},
+		{
+			name: "added and removed members",
+			args: args{
+				before: `{"description":"seed"}`,
+				after:  `{"image_url":"https://image.com/1"}`,
+			},
+			wantChanges: []Change{
+				{Path: "description", From: "seed", To: nil},
+				{Path: "image_url", From: nil, To: "https://image.com/1"},
+			},
+		},
+		{
+			name: "array is compared as a whole",
+			args: args{
+				before: `{"types":[1,9]}`,
+				after:  `{"types":[9]}`,
+			},
+			wantChanges: []Change{{Path: "types", From: []interface{}{float64(1), float64(9)}, To: []interface{}{float64(9)}}},
+		},
+		{
+			name: "invalid before",
+			args: args{
+				before: `{`,
+				after:  `{}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid after",
+			args: args{
+				before: `{}`,
+				after:  `{`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotChanges, err := DiffJSON([]byte(tt.args.before), []byte(tt.args.after))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DiffJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotChanges, tt.wantChanges) {
+				t.Errorf("DiffJSON() = %v, want %v", gotChanges, tt.wantChanges)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidMergePatch is returned when the patch or the document is not valid json
+var ErrInvalidMergePatch = errors.New("merge patch is not valid json")
+
+// MergePatch applies RFC 7396 json merge patch to the document, members of the patch replace the members of the document,
+// null removes the member and objects are merged recursively
+func MergePatch(document []byte, patch []byte) (result []byte, err error) {
+	var doc, p interface{}
+	if err = json.Unmarshal(document, &doc); err != nil {
+		return result, ErrInvalidMergePatch
+	}
+
+	if err = json.Unmarshal(patch, &p); err != nil {
+		return result, ErrInvalidMergePatch
+	}
+
+	return json.Marshal(mergeValue(doc, p))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+
+		targetObject[name] = mergeValue(targetObject[name], value)
+	}
+
+	return targetObject
+}
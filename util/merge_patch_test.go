@@ -0,0 +1,88 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	type args struct {
+		document string
+		patch    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "replace member",
+			args: args{
+				document: `{"name":"Bulbasaur","species":"Seed Pokemon"}`,
+				patch:    `{"name":"Ivysaur"}`,
+			},
+			want: `{"name":"Ivysaur","species":"Seed Pokemon"}`,
+		},
+		{
+			name: "merge nested object",
+			args: args{
+				document: `{"stats":{"hp":45,"attack":49}}`,
+				patch:    `{"stats":{"hp":60}}`,
+			},
+			want: `{"stats":{"hp":60,"attack":49}}`,
+		},
+		{
+			name: "null removes member",
+			args: args{
+				document: `{"name":"Bulbasaur","description":"seed"}`,
+				patch:    `{"description":null}`,
+			},
+			want: `{"name":"Bulbasaur"}`,
+		},
+		{
+			name: "array is replaced",
+			args: args{
+				document: `{"types":[1,9]}`,
+				patch:    `{"types":[2]}`,
+			},
+			want: `{"types":[2]}`,
+		},
+		{
+			name: "patch that is not object replaces document",
+			args: args{
+				document: `{"name":"Bulbasaur"}`,
+				patch:    `["a"]`,
+			},
+			want: `["a"]`,
+		},
+		{
+			name: "invalid patch",
+			args: args{
+				document: `{"name":"Bulbasaur"}`,
+				patch:    `{"name":`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.args.document), []byte(tt.args.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergePatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotValue, wantValue interface{}
+			json.Unmarshal(got, &gotValue)
+			json.Unmarshal([]byte(tt.want), &wantValue)
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("MergePatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxPasswordBytes is the longest password bcrypt hashes, the bytes after it are ignored by bcrypt
+const MaxPasswordBytes = 72
+
+// PasswordPolicy describes the passwords accepted for an account, zero value only rejects empty passwords and
+// passwords longer than MaxPasswordBytes. MinLength counts characters and MaxLength counts bytes like bcrypt
+type PasswordPolicy struct {
+	MinLength int64
+	MaxLength int64
+	Breached  map[string]bool
+}
+
+var ErrBreachedPassword = errors.New("password is too common, choose a different one")
+
+// Validate returns the reason the password does not satisfy the policy
+func (pp PasswordPolicy) Validate(password string) error {
+	if password == "" {
+		return errors.New("password can't be empty")
+	}
+
+	if int64(utf8.RuneCountInString(password)) < pp.MinLength {
+		return fmt.Errorf("password must be at least %d characters", pp.MinLength)
+	}
+
+	maxLength := int64(MaxPasswordBytes)
+	if pp.MaxLength > 0 && pp.MaxLength < maxLength {
+		maxLength = pp.MaxLength
+	}
+	if int64(len(password)) > maxLength {
+		return fmt.Errorf("password must be at most %d bytes", maxLength)
+	}
+
+	if pp.Breached[strings.ToLower(password)] {
+		return ErrBreachedPassword
+	}
+
+	return nil
+}
+
+// LoadBreachedPasswords reads breached password list with one password per line, empty lines and lines starting with # are skipped
+func LoadBreachedPasswords(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return passwords, nil
+}
@@ -0,0 +1,128 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 16,
+		Breached:  map[string]bool{"password123": true},
+	}
+
+	type args struct {
+		password string
+	}
+	tests := []struct {
+		name    string
+		policy  PasswordPolicy
+		args    args
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			policy: policy,
+			args: args{
+				password: "pikachu-thunder",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "success zero policy",
+			policy: PasswordPolicy{},
+			args: args{
+				password: "1",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "failed empty",
+			policy: PasswordPolicy{},
+			args: args{
+				password: "",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed too short",
+			policy: policy,
+			args: args{
+				password: "pika",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed too long",
+			policy: policy,
+			args: args{
+				password: "pikachu-thunderbolt",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed too many bytes of multibyte characters",
+			policy: policy,
+			args: args{
+				password: "ピカチュウ十万ボルト",
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed longer than bcrypt with zero policy",
+			policy: PasswordPolicy{},
+			args: args{
+				password: strings.Repeat("a", MaxPasswordBytes+1),
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed longer than bcrypt with larger policy",
+			policy: PasswordPolicy{MaxLength: 128},
+			args: args{
+				password: strings.Repeat("é", MaxPasswordBytes/2+1),
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed breached ignoring case",
+			policy: policy,
+			args: args{
+				password: "Password123",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(tt.args.password); (err != nil) != tt.wantErr {
+				t.Errorf("PasswordPolicy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(path, []byte("# common passwords\nPassword\n\n  qwerty  \n"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	got, err := LoadBreachedPasswords(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedPasswords() error = %v", err)
+	}
+
+	if len(got) != 2 || !got["password"] || !got["qwerty"] {
+		t.Errorf("LoadBreachedPasswords() = %v, want password and qwerty", got)
+	}
+
+	_, err = LoadBreachedPasswords(filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil {
+		t.Errorf("LoadBreachedPasswords() error = nil, want error for missing file")
+	}
+}
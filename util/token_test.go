@@ -0,0 +1,52 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	first, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	second, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if len(first) != 43 {
+		t.Errorf("GenerateToken() length = %v, want %v", len(first), 43)
+	}
+	if first == second {
+		t.Errorf("GenerateToken() returns the same token twice")
+	}
+}
+
+func TestSignToken(t *testing.T) {
+	type args struct {
+		secret string
+		token  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "success",
+			args: args{
+				secret: "secret",
+				token:  "token",
+			},
+			want: "e941110e3d2bfe82621f0e3e1434730d7305d106c5f68c87165d0b27a4611a4a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SignToken(tt.args.secret, tt.args.token); got != tt.want {
+				t.Errorf("SignToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
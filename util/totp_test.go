@@ -0,0 +1,163 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the base32 encoding of the RFC 6238 SHA1 test key "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+
+	if len(secret) != 32 {
+		t.Errorf("GenerateTOTPSecret() length = %v, want %v", len(secret), 32)
+	}
+
+	if _, err := GenerateTOTPCode(secret, time.Now()); err != nil {
+		t.Errorf("GenerateTOTPCode() error = %v", err)
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	got := TOTPURI("Go Pokedex", "ash@mail", "SECRET")
+	if !strings.HasPrefix(got, "otpauth://totp/Go%20Pokedex:ash@mail?") || !strings.Contains(got, "secret=SECRET") || !strings.Contains(got, "issuer=Go+Pokedex") {
+		t.Errorf("TOTPURI() = %v", got)
+	}
+}
+
+func TestGenerateTOTPCode(t *testing.T) {
+	type args struct {
+		secret string
+		t      time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "success rfc 6238 vector 59",
+			args: args{
+				secret: rfcSecret,
+				t:      time.Unix(59, 0),
+			},
+			want:    "287082",
+			wantErr: false,
+		},
+		{
+			name: "success rfc 6238 vector 1111111109",
+			args: args{
+				secret: rfcSecret,
+				t:      time.Unix(1111111109, 0),
+			},
+			want:    "081804",
+			wantErr: false,
+		},
+		{
+			name: "failed invalid secret",
+			args: args{
+				secret: "not base32!",
+				t:      time.Unix(59, 0),
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateTOTPCode(tt.args.secret, tt.args.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateTOTPCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GenerateTOTPCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	type args struct {
+		code string
+		t    time.Time
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantStep int64
+		want     bool
+	}{
+		{
+			name: "success current step",
+			args: args{
+				code: "081804",
+				t:    now,
+			},
+			wantStep: 37037036,
+			want:     true,
+		},
+		{
+			name: "success previous step",
+			args: args{
+				code: "081804",
+				t:    now.Add(TOTPPeriod),
+			},
+			wantStep: 37037036,
+			want:     true,
+		},
+		{
+			name: "failed outside skew",
+			args: args{
+				code: "081804",
+				t:    now.Add(3 * TOTPPeriod),
+			},
+			want: false,
+		},
+		{
+			name: "failed wrong code",
+			args: args{
+				code: "000000",
+				t:    now,
+			},
+			want: false,
+		},
+		{
+			name: "failed wrong length",
+			args: args{
+				code: "81804",
+				t:    now,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, got := ValidateTOTPCode(rfcSecret, tt.args.code, tt.args.t)
+			if got != tt.want || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTPCode() = %v, %v, want %v, %v", gotStep, got, tt.wantStep, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCode() error = %v", err)
+	}
+
+	if !regexp.MustCompile(`^[a-z2-9]{5}-[a-z2-9]{5}$`).MatchString(code) {
+		t.Errorf("GenerateRecoveryCode() = %v, want xxxxx-xxxxx format", code)
+	}
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/winartodev/go-pokedex/apperror"
+)
+
+// Validator collects every field error of a payload so the client can fix all of them at once,
+// the zero value is ready to use
+type Validator struct {
+	fields []apperror.FieldError
+}
+
+// Add records error of the field
+func (v *Validator) Add(field string, code string, message string) {
+	v.fields = append(v.fields, apperror.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// Check records error of the field when ok is false, it returns ok so dependent checks can be skipped
+func (v *Validator) Check(ok bool, field string, code string, message string) bool {
+	if !ok {
+		v.Add(field, code, message)
+	}
+
+	return ok
+}
+
+// Required checks the value is not blank
+func (v *Validator) Required(field string, value string) bool {
+	return v.Check(strings.TrimSpace(value) != "", field, "required", fmt.Sprintf("%s can't be empty", field))
+}
+
+// MaxLength checks the value has at most max characters
+func (v *Validator) MaxLength(field string, value string, max int) bool {
+	return v.Check(utf8.RuneCountInString(value) <= max, field, "too_long", fmt.Sprintf("%s must be at most %d characters", field, max))
+}
+
+// Min checks the value is not less than min
+func (v *Validator) Min(field string, value float64, min float64) bool {
+	return v.Check(value >= min, field, "too_small", fmt.Sprintf("%s must be at least %v", field, min))
+}
+
+// Email checks the value is a plain email address without display name
+func (v *Validator) Email(field string, value string) bool {
+	address, err := mail.ParseAddress(value)
+	return v.Check(err == nil && address.Address == value, field, "invalid_email", fmt.Sprintf("%s is not a valid email address", field))
+}
+
+// Valid reports whether no field error is recorded
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err returns validation error with every recorded field error, or nil when the payload is valid
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+
+	messages := make([]string, 0, len(v.fields))
+	for _, field := range v.fields {
+		messages = append(messages, field.Message)
+	}
+
+	return &apperror.Error{
+		Kind:    apperror.Validation,
+		Code:    "validation_failed",
+		Message: strings.Join(messages, ", "),
+		Fields:  v.fields,
+	}
+}
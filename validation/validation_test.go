@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/winartodev/go-pokedex/apperror"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		validate   func(v *Validator)
+		wantFields []apperror.FieldError
+	}{
+		{
+			name: "valid",
+			validate: func(v *Validator) {
+				v.Required("name", "Bulbasaur")
+				v.MaxLength("name", "Bulbasaur", 255)
+				v.Min("weight", 6.9, 0)
+				v.Email("email", "ash@mail.com")
+			},
+			wantFields: nil,
+		},
+		{
+			name: "every error is collected",
+			validate: func(v *Validator) {
+				v.Required("name", "  ")
+				v.MaxLength("species", "Seed Pokemon", 4)
+				v.Min("weight", -1, 0)
+				v.Email("email", "Ash <ash@mail.com>")
+			},
+			wantFields: []apperror.FieldError{
+				{Field: "name", Code: "required", Message: "name can't be empty"},
+				{Field: "species", Code: "too_long", Message: "species must be at most 4 characters"},
+				{Field: "weight", Code: "too_small", Message: "weight must be at least 0"},
+				{Field: "email", Code: "invalid_email", Message: "email is not a valid email address"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			tt.validate(&v)
+
+			err := v.Err()
+			if (err != nil) != (tt.wantFields != nil) {
+				t.Fatalf("Validator.Err() error = %v, want fields %v", err, tt.wantFields)
+			}
+
+			if err == nil {
+				return
+			}
+
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Kind != apperror.Validation {
+				t.Fatalf("Validator.Err() = %v, want validation error", err)
+			}
+
+			if !reflect.DeepEqual(appErr.Fields, tt.wantFields) {
+				t.Errorf("Validator.Err() fields = %v, want %v", appErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}